@@ -18,7 +18,10 @@ import (
 	"chatbox-gin/internal/middleware"
 	"chatbox-gin/internal/realtime"
 	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/routing"
+	"chatbox-gin/internal/scheduler"
 	"chatbox-gin/internal/services"
+	"chatbox-gin/internal/storage"
 	"chatbox-gin/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -78,6 +81,22 @@ func main() {
 	ruleRepo := repositories.NewRuleRepository(db)
 	userRepo := repositories.NewUserRepository(db)
 	channelAccountRepo := repositories.NewChannelAccountRepository(db)
+	noteRepo := repositories.NewNoteRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	workspaceRepo := repositories.NewWorkspaceRepository(db)
+	routingLogRepo := repositories.NewRoutingLogRepository(db)
+	csatRepo := repositories.NewCSATRepository(db)
+	searchRepo := repositories.NewSearchRepository(db)
+	mediaRepo := repositories.NewMediaRepository(db)
+	cannedRepo := repositories.NewCannedResponseRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	macroRepo := repositories.NewMacroRepository(db)
+	bulkJobRepo := repositories.NewBulkJobRepository(db)
+	scheduledMessageRepo := repositories.NewScheduledMessageRepository(db)
+	campaignRepo := repositories.NewCampaignRepository(db)
+	contactRepo := repositories.NewContactRepository(db)
+	customFieldRepo := repositories.NewCustomFieldRepository(db)
+	contactJobRepo := repositories.NewContactJobRepository(db)
 
 	log.Info("repositories initialized")
 
@@ -122,9 +141,58 @@ func main() {
 		log.Warn("centrifugo not configured, using noop publisher")
 	}
 
+	// =========================================================================
+	// Khởi tạo Blob Storage (file đính kèm)
+	// =========================================================================
+	blobStore, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatal("failed to init storage", zap.Error(err))
+	}
+	log.Info("storage initialized", zap.String("driver", cfg.Storage.Driver))
+
+	// =========================================================================
+	// Khởi tạo Routing Engine (tự động phân công agent)
+	// =========================================================================
+	conversationRouter := routing.NewRouter(
+		workspaceRepo,
+		userRepo,
+		conversationRepo,
+		routingLogRepo,
+		publisher,
+		log,
+	)
+
 	// =========================================================================
 	// Khởi tạo Services
 	// =========================================================================
+	customFieldService := services.NewCustomFieldService(customFieldRepo, log)
+	mediaService := services.NewMediaService(
+		mediaRepo,
+		messageRepo,
+		blobStore,
+		publisher,
+		cfg.Media,
+		log,
+	)
+	outboundService := services.NewOutboundService(
+		conversationRepo,
+		messageRepo,
+		participantRepo,
+		channelAccountRepo,
+		channelRegistry,
+		mediaService,
+		publisher,
+		cfg.Window,
+		log,
+	)
+	csatService := services.NewCSATService(
+		csatRepo,
+		workspaceRepo,
+		channelAccountRepo,
+		userRepo,
+		outboundService,
+		log,
+	)
 	messageService := services.NewMessageService(
 		participantRepo,
 		conversationRepo,
@@ -132,9 +200,140 @@ func main() {
 		channelAccountRepo,
 		channelRegistry,
 		botResponder,
+		conversationRouter,
+		csatService,
+		mediaService,
+		customFieldService,
+		publisher,
+		log,
+	)
+	notificationService := services.NewNotificationService(notificationRepo, publisher, log)
+	noteService := services.NewNoteService(
+		noteRepo,
+		conversationRepo,
+		userRepo,
+		notificationService,
+		publisher,
+		log,
+	)
+	presenceService := services.NewPresenceService(
+		userRepo,
+		conversationRepo,
+		workspaceRepo,
+		publisher,
+		cfg.Presence,
+		log,
+	)
+	slaService := services.NewSLAService(
+		workspaceRepo,
+		conversationRepo,
+		userRepo,
+		notificationService,
+		publisher,
+		log,
+	)
+	autoCloseService := services.NewAutoCloseService(
+		workspaceRepo,
+		conversationRepo,
+		outboundService,
+		csatService,
+		publisher,
+		cfg.AutoClose.BatchSize,
+		log,
+	)
+	searchService := services.NewSearchService(searchRepo, conversationRepo, log)
+	typingService := services.NewTypingService(
+		conversationRepo,
+		userRepo,
+		outboundService,
+		publisher,
+		log,
+	)
+	readService := services.NewReadService(
+		conversationRepo,
+		messageRepo,
+		outboundService,
 		publisher,
 		log,
 	)
+	routingService := services.NewRoutingService(
+		conversationRouter,
+		routingLogRepo,
+		conversationRepo,
+		workspaceRepo,
+		userRepo,
+		log,
+	)
+	cannedService := services.NewCannedResponseService(
+		cannedRepo,
+		participantRepo,
+		userRepo,
+		workspaceRepo,
+		mediaService,
+		log,
+	)
+	macroService := services.NewMacroService(
+		macroRepo,
+		conversationRepo,
+		tagRepo,
+		userRepo,
+		conversationRouter,
+		cannedService,
+		outboundService,
+		csatService,
+		publisher,
+		log,
+	)
+	bulkService := services.NewBulkService(
+		bulkJobRepo,
+		conversationRepo,
+		tagRepo,
+		userRepo,
+		csatService,
+		publisher,
+		cfg.Bulk,
+		log,
+	)
+	snoozeService := services.NewSnoozeService(
+		conversationRepo,
+		notificationService,
+		publisher,
+		cfg.Snooze.BatchSize,
+		log,
+	)
+	scheduledService := services.NewScheduledMessageService(
+		scheduledMessageRepo,
+		conversationRepo,
+		workspaceRepo,
+		outboundService,
+		notificationService,
+		cfg.Schedule.BatchSize,
+		log,
+	)
+	campaignService := services.NewCampaignService(
+		campaignRepo,
+		conversationRepo,
+		participantRepo,
+		channelAccountRepo,
+		workspaceRepo,
+		channelRegistry,
+		outboundService,
+		publisher,
+		cfg.Campaign,
+		log,
+	)
+	contactService := services.NewContactService(contactRepo, customFieldService, log)
+	contactJobService := services.NewContactJobService(
+		contactJobRepo,
+		contactRepo,
+		channelAccountRepo,
+		customFieldRepo,
+		customFieldService,
+		blobStore,
+		publisher,
+		cfg.ContactJob,
+		log,
+	)
 
 	log.Info("services initialized")
 
@@ -142,17 +341,41 @@ func main() {
 	// Khởi tạo Handlers
 	// =========================================================================
 	mockHandler := handlers.NewMockHandler(channelRegistry, messageService, log)
-	ruleHandler := handlers.NewRuleHandler(ruleRepo, log)
+	ruleHandler := handlers.NewRuleHandler(ruleRepo, customFieldService, log)
 	conversationHandler := handlers.NewConversationHandler(
 		conversationRepo,
 		messageRepo,
-		participantRepo,
-		channelAccountRepo,
-		channelRegistry,
+		outboundService,
+		csatService,
+		readService,
+		mediaService,
+		cannedService,
+		scheduledService,
+		customFieldService,
 		publisher,
 		log,
 	)
 
+	noteHandler := handlers.NewNoteHandler(noteService, log)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo, log)
+	routingHandler := handlers.NewRoutingHandler(routingService, log)
+	presenceHandler := handlers.NewPresenceHandler(presenceService, log)
+	slaHandler := handlers.NewSLAHandler(slaService, log)
+	autoCloseHandler := handlers.NewAutoCloseHandler(autoCloseService, log)
+	csatHandler := handlers.NewCSATHandler(csatService, log)
+	searchHandler := handlers.NewSearchHandler(searchService, log)
+	typingHandler := handlers.NewTypingHandler(typingService, log)
+	mediaHandler := handlers.NewMediaHandler(mediaService, log)
+	cannedHandler := handlers.NewCannedResponseHandler(cannedService, mediaService, conversationRepo, log)
+	macroHandler := handlers.NewMacroHandler(macroService, log)
+	bulkHandler := handlers.NewBulkHandler(bulkService, log)
+	snoozeHandler := handlers.NewSnoozeHandler(snoozeService, log)
+	scheduledHandler := handlers.NewScheduledMessageHandler(scheduledService, log)
+	campaignHandler := handlers.NewCampaignHandler(campaignService, log)
+	contactHandler := handlers.NewContactHandler(contactService, log)
+	contactJobHandler := handlers.NewContactJobHandler(contactJobService, log)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldService, log)
+
 	// Auth handler
 	jwtService := auth.NewJWTService(cfg.JWT)
 	authService := services.NewAuthService(userRepo, jwtService, log)
@@ -202,7 +425,7 @@ func main() {
 			"status":   "healthy",
 			"service":  cfg.App.Name,
 			"version":  "1.0.0",
-			"channels": channelRegistry.GetAll(),
+			"channels": channelRegistry.Describe(),
 		})
 	})
 
@@ -235,6 +458,63 @@ func main() {
 			// Conversation & Message routes
 			conversationHandler.RegisterRoutes(protected)
 
+			// Tìm kiếm lịch sử hội thoại
+			searchHandler.RegisterRoutes(protected)
+
+			// Internal notes trên conversation
+			noteHandler.RegisterRoutes(protected)
+
+			// Agent đang soạn tin (typing indicator)
+			typingHandler.RegisterRoutes(protected)
+
+			// File đính kèm đã lưu trong storage
+			mediaHandler.RegisterRoutes(protected)
+
+			// Câu trả lời soạn sẵn (shortcut trong composer)
+			cannedHandler.RegisterRoutes(protected)
+
+			// Macro: nhiều thao tác trên hội thoại bằng một click
+			macroHandler.RegisterRoutes(protected)
+
+			// Thao tác hàng loạt trên hội thoại (job chạy nền)
+			bulkHandler.RegisterRoutes(protected)
+
+			// Tạm ẩn hội thoại đến hạn hoặc khi khách nhắn lại
+			snoozeHandler.RegisterRoutes(protected)
+
+			// Tin nhắn hẹn giờ gửi
+			scheduledHandler.RegisterRoutes(protected)
+
+			// Chiến dịch gửi tin hàng loạt
+			campaignHandler.RegisterRoutes(protected)
+
+			// Khách hàng: tìm kiếm, sửa thông tin, tag, lịch sử
+			contactHandler.RegisterRoutes(protected)
+
+			// Nhập/xuất danh sách khách (CSV)
+			contactJobHandler.RegisterRoutes(protected)
+
+			// Định nghĩa trường tùy chỉnh của khách
+			customFieldHandler.RegisterRoutes(protected)
+
+			// Thông báo của user (mention, ...)
+			notificationHandler.RegisterRoutes(protected)
+
+			// Tự động phân công agent
+			routingHandler.RegisterRoutes(protected)
+
+			// Trạng thái agent & team availability
+			presenceHandler.RegisterRoutes(protected)
+
+			// Chính sách SLA
+			slaHandler.RegisterRoutes(protected)
+
+			// Tự động đóng hội thoại không hoạt động
+			autoCloseHandler.RegisterRoutes(protected)
+
+			// Khảo sát CSAT & báo cáo
+			csatHandler.RegisterRoutes(protected)
+
 			// Rule management routes (dashboard)
 			ruleHandler.RegisterRoutes(protected)
 		}
@@ -246,10 +526,45 @@ func main() {
 			"/api/v1/conversations",
 			"/api/v1/conversations/:id",
 			"/api/v1/conversations/:id/messages",
+			"/api/v1/conversations/:id/notes",
+			"/api/v1/conversations/bulk",
+			"/api/v1/conversations/:id/snooze",
+			"/api/v1/conversations/:id/scheduled-messages",
+			"/api/v1/search",
+			"/api/v1/media/:id",
+			"/api/v1/canned-responses",
+			"/api/v1/macros",
+			"/api/v1/campaigns",
+			"/api/v1/contacts",
+			"/api/v1/contacts/jobs",
+			"/api/v1/custom-fields",
+			"/api/v1/notifications",
+			"/api/v1/routing",
+			"/api/v1/presence",
+			"/api/v1/team/availability",
+			"/api/v1/sla/policy",
+			"/api/v1/auto-close/settings",
+			"/api/v1/csat",
 			"/api/v1/rules",
 		}),
 	)
 
+	// =========================================================================
+	// Khởi động Background Jobs
+	// =========================================================================
+	jobs := scheduler.New(log)
+	jobs.Every("presence_sweep", cfg.Presence.SweepInterval, presenceService.SweepIdle)
+	jobs.Every("sla_evaluate", cfg.SLA.EvaluateInterval, slaService.EvaluateAll)
+	jobs.Every("auto_close", cfg.AutoClose.SweepInterval, autoCloseService.SweepInactive)
+	jobs.Every("media_retry", cfg.Media.RetryInterval, mediaService.RetryPending)
+	jobs.Every("bulk_jobs", cfg.Bulk.PollInterval, bulkService.RunPending)
+	jobs.Every("snooze_wake", cfg.Snooze.SweepInterval, snoozeService.WakeDue)
+	jobs.Every("scheduled_messages", cfg.Schedule.SweepInterval, scheduledService.SendDue)
+	jobs.Every("campaigns", cfg.Campaign.PollInterval, campaignService.RunActive)
+	jobs.Every("contact_jobs", cfg.ContactJob.PollInterval, contactJobService.RunPending)
+	jobs.Start(context.Background())
+	mediaService.Start(context.Background())
+
 	// =========================================================================
 	// Khởi động HTTP Server
 	// =========================================================================
@@ -284,5 +599,10 @@ func main() {
 		log.Error("server forced to shutdown", zap.Error(err))
 	}
 
+	// Dừng background jobs sau khi không còn request mới
+	jobs.Stop()
+	messageService.Stop()
+	mediaService.Stop()
+
 	log.Info("server exited")
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	"chatbox-gin/internal/channel"
 	"chatbox-gin/internal/models"
 	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/template"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -39,12 +40,16 @@ type BotResponse struct {
 
 	// Confidence độ tin cậy
 	Confidence float64
+
+	// SetFields trường tùy chỉnh cần đặt cho khách theo rule (null = xóa)
+	SetFields map[string]interface{}
 }
 
 // Responder interface cho bot responder
 type Responder interface {
 	// Process xử lý inbound message và tạo response
-	Process(ctx context.Context, workspaceID uuid.UUID, recipientID string, content string) (*BotResponse, error)
+	// participant dùng cho điều kiện trường tùy chỉnh và biến template của rule
+	Process(ctx context.Context, workspaceID uuid.UUID, participant *models.Participant, recipientID string, content string) (*BotResponse, error)
 }
 
 // ===========================================================================
@@ -75,7 +80,7 @@ func NewResponder(
 }
 
 // Process xử lý inbound message
-func (r *responder) Process(ctx context.Context, workspaceID uuid.UUID, recipientID string, content string) (*BotResponse, error) {
+func (r *responder) Process(ctx context.Context, workspaceID uuid.UUID, participant *models.Participant, recipientID string, content string) (*BotResponse, error) {
 	// 1. Lấy tất cả active rules của workspace
 	rules, err := r.ruleRepo.FindActiveByWorkspace(ctx, workspaceID)
 	if err != nil {
@@ -94,7 +99,7 @@ func (r *responder) Process(ctx context.Context, workspaceID uuid.UUID, recipien
 	}
 
 	// 2. Match message với rules
-	matchResult := r.ruleEngine.Match(ctx, rules, content)
+	matchResult := r.ruleEngine.Match(ctx, rules, content, participant)
 
 	if !matchResult.Matched {
 		r.logger.Debug("no rule matched",
@@ -120,6 +125,12 @@ func (r *responder) Process(ctx context.Context, workspaceID uuid.UUID, recipien
 		MatchedRule:    rule,
 		MatchedKeyword: matchResult.MatchedKeyword,
 		Confidence:     matchResult.Confidence,
+		SetFields:      rule.ResponseConfig.SetFields,
+	}
+
+	var vars template.Vars
+	if participant != nil {
+		vars = template.ContactVars(participant)
 	}
 
 	if rule.IsHandoffResponse() {
@@ -133,12 +144,12 @@ func (r *responder) Process(ctx context.Context, workspaceID uuid.UUID, recipien
 		// Vẫn gửi message thông báo
 		if rule.ResponseConfig.Message != "" {
 			response.ShouldReply = true
-			response.Response = r.responseBuilder.BuildFromRule(rule, recipientID)
+			response.Response = r.responseBuilder.BuildFromRule(rule, recipientID, vars)
 		}
 	} else {
 		// Regular response
 		response.ShouldReply = true
-		response.Response = r.responseBuilder.BuildFromRule(rule, recipientID)
+		response.Response = r.responseBuilder.BuildFromRule(rule, recipientID, vars)
 	}
 
 	r.logger.Info("bot response generated",
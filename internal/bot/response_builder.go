@@ -3,6 +3,7 @@ package bot
 import (
 	"chatbox-gin/internal/channel"
 	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/template"
 )
 
 // ===========================================================================
@@ -14,7 +15,8 @@ import (
 // ResponseBuilder interface xây dựng response từ rule
 type ResponseBuilder interface {
 	// BuildFromRule tạo OutboundMessage từ rule
-	BuildFromRule(rule *models.Rule, recipientID string) *channel.OutboundMessage
+	// vars là biến template của khách, dùng cho response kiểu template
+	BuildFromRule(rule *models.Rule, recipientID string, vars template.Vars) *channel.OutboundMessage
 }
 
 // ===========================================================================
@@ -30,7 +32,7 @@ func NewResponseBuilder() ResponseBuilder {
 }
 
 // BuildFromRule tạo OutboundMessage từ rule response config
-func (b *responseBuilder) BuildFromRule(rule *models.Rule, recipientID string) *channel.OutboundMessage {
+func (b *responseBuilder) BuildFromRule(rule *models.Rule, recipientID string, vars template.Vars) *channel.OutboundMessage {
 	msg := &channel.OutboundMessage{
 		RecipientID: recipientID,
 		ContentType: "text",
@@ -41,8 +43,8 @@ func (b *responseBuilder) BuildFromRule(rule *models.Rule, recipientID string) *
 		msg.Content = rule.ResponseConfig.Text
 
 	case models.ResponseTemplate:
-		// TODO: Xử lý template rendering
-		msg.Content = rule.ResponseConfig.Text
+		// Thay biến của khách, VD: {{contact.name|bạn}}, {{contact.fields.plan}}
+		msg.Content = template.Render(rule.ResponseConfig.Text, vars)
 
 	case models.ResponseHandoff:
 		// Tin nhắn khi handoff
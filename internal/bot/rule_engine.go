@@ -34,7 +34,8 @@ type MatchResult struct {
 type RuleEngine interface {
 	// Match tìm rule phù hợp với message content
 	// Rules được sắp xếp theo priority, trả về rule đầu tiên match
-	Match(ctx context.Context, rules []models.Rule, content string) MatchResult
+	// Rule có điều kiện trường tùy chỉnh chỉ match khi khách thỏa mọi điều kiện
+	Match(ctx context.Context, rules []models.Rule, content string, participant *models.Participant) MatchResult
 }
 
 // ===========================================================================
@@ -52,12 +53,12 @@ func NewRuleEngine(logger *zap.Logger) RuleEngine {
 }
 
 // Match tìm rule phù hợp với message content
-func (e *ruleEngine) Match(ctx context.Context, rules []models.Rule, content string) MatchResult {
+func (e *ruleEngine) Match(ctx context.Context, rules []models.Rule, content string, participant *models.Participant) MatchResult {
 	now := time.Now()
 
 	// Duyệt qua các rules theo thứ tự priority (đã được sort từ DB)
 	for _, rule := range rules {
-		if !rule.IsActive {
+		if !rule.IsActive || !rule.MatchesConditions(participant) {
 			continue
 		}
 
@@ -97,7 +98,7 @@ func (e *ruleEngine) Match(ctx context.Context, rules []models.Rule, content str
 
 	// Không match rule nào, tìm fallback
 	for _, rule := range rules {
-		if rule.TriggerType == models.TriggerFallback && rule.IsActive {
+		if rule.TriggerType == models.TriggerFallback && rule.IsActive && rule.MatchesConditions(participant) {
 			e.logger.Debug("fallback rule used",
 				zap.String("rule_name", rule.Name),
 			)
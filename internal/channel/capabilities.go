@@ -0,0 +1,373 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ===========================================================================
+// Channel Capabilities
+// Mỗi channel có giới hạn riêng (độ dài text, số quick reply, loại file, ...)
+// Tin nhắn gửi đi được điều chỉnh theo capabilities trước khi gửi:
+// tách text dài, chuyển nút không hỗ trợ thành lựa chọn đánh số,
+// và báo lại những gì đã bị thay đổi (degradation)
+// ===========================================================================
+
+// Capabilities mô tả những gì channel adapter gửi được
+// Giới hạn bằng 0 nghĩa là không giới hạn
+type Capabilities struct {
+	// MaxTextLength số ký tự tối đa của một tin nhắn text
+	MaxTextLength int `json:"max_text_length"`
+
+	// QuickReplies hỗ trợ quick reply
+	QuickReplies bool `json:"quick_replies"`
+
+	// MaxQuickReplies số quick reply tối đa mỗi tin nhắn
+	MaxQuickReplies int `json:"max_quick_replies"`
+
+	// MaxQuickReplyTitle số ký tự tối đa của tiêu đề quick reply
+	MaxQuickReplyTitle int `json:"max_quick_reply_title"`
+
+	// Buttons hỗ trợ nút bấm (postback, web_url, phone_number)
+	Buttons bool `json:"buttons"`
+
+	// MaxButtons số nút tối đa mỗi tin nhắn
+	MaxButtons int `json:"max_buttons"`
+
+	// MaxButtonTitle số ký tự tối đa của tiêu đề nút
+	MaxButtonTitle int `json:"max_button_title"`
+
+	// AttachmentTypes các loại file đính kèm gửi được (image, video, audio, file)
+	AttachmentTypes []string `json:"attachment_types"`
+
+	// SenderActions hỗ trợ typing/mark_seen
+	SenderActions bool `json:"sender_actions"`
+
+	// MessagingWindowHours số giờ kể từ tin nhắn cuối của khách được gửi tin tự do (0 = không giới hạn)
+	// Ngoài cửa sổ chỉ gửi được tin kèm message tag channel chấp nhận
+	MessagingWindowHours int `json:"messaging_window_hours"`
+
+	// MessageTags các message tag dùng được ngoài cửa sổ (xem MetadataMessageTag)
+	MessageTags []string `json:"message_tags,omitempty"`
+
+	// MessageTagMaxAgeHours số giờ tối đa kể từ tin nhắn cuối của khách mà tag còn dùng được
+	// Tag không có trong map thì không giới hạn (VD: HUMAN_AGENT chỉ trong 7 ngày)
+	MessageTagMaxAgeHours map[string]int `json:"message_tag_max_age_hours,omitempty"`
+}
+
+// SupportsMessageTag kiểm tra message tag có dùng được không
+func (c Capabilities) SupportsMessageTag(tag string) bool {
+	for _, t := range c.MessageTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageTagAllowed kiểm tra message tag được hỗ trợ và chưa quá hạn dùng của tag
+// lastInbound là thời điểm khách nhắn gần nhất (nil = khách chưa từng nhắn)
+func (c Capabilities) MessageTagAllowed(tag string, lastInbound *time.Time, now time.Time) bool {
+	if !c.SupportsMessageTag(tag) {
+		return false
+	}
+	maxAge := c.MessageTagMaxAgeHours[tag]
+	if maxAge <= 0 {
+		return true
+	}
+	if lastInbound == nil {
+		return false
+	}
+	return now.Sub(*lastInbound) < time.Duration(maxAge)*time.Hour
+}
+
+// AllowedMessageTags các message tag còn dùng được tại thời điểm now
+func (c Capabilities) AllowedMessageTags(lastInbound *time.Time, now time.Time) []string {
+	var tags []string
+	for _, tag := range c.MessageTags {
+		if c.MessageTagAllowed(tag, lastInbound, now) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// InMessagingWindow kiểm tra còn trong cửa sổ gửi tin tự do không
+// lastInbound là thời điểm khách nhắn gần nhất (nil = khách chưa từng nhắn)
+func (c Capabilities) InMessagingWindow(lastInbound *time.Time, now time.Time) bool {
+	if c.MessagingWindowHours <= 0 {
+		return true
+	}
+	if lastInbound == nil {
+		return false
+	}
+	return now.Sub(*lastInbound) < time.Duration(c.MessagingWindowHours)*time.Hour
+}
+
+// SupportsAttachment kiểm tra loại file đính kèm có gửi được không
+func (c Capabilities) SupportsAttachment(attachmentType string) bool {
+	for _, t := range c.AttachmentTypes {
+		if t == attachmentType {
+			return true
+		}
+	}
+	return false
+}
+
+// DegradationKind loại điều chỉnh đã áp dụng cho tin nhắn
+type DegradationKind string
+
+const (
+	// DegradeTextSplit text dài được tách thành nhiều tin nhắn
+	DegradeTextSplit DegradationKind = "text_split"
+
+	// DegradeQuickRepliesAsText quick reply được chuyển thành lựa chọn đánh số
+	DegradeQuickRepliesAsText DegradationKind = "quick_replies_as_text"
+
+	// DegradeButtonsAsText nút bấm được chuyển thành lựa chọn đánh số
+	DegradeButtonsAsText DegradationKind = "buttons_as_text"
+
+	// DegradeTitleTruncated tiêu đề nút/quick reply bị cắt ngắn
+	DegradeTitleTruncated DegradationKind = "title_truncated"
+
+	// DegradeAttachmentAsLink file đính kèm được gửi dạng link trong text
+	DegradeAttachmentAsLink DegradationKind = "attachment_as_link"
+)
+
+// Degradation một điều chỉnh đã áp dụng khi gửi
+type Degradation struct {
+	Kind   DegradationKind `json:"kind"`
+	Detail string          `json:"detail"`
+}
+
+// String mô tả ngắn gọn để lưu log/metadata
+func (d Degradation) String() string {
+	return string(d.Kind) + ": " + d.Detail
+}
+
+// ===========================================================================
+// Outbound Transformer
+// ===========================================================================
+
+// Adapt điều chỉnh tin nhắn theo capabilities của channel
+// Trả về các tin nhắn cần gửi lần lượt (quick reply, nút, file đi kèm tin cuối)
+// và danh sách điều chỉnh đã áp dụng. Tin nhắn gốc không bị thay đổi
+func Adapt(msg *OutboundMessage, caps Capabilities) ([]*OutboundMessage, []Degradation) {
+	adapted := *msg
+	var degradations []Degradation
+	var options []string
+
+	// Nút bấm: không hỗ trợ hoặc quá số lượng thì chuyển thành lựa chọn đánh số
+	if len(adapted.Buttons) > 0 {
+		switch {
+		case !caps.Buttons || exceeds(len(adapted.Buttons), caps.MaxButtons):
+			for _, b := range adapted.Buttons {
+				options = append(options, buttonOption(b))
+			}
+			degradations = append(degradations, Degradation{
+				Kind:   DegradeButtonsAsText,
+				Detail: fmt.Sprintf("%d nút chuyển thành lựa chọn đánh số", len(adapted.Buttons)),
+			})
+			adapted.Buttons = nil
+		default:
+			buttons := make([]ButtonData, len(adapted.Buttons))
+			for i, b := range adapted.Buttons {
+				b.Title = truncateTitle(b.Title, caps.MaxButtonTitle, &degradations)
+				buttons[i] = b
+			}
+			adapted.Buttons = buttons
+		}
+	}
+
+	// Quick reply: tương tự nút bấm
+	if len(adapted.QuickReplies) > 0 {
+		switch {
+		case !caps.QuickReplies || exceeds(len(adapted.QuickReplies), caps.MaxQuickReplies):
+			for _, qr := range adapted.QuickReplies {
+				options = append(options, qr.Title)
+			}
+			degradations = append(degradations, Degradation{
+				Kind:   DegradeQuickRepliesAsText,
+				Detail: fmt.Sprintf("%d quick reply chuyển thành lựa chọn đánh số", len(adapted.QuickReplies)),
+			})
+			adapted.QuickReplies = nil
+		default:
+			quickReplies := make([]QuickReplyData, len(adapted.QuickReplies))
+			for i, qr := range adapted.QuickReplies {
+				qr.Title = truncateTitle(qr.Title, caps.MaxQuickReplyTitle, &degradations)
+				quickReplies[i] = qr
+			}
+			adapted.QuickReplies = quickReplies
+		}
+	}
+
+	// File đính kèm không gửi được thì gửi link trong text
+	var links []string
+	if len(adapted.Attachments) > 0 {
+		kept := make([]AttachmentData, 0, len(adapted.Attachments))
+		for _, att := range adapted.Attachments {
+			if caps.SupportsAttachment(att.Type) {
+				kept = append(kept, att)
+				continue
+			}
+			links = append(links, attachmentLink(att))
+			degradations = append(degradations, Degradation{
+				Kind:   DegradeAttachmentAsLink,
+				Detail: fmt.Sprintf("file %s gửi dạng link", att.Type),
+			})
+		}
+		adapted.Attachments = kept
+	}
+
+	content := composeText(adapted.Content, options, links)
+
+	parts := splitText(content, caps.MaxTextLength)
+	if len(parts) > 1 {
+		degradations = append(degradations, Degradation{
+			Kind:   DegradeTextSplit,
+			Detail: fmt.Sprintf("tách thành %d tin nhắn (tối đa %d ký tự)", len(parts), caps.MaxTextLength),
+		})
+	}
+	if len(parts) == 0 {
+		parts = []string{""}
+	}
+
+	messages := make([]*OutboundMessage, len(parts))
+	for i, part := range parts {
+		m := adapted
+		m.Content = part
+		if i < len(parts)-1 {
+			m.QuickReplies = nil
+			m.Buttons = nil
+			m.Attachments = nil
+		}
+		messages[i] = &m
+	}
+	return messages, degradations
+}
+
+// SendAdapted điều chỉnh tin nhắn theo capabilities của channel rồi gửi lần lượt
+// Dừng ở phần đầu tiên gửi lỗi; kết quả trả về là của phần gửi cuối cùng
+func SendAdapted(ctx context.Context, ch Channel, msg *OutboundMessage, credentials map[string]string) (*SendResult, []Degradation, error) {
+	messages, degradations := Adapt(msg, ch.Capabilities())
+
+	var result *SendResult
+	for _, m := range messages {
+		var err error
+		result, err = ch.Send(ctx, m, credentials)
+		if err != nil {
+			return nil, degradations, err
+		}
+		if !result.Success {
+			if result.Error == nil {
+				result.Error = errors.New("channel returned unsuccessful result")
+			}
+			return result, degradations, nil
+		}
+	}
+	return result, degradations, nil
+}
+
+// exceeds kiểm tra số lượng vượt giới hạn (0 = không giới hạn)
+func exceeds(count, limit int) bool {
+	return limit > 0 && count > limit
+}
+
+// truncateTitle cắt tiêu đề quá dài và ghi nhận degradation
+func truncateTitle(title string, limit int, degradations *[]Degradation) string {
+	if limit <= 0 || utf8.RuneCountInString(title) <= limit {
+		return title
+	}
+	*degradations = append(*degradations, Degradation{
+		Kind:   DegradeTitleTruncated,
+		Detail: fmt.Sprintf("%q cắt còn %d ký tự", title, limit),
+	})
+	runes := []rune(title)
+	return string(runes[:limit-1]) + "…"
+}
+
+// buttonOption nội dung lựa chọn đánh số của một nút (kèm link/số điện thoại nếu có)
+func buttonOption(b ButtonData) string {
+	if b.Type == "web_url" || b.Type == "phone_number" {
+		return b.Title + ": " + b.Payload
+	}
+	return b.Title
+}
+
+// attachmentLink dòng text thay cho file đính kèm
+func attachmentLink(att AttachmentData) string {
+	if att.Name != "" {
+		return "📎 " + att.Name + ": " + att.URL
+	}
+	return "📎 " + att.URL
+}
+
+// composeText ghép nội dung với các lựa chọn đánh số và link file
+func composeText(content string, options, links []string) string {
+	blocks := make([]string, 0, 3)
+	if strings.TrimSpace(content) != "" {
+		blocks = append(blocks, content)
+	}
+	if len(options) > 0 {
+		lines := make([]string, len(options))
+		for i, option := range options {
+			lines[i] = fmt.Sprintf("%d. %s", i+1, option)
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	if len(links) > 0 {
+		blocks = append(blocks, strings.Join(links, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// splitText tách text thành các phần không quá limit ký tự (0 = không tách)
+// Ưu tiên ngắt ở xuống dòng, rồi cuối câu, rồi khoảng trắng; không có chỗ ngắt hợp lý thì cắt cứng
+func splitText(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	runes := []rune(text)
+	for len(runes) > limit {
+		cut := breakPoint(runes, limit)
+		if part := strings.TrimSpace(string(runes[:cut])); part != "" {
+			parts = append(parts, part)
+		}
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " \t\n"))
+	}
+	if rest := strings.TrimSpace(string(runes)); rest != "" {
+		parts = append(parts, rest)
+	}
+	return parts
+}
+
+// breakPoint vị trí ngắt tốt nhất trong limit ký tự đầu
+// Chỉ xét nửa sau để các phần không quá ngắn
+func breakPoint(runes []rune, limit int) int {
+	floor := limit / 2
+	matchers := []func(i int) bool{
+		func(i int) bool { return runes[i] == '\n' },
+		func(i int) bool {
+			return (runes[i] == '.' || runes[i] == '!' || runes[i] == '?') && i+1 < len(runes) && runes[i+1] == ' '
+		},
+		func(i int) bool { return runes[i] == ' ' },
+	}
+	for _, match := range matchers {
+		for i := limit - 1; i >= floor; i-- {
+			if match(i) {
+				return i + 1
+			}
+		}
+	}
+	return limit
+}
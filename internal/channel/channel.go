@@ -2,6 +2,7 @@ package channel
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -45,6 +46,9 @@ type InboundMessage struct {
 	// Attachments danh sách file đính kèm
 	Attachments []AttachmentData
 
+	// Referral nguồn giới thiệu đi kèm tin nhắn (VD: click quảng cáo)
+	Referral *Referral
+
 	// Timestamp thời điểm gửi tin nhắn
 	Timestamp time.Time
 
@@ -59,6 +63,10 @@ type AttachmentData struct {
 	Name     string `json:"name"`      // Tên file
 	Size     int64  `json:"size"`      // Kích thước (bytes)
 	MimeType string `json:"mime_type"` // MIME type
+
+	// Open mở nội dung file để upload trực tiếp lên channel (tin nhắn gửi đi)
+	// nil = channel gửi theo URL (URL phải truy cập được từ bên ngoài)
+	Open func(ctx context.Context) (io.ReadCloser, error) `json:"-"`
 }
 
 // OutboundMessage đại diện cho tin nhắn gửi đi cho khách hàng
@@ -84,10 +92,20 @@ type OutboundMessage struct {
 	// Buttons các nút bấm
 	Buttons []ButtonData
 
-	// Metadata thông tin bổ sung
+	// Metadata thông tin bổ sung (VD: MetadataMessageTag)
 	Metadata map[string]interface{}
 }
 
+// MetadataMessageTag key trong OutboundMessage.Metadata chứa message tag
+// (gửi ngoài cửa sổ nhắn tin, xem Capabilities.MessageTags)
+const MetadataMessageTag = "message_tag"
+
+// MessageTag message tag của tin nhắn (rỗng nếu không có)
+func (m *OutboundMessage) MessageTag() string {
+	tag, _ := m.Metadata[MetadataMessageTag].(string)
+	return tag
+}
+
 // QuickReplyData đại diện cho nút quick reply
 type QuickReplyData struct {
 	Title   string `json:"title"`   // Text hiển thị
@@ -143,6 +161,27 @@ type SignatureVerifier interface {
 	Verify(signature string, body []byte, secret string) bool
 }
 
+// SenderAction hành động hiển thị phía khách hàng (đang soạn, đã xem)
+type SenderAction string
+
+const (
+	// ActionTypingOn hiển thị "đang soạn tin" cho khách
+	ActionTypingOn SenderAction = "typing_on"
+
+	// ActionTypingOff tắt "đang soạn tin"
+	ActionTypingOff SenderAction = "typing_off"
+
+	// ActionMarkSeen đánh dấu đã xem tin nhắn của khách
+	ActionMarkSeen SenderAction = "mark_seen"
+)
+
+// SenderActions capability tùy chọn: gửi sender action cho khách
+// Channel không hỗ trợ thì không implement, kiểm tra bằng type assertion
+type SenderActions interface {
+	// SendAction gửi sender action cho người nhận
+	SendAction(ctx context.Context, recipientID string, action SenderAction, credentials map[string]string) error
+}
+
 // Channel là interface tổng hợp cho một channel adapter
 // Mỗi channel type (facebook, zalo, mock) sẽ implement interface này
 type Channel interface {
@@ -152,4 +191,7 @@ type Channel interface {
 
 	// Type trả về loại channel
 	Type() string
+
+	// Capabilities mô tả giới hạn gửi tin của channel
+	Capabilities() Capabilities
 }
@@ -0,0 +1,113 @@
+package channel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Inbound Events
+// Một webhook có thể chứa nhiều loại sự kiện ngoài tin nhắn của khách:
+// báo đã nhận, đã xem, tin nhắn page tự gửi (echo), reaction, referral
+// ===========================================================================
+
+// EventType loại sự kiện webhook
+type EventType string
+
+const (
+	// EventMessage tin nhắn hoặc postback của khách
+	EventMessage EventType = "message"
+
+	// EventEcho tin nhắn page gửi cho khách (VD: trả lời từ Meta Business Suite)
+	EventEcho EventType = "echo"
+
+	// EventDelivery khách đã nhận tin nhắn
+	EventDelivery EventType = "delivery"
+
+	// EventRead khách đã xem tin nhắn
+	EventRead EventType = "read"
+
+	// EventReaction khách thả/bỏ reaction trên một tin nhắn
+	EventReaction EventType = "reaction"
+
+	// EventReferral khách đến từ link m.me, quảng cáo, plugin chat
+	EventReferral EventType = "referral"
+)
+
+// InboundEvent một sự kiện đã chuẩn hóa từ webhook
+// Chỉ field tương ứng với Type được set
+type InboundEvent struct {
+	// Type loại sự kiện
+	Type EventType
+
+	// SenderID ID khách hàng trên channel (với echo là người nhận)
+	SenderID string
+
+	// RecipientID ID page/OA nhận webhook
+	RecipientID string
+
+	// Timestamp thời điểm xảy ra sự kiện
+	Timestamp time.Time
+
+	// Message tin nhắn (message, echo)
+	Message *InboundMessage
+
+	// Receipt báo nhận/đã xem (delivery, read)
+	Receipt *Receipt
+
+	// Reaction reaction trên tin nhắn
+	Reaction *Reaction
+
+	// Referral nguồn giới thiệu
+	Referral *Referral
+
+	// OwnEcho echo của tin nhắn do hệ thống gửi qua API (đã lưu khi gửi)
+	OwnEcho bool
+}
+
+// Receipt báo nhận/đã xem của khách
+// Các tin nhắn trong MessageIDs hoặc gửi trước Watermark được tính là đã nhận/đã xem
+// Watermark zero = không có watermark
+type Receipt struct {
+	MessageIDs []string
+	Watermark  time.Time
+}
+
+// Reaction khách thả hoặc bỏ reaction
+type Reaction struct {
+	// MessageID ID tin nhắn trên channel được reaction
+	MessageID string
+
+	// Emoji ký tự emoji (VD: "❤")
+	Emoji string
+
+	// Reaction tên reaction (VD: "love", "like")
+	Reaction string
+
+	// Removed khách bỏ reaction
+	Removed bool
+}
+
+// Referral thông tin nguồn giới thiệu
+type Referral struct {
+	// Ref tham số ref của link/quảng cáo
+	Ref string
+
+	// Source nguồn (SHORTLINK, ADS, CUSTOMER_CHAT_PLUGIN, ...)
+	Source string
+
+	// Type loại referral (OPEN_THREAD)
+	Type string
+
+	// AdID ID quảng cáo (nếu từ quảng cáo)
+	AdID string
+}
+
+// EventNormalizer capability tùy chọn: tách webhook thành các sự kiện có kiểu
+// Channel không implement thì chỉ xử lý tin nhắn qua Normalizer
+type EventNormalizer interface {
+	// NormalizeEvents chuyển đổi raw payload thành danh sách sự kiện
+	NormalizeEvents(ctx context.Context, channelAccountID uuid.UUID, payload map[string]interface{}) ([]*InboundEvent, error)
+}
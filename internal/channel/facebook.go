@@ -8,7 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"strings"
 	"time"
 
@@ -38,6 +41,45 @@ func (c *FacebookChannel) Type() string {
 	return "facebook"
 }
 
+// Message tag của Messenger: gửi tin ngoài cửa sổ 24h sau tin nhắn cuối của khách
+// Không được dùng cho nội dung quảng cáo/khuyến mãi
+const (
+	// FBTagConfirmedEventUpdate nhắc/cập nhật sự kiện khách đã đăng ký
+	FBTagConfirmedEventUpdate = "CONFIRMED_EVENT_UPDATE"
+
+	// FBTagPostPurchaseUpdate cập nhật đơn hàng khách đã mua
+	FBTagPostPurchaseUpdate = "POST_PURCHASE_UPDATE"
+
+	// FBTagAccountUpdate thay đổi về tài khoản/hồ sơ của khách
+	FBTagAccountUpdate = "ACCOUNT_UPDATE"
+
+	// FBTagHumanAgent agent trả lời trong vòng 7 ngày sau tin nhắn của khách
+	FBTagHumanAgent = "HUMAN_AGENT"
+)
+
+// Capabilities giới hạn của Messenger Send API
+// Adapter chưa gửi button template nên nút bấm được chuyển thành text
+func (c *FacebookChannel) Capabilities() Capabilities {
+	return Capabilities{
+		MaxTextLength:        2000,
+		QuickReplies:         true,
+		MaxQuickReplies:      13,
+		MaxQuickReplyTitle:   20,
+		AttachmentTypes:      []string{"image", "video", "audio", "file"},
+		SenderActions:        true,
+		MessagingWindowHours: 24,
+		MessageTags: []string{
+			FBTagConfirmedEventUpdate,
+			FBTagPostPurchaseUpdate,
+			FBTagAccountUpdate,
+			FBTagHumanAgent,
+		},
+		MessageTagMaxAgeHours: map[string]int{
+			FBTagHumanAgent: 7 * 24,
+		},
+	}
+}
+
 // ===========================================================================
 // Webhook Payload Structures
 // ===========================================================================
@@ -56,12 +98,17 @@ type FBWebhookEntry struct {
 }
 
 // FBMessagingEvent một sự kiện messaging
+// Mỗi event chỉ có một trong các field message, postback, delivery, read, reaction, referral
 type FBMessagingEvent struct {
-	Sender    FBUser       `json:"sender"`
-	Recipient FBUser       `json:"recipient"`
-	Timestamp int64        `json:"timestamp"`
-	Message   *FBMessage   `json:"message,omitempty"`
-	Postback  *FBPostback  `json:"postback,omitempty"`
+	Sender    FBUser      `json:"sender"`
+	Recipient FBUser      `json:"recipient"`
+	Timestamp int64       `json:"timestamp"`
+	Message   *FBMessage  `json:"message,omitempty"`
+	Postback  *FBPostback `json:"postback,omitempty"`
+	Delivery  *FBDelivery `json:"delivery,omitempty"`
+	Read      *FBRead     `json:"read,omitempty"`
+	Reaction  *FBReaction `json:"reaction,omitempty"`
+	Referral  *FBReferral `json:"referral,omitempty"`
 }
 
 // FBUser thông tin user
@@ -69,12 +116,18 @@ type FBUser struct {
 	ID string `json:"id"`
 }
 
-// FBMessage tin nhắn từ user
+// FBMessage tin nhắn từ user (hoặc echo tin nhắn page đã gửi)
 type FBMessage struct {
-	MID         string          `json:"mid"`
-	Text        string          `json:"text"`
-	Attachments []FBAttachment  `json:"attachments,omitempty"`
-	QuickReply  *FBQuickReply   `json:"quick_reply,omitempty"`
+	MID         string         `json:"mid"`
+	Text        string         `json:"text"`
+	Attachments []FBAttachment `json:"attachments,omitempty"`
+	QuickReply  *FBQuickReply  `json:"quick_reply,omitempty"`
+	Referral    *FBReferral    `json:"referral,omitempty"`
+
+	// IsEcho tin nhắn do page gửi, AppID là app đã gửi (0 = Page inbox)
+	IsEcho   bool   `json:"is_echo,omitempty"`
+	AppID    int64  `json:"app_id,omitempty"`
+	Metadata string `json:"metadata,omitempty"`
 }
 
 // FBAttachment file đính kèm  
@@ -97,10 +150,43 @@ type FBQuickReply struct {
 
 // FBPostback postback button được bấm
 type FBPostback struct {
-	Title   string `json:"title"`
-	Payload string `json:"payload"`
+	MID      string      `json:"mid,omitempty"`
+	Title    string      `json:"title"`
+	Payload  string      `json:"payload"`
+	Referral *FBReferral `json:"referral,omitempty"`
 }
 
+// FBDelivery báo khách đã nhận tin nhắn
+type FBDelivery struct {
+	MIDs      []string `json:"mids,omitempty"`
+	Watermark int64    `json:"watermark"`
+}
+
+// FBRead báo khách đã xem mọi tin nhắn gửi trước watermark
+type FBRead struct {
+	Watermark int64 `json:"watermark"`
+}
+
+// FBReaction reaction của khách trên một tin nhắn
+type FBReaction struct {
+	MID      string `json:"mid"`
+	Action   string `json:"action"` // react, unreact
+	Emoji    string `json:"emoji,omitempty"`
+	Reaction string `json:"reaction,omitempty"`
+}
+
+// FBReferral nguồn giới thiệu (m.me link, quảng cáo, plugin chat)
+type FBReferral struct {
+	Ref    string `json:"ref,omitempty"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	AdID   string `json:"ad_id,omitempty"`
+}
+
+// fbOutboundMetadata đánh dấu tin nhắn do hệ thống gửi qua Send API
+// Facebook trả lại trong echo để phân biệt với tin nhắn gửi từ Page inbox
+const fbOutboundMetadata = "chatbox"
+
 // FBUserProfile thông tin profile user từ Graph API
 type FBUserProfile struct {
 	ID         string `json:"id"`
@@ -159,7 +245,24 @@ func (c *FacebookChannel) GetUserProfile(ctx context.Context, userID, accessToke
 // ===========================================================================
 
 // Normalize chuyển đổi FB webhook payload thành InboundMessage chuẩn
+// Trả về tin nhắn đầu tiên của khách, bỏ qua các sự kiện khác
 func (c *FacebookChannel) Normalize(ctx context.Context, channelAccountID uuid.UUID, payload map[string]interface{}) (*InboundMessage, error) {
+	events, err := c.NormalizeEvents(ctx, channelAccountID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.Type == EventMessage {
+			return event.Message, nil
+		}
+	}
+	return nil, fmt.Errorf("no message events")
+}
+
+// NormalizeEvents chuyển đổi FB webhook payload thành các sự kiện có kiểu
+// Một webhook có thể gom nhiều entry và nhiều messaging event
+func (c *FacebookChannel) NormalizeEvents(ctx context.Context, channelAccountID uuid.UUID, payload map[string]interface{}) ([]*InboundEvent, error) {
 	// Parse payload
 	jsonBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -176,20 +279,89 @@ func (c *FacebookChannel) Normalize(ctx context.Context, channelAccountID uuid.U
 		return nil, fmt.Errorf("invalid object type: %s", fbPayload.Object)
 	}
 
-	if len(fbPayload.Entry) == 0 || len(fbPayload.Entry[0].Messaging) == 0 {
+	events := make([]*InboundEvent, 0, len(fbPayload.Entry))
+	for _, entry := range fbPayload.Entry {
+		for i := range entry.Messaging {
+			event := c.normalizeEvent(&entry.Messaging[i], payload)
+			if event == nil {
+				c.logger.Debug("unsupported fb messaging event", zap.String("page_id", entry.ID))
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
 		return nil, fmt.Errorf("no messaging events")
 	}
 
-	// Get first messaging event
-	event := fbPayload.Entry[0].Messaging[0]
-	
-	// Build InboundMessage
+	c.logger.Info("normalized fb webhook", zap.Int("events", len(events)))
+
+	return events, nil
+}
+
+// normalizeEvent chuyển một messaging event thành InboundEvent
+// Trả về nil nếu loại event chưa được hỗ trợ
+func (c *FacebookChannel) normalizeEvent(event *FBMessagingEvent, payload map[string]interface{}) *InboundEvent {
+	result := &InboundEvent{
+		SenderID:    event.Sender.ID,
+		RecipientID: event.Recipient.ID,
+		Timestamp:   time.UnixMilli(event.Timestamp),
+	}
+
+	switch {
+	case event.Message != nil && event.Message.IsEcho:
+		// Echo: page là người gửi, khách là người nhận
+		result.Type = EventEcho
+		result.SenderID = event.Recipient.ID
+		result.RecipientID = event.Sender.ID
+		result.Message = c.normalizeMessage(event, payload)
+		result.Message.SenderID = result.SenderID
+		result.Message.RecipientID = result.RecipientID
+		result.OwnEcho = event.Message.Metadata == fbOutboundMetadata
+
+	case event.Message != nil || event.Postback != nil:
+		result.Type = EventMessage
+		result.Message = c.normalizeMessage(event, payload)
+
+	case event.Delivery != nil:
+		result.Type = EventDelivery
+		result.Receipt = &Receipt{
+			MessageIDs: event.Delivery.MIDs,
+			Watermark:  fbWatermark(event.Delivery.Watermark),
+		}
+
+	case event.Read != nil:
+		result.Type = EventRead
+		result.Receipt = &Receipt{Watermark: fbWatermark(event.Read.Watermark)}
+
+	case event.Reaction != nil:
+		result.Type = EventReaction
+		result.Reaction = &Reaction{
+			MessageID: event.Reaction.MID,
+			Emoji:     event.Reaction.Emoji,
+			Reaction:  event.Reaction.Reaction,
+			Removed:   event.Reaction.Action == "unreact",
+		}
+
+	case event.Referral != nil:
+		result.Type = EventReferral
+		result.Referral = event.Referral.toReferral()
+
+	default:
+		return nil
+	}
+
+	return result
+}
+
+// normalizeMessage chuyển message/postback thành InboundMessage chuẩn
+func (c *FacebookChannel) normalizeMessage(event *FBMessagingEvent, payload map[string]interface{}) *InboundMessage {
 	inbound := &InboundMessage{
-		ChannelType:      "facebook",
-		SenderID:         event.Sender.ID,
-		RecipientID:      event.Recipient.ID,
-		Timestamp:        time.UnixMilli(event.Timestamp),
-		RawPayload:       payload,
+		ChannelType: "facebook",
+		SenderID:    event.Sender.ID,
+		RecipientID: event.Recipient.ID,
+		Timestamp:   time.UnixMilli(event.Timestamp),
+		RawPayload:  payload,
 	}
 
 	// Handle message
@@ -197,6 +369,7 @@ func (c *FacebookChannel) Normalize(ctx context.Context, channelAccountID uuid.U
 		inbound.ChannelMessageID = event.Message.MID
 		inbound.Content = event.Message.Text
 		inbound.ContentType = "text"
+		inbound.Referral = event.Message.Referral.toReferral()
 
 		// Handle attachments
 		for _, att := range event.Message.Attachments {
@@ -205,7 +378,7 @@ func (c *FacebookChannel) Normalize(ctx context.Context, channelAccountID uuid.U
 				URL:  att.Payload.URL,
 			}
 			inbound.Attachments = append(inbound.Attachments, attData)
-			
+
 			// Update content type
 			if inbound.ContentType == "text" && att.Type != "" {
 				inbound.ContentType = att.Type
@@ -222,15 +395,35 @@ func (c *FacebookChannel) Normalize(ctx context.Context, channelAccountID uuid.U
 	if event.Postback != nil {
 		inbound.Content = event.Postback.Payload
 		inbound.ContentType = "postback"
-		inbound.ChannelMessageID = fmt.Sprintf("postback_%d", event.Timestamp)
+		inbound.ChannelMessageID = event.Postback.MID
+		if inbound.ChannelMessageID == "" {
+			inbound.ChannelMessageID = fmt.Sprintf("postback_%d", event.Timestamp)
+		}
+		inbound.Referral = event.Postback.Referral.toReferral()
 	}
 
-	c.logger.Info("normalized fb message",
-		zap.String("sender_id", inbound.SenderID),
-		zap.String("content_type", inbound.ContentType),
-	)
+	return inbound
+}
 
-	return inbound, nil
+// fbWatermark chuyển watermark (ms) sang time, 0 = không có watermark
+func fbWatermark(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// toReferral chuyển referral của FB sang dạng chuẩn (nil-safe)
+func (r *FBReferral) toReferral() *Referral {
+	if r == nil {
+		return nil
+	}
+	return &Referral{
+		Ref:    r.Ref,
+		Source: r.Source,
+		Type:   r.Type,
+		AdID:   r.AdID,
+	}
 }
 
 // ===========================================================================
@@ -242,6 +435,7 @@ type FBSendRequest struct {
 	Recipient    FBUser         `json:"recipient"`
 	Message      FBSendMessage  `json:"message"`
 	MessagingType string        `json:"messaging_type"`
+	Tag          string         `json:"tag,omitempty"`
 }
 
 // FBSendMessage tin nhắn gửi đi
@@ -249,14 +443,20 @@ type FBSendMessage struct {
 	Text         string           `json:"text,omitempty"`
 	Attachment   *FBSendAttachment `json:"attachment,omitempty"`
 	QuickReplies []FBSendQR       `json:"quick_replies,omitempty"`
+	Metadata     string           `json:"metadata,omitempty"`
 }
 
 // FBSendAttachment attachment gửi đi
 type FBSendAttachment struct {
-	Type    string `json:"type"`
-	Payload struct {
-		URL string `json:"url"`
-	} `json:"payload"`
+	Type    string                  `json:"type"`
+	Payload FBSendAttachmentPayload `json:"payload"`
+}
+
+// FBSendAttachmentPayload nguồn file: attachment_id đã upload hoặc URL để FB tự tải
+type FBSendAttachmentPayload struct {
+	URL          string `json:"url,omitempty"`
+	AttachmentID string `json:"attachment_id,omitempty"`
+	IsReusable   bool   `json:"is_reusable,omitempty"`
 }
 
 // FBSendQR quick reply gửi đi
@@ -267,39 +467,87 @@ type FBSendQR struct {
 }
 
 // Send gửi tin nhắn qua Facebook Messenger
+// Mỗi tin nhắn Messenger chỉ chứa text hoặc một file nên text được gửi trước rồi đến từng file,
+// quick replies gắn vào tin nhắn cuối cùng. Kết quả trả về là ID của tin nhắn cuối
 func (c *FacebookChannel) Send(ctx context.Context, msg *OutboundMessage, credentials map[string]string) (*SendResult, error) {
 	accessToken := credentials["page_access_token"]
 	if accessToken == "" {
 		return &SendResult{Success: false, Error: fmt.Errorf("missing page_access_token")}, nil
 	}
 
-	// Build request
-	fbReq := FBSendRequest{
-		Recipient:     FBUser{ID: msg.RecipientID},
-		MessagingType: "RESPONSE",
+	messages := make([]FBSendMessage, 0, 1+len(msg.Attachments))
+	if msg.Content != "" || len(msg.Attachments) == 0 {
+		messages = append(messages, FBSendMessage{Text: msg.Content})
 	}
-
-	// Set message content
-	if msg.Content != "" {
-		fbReq.Message.Text = msg.Content
+	for _, att := range msg.Attachments {
+		attachment, err := c.buildAttachment(ctx, att, accessToken)
+		if err != nil {
+			return &SendResult{Success: false, Error: err}, nil
+		}
+		messages = append(messages, FBSendMessage{Attachment: attachment})
 	}
 
 	// Add quick replies
+	last := &messages[len(messages)-1]
 	for _, qr := range msg.QuickReplies {
-		fbReq.Message.QuickReplies = append(fbReq.Message.QuickReplies, FBSendQR{
+		last.QuickReplies = append(last.QuickReplies, FBSendQR{
 			ContentType: "text",
 			Title:       qr.Title,
 			Payload:     qr.Payload,
 		})
 	}
 
-	// Send to Graph API
+	// Có message tag thì gửi dạng MESSAGE_TAG (được phép ngoài cửa sổ 24h)
+	messagingType := "RESPONSE"
+	tag := msg.MessageTag()
+	if tag != "" {
+		messagingType = "MESSAGE_TAG"
+	}
+
+	var messageID string
+	for _, message := range messages {
+		message.Metadata = fbOutboundMetadata
+		id, err := c.postMessage(ctx, accessToken, FBSendRequest{
+			Recipient:     FBUser{ID: msg.RecipientID},
+			Message:       message,
+			MessagingType: messagingType,
+			Tag:           tag,
+		})
+		if err != nil {
+			return &SendResult{Success: false, Error: err}, nil
+		}
+		messageID = id
+	}
+
+	c.logger.Info("fb message sent",
+		zap.String("recipient", msg.RecipientID),
+		zap.String("message_id", messageID),
+		zap.Int("parts", len(messages)),
+	)
+
+	return &SendResult{
+		Success:          true,
+		ChannelMessageID: messageID,
+	}, nil
+}
+
+// postMessage gọi Send API cho một tin nhắn, trả về message ID của Facebook
+func (c *FacebookChannel) postMessage(ctx context.Context, accessToken string, fbReq FBSendRequest) (string, error) {
+	jsonBody, err := json.Marshal(fbReq)
+	if err != nil {
+		return "", err
+	}
+
 	url := fmt.Sprintf("https://graph.facebook.com/v18.0/me/messages?access_token=%s", accessToken)
-	
-	jsonBody, _ := json.Marshal(fbReq)
-	resp, err := http.Post(url, "application/json", strings.NewReader(string(jsonBody)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return &SendResult{Success: false, Error: err}, nil
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -310,7 +558,7 @@ func (c *FacebookChannel) Send(ctx context.Context, msg *OutboundMessage, creden
 			zap.Int("status", resp.StatusCode),
 			zap.String("body", string(body)),
 		)
-		return &SendResult{Success: false, Error: fmt.Errorf("fb api error: %s", string(body))}, nil
+		return "", fmt.Errorf("fb api error: %s", string(body))
 	}
 
 	// Parse response
@@ -318,16 +566,163 @@ func (c *FacebookChannel) Send(ctx context.Context, msg *OutboundMessage, creden
 		MessageID string `json:"message_id"`
 	}
 	json.Unmarshal(body, &fbResp)
+	return fbResp.MessageID, nil
+}
 
-	c.logger.Info("fb message sent",
-		zap.String("recipient", msg.RecipientID),
-		zap.String("message_id", fbResp.MessageID),
-	)
+// buildAttachment chuẩn bị attachment gửi đi
+// File có nội dung (Open) được upload lên Attachment Upload API, còn lại gửi theo URL
+func (c *FacebookChannel) buildAttachment(ctx context.Context, att AttachmentData, accessToken string) (*FBSendAttachment, error) {
+	attachment := &FBSendAttachment{Type: fbAttachmentType(att.Type)}
+	if att.Open == nil {
+		attachment.Payload = FBSendAttachmentPayload{URL: att.URL, IsReusable: true}
+		return attachment, nil
+	}
 
-	return &SendResult{
-		Success:          true,
-		ChannelMessageID: fbResp.MessageID,
-	}, nil
+	attachmentID, err := c.uploadAttachment(ctx, attachment.Type, att, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("upload %s: %w", att.Name, err)
+	}
+	attachment.Payload = FBSendAttachmentPayload{AttachmentID: attachmentID}
+	return attachment, nil
+}
+
+// uploadAttachment upload file lên /me/message_attachments, trả về attachment_id
+// Body multipart được stream trực tiếp từ storage, không đọc cả file vào bộ nhớ
+func (c *FacebookChannel) uploadAttachment(ctx context.Context, fbType string, att AttachmentData, accessToken string) (string, error) {
+	file, err := att.Open(ctx)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+	defer file.Close()
+
+	message, err := json.Marshal(map[string]interface{}{
+		"attachment": map[string]interface{}{
+			"type":    fbType,
+			"payload": map[string]bool{"is_reusable": true},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeAttachmentForm(form, message, att, file))
+	}()
+
+	url := fmt.Sprintf("https://graph.facebook.com/v18.0/me/message_attachments?access_token=%s", accessToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pr)
+	if err != nil {
+		pr.Close()
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		c.logger.Error("fb attachment upload failed",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(body)),
+		)
+		return "", fmt.Errorf("fb api error: %s", string(body))
+	}
+
+	var fbResp struct {
+		AttachmentID string `json:"attachment_id"`
+	}
+	if err := json.Unmarshal(body, &fbResp); err != nil || fbResp.AttachmentID == "" {
+		return "", fmt.Errorf("fb api error: unexpected response %s", string(body))
+	}
+	return fbResp.AttachmentID, nil
+}
+
+// writeAttachmentForm ghi các field message và filedata của request upload
+func writeAttachmentForm(form *multipart.Writer, message []byte, att AttachmentData, file io.Reader) error {
+	if err := form.WriteField("message", string(message)); err != nil {
+		return err
+	}
+
+	name := att.Name
+	if name == "" {
+		name = "attachment"
+	}
+	contentType := att.MimeType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     "filedata",
+		"filename": name,
+	}))
+	header.Set("Content-Type", contentType)
+
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	return form.Close()
+}
+
+// fbAttachmentType loại attachment của Messenger (image, video, audio, file)
+func fbAttachmentType(attachmentType string) string {
+	switch attachmentType {
+	case "image", "video", "audio":
+		return attachmentType
+	default:
+		return "file"
+	}
+}
+
+// FBSenderActionRequest request gửi sender action
+type FBSenderActionRequest struct {
+	Recipient    FBUser `json:"recipient"`
+	SenderAction string `json:"sender_action"`
+}
+
+// SendAction gửi sender action (mark_seen, ...) qua Facebook Messenger
+func (c *FacebookChannel) SendAction(ctx context.Context, recipientID string, action SenderAction, credentials map[string]string) error {
+	accessToken := credentials["page_access_token"]
+	if accessToken == "" {
+		return fmt.Errorf("missing page_access_token")
+	}
+
+	jsonBody, err := json.Marshal(FBSenderActionRequest{
+		Recipient:    FBUser{ID: recipientID},
+		SenderAction: string(action),
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://graph.facebook.com/v18.0/me/messages?access_token=%s", accessToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("fb api error: %s", string(body))
+	}
+	return nil
 }
 
 // ===========================================================================
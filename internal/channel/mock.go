@@ -20,6 +20,16 @@ type MockChannel struct {
 
 	// sentMessages lưu các tin nhắn đã "gửi" (để testing)
 	sentMessages []*OutboundMessage
+
+	// sentActions lưu các sender action đã "gửi" (để testing)
+	sentActions []MockSentAction
+}
+
+// MockSentAction một sender action mock channel đã nhận
+type MockSentAction struct {
+	RecipientID string
+	Action      SenderAction
+	SentAt      time.Time
 }
 
 // NewMockChannel tạo một MockChannel mới
@@ -35,6 +45,16 @@ func (m *MockChannel) Type() string {
 	return "mock"
 }
 
+// Capabilities mock channel nhận mọi loại tin nhắn, không giới hạn
+func (m *MockChannel) Capabilities() Capabilities {
+	return Capabilities{
+		QuickReplies:    true,
+		Buttons:         true,
+		AttachmentTypes: []string{"image", "video", "audio", "file"},
+		SenderActions:   true,
+	}
+}
+
 // ===========================================================================
 // Normalizer implementation
 // ===========================================================================
@@ -165,6 +185,30 @@ func (m *MockChannel) Send(
 	}, nil
 }
 
+// SendAction "gửi" sender action (typing_on/typing_off/mark_seen), chỉ log và lưu lại
+func (m *MockChannel) SendAction(
+	ctx context.Context,
+	recipientID string,
+	action SenderAction,
+	credentials map[string]string,
+) error {
+	if recipientID == "" {
+		return fmt.Errorf("recipient_id không được để trống")
+	}
+
+	m.logger.Info("mock channel: đã gửi sender action",
+		zap.String("recipient_id", recipientID),
+		zap.String("action", string(action)),
+	)
+
+	m.sentActions = append(m.sentActions, MockSentAction{
+		RecipientID: recipientID,
+		Action:      action,
+		SentAt:      time.Now(),
+	})
+	return nil
+}
+
 // ===========================================================================
 // SignatureVerifier implementation
 // ===========================================================================
@@ -188,6 +232,12 @@ func (m *MockChannel) GetSentMessages() []*OutboundMessage {
 // ClearSentMessages xóa danh sách tin nhắn đã gửi
 func (m *MockChannel) ClearSentMessages() {
 	m.sentMessages = make([]*OutboundMessage, 0)
+	m.sentActions = nil
+}
+
+// GetSentActions trả về danh sách sender action đã gửi (để testing)
+func (m *MockChannel) GetSentActions() []MockSentAction {
+	return m.sentActions
 }
 
 // GetLastSentMessage trả về tin nhắn cuối cùng đã gửi
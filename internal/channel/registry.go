@@ -2,6 +2,7 @@ package channel
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -62,6 +63,31 @@ func (r *Registry) GetAll() []string {
 	return types
 }
 
+// ChannelDescriptor mô tả một channel đã đăng ký
+type ChannelDescriptor struct {
+	Type         string       `json:"type"`
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+// Describe trả về các channel đã đăng ký kèm capabilities, sắp xếp theo type
+func (r *Registry) Describe() []ChannelDescriptor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	descriptors := make([]ChannelDescriptor, 0, len(r.channels))
+	for t, ch := range r.channels {
+		descriptors = append(descriptors, ChannelDescriptor{
+			Type:         t,
+			Capabilities: ch.Capabilities(),
+		})
+	}
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].Type < descriptors[j].Type
+	})
+
+	return descriptors
+}
+
 // Has kiểm tra xem channel type đã được đăng ký chưa
 func (r *Registry) Has(channelType string) bool {
 	r.mu.RLock()
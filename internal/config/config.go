@@ -18,6 +18,17 @@ type Config struct {
 	Centrifugo CentrifugoConfig `mapstructure:"centrifugo"`
 	JWT        JWTConfig        `mapstructure:"jwt"`
 	Logging    LoggingConfig    `mapstructure:"logging"`
+	Presence   PresenceConfig   `mapstructure:"presence"`
+	SLA        SLAConfig        `mapstructure:"sla"`
+	AutoClose  AutoCloseConfig  `mapstructure:"auto_close"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Media      MediaConfig      `mapstructure:"media"`
+	Bulk       BulkConfig       `mapstructure:"bulk"`
+	Snooze     SnoozeConfig     `mapstructure:"snooze"`
+	Schedule   ScheduleConfig   `mapstructure:"schedule"`
+	Campaign   CampaignConfig   `mapstructure:"campaign"`
+	Window     WindowConfig     `mapstructure:"messaging_window"`
+	ContactJob ContactJobConfig `mapstructure:"contact_jobs"`
 }
 
 type AppConfig struct {
@@ -67,6 +78,139 @@ type LoggingConfig struct {
 	Format string `mapstructure:"format"`
 }
 
+// PresenceConfig cấu hình tự động đổi trạng thái agent khi không hoạt động
+type PresenceConfig struct {
+	// IdleTimeout không có heartbeat quá thời gian này thì online → away
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	// OfflineTimeout không có heartbeat quá thời gian này thì → offline
+	OfflineTimeout time.Duration `mapstructure:"offline_timeout"`
+	// SweepInterval chu kỳ quét agent idle
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+}
+
+// SLAConfig cấu hình evaluator SLA chạy nền
+type SLAConfig struct {
+	// EvaluateInterval chu kỳ đánh giá SLA các conversation đang mở
+	EvaluateInterval time.Duration `mapstructure:"evaluate_interval"`
+}
+
+// AutoCloseConfig cấu hình job tự động đóng hội thoại không hoạt động
+// Số giờ không hoạt động cấu hình theo từng workspace
+type AutoCloseConfig struct {
+	// SweepInterval chu kỳ quét conversation không hoạt động
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+	// BatchSize số conversation tối đa đóng mỗi workspace trong một lần quét
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// StorageConfig cấu hình nơi lưu file (ảnh, video, tài liệu của tin nhắn)
+type StorageConfig struct {
+	// Driver loại storage: local (thư mục trên máy chủ) hoặc s3 (S3/MinIO/R2)
+	Driver string `mapstructure:"driver"`
+	// LocalDir thư mục gốc khi dùng driver local
+	LocalDir string `mapstructure:"local_dir"`
+	// S3 cấu hình khi dùng driver s3
+	S3 S3Config `mapstructure:"s3"`
+}
+
+// S3Config cấu hình storage tương thích S3
+type S3Config struct {
+	// Endpoint URL của dịch vụ (VD: http://localhost:9000), rỗng = AWS S3 theo region
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	// PathStyle dùng URL dạng endpoint/bucket/key (bắt buộc với MinIO)
+	PathStyle bool `mapstructure:"path_style"`
+}
+
+// MediaConfig cấu hình tải file đính kèm inbound về storage
+type MediaConfig struct {
+	// Workers số goroutine tải file song song
+	Workers int `mapstructure:"workers"`
+	// MaxDownloadBytes dung lượng tối đa một file được tải về
+	MaxDownloadBytes int64 `mapstructure:"max_download_bytes"`
+	// DownloadTimeout thời gian tối đa tải một file
+	DownloadTimeout time.Duration `mapstructure:"download_timeout"`
+	// ThumbnailSize cạnh dài nhất của ảnh thumbnail (pixel)
+	ThumbnailSize int `mapstructure:"thumbnail_size"`
+	// MaxAttempts số lần thử tải tối đa trước khi bỏ cuộc
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryInterval chu kỳ quét file tải lỗi để thử lại
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
+	// MaxUploadBytes dung lượng tối đa một file agent upload
+	MaxUploadBytes int64 `mapstructure:"max_upload_bytes"`
+	// MaxUploadFiles số file tối đa trong một tin nhắn
+	MaxUploadFiles int `mapstructure:"max_upload_files"`
+	// AllowedUploadTypes MIME type được phép upload ("image/*" = mọi loại ảnh)
+	AllowedUploadTypes []string `mapstructure:"allowed_upload_types"`
+}
+
+// BulkConfig cấu hình job thao tác hàng loạt trên hội thoại
+type BulkConfig struct {
+	// PollInterval chu kỳ kiểm tra job mới
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// BatchSize số conversation xử lý trong một lô (lưu tiến độ sau mỗi lô)
+	BatchSize int `mapstructure:"batch_size"`
+	// MaxConversations số conversation tối đa trong một job
+	MaxConversations int `mapstructure:"max_conversations"`
+}
+
+// SnoozeConfig cấu hình job mở lại hội thoại tạm ẩn
+type SnoozeConfig struct {
+	// SweepInterval chu kỳ quét hội thoại hết hạn tạm ẩn hoặc khách đã nhắn lại
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+	// BatchSize số hội thoại tối đa mở lại trong một lần quét
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// ScheduleConfig cấu hình job gửi tin nhắn hẹn giờ
+type ScheduleConfig struct {
+	// SweepInterval chu kỳ quét tin nhắn đến giờ gửi
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+	// BatchSize số tin nhắn tối đa gửi trong một lần quét
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// CampaignConfig cấu hình gửi chiến dịch
+type CampaignConfig struct {
+	// PollInterval chu kỳ gửi tiếp các chiến dịch đang chạy
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// DefaultRatePerMinute số tin mỗi phút trên mỗi kênh khi chiến dịch không đặt
+	DefaultRatePerMinute int `mapstructure:"default_rate_per_minute"`
+	// MaxRatePerMinute số tin mỗi phút tối đa được đặt cho một kênh
+	MaxRatePerMinute int `mapstructure:"max_rate_per_minute"`
+	// MaxRecipients số người nhận tối đa của một chiến dịch
+	MaxRecipients int `mapstructure:"max_recipients"`
+}
+
+// ContactJobConfig cấu hình job nhập/xuất danh sách khách (CSV)
+type ContactJobConfig struct {
+	// PollInterval chu kỳ kiểm tra job mới
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// BatchSize số dòng/khách xử lý trong một lô (lưu tiến độ sau mỗi lô)
+	BatchSize int `mapstructure:"batch_size"`
+	// MaxRows số dòng tối đa của file nhập và số khách tối đa của một lần xuất
+	MaxRows int `mapstructure:"max_rows"`
+	// MaxUploadBytes dung lượng tối đa của file nhập
+	MaxUploadBytes int64 `mapstructure:"max_upload_bytes"`
+}
+
+// Chính sách khi agent trả lời ngoài cửa sổ nhắn tin của channel
+const (
+	// WindowPolicyBlock từ chối gửi nếu không chọn message tag
+	WindowPolicyBlock = "block"
+	// WindowPolicyWarn vẫn gửi, đánh dấu tin nhắn ngoài cửa sổ
+	WindowPolicyWarn = "warn"
+)
+
+// WindowConfig cấu hình cửa sổ nhắn tin (VD: 24 giờ của Facebook)
+type WindowConfig struct {
+	// Policy block hoặc warn khi agent trả lời ngoài cửa sổ không kèm message tag
+	Policy string `mapstructure:"policy"`
+}
+
 // IsProduction checks if app is in production mode
 func (c *AppConfig) IsProduction() bool {
 	return c.Env == "production"
@@ -132,6 +276,69 @@ func Load(configPath string) (*Config, error) {
 			Level:  getEnvOrDefault("LOG_LEVEL", v.GetString("logging.level")),
 			Format: getEnvOrDefault("LOG_FORMAT", v.GetString("logging.format")),
 		},
+		Presence: PresenceConfig{
+			IdleTimeout:    v.GetDuration("presence.idle_timeout"),
+			OfflineTimeout: v.GetDuration("presence.offline_timeout"),
+			SweepInterval:  v.GetDuration("presence.sweep_interval"),
+		},
+		SLA: SLAConfig{
+			EvaluateInterval: v.GetDuration("sla.evaluate_interval"),
+		},
+		AutoClose: AutoCloseConfig{
+			SweepInterval: v.GetDuration("auto_close.sweep_interval"),
+			BatchSize:     v.GetInt("auto_close.batch_size"),
+		},
+		Storage: StorageConfig{
+			Driver:   getEnvOrDefault("STORAGE_DRIVER", v.GetString("storage.driver")),
+			LocalDir: getEnvOrDefault("STORAGE_LOCAL_DIR", v.GetString("storage.local_dir")),
+			S3: S3Config{
+				Endpoint:  getEnvOrDefault("S3_ENDPOINT", v.GetString("storage.s3.endpoint")),
+				Region:    getEnvOrDefault("S3_REGION", v.GetString("storage.s3.region")),
+				Bucket:    getEnvOrDefault("S3_BUCKET", v.GetString("storage.s3.bucket")),
+				AccessKey: getEnvOrDefault("S3_ACCESS_KEY", v.GetString("storage.s3.access_key")),
+				SecretKey: getEnvOrDefault("S3_SECRET_KEY", v.GetString("storage.s3.secret_key")),
+				PathStyle: v.GetBool("storage.s3.path_style"),
+			},
+		},
+		Media: MediaConfig{
+			Workers:            v.GetInt("media.workers"),
+			MaxDownloadBytes:   v.GetInt64("media.max_download_bytes"),
+			DownloadTimeout:    v.GetDuration("media.download_timeout"),
+			ThumbnailSize:      v.GetInt("media.thumbnail_size"),
+			MaxAttempts:        v.GetInt("media.max_attempts"),
+			RetryInterval:      v.GetDuration("media.retry_interval"),
+			MaxUploadBytes:     v.GetInt64("media.max_upload_bytes"),
+			MaxUploadFiles:     v.GetInt("media.max_upload_files"),
+			AllowedUploadTypes: v.GetStringSlice("media.allowed_upload_types"),
+		},
+		Bulk: BulkConfig{
+			PollInterval:     v.GetDuration("bulk.poll_interval"),
+			BatchSize:        v.GetInt("bulk.batch_size"),
+			MaxConversations: v.GetInt("bulk.max_conversations"),
+		},
+		Snooze: SnoozeConfig{
+			SweepInterval: v.GetDuration("snooze.sweep_interval"),
+			BatchSize:     v.GetInt("snooze.batch_size"),
+		},
+		Schedule: ScheduleConfig{
+			SweepInterval: v.GetDuration("schedule.sweep_interval"),
+			BatchSize:     v.GetInt("schedule.batch_size"),
+		},
+		Campaign: CampaignConfig{
+			PollInterval:         v.GetDuration("campaign.poll_interval"),
+			DefaultRatePerMinute: v.GetInt("campaign.default_rate_per_minute"),
+			MaxRatePerMinute:     v.GetInt("campaign.max_rate_per_minute"),
+			MaxRecipients:        v.GetInt("campaign.max_recipients"),
+		},
+		Window: WindowConfig{
+			Policy: v.GetString("messaging_window.policy"),
+		},
+		ContactJob: ContactJobConfig{
+			PollInterval:   v.GetDuration("contact_jobs.poll_interval"),
+			BatchSize:      v.GetInt("contact_jobs.batch_size"),
+			MaxRows:        v.GetInt("contact_jobs.max_rows"),
+			MaxUploadBytes: v.GetInt64("contact_jobs.max_upload_bytes"),
+		},
 	}
 
 	// Set defaults
@@ -156,6 +363,122 @@ func Load(configPath string) (*Config, error) {
 	if cfg.JWT.RefreshDuration == 0 {
 		cfg.JWT.RefreshDuration = 168 * time.Hour
 	}
+	if cfg.Presence.IdleTimeout == 0 {
+		cfg.Presence.IdleTimeout = 5 * time.Minute
+	}
+	if cfg.Presence.OfflineTimeout == 0 {
+		cfg.Presence.OfflineTimeout = 30 * time.Minute
+	}
+	if cfg.Presence.SweepInterval == 0 {
+		cfg.Presence.SweepInterval = time.Minute
+	}
+	if cfg.SLA.EvaluateInterval == 0 {
+		cfg.SLA.EvaluateInterval = time.Minute
+	}
+	if cfg.AutoClose.SweepInterval == 0 {
+		cfg.AutoClose.SweepInterval = 5 * time.Minute
+	}
+	if cfg.AutoClose.BatchSize == 0 {
+		cfg.AutoClose.BatchSize = 200
+	}
+	if cfg.Storage.Driver == "" {
+		cfg.Storage.Driver = "local"
+	}
+	if cfg.Storage.LocalDir == "" {
+		cfg.Storage.LocalDir = "data/media"
+	}
+	if cfg.Storage.S3.Region == "" {
+		cfg.Storage.S3.Region = "us-east-1"
+	}
+	if cfg.Media.Workers == 0 {
+		cfg.Media.Workers = 4
+	}
+	if cfg.Media.MaxDownloadBytes == 0 {
+		cfg.Media.MaxDownloadBytes = 25 << 20
+	}
+	if cfg.Media.DownloadTimeout == 0 {
+		cfg.Media.DownloadTimeout = time.Minute
+	}
+	if cfg.Media.ThumbnailSize == 0 {
+		cfg.Media.ThumbnailSize = 320
+	}
+	if cfg.Media.MaxAttempts == 0 {
+		cfg.Media.MaxAttempts = 5
+	}
+	if cfg.Media.RetryInterval == 0 {
+		cfg.Media.RetryInterval = 5 * time.Minute
+	}
+	if cfg.Media.MaxUploadBytes == 0 {
+		cfg.Media.MaxUploadBytes = 25 << 20
+	}
+	if cfg.Media.MaxUploadFiles == 0 {
+		cfg.Media.MaxUploadFiles = 10
+	}
+	if len(cfg.Media.AllowedUploadTypes) == 0 {
+		cfg.Media.AllowedUploadTypes = []string{
+			"image/jpeg", "image/png", "image/gif", "image/webp",
+			"video/mp4", "audio/mpeg", "audio/mp4", "audio/aac",
+			"application/pdf", "text/plain", "text/csv", "application/zip",
+			"application/msword", "application/vnd.ms-excel", "application/vnd.ms-powerpoint",
+			"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			"application/vnd.openxmlformats-officedocument.presentationml.presentation",
+		}
+	}
+
+	if cfg.Bulk.PollInterval == 0 {
+		cfg.Bulk.PollInterval = 5 * time.Second
+	}
+	if cfg.Bulk.BatchSize == 0 {
+		cfg.Bulk.BatchSize = 100
+	}
+	if cfg.Bulk.MaxConversations == 0 {
+		cfg.Bulk.MaxConversations = 10000
+	}
+
+	if cfg.Snooze.SweepInterval == 0 {
+		cfg.Snooze.SweepInterval = 30 * time.Second
+	}
+	if cfg.Snooze.BatchSize == 0 {
+		cfg.Snooze.BatchSize = 200
+	}
+
+	if cfg.Schedule.SweepInterval == 0 {
+		cfg.Schedule.SweepInterval = 15 * time.Second
+	}
+	if cfg.Schedule.BatchSize == 0 {
+		cfg.Schedule.BatchSize = 100
+	}
+
+	if cfg.Campaign.PollInterval == 0 {
+		cfg.Campaign.PollInterval = 10 * time.Second
+	}
+	if cfg.Campaign.DefaultRatePerMinute == 0 {
+		cfg.Campaign.DefaultRatePerMinute = 60
+	}
+	if cfg.Campaign.MaxRatePerMinute == 0 {
+		cfg.Campaign.MaxRatePerMinute = 600
+	}
+	if cfg.Campaign.MaxRecipients == 0 {
+		cfg.Campaign.MaxRecipients = 50000
+	}
+
+	if cfg.Window.Policy == "" {
+		cfg.Window.Policy = WindowPolicyBlock
+	}
+
+	if cfg.ContactJob.PollInterval == 0 {
+		cfg.ContactJob.PollInterval = 5 * time.Second
+	}
+	if cfg.ContactJob.BatchSize == 0 {
+		cfg.ContactJob.BatchSize = 200
+	}
+	if cfg.ContactJob.MaxRows == 0 {
+		cfg.ContactJob.MaxRows = 50000
+	}
+	if cfg.ContactJob.MaxUploadBytes == 0 {
+		cfg.ContactJob.MaxUploadBytes = 10 << 20
+	}
 
 	// Validate config
 	if err := cfg.Validate(); err != nil {
@@ -207,6 +530,20 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT secret is required")
 	}
 
+	switch c.Storage.Driver {
+	case "local":
+	case "s3":
+		if c.Storage.S3.Bucket == "" {
+			return fmt.Errorf("storage.s3.bucket is required")
+		}
+	default:
+		return fmt.Errorf("invalid storage driver: %s", c.Storage.Driver)
+	}
+
+	if c.Window.Policy != WindowPolicyBlock && c.Window.Policy != WindowPolicyWarn {
+		return fmt.Errorf("invalid messaging_window.policy: %s", c.Window.Policy)
+	}
+
 	return nil
 }
 
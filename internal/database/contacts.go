@@ -0,0 +1,38 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Contact Lookup Indexes
+// Expression index tìm khách theo số điện thoại/email đã chuẩn hóa
+// (chống trùng khi nhập CSV chạy một truy vấn cho mỗi dòng)
+// ===========================================================================
+
+// ParticipantPhoneKey số điện thoại chuẩn hóa: tối đa 9 chữ số cuối, bỏ ký tự không phải số
+// để 0912 345 678 và +84912345678 là một. Truy vấn phải dùng đúng biểu thức này mới dùng được index
+const ParticipantPhoneKey = `right(regexp_replace(phone, '\D', '', 'g'), 9)`
+
+// ParticipantEmailKey email chuẩn hóa: bỏ khoảng trắng, chữ thường
+const ParticipantEmailKey = `lower(trim(email))`
+
+// contactIndexStatements các câu lệnh idempotent tạo index tìm khách
+var contactIndexStatements = []string{
+	`CREATE INDEX IF NOT EXISTS idx_participants_phone_key ON participants
+		(workspace_id, (` + ParticipantPhoneKey + `)) WHERE phone IS NOT NULL AND deleted_at IS NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_participants_email_key ON participants
+		(workspace_id, (` + ParticipantEmailKey + `)) WHERE email IS NOT NULL AND deleted_at IS NULL`,
+}
+
+// SetupContactIndexes tạo index tìm khách theo số điện thoại/email (chạy sau khi migrate bảng)
+func SetupContactIndexes(db *gorm.DB) error {
+	for _, stmt := range contactIndexStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("setup contact indexes: %w", err)
+		}
+	}
+	return nil
+}
@@ -56,8 +56,15 @@ func Close(db *gorm.DB) error {
 }
 
 // AutoMigrate runs auto migration for all models
+// Sau đó tạo cấu hình full-text search và index tìm khách (index phụ thuộc các bảng đã migrate)
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(models.AllModels()...)
+	if err := db.AutoMigrate(models.AllModels()...); err != nil {
+		return err
+	}
+	if err := SetupSearch(db); err != nil {
+		return err
+	}
+	return SetupContactIndexes(db)
 }
 
 func ConnectPostgres(cfg *config.DatabaseConfig) (*gorm.DB, error) {
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Full-text Search Setup
+// Cấu hình text search bỏ dấu tiếng Việt (unaccent) và GIN index
+// cho messages, notes, participants
+// ===========================================================================
+
+// SearchConfig tên text search configuration dùng cho tìm kiếm
+// Dựa trên "simple" (không stemming) + unaccent để "ban hang" khớp "bán hàng"
+const SearchConfig = "chatbox_search"
+
+// searchSetupStatements các câu lệnh idempotent tạo extension, configuration và index
+var searchSetupStatements = []string{
+	`CREATE EXTENSION IF NOT EXISTS unaccent`,
+	`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = '` + SearchConfig + `') THEN
+		CREATE TEXT SEARCH CONFIGURATION ` + SearchConfig + ` (COPY = simple);
+		ALTER TEXT SEARCH CONFIGURATION ` + SearchConfig + `
+			ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+	END IF;
+END
+$$`,
+	`CREATE INDEX IF NOT EXISTS idx_messages_content_fts ON messages
+		USING GIN (to_tsvector('` + SearchConfig + `', COALESCE(content, '')))`,
+	`CREATE INDEX IF NOT EXISTS idx_notes_content_fts ON notes
+		USING GIN (to_tsvector('` + SearchConfig + `', content))`,
+	`CREATE INDEX IF NOT EXISTS idx_participants_fts ON participants
+		USING GIN (to_tsvector('` + SearchConfig + `', COALESCE(name, '') || ' ' || COALESCE(email, '') || ' ' || COALESCE(phone, '')))`,
+}
+
+// SetupSearch tạo cấu hình full-text search (chạy sau khi migrate bảng)
+func SetupSearch(db *gorm.DB) error {
+	for _, stmt := range searchSetupStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("setup search: %w", err)
+		}
+	}
+	return nil
+}
@@ -40,7 +40,7 @@ type ListConversationsRequest struct {
 	PaginationRequest
 
 	// Status filter theo trạng thái
-	Status string `form:"status" binding:"omitempty,oneof=open pending closed bot_paused"`
+	Status string `form:"status" binding:"omitempty,oneof=open pending closed bot_paused snoozed"`
 
 	// AssignedTo filter theo agent được assign
 	AssignedTo *uuid.UUID `form:"assigned_to"`
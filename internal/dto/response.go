@@ -20,6 +20,9 @@ type Response struct {
 
 	// Meta thông tin phân trang (cho list API)
 	Meta *Meta `json:"meta,omitempty"`
+
+	// Cursor thông tin phân trang theo cursor (cho list API dạng keyset)
+	Cursor *CursorMeta `json:"cursor,omitempty"`
 }
 
 // APIError cấu trúc lỗi chuẩn
@@ -46,6 +49,25 @@ type Meta struct {
 	TotalPages int `json:"total_pages"`
 }
 
+// CursorMeta thông tin phân trang theo cursor
+// Dữ liệu luôn theo thứ tự mới nhất trước
+type CursorMeta struct {
+	// Limit số records tối đa mỗi trang
+	Limit int `json:"limit"`
+
+	// Before cursor để lấy trang cũ hơn (rỗng = đã hết)
+	Before string `json:"before,omitempty"`
+
+	// After cursor để lấy các records mới hơn trang hiện tại
+	After string `json:"after,omitempty"`
+
+	// HasBefore còn records cũ hơn
+	HasBefore bool `json:"has_before"`
+
+	// HasAfter còn records mới hơn
+	HasAfter bool `json:"has_after"`
+}
+
 // NewMeta tạo Meta từ thông tin phân trang
 func NewMeta(page, limit int, total int64) *Meta {
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
@@ -79,6 +101,15 @@ func SuccessWithMeta(data interface{}, meta *Meta) Response {
 	}
 }
 
+// SuccessWithCursor tạo response thành công với thông tin phân trang cursor
+func SuccessWithCursor(data interface{}, cursor *CursorMeta) Response {
+	return Response{
+		Success: true,
+		Data:    data,
+		Cursor:  cursor,
+	}
+}
+
 // Error tạo response lỗi
 func Error(code, message string) Response {
 	return Response{
@@ -41,6 +41,9 @@ var (
 	// ErrTimeout request timeout
 	ErrTimeout = errors.New("timeout")
 
+	// ErrMessagingWindowClosed khách đã ngoài cửa sổ nhắn tin của channel (VD: 24 giờ của Facebook)
+	ErrMessagingWindowClosed = errors.New("messaging window closed")
+
 	// Auth errors
 	// ErrInvalidCredentials email hoặc password không đúng
 	ErrInvalidCredentials = errors.New("invalid credentials")
@@ -123,6 +126,8 @@ func StatusCode(err error) int {
 		return http.StatusConflict
 	case errors.Is(err, ErrTimeout):
 		return http.StatusGatewayTimeout
+	case errors.Is(err, ErrMessagingWindowClosed):
+		return http.StatusUnprocessableEntity
 	case errors.Is(err, ErrInvalidCredentials):
 		return http.StatusUnauthorized
 	case errors.Is(err, ErrTokenExpired):
@@ -151,6 +156,8 @@ func ErrorCode(err error) string {
 		return "CONFLICT"
 	case errors.Is(err, ErrTimeout):
 		return "TIMEOUT"
+	case errors.Is(err, ErrMessagingWindowClosed):
+		return "MESSAGING_WINDOW_CLOSED"
 	case errors.Is(err, ErrInvalidCredentials):
 		return "INVALID_CREDENTIALS"
 	case errors.Is(err, ErrTokenExpired):
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Auto-Close Handler
+// Cấu hình tự động đóng hội thoại không hoạt động
+// ===========================================================================
+
+// AutoCloseHandler xử lý các endpoint auto-close
+type AutoCloseHandler struct {
+	autoCloseService services.AutoCloseService
+	logger           *zap.Logger
+}
+
+// NewAutoCloseHandler tạo AutoCloseHandler mới
+func NewAutoCloseHandler(autoCloseService services.AutoCloseService, logger *zap.Logger) *AutoCloseHandler {
+	return &AutoCloseHandler{
+		autoCloseService: autoCloseService,
+		logger:           logger,
+	}
+}
+
+// UpdateAutoCloseBody body cấu hình auto-close
+type UpdateAutoCloseBody struct {
+	Enabled        bool   `json:"enabled"`
+	InactiveHours  int    `json:"inactive_hours" binding:"min=0,max=8760"`
+	ClosingMessage string `json:"closing_message" binding:"max=2000"`
+	SendCSAT       bool   `json:"send_csat"`
+	CSATPrompt     string `json:"csat_prompt" binding:"max=640"`
+}
+
+// GetSettings lấy cấu hình auto-close
+// GET /api/v1/auto-close/settings
+func (h *AutoCloseHandler) GetSettings(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.autoCloseService.GetSettings(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(settings))
+}
+
+// UpdateSettings cập nhật cấu hình auto-close (admin)
+// PUT /api/v1/auto-close/settings
+func (h *AutoCloseHandler) UpdateSettings(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body UpdateAutoCloseBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	updated, err := h.autoCloseService.UpdateSettings(c.Request.Context(), actor, models.AutoCloseSettings{
+		Enabled:        body.Enabled,
+		InactiveHours:  body.InactiveHours,
+		ClosingMessage: body.ClosingMessage,
+		SendCSAT:       body.SendCSAT,
+		CSATPrompt:     body.CSATPrompt,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(updated))
+}
+
+// RegisterRoutes đăng ký routes cho auto-close handler
+func (h *AutoCloseHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	autoClose := rg.Group("/auto-close")
+	{
+		autoClose.GET("/settings", h.GetSettings)    // Cấu hình tự động đóng
+		autoClose.PUT("/settings", h.UpdateSettings) // Cập nhật cấu hình
+	}
+}
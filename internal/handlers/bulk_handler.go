@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Bulk Handler
+// Thao tác hàng loạt trên hội thoại, chạy nền và theo dõi tiến độ
+// ===========================================================================
+
+// BulkHandler xử lý các endpoint thao tác hàng loạt
+type BulkHandler struct {
+	bulkService services.BulkService
+	logger      *zap.Logger
+}
+
+// NewBulkHandler tạo BulkHandler mới
+func NewBulkHandler(bulkService services.BulkService, logger *zap.Logger) *BulkHandler {
+	return &BulkHandler{
+		bulkService: bulkService,
+		logger:      logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// CreateBulkJobBody body tạo job thao tác hàng loạt
+// Chọn hội thoại bằng conversation_ids hoặc filter (chỉ một trong hai)
+type CreateBulkJobBody struct {
+	ConversationIDs []uuid.UUID        `json:"conversation_ids"`
+	Filter          *models.BulkFilter `json:"filter"`
+	Action          string             `json:"action" binding:"required,oneof=assign close reopen tag untag set_priority pause_bot"`
+
+	// UserID agent được assign (assign), bỏ trống = bỏ assign
+	UserID *uuid.UUID `json:"user_id"`
+
+	// Tags tên tag (tag, untag)
+	Tags []string `json:"tags" binding:"omitempty,max=20"`
+
+	// Priority mức ưu tiên (set_priority)
+	Priority string `json:"priority" binding:"omitempty,oneof=low normal high urgent"`
+
+	// Reason lý do (close, pause_bot)
+	Reason string `json:"reason" binding:"max=255"`
+
+	// SendSurvey gửi khảo sát CSAT cho hội thoại vừa đóng (close)
+	SendSurvey bool `json:"send_survey"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// Create tạo job thao tác hàng loạt (admin), trả về 202 kèm job để theo dõi tiến độ
+// POST /api/v1/conversations/bulk
+func (h *BulkHandler) Create(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body CreateBulkJobBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	job, err := h.bulkService.Create(c.Request.Context(), actor, services.CreateBulkJobInput{
+		ConversationIDs: body.ConversationIDs,
+		Filter:          body.Filter,
+		Action:          models.BulkAction(body.Action),
+		Params: models.BulkParams{
+			UserID:     body.UserID,
+			Tags:       body.Tags,
+			Priority:   models.Priority(body.Priority),
+			Reason:     body.Reason,
+			SendSurvey: body.SendSurvey,
+		},
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("bulk job queued",
+		zap.String("request_id", requestID),
+		zap.String("job_id", job.ID.String()),
+		zap.Int("total", job.Total),
+	)
+
+	c.JSON(http.StatusAccepted, dto.Success(job))
+}
+
+// List lấy các job gần nhất của workspace
+// GET /api/v1/conversations/bulk
+func (h *BulkHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.bulkService.List(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"jobs":  jobs,
+		"total": len(jobs),
+	}))
+}
+
+// Get lấy tiến độ và kết quả của job
+// GET /api/v1/conversations/bulk/:jobId
+func (h *BulkHandler) Get(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Job ID không hợp lệ"))
+		return
+	}
+
+	job, err := h.bulkService.Get(c.Request.Context(), actor, jobID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(job))
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho bulk handler
+func (h *BulkHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	bulk := rg.Group("/conversations/bulk")
+	{
+		bulk.POST("", h.Create)    // Tạo job (admin)
+		bulk.GET("", h.List)       // Các job gần nhất
+		bulk.GET("/:jobId", h.Get) // Tiến độ & kết quả
+	}
+}
@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Campaign Handler
+// Chiến dịch gửi tin hàng loạt: soạn, xem trước tệp khách, launch, theo dõi và hủy
+// ===========================================================================
+
+// CampaignHandler xử lý các endpoint chiến dịch
+type CampaignHandler struct {
+	campaignService services.CampaignService
+	logger          *zap.Logger
+}
+
+// NewCampaignHandler tạo CampaignHandler mới
+func NewCampaignHandler(campaignService services.CampaignService, logger *zap.Logger) *CampaignHandler {
+	return &CampaignHandler{
+		campaignService: campaignService,
+		logger:          logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// ListCampaignsQuery query danh sách chiến dịch
+type ListCampaignsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=draft scheduled running completed cancelled failed"`
+}
+
+// CreateCampaignBody body tạo chiến dịch
+type CreateCampaignBody struct {
+	Name     string                  `json:"name" binding:"required,max=255"`
+	Content  string                  `json:"content" binding:"required,max=5000"`
+	Audience models.CampaignAudience `json:"audience"`
+
+	// MessageTag để gửi cho khách ngoài cửa sổ nhắn tin (VD: POST_PURCHASE_UPDATE)
+	MessageTag string `json:"message_tag" binding:"omitempty,max=50"`
+
+	// RatePerMinute số tin mỗi phút trên mỗi kênh (0 = mặc định)
+	RatePerMinute int `json:"rate_per_minute" binding:"omitempty,min=1"`
+}
+
+// UpdateCampaignBody body sửa chiến dịch nháp
+type UpdateCampaignBody struct {
+	Name          *string                  `json:"name" binding:"omitempty,max=255"`
+	Content       *string                  `json:"content" binding:"omitempty,max=5000"`
+	Audience      *models.CampaignAudience `json:"audience"`
+	MessageTag    *string                  `json:"message_tag" binding:"omitempty,max=50"`
+	RatePerMinute *int                     `json:"rate_per_minute" binding:"omitempty,min=0"`
+}
+
+// PreviewAudienceBody body xem trước tệp khách
+type PreviewAudienceBody struct {
+	Audience   models.CampaignAudience `json:"audience"`
+	MessageTag string                  `json:"message_tag" binding:"omitempty,max=50"`
+}
+
+// LaunchCampaignBody body launch chiến dịch
+type LaunchCampaignBody struct {
+	// ScheduledAt thời điểm bắt đầu gửi (bỏ trống = gửi ngay)
+	ScheduledAt *time.Time `json:"scheduled_at"`
+}
+
+// ListRecipientsQuery query kết quả gửi theo người nhận
+type ListRecipientsQuery struct {
+	dto.PaginationRequest
+	Status string `form:"status" binding:"omitempty,oneof=pending sending sent failed skipped cancelled"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// List lấy danh sách chiến dịch
+// GET /api/v1/campaigns?status=running
+func (h *CampaignHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query ListCampaignsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+
+	campaigns, err := h.campaignService.List(c.Request.Context(), actor, models.CampaignStatus(query.Status))
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"campaigns": campaigns,
+		"total":     len(campaigns),
+	}))
+}
+
+// Get lấy chi tiết và tiến độ chiến dịch
+// GET /api/v1/campaigns/:id
+func (h *CampaignHandler) Get(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	campaign, err := h.campaignService.Get(c.Request.Context(), actor, id)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(campaign))
+}
+
+// Create tạo chiến dịch nháp
+// POST /api/v1/campaigns
+func (h *CampaignHandler) Create(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body CreateCampaignBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	campaign, err := h.campaignService.Create(c.Request.Context(), actor, services.CampaignInput{
+		Name:          body.Name,
+		Content:       body.Content,
+		Audience:      body.Audience,
+		MessageTag:    body.MessageTag,
+		RatePerMinute: body.RatePerMinute,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("campaign created",
+		zap.String("request_id", requestID),
+		zap.String("campaign_id", campaign.ID.String()),
+	)
+
+	c.JSON(http.StatusCreated, dto.Success(campaign))
+}
+
+// Update sửa chiến dịch nháp
+// PATCH /api/v1/campaigns/:id
+func (h *CampaignHandler) Update(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body UpdateCampaignBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	campaign, err := h.campaignService.Update(c.Request.Context(), actor, id, services.UpdateCampaignInput{
+		Name:          body.Name,
+		Content:       body.Content,
+		Audience:      body.Audience,
+		MessageTag:    body.MessageTag,
+		RatePerMinute: body.RatePerMinute,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(campaign))
+}
+
+// Delete xóa chiến dịch nháp
+// DELETE /api/v1/campaigns/:id
+func (h *CampaignHandler) Delete(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.campaignService.Delete(c.Request.Context(), actor, id); err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{"deleted": true}))
+}
+
+// Preview xem trước số khách nhận được tin theo từng kênh
+// POST /api/v1/campaigns/preview
+func (h *CampaignHandler) Preview(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body PreviewAudienceBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	preview, err := h.campaignService.Preview(c.Request.Context(), actor, body.Audience, body.MessageTag)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(preview))
+}
+
+// Launch lên lịch gửi chiến dịch
+// POST /api/v1/campaigns/:id/launch
+func (h *CampaignHandler) Launch(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body LaunchCampaignBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+			return
+		}
+	}
+
+	campaign, err := h.campaignService.Launch(c.Request.Context(), actor, id, body.ScheduledAt)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(campaign))
+}
+
+// Cancel hủy chiến dịch đã lên lịch hoặc đang gửi
+// POST /api/v1/campaigns/:id/cancel
+func (h *CampaignHandler) Cancel(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	campaign, err := h.campaignService.Cancel(c.Request.Context(), actor, id)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(campaign))
+}
+
+// Recipients kết quả gửi theo từng khách
+// GET /api/v1/campaigns/:id/recipients?status=failed&page=1&limit=20
+func (h *CampaignHandler) Recipients(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var query ListRecipientsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	query.SetDefaults()
+
+	recipients, total, err := h.campaignService.Recipients(c.Request.Context(), actor, id,
+		models.RecipientStatus(query.Status), query.Page, query.Limit)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMeta(recipients, dto.NewMeta(query.Page, query.Limit, total)))
+}
+
+// parseID parse UUID từ path param
+func (h *CampaignHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", param+" không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho campaign handler
+func (h *CampaignHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	campaigns := rg.Group("/campaigns")
+	{
+		campaigns.GET("", h.List)                      // Danh sách chiến dịch
+		campaigns.POST("", h.Create)                   // Tạo chiến dịch nháp
+		campaigns.POST("/preview", h.Preview)          // Xem trước tệp khách
+		campaigns.GET("/:id", h.Get)                   // Chi tiết và tiến độ
+		campaigns.PATCH("/:id", h.Update)              // Sửa chiến dịch nháp
+		campaigns.DELETE("/:id", h.Delete)             // Xóa chiến dịch nháp
+		campaigns.POST("/:id/launch", h.Launch)        // Gửi ngay hoặc hẹn giờ
+		campaigns.POST("/:id/cancel", h.Cancel)        // Hủy chiến dịch
+		campaigns.GET("/:id/recipients", h.Recipients) // Kết quả theo người nhận
+	}
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Canned Response Handler
+// Thư viện câu trả lời soạn sẵn cho agent
+// Composer gọi GET /canned-responses?q=/sh khi agent gõ "/" để gợi ý
+// ===========================================================================
+
+// CannedResponseHandler xử lý các endpoint câu trả lời soạn sẵn
+type CannedResponseHandler struct {
+	cannedService    services.CannedResponseService
+	mediaService     services.MediaService
+	conversationRepo repositories.ConversationRepository
+	logger           *zap.Logger
+}
+
+// NewCannedResponseHandler tạo CannedResponseHandler mới
+func NewCannedResponseHandler(
+	cannedService services.CannedResponseService,
+	mediaService services.MediaService,
+	conversationRepo repositories.ConversationRepository,
+	logger *zap.Logger,
+) *CannedResponseHandler {
+	return &CannedResponseHandler{
+		cannedService:    cannedService,
+		mediaService:     mediaService,
+		conversationRepo: conversationRepo,
+		logger:           logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// ListCannedResponsesQuery query tìm câu trả lời
+type ListCannedResponsesQuery struct {
+	Q          string `form:"q"`
+	Visibility string `form:"visibility" binding:"omitempty,oneof=shared personal"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// CreateCannedResponseBody body tạo câu trả lời
+type CreateCannedResponseBody struct {
+	Title        string              `json:"title" binding:"required,min=1,max=255"`
+	Shortcut     string              `json:"shortcut" binding:"required,min=1,max=51"`
+	Body         string              `json:"body" binding:"required,min=1,max=5000"`
+	Visibility   string              `json:"visibility" binding:"omitempty,oneof=shared personal"`
+	QuickReplies []models.QuickReply `json:"quick_replies"`
+}
+
+// UpdateCannedResponseBody body sửa câu trả lời
+type UpdateCannedResponseBody struct {
+	Title        *string              `json:"title" binding:"omitempty,min=1,max=255"`
+	Shortcut     *string              `json:"shortcut" binding:"omitempty,min=1,max=51"`
+	Body         *string              `json:"body" binding:"omitempty,min=1,max=5000"`
+	Visibility   *string              `json:"visibility" binding:"omitempty,oneof=shared personal"`
+	QuickReplies *[]models.QuickReply `json:"quick_replies"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// List tìm câu trả lời soạn sẵn
+// GET /api/v1/canned-responses?q=/sh&visibility=shared&limit=20
+func (h *CannedResponseHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query ListCannedResponsesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+
+	responses, err := h.cannedService.List(c.Request.Context(), actor, services.ListCannedResponsesInput{
+		Query:      query.Q,
+		Visibility: models.CannedVisibility(query.Visibility),
+		Limit:      query.Limit,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"canned_responses": responses,
+		"total":            len(responses),
+	}))
+}
+
+// Variables danh sách biến template dùng được trong nội dung
+// GET /api/v1/canned-responses/variables
+func (h *CannedResponseHandler) Variables(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"variables": services.CannedVariables,
+	}))
+}
+
+// Get lấy chi tiết câu trả lời
+// GET /api/v1/canned-responses/:id
+func (h *CannedResponseHandler) Get(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.cannedService.Get(c.Request.Context(), actor, id)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Create tạo câu trả lời mới
+// POST /api/v1/canned-responses
+func (h *CannedResponseHandler) Create(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body CreateCannedResponseBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	response, err := h.cannedService.Create(c.Request.Context(), actor, services.CreateCannedResponseInput{
+		Title:        body.Title,
+		Shortcut:     body.Shortcut,
+		Body:         body.Body,
+		Visibility:   models.CannedVisibility(body.Visibility),
+		QuickReplies: body.QuickReplies,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("canned response created",
+		zap.String("request_id", requestID),
+		zap.String("canned_response_id", response.ID.String()),
+	)
+
+	c.JSON(http.StatusCreated, dto.Success(response))
+}
+
+// Update sửa câu trả lời
+// PATCH /api/v1/canned-responses/:id
+func (h *CannedResponseHandler) Update(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var body UpdateCannedResponseBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	input := services.UpdateCannedResponseInput{
+		Title:        body.Title,
+		Shortcut:     body.Shortcut,
+		Body:         body.Body,
+		QuickReplies: body.QuickReplies,
+	}
+	if body.Visibility != nil {
+		visibility := models.CannedVisibility(*body.Visibility)
+		input.Visibility = &visibility
+	}
+
+	response, err := h.cannedService.Update(c.Request.Context(), actor, id, input)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Delete xóa câu trả lời
+// DELETE /api/v1/canned-responses/:id
+func (h *CannedResponseHandler) Delete(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.cannedService.Delete(c.Request.Context(), actor, id); err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"message": "Đã xóa câu trả lời soạn sẵn",
+	}))
+}
+
+// AddAttachments upload file đính kèm (multipart, field files)
+// POST /api/v1/canned-responses/:id/attachments
+func (h *CannedResponseHandler) AddAttachments(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.mediaService.MaxUploadBytes()+1<<20)
+	files, closeFiles, ok := readUploadFiles(c)
+	if !ok {
+		return
+	}
+	defer closeFiles()
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Cần chọn ít nhất một file"))
+		return
+	}
+
+	response, err := h.cannedService.AddAttachments(c.Request.Context(), actor, id, files)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// RemoveAttachment bỏ file đính kèm
+// DELETE /api/v1/canned-responses/:id/attachments/:mediaId
+func (h *CannedResponseHandler) RemoveAttachment(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	mediaID, err := uuid.Parse(c.Param("mediaId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Media ID không hợp lệ"))
+		return
+	}
+
+	response, err := h.cannedService.RemoveAttachment(c.Request.Context(), actor, id, mediaID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(response))
+}
+
+// Preview xem trước nội dung đã thay biến theo hội thoại (không tính lượt dùng)
+// GET /api/v1/conversations/:id/canned-responses/:cannedId/preview
+func (h *CannedResponseHandler) Preview(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return
+	}
+	cannedID, err := uuid.Parse(c.Param("cannedId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Canned response ID không hợp lệ"))
+		return
+	}
+
+	conversation, err := h.conversationRepo.FindByID(c.Request.Context(), conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.Error("NOT_FOUND", "Không tìm thấy conversation"))
+			return
+		}
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	expansion, err := h.cannedService.Expand(c.Request.Context(), actor, conversation, cannedID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(expansion))
+}
+
+// parseID parse canned response ID từ path
+func (h *CannedResponseHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Canned response ID không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho canned response handler
+func (h *CannedResponseHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	canned := rg.Group("/canned-responses")
+	{
+		canned.GET("", h.List)                                         // Tìm kiếm / gợi ý theo shortcut
+		canned.GET("/variables", h.Variables)                          // Biến template hỗ trợ
+		canned.POST("", h.Create)                                      // Tạo câu trả lời
+		canned.GET("/:id", h.Get)                                      // Chi tiết
+		canned.PATCH("/:id", h.Update)                                 // Sửa
+		canned.DELETE("/:id", h.Delete)                                // Xóa
+		canned.POST("/:id/attachments", h.AddAttachments)              // Upload file đính kèm
+		canned.DELETE("/:id/attachments/:mediaId", h.RemoveAttachment) // Bỏ file đính kèm
+	}
+
+	rg.GET("/conversations/:id/canned-responses/:cannedId/preview", h.Preview) // Xem trước nội dung đã thay biến
+}
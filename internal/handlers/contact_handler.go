@@ -0,0 +1,455 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Contact Handler
+// Danh sách khách hàng, chi tiết kèm hội thoại, sửa thông tin, tag và lịch sử hoạt động,
+// gộp/tách danh tính của cùng một khách trên nhiều kênh
+// ===========================================================================
+
+// ContactHandler xử lý các endpoint khách hàng
+type ContactHandler struct {
+	contactService services.ContactService
+	logger         *zap.Logger
+}
+
+// NewContactHandler tạo ContactHandler mới
+func NewContactHandler(contactService services.ContactService, logger *zap.Logger) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+		logger:         logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// ListContactsQuery query danh sách khách
+type ListContactsQuery struct {
+	dto.PaginationRequest
+
+	// Q tìm theo tên, email, số điện thoại (khớp một phần)
+	Q string `form:"q" binding:"max=200"`
+
+	// Channel loại kênh (facebook, zalo, web, mock)
+	Channel          string     `form:"channel" binding:"omitempty,oneof=facebook zalo web mock"`
+	ChannelAccountID *uuid.UUID `form:"channel_account_id"`
+
+	Tag string `form:"tag" binding:"max=100"`
+
+	// SeenFrom, SeenTo khoảng thời gian khách nhắn tin lần cuối
+	SeenFrom *time.Time `form:"seen_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	SeenTo   *time.Time `form:"seen_to" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Sort last_seen, created, name hoặc field.<key> / -field.<key> theo trường tùy chỉnh
+	Sort string `form:"sort" binding:"max=60"`
+}
+
+// UpdateContactBody body sửa khách (bỏ qua field = giữ nguyên, chuỗi rỗng = xóa)
+type UpdateContactBody struct {
+	Name  *string `json:"name" binding:"omitempty,max=255"`
+	Email *string `json:"email" binding:"omitempty,max=255"`
+	Phone *string `json:"phone" binding:"omitempty,max=50"`
+
+	// CustomFields trường tùy chỉnh cần đặt, giá trị null thì xóa trường
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// ContactTagsBody body gắn tag cho khách
+type ContactTagsBody struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20"`
+}
+
+// ContactTimelineQuery query lịch sử hoạt động
+type ContactTimelineQuery struct {
+	// Before lấy các hoạt động trước thời điểm này (trang tiếp theo)
+	Before *time.Time `form:"before" time_format:"2006-01-02T15:04:05.999999999Z07:00"`
+	Limit  int        `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ContactMessagesQuery query tin nhắn của khách trên mọi danh tính
+type ContactMessagesQuery struct {
+	// Before lấy tin nhắn cũ hơn cursor
+	Before string `form:"before"`
+
+	// After lấy tin nhắn mới hơn cursor
+	After string `form:"after"`
+
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// MergeContactsBody body gộp khách
+type MergeContactsBody struct {
+	ParticipantIDs []uuid.UUID `json:"participant_ids" binding:"required,min=1,max=20"`
+}
+
+// MergeSuggestionsQuery query gợi ý gộp khách
+type MergeSuggestionsQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// List lấy danh sách khách
+// GET /api/v1/contacts?q=&channel=&channel_account_id=&tag=&seen_from=&seen_to=&sort=last_seen&page=1&limit=20
+// Lọc theo trường tùy chỉnh: field.<key>=<giá trị> hoặc field.<key>.<toán tử>=<giá trị>
+func (h *ContactHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query ListContactsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	query.SetDefaults()
+
+	input := services.ContactListInput{
+		Query:            query.Q,
+		ChannelAccountID: query.ChannelAccountID,
+		ChannelType:      query.Channel,
+		Tag:              query.Tag,
+		SeenFrom:         query.SeenFrom,
+		SeenTo:           query.SeenTo,
+		Fields:           parseFieldFilters(c),
+		Offset:           query.Offset(),
+		Limit:            query.Limit,
+	}
+	if key, desc, ok := parseFieldSort(query.Sort); ok {
+		input.SortField, input.SortDesc = key, desc
+	} else {
+		switch query.Sort {
+		case "", repositories.ContactSortLastSeen, repositories.ContactSortCreated, repositories.ContactSortName:
+			input.Sort = query.Sort
+		default:
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "sort không hợp lệ"))
+			return
+		}
+	}
+
+	contacts, total, err := h.contactService.List(c.Request.Context(), actor, input)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMeta(contacts, dto.NewMeta(query.Page, query.Limit, total)))
+}
+
+// Get lấy chi tiết khách kèm mọi hội thoại
+// GET /api/v1/contacts/:id
+func (h *ContactHandler) Get(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.contactService.Get(c.Request.Context(), actor, id)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(detail))
+}
+
+// Update sửa thông tin khách
+// PATCH /api/v1/contacts/:id
+func (h *ContactHandler) Update(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body UpdateContactBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	contact, err := h.contactService.Update(c.Request.Context(), actor, id, services.UpdateContactInput{
+		Name:         body.Name,
+		Email:        body.Email,
+		Phone:        body.Phone,
+		CustomFields: body.CustomFields,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(contact))
+}
+
+// AddTags gắn tag cho khách
+// POST /api/v1/contacts/:id/tags
+func (h *ContactHandler) AddTags(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body ContactTagsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	contact, err := h.contactService.AddTags(c.Request.Context(), actor, id, body.Tags)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(contact))
+}
+
+// RemoveTag gỡ tag của khách
+// DELETE /api/v1/contacts/:id/tags/:tag
+func (h *ContactHandler) RemoveTag(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	contact, err := h.contactService.RemoveTag(c.Request.Context(), actor, id, c.Param("tag"))
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(contact))
+}
+
+// Timeline lịch sử hoạt động của khách
+// GET /api/v1/contacts/:id/timeline?before=&limit=50
+func (h *ContactHandler) Timeline(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var query ContactTimelineQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	activities, err := h.contactService.Timeline(c.Request.Context(), actor, id, query.Before, query.Limit)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	// Trang đầy thì có thể còn hoạt động cũ hơn
+	var nextBefore *time.Time
+	if len(activities) == query.Limit {
+		nextBefore = &activities[len(activities)-1].At
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"activities":  activities,
+		"next_before": nextBefore,
+	}))
+}
+
+// Messages tin nhắn của khách trên mọi kênh đã gộp, mới nhất trước
+// GET /api/v1/contacts/:id/messages?before=&after=&limit=50
+func (h *ContactHandler) Messages(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var query ContactMessagesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+	cursorQuery, ok := parseCursorQuery(c, query.Before, query.After, query.Limit)
+	if !ok {
+		return
+	}
+
+	messages, hasMore, err := h.contactService.Messages(c.Request.Context(), actor, id, cursorQuery)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithCursor(
+		messages,
+		newCursorMeta(cursorQuery, messageCursors(messages), hasMore),
+	))
+}
+
+// Merge gộp các khách khác vào khách này
+// POST /api/v1/contacts/:id/merge
+func (h *ContactHandler) Merge(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body MergeContactsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	detail, err := h.contactService.Merge(c.Request.Context(), actor, id, body.ParticipantIDs)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(detail))
+}
+
+// Unmerge tách khách khỏi các danh tính đã gộp
+// POST /api/v1/contacts/:id/unmerge
+func (h *ContactHandler) Unmerge(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	contact, err := h.contactService.Unmerge(c.Request.Context(), actor, id)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(contact))
+}
+
+// MergeSuggestions gợi ý gộp khách trùng số điện thoại hoặc email trong workspace
+// GET /api/v1/contacts/merge-suggestions?limit=20
+func (h *ContactHandler) MergeSuggestions(c *gin.Context) {
+	h.mergeSuggestions(c, nil)
+}
+
+// ContactMergeSuggestions gợi ý gộp cho một khách
+// GET /api/v1/contacts/:id/merge-suggestions?limit=20
+func (h *ContactHandler) ContactMergeSuggestions(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	h.mergeSuggestions(c, &id)
+}
+
+// mergeSuggestions trả về gợi ý gộp (id nil = cả workspace)
+func (h *ContactHandler) mergeSuggestions(c *gin.Context, id *uuid.UUID) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query MergeSuggestionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	suggestions, err := h.contactService.MergeSuggestions(c.Request.Context(), actor, id, query.Limit)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"suggestions": suggestions,
+		"total":       len(suggestions),
+	}))
+}
+
+// parseID parse UUID từ path param
+func (h *ContactHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", param+" không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho contact handler
+func (h *ContactHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	contacts := rg.Group("/contacts")
+	{
+		contacts.GET("", h.List)                                          // Danh sách khách
+		contacts.GET("/merge-suggestions", h.MergeSuggestions)            // Gợi ý gộp trong workspace
+		contacts.GET("/:id", h.Get)                                       // Chi tiết kèm hội thoại
+		contacts.PATCH("/:id", h.Update)                                  // Sửa thông tin, trường tùy chỉnh
+		contacts.POST("/:id/tags", h.AddTags)                             // Gắn tag
+		contacts.DELETE("/:id/tags/:tag", h.RemoveTag)                    // Gỡ tag
+		contacts.GET("/:id/timeline", h.Timeline)                         // Lịch sử hoạt động
+		contacts.GET("/:id/messages", h.Messages)                         // Tin nhắn trên mọi kênh đã gộp
+		contacts.POST("/:id/merge", h.Merge)                              // Gộp khách khác vào khách này
+		contacts.POST("/:id/unmerge", h.Unmerge)                          // Tách khỏi các danh tính đã gộp
+		contacts.GET("/:id/merge-suggestions", h.ContactMergeSuggestions) // Gợi ý gộp cho khách
+	}
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"time"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Contact Job Handler
+// Nhập khách từ file CSV và xuất khách ra CSV, chạy nền và theo dõi tiến độ
+// ===========================================================================
+
+// ContactJobHandler xử lý các endpoint nhập/xuất danh sách khách
+type ContactJobHandler struct {
+	jobService services.ContactJobService
+	logger     *zap.Logger
+}
+
+// NewContactJobHandler tạo ContactJobHandler mới
+func NewContactJobHandler(jobService services.ContactJobService, logger *zap.Logger) *ContactJobHandler {
+	return &ContactJobHandler{
+		jobService: jobService,
+		logger:     logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// ImportContactsForm form multipart nhập khách (file CSV ở field file)
+type ImportContactsForm struct {
+	// ChannelAccountID kênh gắn cho khách mới
+	ChannelAccountID string `form:"channel_account_id" binding:"required,uuid"`
+
+	// Columns ánh xạ cột dạng JSON: [{"column":"Số ĐT","target":"phone"}, ...]
+	// Bỏ trống thì tự ánh xạ theo tên cột
+	Columns string `form:"columns"`
+
+	// DryRun chỉ kiểm tra file và đếm kết quả dự kiến
+	DryRun bool `form:"dry_run"`
+}
+
+// ExportContactsQuery bộ lọc khách cần xuất (giống danh sách khách)
+type ExportContactsQuery struct {
+	Q                string     `form:"q" binding:"max=200"`
+	Channel          string     `form:"channel" binding:"omitempty,oneof=facebook zalo web mock"`
+	ChannelAccountID *uuid.UUID `form:"channel_account_id"`
+	Tag              string     `form:"tag" binding:"max=100"`
+	SeenFrom         *time.Time `form:"seen_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	SeenTo           *time.Time `form:"seen_to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// Import tải file CSV lên và tạo job nhập khách (admin), trả về 202 kèm job để theo dõi tiến độ
+// POST /api/v1/contacts/import (multipart: file, channel_account_id, columns, dry_run)
+func (h *ContactJobHandler) Import(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var form ImportContactsForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	var columns []models.ContactImportColumn
+	if form.Columns != "" {
+		if err := json.Unmarshal([]byte(form.Columns), &columns); err != nil {
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "columns phải là mảng JSON {column, target}"))
+			return
+		}
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Cần file CSV ở field file"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Không đọc được file "+header.Filename))
+		return
+	}
+	defer file.Close()
+
+	job, err := h.jobService.Import(c.Request.Context(), actor, services.ContactImportInput{
+		File: services.UploadFile{
+			Name:        header.Filename,
+			Size:        header.Size,
+			ContentType: header.Header.Get("Content-Type"),
+			Body:        file,
+		},
+		Columns:          columns,
+		ChannelAccountID: uuid.MustParse(form.ChannelAccountID),
+		DryRun:           form.DryRun,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("contact import queued",
+		zap.String("request_id", requestID),
+		zap.String("job_id", job.ID.String()),
+		zap.Int("total", job.Total),
+		zap.Bool("dry_run", job.DryRun),
+	)
+
+	c.JSON(http.StatusAccepted, dto.Success(job))
+}
+
+// Export tạo job xuất khách khớp bộ lọc (admin), trả về 202 kèm job để theo dõi tiến độ
+// POST /api/v1/contacts/export?q=&channel=&channel_account_id=&tag=&seen_from=&seen_to=&field.<key>=
+func (h *ContactJobHandler) Export(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query ExportContactsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+
+	job, err := h.jobService.Export(c.Request.Context(), actor, models.ContactExportFilter{
+		Query:            query.Q,
+		ChannelAccountID: query.ChannelAccountID,
+		ChannelType:      query.Channel,
+		Tag:              query.Tag,
+		SeenFrom:         query.SeenFrom,
+		SeenTo:           query.SeenTo,
+		Fields:           parseFieldFilters(c),
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("contact export queued",
+		zap.String("request_id", requestID),
+		zap.String("job_id", job.ID.String()),
+		zap.Int("total", job.Total),
+	)
+
+	c.JSON(http.StatusAccepted, dto.Success(job))
+}
+
+// List lấy các job nhập/xuất gần nhất của workspace
+// GET /api/v1/contacts/jobs
+func (h *ContactJobHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.jobService.List(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"jobs":  jobs,
+		"total": len(jobs),
+	}))
+}
+
+// Get lấy tiến độ và kết quả của job (kèm lỗi theo dòng của job nhập)
+// GET /api/v1/contacts/jobs/:jobId
+func (h *ContactJobHandler) Get(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	jobID, ok := h.parseJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobService.Get(c.Request.Context(), actor, jobID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(job))
+}
+
+// Download tải file kết quả (admin): CSV khách của job xuất, báo cáo dòng lỗi của job nhập
+// GET /api/v1/contacts/jobs/:jobId/download
+func (h *ContactJobHandler) Download(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	jobID, ok := h.parseJobID(c)
+	if !ok {
+		return
+	}
+
+	file, err := h.jobService.Download(c.Request.Context(), actor, jobID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+	defer file.Body.Close()
+
+	disposition := "attachment"
+	if formatted := mime.FormatMediaType(disposition, map[string]string{"filename": file.Name}); formatted != "" {
+		disposition = formatted
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, -1, file.ContentType, file.Body, map[string]string{
+		"Content-Disposition": disposition,
+	})
+}
+
+// parseJobID parse job ID từ path param
+func (h *ContactJobHandler) parseJobID(c *gin.Context) (uuid.UUID, bool) {
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Job ID không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return jobID, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho contact job handler
+func (h *ContactJobHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	contacts := rg.Group("/contacts")
+	{
+		contacts.POST("/import", h.Import)                // Nhập khách từ CSV (admin)
+		contacts.POST("/export", h.Export)                // Xuất khách ra CSV (admin)
+		contacts.GET("/jobs", h.List)                     // Các job gần nhất
+		contacts.GET("/jobs/:jobId", h.Get)               // Tiến độ & kết quả
+		contacts.GET("/jobs/:jobId/download", h.Download) // Tải file xuất / báo cáo lỗi (admin)
+	}
+}
@@ -4,14 +4,14 @@ import (
 	"context"
 	"errors"
 	"net/http"
-	"strconv"
+	"strings"
 
-	"chatbox-gin/internal/channel"
 	"chatbox-gin/internal/dto"
 	"chatbox-gin/internal/middleware"
 	"chatbox-gin/internal/models"
 	"chatbox-gin/internal/realtime"
 	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,33 +26,45 @@ import (
 
 // ConversationHandler xử lý các endpoint conversation
 type ConversationHandler struct {
-	conversationRepo   repositories.ConversationRepository
-	messageRepo        repositories.MessageRepository
-	participantRepo    repositories.ParticipantRepository
-	channelAccountRepo repositories.ChannelAccountRepository
-	channelRegistry    *channel.Registry
-	publisher          realtime.Publisher
-	logger             *zap.Logger
+	conversationRepo repositories.ConversationRepository
+	messageRepo      repositories.MessageRepository
+	outboundService  services.OutboundService
+	csatService      services.CSATService
+	readService      services.ReadService
+	mediaService     services.MediaService
+	cannedService    services.CannedResponseService
+	scheduledService services.ScheduledMessageService
+	fieldService     services.CustomFieldService
+	publisher        realtime.Publisher
+	logger           *zap.Logger
 }
 
 // NewConversationHandler tạo handler mới
 func NewConversationHandler(
 	conversationRepo repositories.ConversationRepository,
 	messageRepo repositories.MessageRepository,
-	participantRepo repositories.ParticipantRepository,
-	channelAccountRepo repositories.ChannelAccountRepository,
-	channelRegistry *channel.Registry,
+	outboundService services.OutboundService,
+	csatService services.CSATService,
+	readService services.ReadService,
+	mediaService services.MediaService,
+	cannedService services.CannedResponseService,
+	scheduledService services.ScheduledMessageService,
+	fieldService services.CustomFieldService,
 	publisher realtime.Publisher,
 	logger *zap.Logger,
 ) *ConversationHandler {
 	return &ConversationHandler{
-		conversationRepo:   conversationRepo,
-		messageRepo:        messageRepo,
-		participantRepo:    participantRepo,
-		channelAccountRepo: channelAccountRepo,
-		channelRegistry:    channelRegistry,
-		publisher:          publisher,
-		logger:             logger,
+		conversationRepo: conversationRepo,
+		messageRepo:      messageRepo,
+		outboundService:  outboundService,
+		csatService:      csatService,
+		readService:      readService,
+		mediaService:     mediaService,
+		cannedService:    cannedService,
+		scheduledService: scheduledService,
+		fieldService:     fieldService,
+		publisher:        publisher,
+		logger:           logger,
 	}
 }
 
@@ -98,10 +110,33 @@ func (h *ConversationHandler) handleDBError(c *gin.Context, requestID string, er
 // ListConversationsQuery query params cho list conversations
 type ListConversationsQuery struct {
 	WorkspaceID string `form:"workspace_id" binding:"required"`
-	Status      string `form:"status" binding:"omitempty,oneof=open pending closed bot_paused"`
+	Status      string `form:"status" binding:"omitempty,oneof=open pending closed bot_paused snoozed"`
 	AssignedTo  string `form:"assigned_to"`
-	Page        int    `form:"page"`
-	Limit       int    `form:"limit"`
+	Priority    string `form:"priority" binding:"omitempty,oneof=low normal high urgent"`
+	SLAStatus   string `form:"sla_status" binding:"omitempty,oneof=on_track warning breached"`
+	// Sort last_message, sla_due hoặc field.<key> / -field.<key> theo trường tùy chỉnh của khách
+	Sort  string `form:"sort" binding:"max=60"`
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+
+	// Before/After cursor (keyset pagination theo last_message_at), thay cho page
+	Before string `form:"before"`
+	After  string `form:"after"`
+}
+
+// ListMessagesQuery query params cho list messages
+// Mặc định trả về trang mới nhất, thứ tự mới nhất trước
+type ListMessagesQuery struct {
+	// Before lấy tin nhắn cũ hơn cursor
+	Before string `form:"before"`
+
+	// After lấy tin nhắn mới hơn cursor
+	After string `form:"after"`
+
+	// Around lấy tin nhắn xung quanh message ID (nhảy tới kết quả tìm kiếm)
+	Around *uuid.UUID `form:"around"`
+
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
 }
 
 // UpdateConversationBody body cho update conversation
@@ -111,10 +146,23 @@ type UpdateConversationBody struct {
 	Priority   *string    `json:"priority" binding:"omitempty,oneof=low normal high urgent"`
 }
 
+// MarkReadBody body đánh dấu đã đọc
+type MarkReadBody struct {
+	// MessageID đánh dấu đến hết tin nhắn này (bỏ trống = toàn bộ)
+	MessageID *uuid.UUID `json:"message_id"`
+}
+
 // SendMessageBody body cho gửi tin nhắn
+// Gửi kèm file: dùng multipart/form-data với field content và một hoặc nhiều field files
+// Dùng câu trả lời soạn sẵn: gửi canned_response_id, server thay biến và gửi kèm file/quick reply
+// (content khác rỗng thì dùng content agent đã sửa thay cho nội dung soạn sẵn)
 type SendMessageBody struct {
-	Content     string `json:"content" binding:"required,min=1,max=5000"`
-	ContentType string `json:"content_type" binding:"omitempty,oneof=text image file"`
+	Content          string `json:"content" form:"content" binding:"max=5000"`
+	ContentType      string `json:"content_type" form:"content_type" binding:"omitempty,oneof=text image file"`
+	CannedResponseID string `json:"canned_response_id" form:"canned_response_id" binding:"omitempty,uuid"`
+
+	// MessageTag gửi khi khách đã ngoài cửa sổ nhắn tin của channel (VD: HUMAN_AGENT)
+	MessageTag string `json:"message_tag" form:"message_tag" binding:"omitempty,max=50"`
 }
 
 // ===========================================================================
@@ -123,13 +171,14 @@ type SendMessageBody struct {
 
 // List lấy danh sách conversations
 // GET /api/v1/conversations?workspace_id=xxx&status=open&page=1&limit=20
+// GET /api/v1/conversations?workspace_id=xxx&before=<cursor>&limit=20 (keyset)
 func (h *ConversationHandler) List(c *gin.Context) {
 	requestID := middleware.GetRequestID(c)
 	ctx := c.Request.Context()
 
 	var query ListConversationsQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "workspace_id là bắt buộc"))
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
 		return
 	}
 
@@ -164,12 +213,78 @@ func (h *ConversationHandler) List(c *gin.Context) {
 			opts.Filters["assigned_to"] = assignedID
 		}
 	}
+	if query.Priority != "" {
+		opts.Filters["priority"] = query.Priority
+	}
+	if query.SLAStatus != "" {
+		opts.Filters["sla_status"] = query.SLAStatus
+	}
+	switch query.Sort {
+	case "", "last_message":
+	case "sla_due":
+		opts.OrderBy = "sla_due"
+	default:
+		key, desc, ok := parseFieldSort(query.Sort)
+		if !ok {
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "sort không hợp lệ"))
+			return
+		}
+		if err := h.fieldService.CheckSortField(ctx, workspaceID, key); err != nil {
+			handleServiceError(c, h.logger, err)
+			return
+		}
+		opts.OrderBy = repositories.FieldSortPrefix + key
+		opts.OrderDir = "asc"
+		if desc {
+			opts.OrderDir = "desc"
+		}
+	}
+
+	// Lọc theo trường tùy chỉnh của khách: field.<key>=<giá trị>, field.<key>.<toán tử>=<giá trị>
+	if conditions := parseFieldFilters(c); len(conditions) > 0 {
+		fields, err := h.fieldService.ParseConditions(ctx, workspaceID, conditions)
+		if err != nil {
+			handleServiceError(c, h.logger, err)
+			return
+		}
+		opts.Filters["fields"] = fields
+	}
+
+	// Keyset pagination theo hoạt động gần nhất
+	if query.Before != "" || query.After != "" {
+		if opts.OrderBy != "last_message_at" {
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Cursor chỉ hỗ trợ sort=last_message"))
+			return
+		}
+		cursorQuery, ok := parseCursorQuery(c, query.Before, query.After, query.Limit)
+		if !ok {
+			return
+		}
+		conversations, hasMore, err := h.conversationRepo.FindPageByWorkspace(ctx, workspaceID, opts.Filters, cursorQuery)
+		if err != nil {
+			h.handleDBError(c, requestID, err, "conversations")
+			return
+		}
+		h.fillUnreadCounts(ctx, conversations)
+		h.outboundService.FillWindows(conversations)
+		cursors := make([]repositories.Cursor, len(conversations))
+		for i := range conversations {
+			cursors[i] = repositories.ConversationCursor(&conversations[i])
+		}
+		c.JSON(http.StatusOK, dto.SuccessWithCursor(
+			conversations,
+			newCursorMeta(cursorQuery, cursors, hasMore),
+		))
+		return
+	}
 
 	conversations, total, err := h.conversationRepo.FindByWorkspace(ctx, workspaceID, opts)
 	if err != nil {
 		h.handleDBError(c, requestID, err, "conversations")
 		return
 	}
+	h.fillUnreadCounts(ctx, conversations)
+	h.outboundService.FillWindows(conversations)
 
 	c.JSON(http.StatusOK, dto.SuccessWithMeta(
 		conversations,
@@ -189,15 +304,78 @@ func (h *ConversationHandler) Get(c *gin.Context) {
 		return
 	}
 
-	conversation, err := h.conversationRepo.FindByID(ctx, conversationID)
+	// Lấy kèm notes, tags, agent để hiển thị màn hình chi tiết
+	conversation, err := h.conversationRepo.FindDetailByID(ctx, conversationID)
 	if err != nil {
 		h.handleDBError(c, requestID, err, "conversation")
 		return
 	}
+	conversations := []models.Conversation{*conversation}
+	h.fillUnreadCounts(ctx, conversations)
+	h.outboundService.FillWindows(conversations)
+	conversation.UnreadCount = conversations[0].UnreadCount
+	conversation.MessagingWindow = conversations[0].MessagingWindow
 
 	c.JSON(http.StatusOK, dto.Success(conversation))
 }
 
+// fillUnreadCounts gắn số tin nhắn chưa đọc cho danh sách trả về
+// Lỗi chỉ ghi log, danh sách vẫn trả về với unread_count = 0
+func (h *ConversationHandler) fillUnreadCounts(ctx context.Context, conversations []models.Conversation) {
+	if err := h.readService.FillUnreadCounts(ctx, conversations); err != nil {
+		h.logger.Warn("failed to count unread messages", zap.Error(err))
+	}
+}
+
+// MarkRead đánh dấu đã đọc tin nhắn của khách
+// POST /api/v1/conversations/:id/read
+func (h *ConversationHandler) MarkRead(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return
+	}
+
+	// Body không bắt buộc
+	var body MarkReadBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+			return
+		}
+	}
+
+	result, err := h.readService.MarkRead(c.Request.Context(), actor, conversationID, body.MessageID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(result))
+}
+
+// Unread tổng số tin nhắn chưa đọc của workspace và của agent hiện tại
+// GET /api/v1/conversations/unread
+func (h *ConversationHandler) Unread(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	totals, err := h.readService.UnreadTotals(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(totals))
+}
+
 // Update cập nhật conversation
 // PATCH /api/v1/conversations/:id
 func (h *ConversationHandler) Update(c *gin.Context) {
@@ -223,8 +401,24 @@ func (h *ConversationHandler) Update(c *gin.Context) {
 	}
 
 	// Cập nhật các fields
+	justClosed := false
 	if body.Status != nil {
-		conversation.Status = models.ConversationStatus(*body.Status)
+		status := models.ConversationStatus(*body.Status)
+		// Đổi trạng thái thủ công thì bỏ tạm ẩn (tạm ẩn dùng POST /conversations/:id/snooze)
+		if conversation.IsSnoozed() {
+			conversation.Unsnooze()
+		}
+		switch {
+		case status == models.StatusClosed && !conversation.IsClosed():
+			// Close set ResolvedAt để tính SLA giải quyết
+			conversation.Close(conversation.Metadata.ClosedReason)
+			justClosed = true
+		case status != models.StatusClosed && conversation.IsClosed():
+			conversation.Reopen()
+			conversation.Status = status
+		default:
+			conversation.Status = status
+		}
 	}
 	if body.AssignedTo != nil {
 		conversation.AssignedTo = body.AssignedTo
@@ -243,11 +437,28 @@ func (h *ConversationHandler) Update(c *gin.Context) {
 		zap.String("conversation_id", conversationID.String()),
 	)
 
+	// Gửi khảo sát CSAT trên bản sao để không đụng conversation đang trả về
+	if justClosed {
+		closed := *conversation
+		go h.sendSurvey(context.Background(), &closed)
+	}
+
 	c.JSON(http.StatusOK, dto.Success(conversation))
 }
 
-// ListMessages lấy danh sách messages của conversation
-// GET /api/v1/conversations/:id/messages
+// sendSurvey gửi khảo sát CSAT cho conversation vừa đóng
+func (h *ConversationHandler) sendSurvey(ctx context.Context, conv *models.Conversation) {
+	if err := h.csatService.SendSurvey(ctx, conv); err != nil {
+		h.logger.Warn("sendSurvey: send failed",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// ListMessages lấy danh sách messages của conversation (keyset, mới nhất trước)
+// GET /api/v1/conversations/:id/messages?limit=50
+// GET /api/v1/conversations/:id/messages?before=<cursor> | after=<cursor> | around=<messageId>
 func (h *ConversationHandler) ListMessages(c *gin.Context) {
 	requestID := middleware.GetRequestID(c)
 	ctx := c.Request.Context()
@@ -265,37 +476,109 @@ func (h *ConversationHandler) ListMessages(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination
-	page := 1
-	limit := 50
-	if p := c.Query("page"); p != "" {
-		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
-			page = parsed
-		}
+	var query ListMessagesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
 	}
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
-			limit = parsed
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	if query.Around != nil {
+		h.listMessagesAround(c, requestID, conversationID, *query.Around, query.Limit)
+		return
+	}
+
+	cursorQuery, ok := parseCursorQuery(c, query.Before, query.After, query.Limit)
+	if !ok {
+		return
+	}
+
+	messages, hasMore, err := h.messageRepo.FindPageByConversation(ctx, conversationID, cursorQuery)
+	if err != nil {
+		h.handleDBError(c, requestID, err, "messages")
+		return
+	}
+	cursor := newCursorMeta(cursorQuery, messageCursors(messages), hasMore)
+
+	// Trang mới nhất: tin nhắn hẹn giờ đang chờ gửi nằm trên cùng
+	// (metadata.scheduled_at, không tính vào cursor)
+	if cursorQuery.Before == nil && cursorQuery.After == nil {
+		pending, err := h.scheduledService.PendingMessages(ctx, conversationID)
+		if err != nil {
+			h.handleDBError(c, requestID, err, "scheduled messages")
+			return
 		}
+		messages = append(pending, messages...)
 	}
 
-	opts := repositories.FindOptions{
-		Offset:   (page - 1) * limit,
-		Limit:    limit,
-		OrderBy:  "created_at",
-		OrderDir: "asc", // Messages theo thứ tự thời gian
+	c.JSON(http.StatusOK, dto.SuccessWithCursor(messages, cursor))
+}
+
+// listMessagesAround lấy tin nhắn xung quanh một message (kể cả message đó)
+// Nửa trang tin nhắn cũ hơn, phần còn lại tin nhắn mới hơn
+func (h *ConversationHandler) listMessagesAround(c *gin.Context, requestID string, conversationID, messageID uuid.UUID, limit int) {
+	ctx := c.Request.Context()
+
+	anchor, err := h.messageRepo.FindByID(ctx, messageID)
+	if err == nil && anchor.ConversationID != conversationID {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		h.handleDBError(c, requestID, err, "message")
+		return
 	}
 
-	messages, total, err := h.messageRepo.FindByConversation(ctx, conversationID, opts)
+	cursor := repositories.MessageCursor(anchor)
+	olderLimit := limit / 2
+	newerLimit := limit - olderLimit - 1
+
+	older, hasBefore, err := h.messageRepo.FindPageByConversation(ctx, conversationID, repositories.CursorQuery{
+		Before: &cursor,
+		Limit:  olderLimit,
+	})
 	if err != nil {
 		h.handleDBError(c, requestID, err, "messages")
 		return
 	}
+	var newer []models.Message
+	hasAfter := false
+	if newerLimit > 0 {
+		newer, hasAfter, err = h.messageRepo.FindPageByConversation(ctx, conversationID, repositories.CursorQuery{
+			After: &cursor,
+			Limit: newerLimit,
+		})
+		if err != nil {
+			h.handleDBError(c, requestID, err, "messages")
+			return
+		}
+	} else {
+		hasAfter = true
+	}
 
-	c.JSON(http.StatusOK, dto.SuccessWithMeta(
-		messages,
-		dto.NewMeta(page, limit, total),
-	))
+	messages := make([]models.Message, 0, len(newer)+1+len(older))
+	messages = append(messages, newer...)
+	messages = append(messages, *anchor)
+	messages = append(messages, older...)
+
+	cursors := messageCursors(messages)
+	c.JSON(http.StatusOK, dto.SuccessWithCursor(messages, &dto.CursorMeta{
+		Limit:     limit,
+		After:     cursors[0].Encode(),
+		Before:    cursors[len(cursors)-1].Encode(),
+		HasBefore: hasBefore,
+		HasAfter:  hasAfter,
+	}))
+}
+
+// messageCursors cursor của từng message
+func messageCursors(messages []models.Message) []repositories.Cursor {
+	cursors := make([]repositories.Cursor, len(messages))
+	for i := range messages {
+		cursors[i] = repositories.MessageCursor(&messages[i])
+	}
+	return cursors
 }
 
 // SendMessage gửi tin nhắn từ agent
@@ -317,25 +600,81 @@ func (h *ConversationHandler) SendMessage(c *gin.Context) {
 		return
 	}
 
+	// Giới hạn dung lượng request có file đính kèm (thêm 1 MB cho các field khác)
+	if c.ContentType() == "multipart/form-data" {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.mediaService.MaxUploadBytes()+1<<20)
+	}
+
 	var body SendMessageBody
-	if err := c.ShouldBindJSON(&body); err != nil {
+	if err := c.ShouldBind(&body); err != nil {
 		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
 		return
 	}
 
+	// Cửa sổ nhắn tin của channel (VD: Facebook 24 giờ): kiểm tra trước khi lưu file
+	outsideWindow, err := h.outboundService.CheckReply(ctx, conversation, body.MessageTag)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	// Câu trả lời soạn sẵn: thay biến trước khi upload để không lưu file thừa nếu lỗi
+	canned, ok := h.expandCanned(c, conversation, body.CannedResponseID)
+	if !ok {
+		return
+	}
+
+	// File đính kèm (multipart): kiểm tra và lưu vào storage trước khi tạo tin nhắn
+	attachments, ok := h.uploadAttachments(c, conversation)
+	if !ok {
+		return
+	}
+	var quickReplies []models.QuickReply
+	if canned != nil {
+		if strings.TrimSpace(body.Content) == "" {
+			body.Content = canned.Content
+		}
+		attachments = append(append(models.Attachments{}, canned.Attachments...), attachments...)
+		quickReplies = canned.QuickReplies
+	}
+	if strings.TrimSpace(body.Content) == "" && len(attachments) == 0 {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Cần nhập nội dung hoặc đính kèm file"))
+		return
+	}
+
 	// Tạo message mới
 	contentType := models.ContentText
 	if body.ContentType != "" {
 		contentType = models.ContentType(body.ContentType)
 	}
+	if len(attachments) > 0 {
+		contentType = services.AttachmentContentType(attachments)
+	}
+	if len(quickReplies) > 0 {
+		contentType = models.ContentQuickReply
+	}
 
 	message := &models.Message{
 		ConversationID: conversationID,
 		Direction:      models.DirectionOut,
 		SenderType:     models.SenderAgent,
-		Content:        &body.Content,
 		ContentType:    contentType,
-		// TODO: Lấy SenderID từ JWT auth
+		Attachments:    attachments,
+		Metadata: models.MessageMetadata{
+			QuickReplies:  quickReplies,
+			MessageTag:    body.MessageTag,
+			OutsideWindow: outsideWindow,
+		},
+	}
+	if canned != nil {
+		cannedID := canned.CannedResponse.ID
+		message.Metadata.CannedResponseID = &cannedID
+	}
+	if body.Content != "" {
+		message.Content = &body.Content
+	}
+	if actor, ok := currentActor(c); ok {
+		message.SenderID = &actor.UserID
 	}
 
 	if err := h.messageRepo.Create(ctx, message); err != nil {
@@ -346,8 +685,17 @@ func (h *ConversationHandler) SendMessage(c *gin.Context) {
 	// Gửi message qua channel (Facebook, Zalo, etc.)
 	go h.sendToChannel(context.Background(), conversation, message)
 
-	// Cập nhật last message
-	conversation.UpdateLastMessage(body.Content, message.CreatedAt)
+	if canned != nil {
+		h.cannedService.RecordUsage(ctx, canned.CannedResponse.ID)
+	}
+
+	// Cập nhật last message và thời điểm agent trả lời lần đầu (SLA)
+	preview := body.Content
+	if preview == "" {
+		preview = attachments.Preview()
+	}
+	conversation.UpdateLastMessage(preview, message.CreatedAt)
+	conversation.SetFirstResponse(message.CreatedAt)
 	_ = h.conversationRepo.Update(ctx, conversation)
 
 	// Publish realtime event
@@ -379,64 +727,66 @@ func (h *ConversationHandler) SendMessage(c *gin.Context) {
 	c.JSON(http.StatusCreated, dto.Success(message))
 }
 
-// sendToChannel gửi message qua channel tương ứng (FB, Zalo, etc.)
-func (h *ConversationHandler) sendToChannel(ctx context.Context, conv *models.Conversation, msg *models.Message) {
-	// Lấy participant để có recipient ID
-	participant, err := h.participantRepo.FindByID(ctx, conv.ParticipantID)
-	if err != nil {
-		h.logger.Warn("sendToChannel: failed to get participant", zap.Error(err))
-		return
+// uploadAttachments lưu các file trong field files của request multipart
+// Trả về false (đã ghi response lỗi) nếu file không hợp lệ
+func (h *ConversationHandler) uploadAttachments(c *gin.Context, conversation *models.Conversation) (models.Attachments, bool) {
+	if c.ContentType() != "multipart/form-data" {
+		return nil, true
+	}
+	files, closeFiles, ok := readUploadFiles(c)
+	if !ok {
+		return nil, false
+	}
+	defer closeFiles()
+	if len(files) == 0 {
+		return nil, true
 	}
 
-	// Lấy channel account để có credentials và channel type
-	channelAccount, err := h.channelAccountRepo.FindByID(ctx, conv.ChannelAccountID)
-	if err != nil {
-		h.logger.Warn("sendToChannel: failed to get channel account", zap.Error(err))
-		return
+	actor, ok := currentActor(c)
+	if !ok {
+		return nil, false
 	}
 
-	// Lấy channel từ registry
-	ch, err := h.channelRegistry.Get(string(channelAccount.ChannelType))
+	attachments, err := h.mediaService.Upload(c.Request.Context(), actor, conversation, files)
 	if err != nil {
-		h.logger.Warn("sendToChannel: channel not found", zap.String("type", string(channelAccount.ChannelType)))
-		return
+		handleServiceError(c, h.logger, err)
+		return nil, false
 	}
+	return attachments, true
+}
 
-	// Tạo outbound message
-	content := ""
-	if msg.Content != nil {
-		content = *msg.Content
+// expandCanned thay biến câu trả lời soạn sẵn theo conversation (nil nếu không dùng)
+// Trả về false (đã ghi response lỗi) nếu không dùng được câu trả lời
+func (h *ConversationHandler) expandCanned(c *gin.Context, conversation *models.Conversation, rawID string) (*services.CannedExpansion, bool) {
+	if rawID == "" {
+		return nil, true
 	}
-	outbound := &channel.OutboundMessage{
-		RecipientID: participant.ChannelUserID,
-		Content:     content,
-		ContentType: string(msg.ContentType),
+	cannedID, err := uuid.Parse(rawID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "canned_response_id không hợp lệ"))
+		return nil, false
 	}
 
-	// Chuẩn bị credentials
-	credentials := map[string]string{
-		"page_access_token": channelAccount.Credentials.PageAccessToken,
+	actor, ok := currentActor(c)
+	if !ok {
+		return nil, false
 	}
 
-	// Gửi qua channel
-	result, err := ch.Send(ctx, outbound, credentials)
+	expansion, err := h.cannedService.Expand(c.Request.Context(), actor, conversation, cannedID)
 	if err != nil {
-		h.logger.Warn("sendToChannel: send failed", zap.Error(err))
-		return
+		handleServiceError(c, h.logger, err)
+		return nil, false
 	}
+	return expansion, true
+}
 
-	if result.Success {
-		// Cập nhật channel_message_id cho message
-		msg.ChannelMessageID = &result.ChannelMessageID
-		if updateErr := h.messageRepo.Update(ctx, msg); updateErr != nil {
-			h.logger.Warn("sendToChannel: failed to update message ID", zap.Error(updateErr))
-		}
-		h.logger.Info("message sent to channel",
-			zap.String("channel", string(channelAccount.ChannelType)),
-			zap.String("channel_message_id", result.ChannelMessageID),
+// sendToChannel gửi message qua channel tương ứng (FB, Zalo, etc.)
+func (h *ConversationHandler) sendToChannel(ctx context.Context, conv *models.Conversation, msg *models.Message) {
+	if err := h.outboundService.Deliver(ctx, conv, msg); err != nil {
+		h.logger.Warn("sendToChannel: send failed",
+			zap.String("message_id", msg.ID.String()),
+			zap.Error(err),
 		)
-	} else {
-		h.logger.Warn("sendToChannel: channel returned error", zap.Error(result.Error))
 	}
 }
 
@@ -510,10 +860,12 @@ func (h *ConversationHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	conversations := rg.Group("/conversations")
 	{
 		conversations.GET("", h.List)
+		conversations.GET("/unread", h.Unread)
 		conversations.GET("/:id", h.Get)
 		conversations.PATCH("/:id", h.Update)
 		conversations.GET("/:id/messages", h.ListMessages)
 		conversations.POST("/:id/messages", h.SendMessage)
+		conversations.POST("/:id/read", h.MarkRead)
 		conversations.POST("/:id/bot", h.ToggleBot)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// CSAT Handler
+// Cấu hình khảo sát mức độ hài lòng, danh sách câu trả lời và báo cáo
+// ===========================================================================
+
+// CSATHandler xử lý các endpoint CSAT
+type CSATHandler struct {
+	csatService services.CSATService
+	logger      *zap.Logger
+}
+
+// NewCSATHandler tạo CSATHandler mới
+func NewCSATHandler(csatService services.CSATService, logger *zap.Logger) *CSATHandler {
+	return &CSATHandler{
+		csatService: csatService,
+		logger:      logger,
+	}
+}
+
+// UpdateCSATSettingsBody body cấu hình khảo sát
+type UpdateCSATSettingsBody struct {
+	Enabled         bool   `json:"enabled"`
+	Prompt          string `json:"prompt" binding:"max=640"`
+	AskComment      bool   `json:"ask_comment"`
+	CommentPrompt   string `json:"comment_prompt" binding:"max=640"`
+	ThankYouMessage string `json:"thank_you_message" binding:"max=640"`
+	ExpireHours     int    `json:"expire_hours" binding:"min=0,max=720"`
+}
+
+// ListCSATQuery query params danh sách khảo sát
+type ListCSATQuery struct {
+	dto.PaginationRequest
+	AgentID          *uuid.UUID `form:"agent_id"`
+	ChannelAccountID *uuid.UUID `form:"channel_account_id"`
+	From             *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To               *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Score            *int       `form:"score" binding:"omitempty,min=1,max=5"`
+}
+
+// CSATReportQuery query params báo cáo
+type CSATReportQuery struct {
+	GroupBy          string     `form:"group_by" binding:"omitempty,oneof=agent channel_account day week month"`
+	AgentID          *uuid.UUID `form:"agent_id"`
+	ChannelAccountID *uuid.UUID `form:"channel_account_id"`
+	From             *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To               *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// GetSettings lấy cấu hình khảo sát
+// GET /api/v1/csat/settings
+func (h *CSATHandler) GetSettings(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.csatService.GetSettings(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(settings))
+}
+
+// UpdateSettings cập nhật cấu hình khảo sát (admin)
+// PUT /api/v1/csat/settings
+func (h *CSATHandler) UpdateSettings(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body UpdateCSATSettingsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	updated, err := h.csatService.UpdateSettings(c.Request.Context(), actor, models.CSATSettings{
+		Enabled:         body.Enabled,
+		Prompt:          body.Prompt,
+		AskComment:      body.AskComment,
+		CommentPrompt:   body.CommentPrompt,
+		ThankYouMessage: body.ThankYouMessage,
+		ExpireHours:     body.ExpireHours,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(updated))
+}
+
+// ListResponses lấy danh sách khảo sát
+// GET /api/v1/csat/responses?agent_id=&channel_account_id=&from=&to=&score=&page=1&limit=20
+func (h *CSATHandler) ListResponses(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query ListCSATQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	query.SetDefaults()
+
+	responses, total, err := h.csatService.ListResponses(c.Request.Context(), actor, services.CSATListInput{
+		AgentID:          query.AgentID,
+		ChannelAccountID: query.ChannelAccountID,
+		From:             query.From,
+		To:               query.To,
+		Score:            query.Score,
+		Offset:           query.Offset(),
+		Limit:            query.Limit,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMeta(responses, dto.NewMeta(query.Page, query.Limit, total)))
+}
+
+// Report báo cáo CSAT
+// GET /api/v1/csat/report?group_by=agent|channel_account|day|week|month&from=&to=
+func (h *CSATHandler) Report(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query CSATReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+
+	report, err := h.csatService.Report(c.Request.Context(), actor, services.CSATReportInput{
+		From:             query.From,
+		To:               query.To,
+		AgentID:          query.AgentID,
+		ChannelAccountID: query.ChannelAccountID,
+		GroupBy:          repositories.CSATGroupBy(query.GroupBy),
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(report))
+}
+
+// RegisterRoutes đăng ký routes cho CSAT handler
+func (h *CSATHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	csat := rg.Group("/csat")
+	{
+		csat.GET("/settings", h.GetSettings)    // Cấu hình khảo sát
+		csat.PUT("/settings", h.UpdateSettings) // Cập nhật cấu hình
+		csat.GET("/responses", h.ListResponses) // Danh sách câu trả lời
+		csat.GET("/report", h.Report)           // Báo cáo theo agent/kênh/kỳ
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Custom Field Handler
+// Admin định nghĩa trường tùy chỉnh của khách (text, number, date, select, boolean)
+// Dashboard dùng danh sách này để hiển thị form sửa khách và bộ lọc
+// ===========================================================================
+
+// CustomFieldHandler xử lý các endpoint trường tùy chỉnh
+type CustomFieldHandler struct {
+	fieldService services.CustomFieldService
+	logger       *zap.Logger
+}
+
+// NewCustomFieldHandler tạo CustomFieldHandler mới
+func NewCustomFieldHandler(fieldService services.CustomFieldService, logger *zap.Logger) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		fieldService: fieldService,
+		logger:       logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// CreateCustomFieldBody body tạo trường tùy chỉnh
+type CreateCustomFieldBody struct {
+	Key      string                 `json:"key" binding:"required,min=1,max=50"`
+	Label    string                 `json:"label" binding:"required,min=1,max=255"`
+	Type     models.CustomFieldType `json:"type" binding:"required,oneof=text number date select boolean"`
+	Required bool                   `json:"required"`
+	Options  []string               `json:"options" binding:"max=100"`
+	Position int                    `json:"position"`
+}
+
+// UpdateCustomFieldBody body sửa trường tùy chỉnh (key và type không đổi được)
+type UpdateCustomFieldBody struct {
+	Label    *string   `json:"label" binding:"omitempty,min=1,max=255"`
+	Required *bool     `json:"required"`
+	Options  *[]string `json:"options" binding:"omitempty,max=100"`
+	Position *int      `json:"position"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// List lấy danh sách trường tùy chỉnh của workspace
+// GET /api/v1/custom-fields
+func (h *CustomFieldHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	fields, err := h.fieldService.List(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"custom_fields": fields,
+		"total":         len(fields),
+	}))
+}
+
+// Create tạo trường tùy chỉnh (admin)
+// POST /api/v1/custom-fields
+func (h *CustomFieldHandler) Create(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body CreateCustomFieldBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	field, err := h.fieldService.Create(c.Request.Context(), actor, services.CustomFieldInput{
+		Key:      body.Key,
+		Label:    body.Label,
+		Type:     body.Type,
+		Required: body.Required,
+		Options:  body.Options,
+		Position: body.Position,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("custom field created",
+		zap.String("request_id", requestID),
+		zap.String("field_id", field.ID.String()),
+	)
+
+	c.JSON(http.StatusCreated, dto.Success(field))
+}
+
+// Update sửa trường tùy chỉnh (admin)
+// PATCH /api/v1/custom-fields/:id
+func (h *CustomFieldHandler) Update(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body UpdateCustomFieldBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	field, err := h.fieldService.Update(c.Request.Context(), actor, id, services.UpdateCustomFieldInput{
+		Label:    body.Label,
+		Required: body.Required,
+		Options:  body.Options,
+		Position: body.Position,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(field))
+}
+
+// Delete xóa trường tùy chỉnh (admin), giá trị đã lưu của khách được giữ nguyên
+// DELETE /api/v1/custom-fields/:id
+func (h *CustomFieldHandler) Delete(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.fieldService.Delete(c.Request.Context(), actor, id); err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"message": "Đã xóa trường tùy chỉnh",
+	}))
+}
+
+// parseID parse UUID từ path param
+func (h *CustomFieldHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", param+" không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho custom field handler
+func (h *CustomFieldHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	fields := rg.Group("/custom-fields")
+	{
+		fields.GET("", h.List)          // Danh sách trường
+		fields.POST("", h.Create)       // Tạo trường (admin)
+		fields.PATCH("/:id", h.Update)  // Sửa (admin)
+		fields.DELETE("/:id", h.Delete) // Xóa (admin)
+	}
+}
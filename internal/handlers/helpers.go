@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"chatbox-gin/internal/dto"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Handler Helpers
+// Các hàm dùng chung cho handlers gọi qua service layer
+// ===========================================================================
+
+// currentActor lấy thông tin user đang đăng nhập từ JWT context
+// Trả về false (và đã ghi response 401) nếu không có
+func currentActor(c *gin.Context) (services.Actor, bool) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error("UNAUTHORIZED", "Chưa đăng nhập"))
+		return services.Actor{}, false
+	}
+	workspaceID, _ := middleware.GetWorkspaceID(c)
+	role, _ := middleware.GetUserRole(c)
+	return services.Actor{
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Role:        role,
+	}, true
+}
+
+// handleServiceError map lỗi từ service layer sang HTTP response
+// AppError được trả về nguyên message, lỗi khác trả về 500 và ghi log
+func handleServiceError(c *gin.Context, logger *zap.Logger, err error) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		c.JSON(appErr.StatusCode, dto.Error(appErr.Code, appErr.Message))
+		return
+	}
+
+	logger.Error("service error",
+		zap.String("request_id", middleware.GetRequestID(c)),
+		zap.Error(err),
+	)
+	c.JSON(http.StatusInternalServerError, dto.Error("INTERNAL_ERROR", "Đã có lỗi xảy ra. Vui lòng thử lại sau."))
+}
+
+// readUploadFiles mở các file trong field files của request multipart
+// Caller gọi hàm close trả về sau khi dùng xong; trả về false (đã ghi response lỗi) nếu không đọc được
+func readUploadFiles(c *gin.Context) ([]services.UploadFile, func(), bool) {
+	var opened []multipart.File
+	closeAll := func() {
+		for _, f := range opened {
+			f.Close()
+		}
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return nil, closeAll, false
+	}
+
+	headers := form.File["files"]
+	files := make([]services.UploadFile, 0, len(headers))
+	for _, header := range headers {
+		f, err := header.Open()
+		if err != nil {
+			closeAll()
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Không đọc được file "+header.Filename))
+			return nil, func() {}, false
+		}
+		opened = append(opened, f)
+		files = append(files, services.UploadFile{
+			Name:        header.Filename,
+			Size:        header.Size,
+			ContentType: header.Header.Get("Content-Type"),
+			Body:        f,
+		})
+	}
+	return files, closeAll, true
+}
+
+// parseCursorQuery đọc before/after cursor từ query params
+// Trả về false (đã ghi response lỗi) nếu cursor không hợp lệ
+func parseCursorQuery(c *gin.Context, before, after string, limit int) (repositories.CursorQuery, bool) {
+	query := repositories.CursorQuery{Limit: limit}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	if before != "" && after != "" {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Chỉ dùng một trong before hoặc after"))
+		return query, false
+	}
+	for _, param := range []struct {
+		value  string
+		target **repositories.Cursor
+	}{{before, &query.Before}, {after, &query.After}} {
+		if param.value == "" {
+			continue
+		}
+		cursor, err := repositories.ParseCursor(param.value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Cursor không hợp lệ"))
+			return query, false
+		}
+		*param.target = &cursor
+	}
+	return query, true
+}
+
+// newCursorMeta tạo thông tin cursor cho trang kết quả (mới nhất trước)
+// hasMore là cờ còn dữ liệu theo hướng đang đi của query
+func newCursorMeta(query repositories.CursorQuery, cursors []repositories.Cursor, hasMore bool) *dto.CursorMeta {
+	meta := &dto.CursorMeta{Limit: query.Limit}
+
+	switch {
+	case query.After != nil:
+		// Đi tới: bản ghi tại cursor luôn cũ hơn trang này
+		meta.HasAfter = hasMore
+		meta.HasBefore = true
+	case query.Before != nil:
+		meta.HasBefore = hasMore
+		meta.HasAfter = true
+	default:
+		meta.HasBefore = hasMore
+	}
+
+	if len(cursors) == 0 {
+		// Trang rỗng khi poll tin mới: giữ nguyên vị trí để client poll tiếp
+		if query.After != nil {
+			meta.After = query.After.Encode()
+		}
+		return meta
+	}
+
+	meta.After = cursors[0].Encode()
+	if meta.HasBefore {
+		meta.Before = cursors[len(cursors)-1].Encode()
+	}
+	return meta
+}
+
+// fieldQueryPrefix tiền tố query param lọc theo trường tùy chỉnh
+// field.<key>=<giá trị> so sánh bằng, field.<key>.<toán tử>=<giá trị> cho toán tử khác
+// (VD: field.plan=gold, field.spent.gte=1000000, field.city.in=HN,HCM, field.email_verified.exists=true)
+const fieldQueryPrefix = "field."
+
+// parseFieldFilters đọc điều kiện lọc theo trường tùy chỉnh từ query params
+// Giá trị được chuẩn hóa theo kiểu trường ở service layer
+func parseFieldFilters(c *gin.Context) []models.FieldCondition {
+	var conditions []models.FieldCondition
+	for param, values := range c.Request.URL.Query() {
+		rest, ok := strings.CutPrefix(param, fieldQueryPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		key, op, _ := strings.Cut(rest, ".")
+		cond := models.FieldCondition{Field: key, Operator: models.FieldOperator(op), Value: values[0]}
+
+		// exists=false tương đương not_exists
+		if cond.Operator == models.OpExists && strings.EqualFold(values[0], "false") {
+			cond.Operator = models.OpNotExists
+		}
+		conditions = append(conditions, cond)
+	}
+	// Thứ tự ổn định để câu truy vấn giống nhau giữa các request
+	sort.Slice(conditions, func(i, j int) bool {
+		if conditions[i].Field != conditions[j].Field {
+			return conditions[i].Field < conditions[j].Field
+		}
+		return conditions[i].Operator < conditions[j].Operator
+	})
+	return conditions
+}
+
+// parseFieldSort đọc sort theo trường tùy chỉnh: field.<key> tăng dần, -field.<key> giảm dần
+// Trả về false nếu sort không phải theo trường tùy chỉnh
+func parseFieldSort(value string) (string, bool, bool) {
+	desc := strings.HasPrefix(value, "-")
+	key, ok := strings.CutPrefix(strings.TrimPrefix(value, "-"), fieldQueryPrefix)
+	if !ok || key == "" {
+		return "", false, false
+	}
+	return key, desc, true
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Macro Handler
+// Admin định nghĩa macro (gửi trả lời, gắn tag, đổi ưu tiên, assign, đóng, ...)
+// Agent chạy macro trên hội thoại bằng một request
+// ===========================================================================
+
+// MacroHandler xử lý các endpoint macro
+type MacroHandler struct {
+	macroService services.MacroService
+	logger       *zap.Logger
+}
+
+// NewMacroHandler tạo MacroHandler mới
+func NewMacroHandler(macroService services.MacroService, logger *zap.Logger) *MacroHandler {
+	return &MacroHandler{
+		macroService: macroService,
+		logger:       logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// ListMacrosQuery query danh sách macro
+type ListMacrosQuery struct {
+	Active bool `form:"active"`
+}
+
+// CreateMacroBody body tạo macro
+type CreateMacroBody struct {
+	Name        string               `json:"name" binding:"required,min=1,max=255"`
+	Description string               `json:"description" binding:"max=2000"`
+	Actions     []models.MacroAction `json:"actions" binding:"required,min=1"`
+	IsActive    *bool                `json:"is_active"`
+}
+
+// UpdateMacroBody body sửa macro
+type UpdateMacroBody struct {
+	Name        *string               `json:"name" binding:"omitempty,min=1,max=255"`
+	Description *string               `json:"description" binding:"omitempty,max=2000"`
+	Actions     *[]models.MacroAction `json:"actions"`
+	IsActive    *bool                 `json:"is_active"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// List lấy danh sách macro của workspace
+// GET /api/v1/macros?active=true
+func (h *MacroHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query ListMacrosQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+
+	macros, err := h.macroService.List(c.Request.Context(), actor, query.Active)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"macros": macros,
+		"total":  len(macros),
+	}))
+}
+
+// Get lấy chi tiết macro
+// GET /api/v1/macros/:id
+func (h *MacroHandler) Get(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	macro, err := h.macroService.Get(c.Request.Context(), actor, id)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(macro))
+}
+
+// Create tạo macro (admin)
+// POST /api/v1/macros
+func (h *MacroHandler) Create(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body CreateMacroBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	macro, err := h.macroService.Create(c.Request.Context(), actor, services.MacroInput{
+		Name:        body.Name,
+		Description: body.Description,
+		Actions:     body.Actions,
+		IsActive:    body.IsActive,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("macro created",
+		zap.String("request_id", requestID),
+		zap.String("macro_id", macro.ID.String()),
+	)
+
+	c.JSON(http.StatusCreated, dto.Success(macro))
+}
+
+// Update sửa macro (admin)
+// PATCH /api/v1/macros/:id
+func (h *MacroHandler) Update(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body UpdateMacroBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	macro, err := h.macroService.Update(c.Request.Context(), actor, id, services.UpdateMacroInput{
+		Name:        body.Name,
+		Description: body.Description,
+		Actions:     body.Actions,
+		IsActive:    body.IsActive,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(macro))
+}
+
+// Delete xóa macro (admin)
+// DELETE /api/v1/macros/:id
+func (h *MacroHandler) Delete(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.macroService.Delete(c.Request.Context(), actor, id); err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"message": "Đã xóa macro",
+	}))
+}
+
+// Execute chạy macro trên conversation
+// POST /api/v1/conversations/:id/macros/:macroId
+func (h *MacroHandler) Execute(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	macroID, ok := h.parseID(c, "macroId")
+	if !ok {
+		return
+	}
+
+	result, err := h.macroService.Execute(c.Request.Context(), actor, conversationID, macroID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("macro executed",
+		zap.String("request_id", requestID),
+		zap.String("macro_id", macroID.String()),
+		zap.String("conversation_id", conversationID.String()),
+	)
+
+	c.JSON(http.StatusOK, dto.Success(result))
+}
+
+// parseID parse UUID từ path param
+func (h *MacroHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", param+" không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho macro handler
+func (h *MacroHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	macros := rg.Group("/macros")
+	{
+		macros.GET("", h.List)          // Danh sách macro
+		macros.POST("", h.Create)       // Tạo macro (admin)
+		macros.GET("/:id", h.Get)       // Chi tiết
+		macros.PATCH("/:id", h.Update)  // Sửa (admin)
+		macros.DELETE("/:id", h.Delete) // Xóa (admin)
+	}
+
+	rg.POST("/conversations/:id/macros/:macroId", h.Execute) // Chạy macro trên hội thoại
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/media"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Media Handler
+// Trả về file đính kèm đã lưu trong storage cho dashboard
+// Cần đăng nhập (cookie access_token dùng được cho thẻ <img>) và đúng workspace
+// ===========================================================================
+
+// MediaHandler xử lý endpoint tải file
+type MediaHandler struct {
+	mediaService services.MediaService
+	logger       *zap.Logger
+}
+
+// NewMediaHandler tạo MediaHandler mới
+func NewMediaHandler(mediaService services.MediaService, logger *zap.Logger) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		logger:       logger,
+	}
+}
+
+// Get trả về nội dung file
+// GET /api/v1/media/:id?thumbnail=true&download=true
+func (h *MediaHandler) Get(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	mediaID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Media ID không hợp lệ"))
+		return
+	}
+	thumbnail := c.Query("thumbnail") == "true"
+
+	content, err := h.mediaService.Open(c.Request.Context(), actor, mediaID, thumbnail)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	// File chưa tải xong: chuyển sang URL gốc của channel
+	if content.RedirectURL != "" {
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, content.RedirectURL)
+		return
+	}
+	defer content.Body.Close()
+
+	// Chỉ hiển thị trực tiếp ảnh/video/audio, file khác (HTML, SVG, ...) luôn tải về
+	disposition := "inline"
+	if c.Query("download") == "true" || media.KindOf(content.ContentType) == "file" || content.ContentType == "image/svg+xml" {
+		disposition = "attachment"
+	}
+	if formatted := mime.FormatMediaType(disposition, map[string]string{"filename": content.Media.Name}); content.Media.Name != "" && formatted != "" {
+		disposition = formatted
+	}
+
+	// File đã lưu không thay đổi nên cho phép trình duyệt cache (riêng tư, cần đăng nhập)
+	etag := fmt.Sprintf(`"%s-%t"`, content.Media.ID, thumbnail)
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Header("ETag", etag)
+	c.Header("X-Content-Type-Options", "nosniff")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, content.Size, content.ContentType, content.Body, map[string]string{
+		"Content-Disposition": disposition,
+	})
+}
+
+// RegisterRoutes đăng ký routes cho media handler
+func (h *MediaHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/media/:id", h.Get) // Tải file đính kèm / ảnh thu nhỏ
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Note Handler
+// Ghi chú nội bộ trên conversation (khách hàng không thấy)
+// Tác giả lấy từ JWT, hỗ trợ @mention để thông báo cho agent khác
+// ===========================================================================
+
+// NoteHandler xử lý các endpoint notes
+type NoteHandler struct {
+	noteService services.NoteService
+	logger      *zap.Logger
+}
+
+// NewNoteHandler tạo NoteHandler mới
+func NewNoteHandler(noteService services.NoteService, logger *zap.Logger) *NoteHandler {
+	return &NoteHandler{
+		noteService: noteService,
+		logger:      logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// CreateNoteBody body tạo note
+type CreateNoteBody struct {
+	Content  string      `json:"content" binding:"required,min=1,max=5000"`
+	Mentions []uuid.UUID `json:"mentions"`
+	IsPinned bool        `json:"is_pinned"`
+}
+
+// UpdateNoteBody body sửa note
+type UpdateNoteBody struct {
+	Content  *string      `json:"content" binding:"omitempty,min=1,max=5000"`
+	Mentions *[]uuid.UUID `json:"mentions"`
+}
+
+// PinNoteBody body ghim/bỏ ghim note
+type PinNoteBody struct {
+	Pinned bool `json:"pinned"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// List lấy danh sách notes của conversation
+// GET /api/v1/conversations/:id/notes
+func (h *NoteHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return
+	}
+
+	notes, err := h.noteService.List(c.Request.Context(), actor, conversationID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"notes": notes,
+		"total": len(notes),
+	}))
+}
+
+// Create tạo note mới
+// POST /api/v1/conversations/:id/notes
+func (h *NoteHandler) Create(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return
+	}
+
+	var body CreateNoteBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	note, err := h.noteService.Create(c.Request.Context(), actor, services.CreateNoteInput{
+		ConversationID: conversationID,
+		Content:        body.Content,
+		Mentions:       body.Mentions,
+		IsPinned:       body.IsPinned,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("note created",
+		zap.String("request_id", requestID),
+		zap.String("note_id", note.ID.String()),
+	)
+
+	c.JSON(http.StatusCreated, dto.Success(note))
+}
+
+// Update sửa note
+// PATCH /api/v1/conversations/:id/notes/:noteId
+func (h *NoteHandler) Update(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, noteID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var body UpdateNoteBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	note, err := h.noteService.Update(c.Request.Context(), actor, conversationID, noteID, services.UpdateNoteInput{
+		Content:  body.Content,
+		Mentions: body.Mentions,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(note))
+}
+
+// Delete xóa note
+// DELETE /api/v1/conversations/:id/notes/:noteId
+func (h *NoteHandler) Delete(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, noteID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.noteService.Delete(c.Request.Context(), actor, conversationID, noteID); err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"message": "Đã xóa ghi chú",
+	}))
+}
+
+// Pin ghim/bỏ ghim note
+// POST /api/v1/conversations/:id/notes/:noteId/pin
+func (h *NoteHandler) Pin(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, noteID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var body PinNoteBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	note, err := h.noteService.SetPinned(c.Request.Context(), actor, conversationID, noteID, body.Pinned)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(note))
+}
+
+// parseIDs parse conversation ID và note ID từ path
+func (h *NoteHandler) parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return uuid.Nil, uuid.Nil, false
+	}
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Note ID không hợp lệ"))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return conversationID, noteID, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho note handler
+func (h *NoteHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	notes := rg.Group("/conversations/:id/notes")
+	{
+		notes.GET("", h.List)              // Danh sách notes
+		notes.POST("", h.Create)           // Tạo note
+		notes.PATCH("/:noteId", h.Update)  // Sửa note
+		notes.DELETE("/:noteId", h.Delete) // Xóa note
+		notes.POST("/:noteId/pin", h.Pin)  // Ghim/bỏ ghim
+	}
+}
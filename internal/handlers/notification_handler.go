@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Notification Handler
+// Danh sách thông báo của user đang đăng nhập
+// ===========================================================================
+
+// NotificationHandler xử lý các endpoint notifications
+type NotificationHandler struct {
+	notificationRepo repositories.NotificationRepository
+	logger           *zap.Logger
+}
+
+// NewNotificationHandler tạo NotificationHandler mới
+func NewNotificationHandler(notificationRepo repositories.NotificationRepository, logger *zap.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationRepo: notificationRepo,
+		logger:           logger,
+	}
+}
+
+// ListNotificationsQuery query params cho list notifications
+type ListNotificationsQuery struct {
+	dto.PaginationRequest
+	UnreadOnly bool `form:"unread_only"`
+}
+
+// List lấy danh sách thông báo của user hiện tại
+// GET /api/v1/notifications?unread_only=true&page=1&limit=20
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error("UNAUTHORIZED", "Chưa đăng nhập"))
+		return
+	}
+
+	var query ListNotificationsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+	query.SetDefaults()
+
+	ctx := c.Request.Context()
+	notifications, total, err := h.notificationRepo.FindByUser(ctx, userID, query.UnreadOnly, repositories.FindOptions{
+		Offset: query.Offset(),
+		Limit:  query.Limit,
+	})
+	if err != nil {
+		h.logger.Error("failed to list notifications", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.Error("DB_ERROR", "Không thể lấy danh sách thông báo"))
+		return
+	}
+
+	unread, err := h.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		h.logger.Warn("failed to count unread notifications", zap.Error(err))
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMeta(gin.H{
+		"notifications": notifications,
+		"unread":        unread,
+	}, dto.NewMeta(query.Page, query.Limit, total)))
+}
+
+// MarkRead đánh dấu một thông báo đã đọc
+// POST /api/v1/notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error("UNAUTHORIZED", "Chưa đăng nhập"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Notification ID không hợp lệ"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	notification, err := h.notificationRepo.FindByID(ctx, id)
+	if err != nil || notification.UserID != userID {
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			h.logger.Error("failed to find notification", zap.Error(err))
+		}
+		c.JSON(http.StatusNotFound, dto.Error("NOT_FOUND", "Không tìm thấy thông báo"))
+		return
+	}
+
+	if err := h.notificationRepo.MarkAsRead(ctx, id); err != nil {
+		h.logger.Error("failed to mark notification read", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.Error("DB_ERROR", "Không thể cập nhật thông báo"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{"message": "Đã đánh dấu đã đọc"}))
+}
+
+// MarkAllRead đánh dấu tất cả thông báo đã đọc
+// POST /api/v1/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.Error("UNAUTHORIZED", "Chưa đăng nhập"))
+		return
+	}
+
+	if err := h.notificationRepo.MarkAllAsRead(c.Request.Context(), userID); err != nil {
+		h.logger.Error("failed to mark all notifications read", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.Error("DB_ERROR", "Không thể cập nhật thông báo"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{"message": "Đã đánh dấu tất cả đã đọc"}))
+}
+
+// RegisterRoutes đăng ký routes cho notification handler
+func (h *NotificationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	notifications := rg.Group("/notifications")
+	{
+		notifications.GET("", h.List)
+		notifications.POST("/read-all", h.MarkAllRead)
+		notifications.POST("/:id/read", h.MarkRead)
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Presence Handler
+// Trạng thái làm việc của agent và tình trạng sẵn sàng của team
+// Dashboard gửi heartbeat định kỳ, hệ thống tự chuyển away khi idle
+// ===========================================================================
+
+// PresenceHandler xử lý các endpoint presence
+type PresenceHandler struct {
+	presenceService services.PresenceService
+	logger          *zap.Logger
+}
+
+// NewPresenceHandler tạo PresenceHandler mới
+func NewPresenceHandler(presenceService services.PresenceService, logger *zap.Logger) *PresenceHandler {
+	return &PresenceHandler{
+		presenceService: presenceService,
+		logger:          logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// SetStatusBody body đổi trạng thái
+type SetStatusBody struct {
+	Status string `json:"status" binding:"required,oneof=online away offline"`
+}
+
+// SetCapacityBody body đặt capacity cho agent
+type SetCapacityBody struct {
+	MaxConcurrent int `json:"max_concurrent" binding:"min=0,max=1000"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// Heartbeat ghi nhận agent còn hoạt động
+// POST /api/v1/presence/heartbeat
+func (h *PresenceHandler) Heartbeat(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.presenceService.Heartbeat(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(presenceResponse(user)))
+}
+
+// SetStatus agent đổi trạng thái
+// PUT /api/v1/presence/status
+func (h *PresenceHandler) SetStatus(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body SetStatusBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	user, err := h.presenceService.SetStatus(c.Request.Context(), actor, models.AgentStatus(body.Status))
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(presenceResponse(user)))
+}
+
+// TeamAvailability danh sách trạng thái của team
+// GET /api/v1/team/availability
+func (h *PresenceHandler) TeamAvailability(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	team, err := h.presenceService.TeamAvailability(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(team))
+}
+
+// SetCapacity đặt capacity cho agent (admin)
+// PUT /api/v1/team/agents/:userId/capacity
+func (h *PresenceHandler) SetCapacity(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "User ID không hợp lệ"))
+		return
+	}
+
+	var body SetCapacityBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	user, err := h.presenceService.SetCapacity(c.Request.Context(), actor, userID, body.MaxConcurrent)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"user_id":        user.ID,
+		"max_concurrent": user.MaxConcurrent,
+	}))
+}
+
+// presenceResponse dữ liệu trạng thái trả về cho agent
+func presenceResponse(user *models.User) gin.H {
+	return gin.H{
+		"user_id":           user.ID,
+		"status":            user.Status,
+		"status_auto":       user.StatusAuto,
+		"status_changed_at": user.StatusChangedAt,
+		"last_seen_at":      user.LastSeenAt,
+	}
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho presence handler
+func (h *PresenceHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	presence := rg.Group("/presence")
+	{
+		presence.POST("/heartbeat", h.Heartbeat) // Heartbeat từ dashboard
+		presence.PUT("/status", h.SetStatus)     // Đổi trạng thái
+	}
+
+	team := rg.Group("/team")
+	{
+		team.GET("/availability", h.TeamAvailability)       // Trạng thái team
+		team.PUT("/agents/:userId/capacity", h.SetCapacity) // Capacity agent
+	}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Routing Handler
+// Phân công tự động: chạy routing thủ công, xem routing log,
+// cấu hình chiến lược của workspace và kỹ năng của agent
+// ===========================================================================
+
+// RoutingHandler xử lý các endpoint routing
+type RoutingHandler struct {
+	routingService services.RoutingService
+	logger         *zap.Logger
+}
+
+// NewRoutingHandler tạo RoutingHandler mới
+func NewRoutingHandler(routingService services.RoutingService, logger *zap.Logger) *RoutingHandler {
+	return &RoutingHandler{
+		routingService: routingService,
+		logger:         logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// UpdateRoutingSettingsBody body cấu hình routing
+type UpdateRoutingSettingsBody struct {
+	Enabled                 bool   `json:"enabled"`
+	Strategy                string `json:"strategy" binding:"omitempty,oneof=round_robin least_open skill"`
+	Sticky                  bool   `json:"sticky"`
+	AssignOnNewConversation bool   `json:"assign_on_new_conversation"`
+	AssignOnHandoff         bool   `json:"assign_on_handoff"`
+	MaxConcurrent           int    `json:"max_concurrent" binding:"min=0,max=1000"`
+	IncludeAdmins           bool   `json:"include_admins"`
+}
+
+// UpdateAgentSkillsBody body cập nhật kỹ năng agent
+type UpdateAgentSkillsBody struct {
+	Skills []string `json:"skills" binding:"max=50,dive,max=100"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// Route chạy routing thủ công cho conversation
+// POST /api/v1/conversations/:id/route
+func (h *RoutingHandler) Route(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return
+	}
+
+	log, err := h.routingService.Route(c.Request.Context(), actor, conversationID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(log))
+}
+
+// ListLogs lấy lịch sử routing của conversation
+// GET /api/v1/conversations/:id/routing-logs
+func (h *RoutingHandler) ListLogs(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return
+	}
+
+	logs, err := h.routingService.ListLogs(c.Request.Context(), actor, conversationID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(logs))
+}
+
+// GetSettings lấy cấu hình routing của workspace
+// GET /api/v1/routing/settings
+func (h *RoutingHandler) GetSettings(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.routingService.GetSettings(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(settings))
+}
+
+// UpdateSettings cập nhật cấu hình routing (admin)
+// PUT /api/v1/routing/settings
+func (h *RoutingHandler) UpdateSettings(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body UpdateRoutingSettingsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	settings, err := h.routingService.UpdateSettings(c.Request.Context(), actor, models.RoutingSettings{
+		Enabled:                 body.Enabled,
+		Strategy:                models.RoutingStrategy(body.Strategy),
+		Sticky:                  body.Sticky,
+		AssignOnNewConversation: body.AssignOnNewConversation,
+		AssignOnHandoff:         body.AssignOnHandoff,
+		MaxConcurrent:           body.MaxConcurrent,
+		IncludeAdmins:           body.IncludeAdmins,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(settings))
+}
+
+// UpdateAgentSkills cập nhật kỹ năng agent (admin)
+// PUT /api/v1/routing/agents/:userId/skills
+func (h *RoutingHandler) UpdateAgentSkills(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "User ID không hợp lệ"))
+		return
+	}
+
+	var body UpdateAgentSkillsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	user, err := h.routingService.UpdateAgentSkills(c.Request.Context(), actor, userID, body.Skills)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"user_id": user.ID,
+		"skills":  user.Skills,
+	}))
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho routing handler
+func (h *RoutingHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/conversations/:id/route", h.Route)          // Routing thủ công
+	rg.GET("/conversations/:id/routing-logs", h.ListLogs) // Lịch sử routing
+
+	routing := rg.Group("/routing")
+	{
+		routing.GET("/settings", h.GetSettings)                    // Cấu hình routing
+		routing.PUT("/settings", h.UpdateSettings)                 // Cập nhật cấu hình
+		routing.PUT("/agents/:userId/skills", h.UpdateAgentSkills) // Kỹ năng agent
+	}
+}
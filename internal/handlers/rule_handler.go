@@ -7,6 +7,8 @@ import (
 	"chatbox-gin/internal/middleware"
 	"chatbox-gin/internal/models"
 	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/services"
+	"chatbox-gin/internal/template"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,15 +23,17 @@ import (
 
 // RuleHandler xử lý các endpoint liên quan đến Rules
 type RuleHandler struct {
-	ruleRepo repositories.RuleRepository
-	logger   *zap.Logger
+	ruleRepo     repositories.RuleRepository
+	fieldService services.CustomFieldService
+	logger       *zap.Logger
 }
 
 // NewRuleHandler tạo RuleHandler mới
-func NewRuleHandler(ruleRepo repositories.RuleRepository, logger *zap.Logger) *RuleHandler {
+func NewRuleHandler(ruleRepo repositories.RuleRepository, fieldService services.CustomFieldService, logger *zap.Logger) *RuleHandler {
 	return &RuleHandler{
-		ruleRepo: ruleRepo,
-		logger:   logger,
+		ruleRepo:     ruleRepo,
+		fieldService: fieldService,
+		logger:       logger,
 	}
 }
 
@@ -166,6 +170,9 @@ func (h *RuleHandler) Create(c *gin.Context) {
 	if req.Description != "" {
 		rule.Description = &req.Description
 	}
+	if !h.validateFields(c, rule) {
+		return
+	}
 
 	if err := h.ruleRepo.Create(ctx, rule); err != nil {
 		h.logger.Error("failed to create rule",
@@ -235,6 +242,9 @@ func (h *RuleHandler) Update(c *gin.Context) {
 	if req.IsActive != nil {
 		rule.IsActive = *req.IsActive
 	}
+	if !h.validateFields(c, rule) {
+		return
+	}
 
 	if err := h.ruleRepo.Update(ctx, rule); err != nil {
 		h.logger.Error("failed to update rule",
@@ -253,6 +263,43 @@ func (h *RuleHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.Success(rule))
 }
 
+// validateFields kiểm tra điều kiện và giá trị trường tùy chỉnh của rule theo định nghĩa của workspace
+// Giá trị được chuẩn hóa theo kiểu trường trước khi lưu; trả về false (đã ghi response lỗi) nếu không hợp lệ
+func (h *RuleHandler) validateFields(c *gin.Context, rule *models.Rule) bool {
+	ctx := c.Request.Context()
+
+	conditions, err := h.fieldService.ParseConditions(ctx, rule.WorkspaceID, rule.TriggerConfig.Conditions)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return false
+	}
+	rule.TriggerConfig.Conditions = conditions
+
+	values, err := h.fieldService.ParseValues(ctx, rule.WorkspaceID, rule.ResponseConfig.SetFields)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return false
+	}
+	rule.ResponseConfig.SetFields = values
+
+	if rule.ResponseType == models.ResponseTemplate {
+		for _, name := range template.Names(rule.ResponseConfig.Text) {
+			if _, ok := ruleTemplateVariables[name]; !ok && !template.IsFieldVariable(name) {
+				c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Biến {{"+name+"}} không được hỗ trợ"))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ruleTemplateVariables các biến dùng được trong response kiểu template (ngoài contact.fields.<key>)
+var ruleTemplateVariables = map[string]bool{
+	"contact.name":  true,
+	"contact.email": true,
+	"contact.phone": true,
+}
+
 // Delete xóa rule (soft delete)
 // DELETE /api/v1/rules/:id
 func (h *RuleHandler) Delete(c *gin.Context) {
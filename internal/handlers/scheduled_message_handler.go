@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/middleware"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Scheduled Message Handler
+// Hẹn giờ gửi tin nhắn trong hội thoại, xem và hủy tin nhắn đang chờ
+// ===========================================================================
+
+// ScheduledMessageHandler xử lý các endpoint tin nhắn hẹn giờ
+type ScheduledMessageHandler struct {
+	scheduledService services.ScheduledMessageService
+	logger           *zap.Logger
+}
+
+// NewScheduledMessageHandler tạo ScheduledMessageHandler mới
+func NewScheduledMessageHandler(scheduledService services.ScheduledMessageService, logger *zap.Logger) *ScheduledMessageHandler {
+	return &ScheduledMessageHandler{
+		scheduledService: scheduledService,
+		logger:           logger,
+	}
+}
+
+// ===========================================================================
+// Request DTOs
+// ===========================================================================
+
+// ListScheduledMessagesQuery query danh sách tin nhắn hẹn giờ
+type ListScheduledMessagesQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending sending sent cancelled failed"`
+}
+
+// ScheduleMessageBody body hẹn giờ gửi tin nhắn
+type ScheduleMessageBody struct {
+	Content string `json:"content" binding:"required,max=5000"`
+
+	// SendAt RFC3339 có múi giờ, hoặc giờ địa phương của workspace (2026-10-19T09:00)
+	SendAt string `json:"send_at" binding:"required"`
+
+	// MessageTag gửi khi đến giờ khách đã ngoài cửa sổ nhắn tin của channel (VD: HUMAN_AGENT)
+	MessageTag string `json:"message_tag" binding:"omitempty,max=50"`
+}
+
+// ===========================================================================
+// Handlers
+// ===========================================================================
+
+// List lấy tin nhắn hẹn giờ của hội thoại
+// GET /api/v1/conversations/:id/scheduled-messages?status=pending
+func (h *ScheduledMessageHandler) List(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var query ListScheduledMessagesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+
+	scheduled, err := h.scheduledService.List(c.Request.Context(), actor, conversationID, models.ScheduledMessageStatus(query.Status))
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{
+		"scheduled_messages": scheduled,
+		"total":              len(scheduled),
+	}))
+}
+
+// Create hẹn giờ gửi tin nhắn
+// POST /api/v1/conversations/:id/scheduled-messages
+func (h *ScheduledMessageHandler) Create(c *gin.Context) {
+	requestID := middleware.GetRequestID(c)
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var body ScheduleMessageBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	scheduled, err := h.scheduledService.Create(c.Request.Context(), actor, conversationID, services.ScheduleMessageInput{
+		Content:    body.Content,
+		SendAt:     body.SendAt,
+		MessageTag: body.MessageTag,
+	})
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Info("scheduled message created",
+		zap.String("request_id", requestID),
+		zap.String("scheduled_message_id", scheduled.ID.String()),
+	)
+
+	c.JSON(http.StatusCreated, dto.Success(scheduled))
+}
+
+// Cancel hủy tin nhắn đang chờ gửi
+// DELETE /api/v1/conversations/:id/scheduled-messages/:scheduledId
+func (h *ScheduledMessageHandler) Cancel(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	scheduledID, ok := h.parseID(c, "scheduledId")
+	if !ok {
+		return
+	}
+
+	scheduled, err := h.scheduledService.Cancel(c.Request.Context(), actor, conversationID, scheduledID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(scheduled))
+}
+
+// parseID parse UUID từ path param
+func (h *ScheduledMessageHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", param+" không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ===========================================================================
+// Route Registration
+// ===========================================================================
+
+// RegisterRoutes đăng ký routes cho scheduled message handler
+func (h *ScheduledMessageHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	scheduled := rg.Group("/conversations/:id/scheduled-messages")
+	{
+		scheduled.GET("", h.List)                   // Danh sách tin nhắn hẹn giờ
+		scheduled.POST("", h.Create)                // Hẹn giờ gửi
+		scheduled.DELETE("/:scheduledId", h.Cancel) // Hủy tin nhắn đang chờ
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Search Handler
+// Tìm kiếm toàn văn trên tin nhắn, ghi chú và thông tin khách hàng
+// ===========================================================================
+
+// SearchHandler xử lý endpoint tìm kiếm
+type SearchHandler struct {
+	searchService services.SearchService
+	logger        *zap.Logger
+}
+
+// NewSearchHandler tạo SearchHandler mới
+func NewSearchHandler(searchService services.SearchService, logger *zap.Logger) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		logger:        logger,
+	}
+}
+
+// SearchQuery query params tìm kiếm
+type SearchQuery struct {
+	dto.PaginationRequest
+
+	// Q từ khóa (hỗ trợ "cụm từ", -loại trừ, OR; không phân biệt dấu)
+	Q string `form:"q" binding:"required,max=200"`
+
+	// Types loại kết quả, phân tách bằng dấu phẩy (message,note,participant)
+	Types string `form:"types"`
+
+	// Channel loại kênh (facebook, zalo, web, mock)
+	Channel          string     `form:"channel" binding:"omitempty,oneof=facebook zalo web mock"`
+	ChannelAccountID *uuid.UUID `form:"channel_account_id"`
+
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// SenderType chỉ tìm tin nhắn của customer/agent/bot
+	SenderType string `form:"sender_type" binding:"omitempty,oneof=customer agent bot"`
+
+	// Tags tên tag của conversation (lặp lại param hoặc phân tách bằng dấu phẩy)
+	Tags []string `form:"tags"`
+}
+
+// Search tìm kiếm
+// GET /api/v1/search?q=...&types=message,note&channel=facebook&from=&to=&sender_type=customer&tags=VIP
+func (h *SearchHandler) Search(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var query SearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+	query.SetDefaults()
+
+	input := services.SearchInput{
+		Query:            query.Q,
+		ChannelAccountID: query.ChannelAccountID,
+		ChannelType:      query.Channel,
+		From:             query.From,
+		To:               query.To,
+		SenderType:       query.SenderType,
+		Tags:             splitList(query.Tags...),
+		Offset:           query.Offset(),
+		Limit:            query.Limit,
+	}
+	for _, t := range splitList(query.Types) {
+		switch hitType := repositories.SearchHitType(t); hitType {
+		case repositories.SearchHitMessage, repositories.SearchHitNote, repositories.SearchHitParticipant:
+			input.Types = append(input.Types, hitType)
+		default:
+			c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Loại kết quả không hợp lệ: "+t))
+			return
+		}
+	}
+
+	hits, total, err := h.searchService.Search(c.Request.Context(), actor, input)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessWithMeta(hits, dto.NewMeta(query.Page, query.Limit, total)))
+}
+
+// splitList tách các giá trị phân tách bằng dấu phẩy, bỏ phần tử rỗng
+func splitList(values ...string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// RegisterRoutes đăng ký routes cho search handler
+func (h *SearchHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/search", h.Search) // Tìm kiếm toàn văn
+}
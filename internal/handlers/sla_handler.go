@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// SLA Handler
+// Cấu hình chính sách SLA của workspace
+// Trạng thái SLA từng conversation trả về qua API conversations
+// ===========================================================================
+
+// SLAHandler xử lý các endpoint SLA
+type SLAHandler struct {
+	slaService services.SLAService
+	logger     *zap.Logger
+}
+
+// NewSLAHandler tạo SLAHandler mới
+func NewSLAHandler(slaService services.SLAService, logger *zap.Logger) *SLAHandler {
+	return &SLAHandler{
+		slaService: slaService,
+		logger:     logger,
+	}
+}
+
+// SLATargetBody mục tiêu SLA của một priority (phút làm việc)
+type SLATargetBody struct {
+	FirstResponseMinutes int `json:"first_response_minutes" binding:"min=0"`
+	ResolutionMinutes    int `json:"resolution_minutes" binding:"min=0"`
+}
+
+// UpdateSLAPolicyBody body cấu hình SLA
+type UpdateSLAPolicyBody struct {
+	Enabled        bool                     `json:"enabled"`
+	Targets        map[string]SLATargetBody `json:"targets" binding:"dive"`
+	WarningPercent int                      `json:"warning_percent" binding:"min=0,max=99"`
+	RaisePriority  bool                     `json:"raise_priority"`
+	NotifyAdmins   bool                     `json:"notify_admins"`
+}
+
+// GetPolicy lấy chính sách SLA
+// GET /api/v1/sla/policy
+func (h *SLAHandler) GetPolicy(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.slaService.GetPolicy(c.Request.Context(), actor)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(policy))
+}
+
+// UpdatePolicy cập nhật chính sách SLA (admin)
+// PUT /api/v1/sla/policy
+func (h *SLAHandler) UpdatePolicy(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	var body UpdateSLAPolicyBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	policy := models.SLAPolicy{
+		Enabled:        body.Enabled,
+		Targets:        make(map[models.Priority]models.SLATarget, len(body.Targets)),
+		WarningPercent: body.WarningPercent,
+		RaisePriority:  body.RaisePriority,
+		NotifyAdmins:   body.NotifyAdmins,
+	}
+	for priority, target := range body.Targets {
+		policy.Targets[models.Priority(priority)] = models.SLATarget{
+			FirstResponseMinutes: target.FirstResponseMinutes,
+			ResolutionMinutes:    target.ResolutionMinutes,
+		}
+	}
+
+	updated, err := h.slaService.UpdatePolicy(c.Request.Context(), actor, policy)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(updated))
+}
+
+// RegisterRoutes đăng ký routes cho SLA handler
+func (h *SLAHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	sla := rg.Group("/sla")
+	{
+		sla.GET("/policy", h.GetPolicy)    // Chính sách SLA
+		sla.PUT("/policy", h.UpdatePolicy) // Cập nhật chính sách
+	}
+}
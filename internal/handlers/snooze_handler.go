@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Snooze Handler
+// Tạm ẩn hội thoại khỏi inbox đến một thời điểm hoặc khi khách nhắn lại
+// ===========================================================================
+
+// SnoozeHandler xử lý các endpoint tạm ẩn hội thoại
+type SnoozeHandler struct {
+	snoozeService services.SnoozeService
+	logger        *zap.Logger
+}
+
+// NewSnoozeHandler tạo SnoozeHandler mới
+func NewSnoozeHandler(snoozeService services.SnoozeService, logger *zap.Logger) *SnoozeHandler {
+	return &SnoozeHandler{
+		snoozeService: snoozeService,
+		logger:        logger,
+	}
+}
+
+// SnoozeBody body tạm ẩn hội thoại
+type SnoozeBody struct {
+	// Until thời điểm mở lại (RFC3339)
+	Until time.Time `json:"until" binding:"required"`
+}
+
+// Snooze tạm ẩn hội thoại
+// POST /api/v1/conversations/:id/snooze
+func (h *SnoozeHandler) Snooze(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var body SnoozeBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", err.Error()))
+		return
+	}
+
+	conversation, err := h.snoozeService.Snooze(c.Request.Context(), actor, conversationID, body.Until)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(conversation))
+}
+
+// Unsnooze mở lại hội thoại trước hạn
+// DELETE /api/v1/conversations/:id/snooze
+func (h *SnoozeHandler) Unsnooze(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	conversation, err := h.snoozeService.Unsnooze(c.Request.Context(), actor, conversationID)
+	if err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(conversation))
+}
+
+// parseID parse conversation ID từ path
+func (h *SnoozeHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// RegisterRoutes đăng ký routes cho snooze handler
+func (h *SnoozeHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/conversations/:id/snooze", h.Snooze)     // Tạm ẩn đến thời điểm until
+	rg.DELETE("/conversations/:id/snooze", h.Unsnooze) // Mở lại trước hạn
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"chatbox-gin/internal/dto"
+	"chatbox-gin/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Typing Handler
+// Dashboard báo agent đang soạn tin, chuyển tới khách và các agent khác
+// ===========================================================================
+
+// TypingHandler xử lý endpoint typing indicator
+type TypingHandler struct {
+	typingService services.TypingService
+	logger        *zap.Logger
+}
+
+// NewTypingHandler tạo TypingHandler mới
+func NewTypingHandler(typingService services.TypingService, logger *zap.Logger) *TypingHandler {
+	return &TypingHandler{
+		typingService: typingService,
+		logger:        logger,
+	}
+}
+
+// TypingBody body báo trạng thái soạn tin
+type TypingBody struct {
+	// Typing true khi bắt đầu/đang gõ, false khi dừng hoặc đã gửi
+	Typing *bool `json:"typing" binding:"required"`
+}
+
+// SetTyping cập nhật trạng thái đang soạn tin của agent
+// POST /api/v1/conversations/:id/typing
+func (h *TypingHandler) SetTyping(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Conversation ID không hợp lệ"))
+		return
+	}
+
+	var body TypingBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, dto.Error("INVALID_REQUEST", "Tham số không hợp lệ: "+err.Error()))
+		return
+	}
+
+	if err := h.typingService.SetTyping(c.Request.Context(), actor, conversationID, *body.Typing); err != nil {
+		handleServiceError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.Success(gin.H{"typing": *body.Typing}))
+}
+
+// RegisterRoutes đăng ký routes cho typing handler
+func (h *TypingHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/conversations/:id/typing", h.SetTyping) // Agent đang soạn tin
+}
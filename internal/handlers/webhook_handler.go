@@ -123,6 +123,21 @@ func (h *WebhookHandler) FacebookWebhook(c *gin.Context) {
 		return
 	}
 
+	// Adapter tách được sự kiện có kiểu (delivery, read, echo, ...) thì xử lý từng sự kiện
+	if normalizer, ok := fbChannel.(channel.EventNormalizer); ok {
+		events, err := normalizer.NormalizeEvents(ctx, channelAcct.ID, payload)
+		if err != nil {
+			h.logger.Warn("normalize failed", zap.Error(err))
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			return
+		}
+		for _, event := range events {
+			h.processEvent(c, requestID, pageID, channelAcct, event)
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		return
+	}
+
 	// Normalize message
 	inbound, err := fbChannel.Normalize(ctx, channelAcct.ID, payload)
 	if err != nil {
@@ -150,6 +165,40 @@ func (h *WebhookHandler) FacebookWebhook(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
+// processEvent xử lý một sự kiện webhook, lỗi chỉ ghi log để không chặn các sự kiện còn lại
+// Sự kiện của page khác trong cùng webhook (batch) được gán đúng channel account
+func (h *WebhookHandler) processEvent(c *gin.Context, requestID, pageID string, channelAcct *models.ChannelAccount, event *channel.InboundEvent) {
+	ctx := c.Request.Context()
+	if event.RecipientID != "" && event.RecipientID != pageID {
+		other, err := h.channelAcctRepo.FindByChannelID(ctx, event.RecipientID, channelAcct.ChannelType)
+		if err != nil {
+			h.logger.Warn("channel account not found",
+				zap.String("page_id", event.RecipientID),
+				zap.Error(err),
+			)
+			return
+		}
+		channelAcct = other
+	}
+
+	result, err := h.messageService.ProcessEvent(ctx, channelAcct.WorkspaceID, channelAcct.ID, event)
+	if err != nil {
+		h.logger.Error("process webhook event failed",
+			zap.String("request_id", requestID),
+			zap.String("event_type", string(event.Type)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	h.logger.Info("fb event processed",
+		zap.String("request_id", requestID),
+		zap.String("event_type", string(event.Type)),
+		zap.String("message_id", result.MessageID.String()),
+		zap.Bool("bot_replied", result.BotReplied),
+	)
+}
+
 // extractPageID lấy Page ID từ FB webhook payload
 func (h *WebhookHandler) extractPageID(payload map[string]interface{}) string {
 	entries, ok := payload["entry"].([]interface{})
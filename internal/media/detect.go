@@ -0,0 +1,119 @@
+package media
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ===========================================================================
+// Nhận diện file
+// Xác định MIME type, loại attachment và tên file của file tải về
+// ===========================================================================
+
+// sniffLen số byte đầu file dùng để nhận diện nội dung (theo http.DetectContentType)
+const sniffLen = 512
+
+// DetectMimeType xác định MIME type từ nội dung file
+// Dùng MIME type khai báo (header Content-Type) khi nội dung không nhận diện được
+func DetectMimeType(head []byte, declared string) string {
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	sniffed := baseType(http.DetectContentType(head))
+	declared = baseType(declared)
+
+	if sniffed == "application/octet-stream" && declared != "" {
+		return declared
+	}
+	// File Office (docx, xlsx, ...) thực chất là zip
+	if sniffed == "application/zip" && strings.HasPrefix(declared, "application/vnd.") {
+		return declared
+	}
+	// text/plain của sniffer quá chung chung (CSV, JSON, ...), ưu tiên khai báo nếu cùng là text
+	if sniffed == "text/plain" && strings.HasPrefix(declared, "text/") {
+		return declared
+	}
+	return sniffed
+}
+
+// KindOf loại attachment (image, video, audio, file) theo MIME type
+func KindOf(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "audio"
+	default:
+		return "file"
+	}
+}
+
+// FileName xác định tên file từ header Content-Disposition hoặc đường dẫn URL
+// Tự thêm phần mở rộng theo MIME type nếu tên chưa có
+func FileName(contentDisposition, rawURL, mimeType string) string {
+	name := ""
+	if contentDisposition != "" {
+		if _, params, err := mime.ParseMediaType(contentDisposition); err == nil {
+			name = params["filename"]
+		}
+	}
+	if name == "" {
+		if u, err := url.Parse(rawURL); err == nil {
+			if base := path.Base(u.Path); base != "." && base != "/" {
+				name = base
+			}
+		}
+	}
+	return CleanName(name, mimeType)
+}
+
+// CleanName bỏ đường dẫn khỏi tên file client gửi lên và thêm phần mở rộng theo MIME type nếu thiếu
+func CleanName(name, mimeType string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		name = "attachment"
+	}
+
+	if path.Ext(name) == "" {
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+	return name
+}
+
+// baseType bỏ tham số (charset, ...) khỏi MIME type
+func baseType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// Allowed kiểm tra MIME type có thuộc danh sách cho phép không
+// Hỗ trợ wildcard theo nhóm (VD: "image/*")
+func Allowed(mimeType string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == mimeType {
+			return true
+		}
+		if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeByName MIME type theo phần mở rộng của tên file (rỗng nếu không biết)
+func TypeByName(name string) string {
+	return baseType(mime.TypeByExtension(strings.ToLower(path.Ext(name))))
+}
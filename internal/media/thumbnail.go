@@ -0,0 +1,141 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+
+	// Đăng ký decoder cho image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// ===========================================================================
+// Thumbnail
+// Thu nhỏ ảnh (jpeg, png, gif) thành JPEG để hiển thị trong danh sách tin nhắn
+// Dùng thư viện chuẩn, lấy trung bình theo vùng (box filter) nên ảnh thu nhỏ không bị răng cưa
+// ===========================================================================
+
+// maxPixels giới hạn số điểm ảnh để tránh ảnh "bom giải nén" chiếm hết bộ nhớ
+const maxPixels = 50_000_000
+
+// thumbnailQuality chất lượng JPEG của thumbnail
+const thumbnailQuality = 80
+
+// ErrImageTooLarge ảnh vượt quá số điểm ảnh cho phép
+var ErrImageTooLarge = errors.New("image too large")
+
+// Thumbnail kết quả thu nhỏ ảnh
+type Thumbnail struct {
+	// Data ảnh JPEG đã thu nhỏ, nil nếu ảnh gốc đã đủ nhỏ
+	Data []byte
+
+	// Width, Height kích thước ảnh gốc
+	Width  int
+	Height int
+}
+
+// MakeThumbnail thu nhỏ ảnh sao cho cạnh dài nhất không quá maxSize
+// Ảnh gốc không lớn hơn maxSize thì chỉ trả về kích thước (Data = nil)
+func MakeThumbnail(data []byte, maxSize int) (*Thumbnail, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image config: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	thumb := &Thumbnail{Width: cfg.Width, Height: cfg.Height}
+	if cfg.Width <= maxSize && cfg.Height <= maxSize {
+		return thumb, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	width, height := fitSize(cfg.Width, cfg.Height, maxSize)
+	var buf bytes.Buffer
+	if err := encodeJPEG(&buf, downscale(img, width, height)); err != nil {
+		return nil, err
+	}
+	thumb.Data = buf.Bytes()
+	return thumb, nil
+}
+
+// fitSize tính kích thước mới giữ nguyên tỉ lệ, cạnh dài nhất = maxSize
+func fitSize(width, height, maxSize int) (int, int) {
+	if width >= height {
+		h := height * maxSize / width
+		if h < 1 {
+			h = 1
+		}
+		return maxSize, h
+	}
+	w := width * maxSize / height
+	if w < 1 {
+		w = 1
+	}
+	return w, maxSize
+}
+
+// downscale thu nhỏ ảnh bằng cách lấy trung bình các điểm ảnh gốc thuộc mỗi điểm ảnh đích
+// Nền trong suốt được phủ trắng vì JPEG không có kênh alpha
+func downscale(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Over)
+
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := (y + 1) * srcH / height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := (x + 1) * srcW / width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+3]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// encodeJPEG mã hóa ảnh thumbnail
+func encodeJPEG(w io.Writer, img image.Image) error {
+	if err := jpeg.Encode(w, img, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return fmt.Errorf("encode thumbnail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Audit Log (Nhật ký thao tác)
+// Ghi lại ai đã làm gì trên dữ liệu của workspace (VD: chạy macro trên hội thoại)
+// ===========================================================================
+
+// Audit actions
+const (
+	// AuditMacroExecuted chạy macro trên hội thoại
+	AuditMacroExecuted = "macro.executed"
+
+	// AuditContactUpdated sửa thông tin khách (tên, email, số điện thoại, trường tùy chỉnh)
+	AuditContactUpdated = "contact.updated"
+
+	// AuditContactTagged, AuditContactUntagged gắn/gỡ tag của khách
+	AuditContactTagged   = "contact.tagged"
+	AuditContactUntagged = "contact.untagged"
+
+	// AuditContactMerged, AuditContactUnmerged gộp/tách danh tính của khách trên các kênh
+	AuditContactMerged   = "contact.merged"
+	AuditContactUnmerged = "contact.unmerged"
+
+	// AuditContactImported khách được tạo từ file nhập (cập nhật khách đã có dùng contact.updated)
+	AuditContactImported = "contact.imported"
+)
+
+// Audit entity types
+const (
+	AuditEntityConversation = "conversation"
+	AuditEntityContact      = "contact"
+)
+
+// AuditData chi tiết thay đổi cho JSONB
+type AuditData map[string]interface{}
+
+// Value implement driver.Valuer cho JSONB
+func (d AuditData) Value() (driver.Value, error) {
+	if d == nil {
+		return json.Marshal(map[string]interface{}{})
+	}
+	return json.Marshal(map[string]interface{}(d))
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (d *AuditData) Scan(value interface{}) error {
+	if value == nil {
+		*d = AuditData{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+// AuditLog một bản ghi nhật ký
+type AuditLog struct {
+	BaseModel
+
+	// WorkspaceID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// ActorID người thực hiện (nil = hệ thống)
+	ActorID *uuid.UUID `gorm:"type:uuid;index" json:"actor_id,omitempty"`
+
+	// Action hành động (VD: "macro.executed")
+	Action string `gorm:"size:100;not null;index" json:"action"`
+
+	// EntityType, EntityID đối tượng bị tác động
+	EntityType string    `gorm:"size:50;not null;index:idx_audit_entity" json:"entity_type"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index:idx_audit_entity" json:"entity_id"`
+
+	// Data chi tiết (giá trị trước/sau, tham số, ...)
+	Data AuditData `gorm:"type:jsonb;default:'{}'" json:"data"`
+}
+
+// TableName trả về tên bảng
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
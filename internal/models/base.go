@@ -1,6 +1,9 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -45,3 +48,71 @@ func (b *BaseModel) GetID() uuid.UUID {
 func (b *BaseModel) IsDeleted() bool {
 	return b.DeletedAt.Valid
 }
+
+// ===========================================================================
+// StringList danh sách string lưu dạng JSONB
+// Dùng cho các trường dạng nhãn (VD: kỹ năng của agent)
+// ===========================================================================
+
+// StringList danh sách string cho JSONB
+type StringList []string
+
+// Value implement driver.Valuer cho JSONB
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal(l)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = StringList{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// ===========================================================================
+// UUIDList danh sách UUID lưu dạng JSONB
+// Dùng cho các trường chứa nhiều ID (VD: user được mention trong note)
+// ===========================================================================
+
+// UUIDList danh sách UUID cho JSONB
+type UUIDList []uuid.UUID
+
+// Value implement driver.Valuer cho JSONB
+func (l UUIDList) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]uuid.UUID{})
+	}
+	return json.Marshal(l)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (l *UUIDList) Scan(value interface{}) error {
+	if value == nil {
+		*l = UUIDList{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Contains kiểm tra danh sách có chứa ID không
+func (l UUIDList) Contains(id uuid.UUID) bool {
+	for _, v := range l {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
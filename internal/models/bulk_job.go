@@ -0,0 +1,216 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Bulk Job (Thao tác hàng loạt)
+// Áp dụng một thao tác (assign, đóng, gắn tag, ...) lên nhiều hội thoại
+// Chạy nền theo từng lô, lưu tiến độ để dashboard theo dõi và chạy tiếp khi restart
+// ===========================================================================
+
+// BulkAction thao tác áp dụng lên từng hội thoại
+type BulkAction string
+
+const (
+	// BulkAssign assign cho agent (Params.UserID), bỏ trống UserID = bỏ assign
+	BulkAssign BulkAction = "assign"
+
+	// BulkClose đóng hội thoại (Params.Reason)
+	BulkClose BulkAction = "close"
+
+	// BulkReopen mở lại hội thoại đã đóng
+	BulkReopen BulkAction = "reopen"
+
+	// BulkTag gắn tag (Params.Tags, tự tạo tag chưa có)
+	BulkTag BulkAction = "tag"
+
+	// BulkUntag gỡ tag (Params.Tags)
+	BulkUntag BulkAction = "untag"
+
+	// BulkSetPriority đổi mức ưu tiên (Params.Priority)
+	BulkSetPriority BulkAction = "set_priority"
+
+	// BulkPauseBot dừng bot (Params.Reason)
+	BulkPauseBot BulkAction = "pause_bot"
+)
+
+// BulkJobStatus trạng thái job
+type BulkJobStatus string
+
+const (
+	// BulkPending chờ worker nhận
+	BulkPending BulkJobStatus = "pending"
+
+	// BulkRunning đang xử lý
+	BulkRunning BulkJobStatus = "running"
+
+	// BulkCompleted đã xử lý hết danh sách (có thể có hội thoại lỗi)
+	BulkCompleted BulkJobStatus = "completed"
+
+	// BulkFailed dừng giữa chừng do lỗi hệ thống
+	BulkFailed BulkJobStatus = "failed"
+)
+
+// MaxBulkJobErrors số lỗi chi tiết tối đa lưu lại cho một job
+const MaxBulkJobErrors = 100
+
+// BulkParams tham số của thao tác, các trường dùng tùy theo Action
+type BulkParams struct {
+	UserID   *uuid.UUID `json:"user_id,omitempty"`
+	Tags     []string   `json:"tags,omitempty"`
+	Priority Priority   `json:"priority,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+
+	// SendSurvey gửi khảo sát CSAT cho hội thoại vừa đóng (close)
+	SendSurvey bool `json:"send_survey,omitempty"`
+}
+
+// Value implement driver.Valuer cho JSONB
+func (p BulkParams) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (p *BulkParams) Scan(value interface{}) error {
+	if value == nil {
+		*p = BulkParams{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// BulkFilter điều kiện chọn hội thoại (thay cho danh sách ID)
+// Tuổi hội thoại tính theo hoạt động gần nhất (tin nhắn cuối, chưa có thì lúc tạo)
+type BulkFilter struct {
+	Status           ConversationStatus `json:"status,omitempty"`
+	Tag              string             `json:"tag,omitempty"`
+	ChannelAccountID *uuid.UUID         `json:"channel_account_id,omitempty"`
+	AssignedTo       *uuid.UUID         `json:"assigned_to,omitempty"`
+	Unassigned       bool               `json:"unassigned,omitempty"`
+	OlderThanHours   int                `json:"older_than_hours,omitempty"`
+	NewerThanHours   int                `json:"newer_than_hours,omitempty"`
+}
+
+// IsEmpty kiểm tra bộ lọc không có điều kiện nào
+func (f BulkFilter) IsEmpty() bool {
+	return f == BulkFilter{}
+}
+
+// Value implement driver.Valuer cho JSONB
+func (f BulkFilter) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (f *BulkFilter) Scan(value interface{}) error {
+	if value == nil {
+		*f = BulkFilter{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// BulkJobError lỗi khi xử lý một hội thoại
+type BulkJobError struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	Error          string    `json:"error"`
+}
+
+// BulkJobErrors danh sách lỗi cho JSONB
+type BulkJobErrors []BulkJobError
+
+// Value implement driver.Valuer cho JSONB
+func (e BulkJobErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return json.Marshal([]BulkJobError{})
+	}
+	return json.Marshal([]BulkJobError(e))
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (e *BulkJobErrors) Scan(value interface{}) error {
+	if value == nil {
+		*e = BulkJobErrors{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+// BulkJob một lần thao tác hàng loạt
+type BulkJob struct {
+	BaseModel
+
+	// WorkspaceID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// CreatedBy người tạo job
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+
+	// Action, Params thao tác và tham số
+	Action BulkAction `gorm:"size:30;not null" json:"action"`
+	Params BulkParams `gorm:"type:jsonb;default:'{}'" json:"params"`
+
+	// Filter điều kiện chọn hội thoại (rỗng khi tạo bằng danh sách ID)
+	Filter BulkFilter `gorm:"type:jsonb;default:'{}'" json:"filter"`
+
+	// ConversationIDs danh sách hội thoại cần xử lý, chốt lúc tạo job
+	ConversationIDs UUIDList `gorm:"type:jsonb;default:'[]'" json:"-"`
+
+	// Status trạng thái: pending, running, completed, failed
+	Status BulkJobStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+
+	// Total tổng số hội thoại, Processed số đã xử lý (vị trí tiếp tục khi restart)
+	Total     int `gorm:"not null;default:0" json:"total"`
+	Processed int `gorm:"not null;default:0" json:"processed"`
+
+	// Succeeded đã thay đổi, Skipped không cần thay đổi, Failed lỗi
+	Succeeded int `gorm:"not null;default:0" json:"succeeded"`
+	Skipped   int `gorm:"not null;default:0" json:"skipped"`
+	Failed    int `gorm:"not null;default:0" json:"failed"`
+
+	// Errors chi tiết lỗi (tối đa MaxBulkJobErrors)
+	Errors BulkJobErrors `gorm:"type:jsonb;default:'[]'" json:"errors"`
+
+	// Error lỗi hệ thống khiến job dừng (status failed)
+	Error string `gorm:"type:text" json:"error,omitempty"`
+
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (BulkJob) TableName() string {
+	return "bulk_jobs"
+}
+
+// IsFinished kiểm tra job đã kết thúc chưa
+func (j *BulkJob) IsFinished() bool {
+	return j.Status == BulkCompleted || j.Status == BulkFailed
+}
+
+// AddError ghi nhận một hội thoại xử lý lỗi
+func (j *BulkJob) AddError(conversationID uuid.UUID, err error) {
+	j.Failed++
+	if len(j.Errors) < MaxBulkJobErrors {
+		j.Errors = append(j.Errors, BulkJobError{ConversationID: conversationID, Error: err.Error()})
+	}
+}
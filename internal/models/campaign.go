@@ -0,0 +1,220 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Campaign (Chiến dịch gửi tin hàng loạt)
+// Gửi một tin nhắn cho nhiều khách cùng lúc (khuyến mãi, thông báo sự cố, ...)
+// Tệp khách chốt lúc chiến dịch bắt đầu, gửi nền có giới hạn tốc độ theo từng kênh,
+// kết quả lưu theo từng người nhận và tin nhắn nằm trong lịch sử hội thoại của khách
+// ===========================================================================
+
+// CampaignStatus trạng thái chiến dịch
+type CampaignStatus string
+
+const (
+	// CampaignDraft đang soạn, chưa lên lịch
+	CampaignDraft CampaignStatus = "draft"
+
+	// CampaignScheduled đã lên lịch, chờ đến giờ bắt đầu
+	CampaignScheduled CampaignStatus = "scheduled"
+
+	// CampaignRunning đang gửi
+	CampaignRunning CampaignStatus = "running"
+
+	// CampaignCompleted đã xử lý hết người nhận
+	CampaignCompleted CampaignStatus = "completed"
+
+	// CampaignCancelled đã hủy (người nhận chưa gửi được đánh dấu cancelled)
+	CampaignCancelled CampaignStatus = "cancelled"
+
+	// CampaignFailed dừng do lỗi hệ thống (VD: không chốt được tệp khách)
+	CampaignFailed CampaignStatus = "failed"
+)
+
+// CampaignAudience điều kiện chọn khách nhận tin
+// Các điều kiện kết hợp bằng AND, danh sách trong một điều kiện kết hợp bằng OR
+type CampaignAudience struct {
+	// ChannelAccountIDs chỉ khách của các kênh này (rỗng = mọi kênh)
+	ChannelAccountIDs []uuid.UUID `json:"channel_account_ids,omitempty"`
+
+	// Tags khách có một trong các tag (tag của khách hoặc của hội thoại của khách)
+	Tags []string `json:"tags,omitempty"`
+
+	// CustomFields khách có trường tùy chỉnh bằng giá trị tương ứng
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+
+	// LastSeenWithinDays khách nhắn tin trong N ngày gần đây
+	LastSeenWithinDays int `json:"last_seen_within_days,omitempty"`
+
+	// LastSeenBeforeDays khách không nhắn tin trong N ngày gần đây
+	LastSeenBeforeDays int `json:"last_seen_before_days,omitempty"`
+}
+
+// Value implement driver.Valuer cho JSONB
+func (a CampaignAudience) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (a *CampaignAudience) Scan(value interface{}) error {
+	if value == nil {
+		*a = CampaignAudience{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// Campaign một chiến dịch gửi tin
+type Campaign struct {
+	BaseModel
+
+	// WorkspaceID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// CreatedBy người tạo (người gửi của các tin nhắn)
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+
+	// Name tên chiến dịch
+	Name string `gorm:"size:255;not null" json:"name"`
+
+	// Content nội dung tin nhắn, hỗ trợ biến {{contact.name}}, ...
+	Content string `gorm:"type:text;not null" json:"content"`
+
+	// Audience điều kiện chọn khách nhận tin
+	Audience CampaignAudience `gorm:"type:jsonb;default:'{}'" json:"audience"`
+
+	// MessageTag message tag để gửi cho khách ngoài cửa sổ nhắn tin của channel
+	// (VD: POST_PURCHASE_UPDATE trên Facebook). Rỗng = bỏ qua khách ngoài cửa sổ
+	MessageTag string `gorm:"size:50" json:"message_tag,omitempty"`
+
+	// ScheduledAt thời điểm bắt đầu gửi (nil = gửi ngay khi launch)
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+
+	// RatePerMinute số tin tối đa mỗi phút trên mỗi kênh
+	RatePerMinute int `gorm:"not null;default:60" json:"rate_per_minute"`
+
+	// Status trạng thái: draft, scheduled, running, completed, cancelled, failed
+	Status CampaignStatus `gorm:"size:20;not null;default:'draft';index" json:"status"`
+
+	// Total tổng số người nhận (chốt lúc bắt đầu)
+	Total int `gorm:"not null;default:0" json:"total"`
+
+	// Sent đã gửi, Failed gửi lỗi, Skipped bỏ qua (ngoài cửa sổ, ...), Cancelled chưa gửi khi hủy
+	Sent      int `gorm:"not null;default:0" json:"sent"`
+	Failed    int `gorm:"not null;default:0" json:"failed"`
+	Skipped   int `gorm:"not null;default:0" json:"skipped"`
+	Cancelled int `gorm:"not null;default:0" json:"cancelled"`
+
+	// Error lỗi hệ thống khiến chiến dịch dừng (status failed)
+	Error string `gorm:"type:text" json:"error,omitempty"`
+
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CancelledBy *uuid.UUID `gorm:"type:uuid" json:"cancelled_by,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (Campaign) TableName() string {
+	return "campaigns"
+}
+
+// IsDraft kiểm tra chiến dịch còn sửa được
+func (c *Campaign) IsDraft() bool {
+	return c.Status == CampaignDraft
+}
+
+// IsFinished kiểm tra chiến dịch đã kết thúc
+func (c *Campaign) IsFinished() bool {
+	return c.Status == CampaignCompleted || c.Status == CampaignCancelled || c.Status == CampaignFailed
+}
+
+// Processed số người nhận đã xử lý xong
+func (c *Campaign) Processed() int {
+	return c.Sent + c.Failed + c.Skipped + c.Cancelled
+}
+
+// RecipientStatus trạng thái gửi cho một người nhận
+type RecipientStatus string
+
+const (
+	// RecipientPending chờ gửi
+	RecipientPending RecipientStatus = "pending"
+
+	// RecipientSending worker đang gửi
+	RecipientSending RecipientStatus = "sending"
+
+	// RecipientSent đã gửi qua channel
+	RecipientSent RecipientStatus = "sent"
+
+	// RecipientFailed gửi lỗi (xem Error)
+	RecipientFailed RecipientStatus = "failed"
+
+	// RecipientSkipped không gửi (xem Reason)
+	RecipientSkipped RecipientStatus = "skipped"
+
+	// RecipientCancelled chưa gửi khi chiến dịch bị hủy
+	RecipientCancelled RecipientStatus = "cancelled"
+)
+
+// Lý do bỏ qua người nhận
+const (
+	// SkipOutsideWindow khách ngoài cửa sổ nhắn tin và chiến dịch không có message tag hợp lệ
+	SkipOutsideWindow = "outside_window"
+
+	// SkipNoConversation khách chưa có hội thoại nào để gửi vào
+	SkipNoConversation = "no_conversation"
+)
+
+// CampaignRecipient kết quả gửi cho một khách
+type CampaignRecipient struct {
+	BaseModel
+
+	// CampaignID chiến dịch
+	CampaignID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_campaign_recipient;index:idx_campaign_recipient_status" json:"campaign_id"`
+
+	// ParticipantID khách nhận tin
+	ParticipantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_campaign_recipient" json:"participant_id"`
+
+	// ChannelAccountID kênh của khách (giới hạn tốc độ theo kênh)
+	ChannelAccountID uuid.UUID `gorm:"type:uuid;not null" json:"channel_account_id"`
+
+	// Status trạng thái: pending, sending, sent, failed, skipped, cancelled
+	Status RecipientStatus `gorm:"size:20;not null;default:'pending';index:idx_campaign_recipient_status" json:"status"`
+
+	// ConversationID, MessageID hội thoại và tin nhắn đã tạo khi gửi
+	ConversationID *uuid.UUID `gorm:"type:uuid" json:"conversation_id,omitempty"`
+	MessageID      *uuid.UUID `gorm:"type:uuid" json:"message_id,omitempty"`
+
+	// MessageTag message tag đã dùng (khách ngoài cửa sổ nhắn tin)
+	MessageTag string `gorm:"size:50" json:"message_tag,omitempty"`
+
+	// Reason lý do bỏ qua, Error lỗi khi gửi
+	Reason string `gorm:"size:50" json:"reason,omitempty"`
+	Error  string `gorm:"type:text" json:"error,omitempty"`
+
+	// AttemptedAt thời điểm bắt đầu gửi (dùng để giới hạn tốc độ)
+	AttemptedAt *time.Time `gorm:"index" json:"attempted_at,omitempty"`
+
+	// SentAt thời điểm gửi thành công
+	SentAt *time.Time `json:"sent_at,omitempty"`
+
+	// Relations
+	Participant *Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (CampaignRecipient) TableName() string {
+	return "campaign_recipients"
+}
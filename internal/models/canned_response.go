@@ -0,0 +1,101 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Canned Response (Câu trả lời soạn sẵn)
+// Agent gõ shortcut (VD: /ship) trong ô soạn tin để chèn câu trả lời có sẵn
+// Nội dung hỗ trợ biến template ({{contact.first_name}}, {{agent.name}}, ...)
+// ===========================================================================
+
+// CannedVisibility phạm vi sử dụng câu trả lời
+type CannedVisibility string
+
+const (
+	// CannedShared dùng chung cho cả workspace (admin quản lý)
+	CannedShared CannedVisibility = "shared"
+
+	// CannedPersonal chỉ người tạo thấy và dùng được
+	CannedPersonal CannedVisibility = "personal"
+)
+
+// QuickReplies danh sách quick reply cho JSONB
+type QuickReplies []QuickReply
+
+// Value implement driver.Valuer cho JSONB
+func (q QuickReplies) Value() (driver.Value, error) {
+	if q == nil {
+		return json.Marshal([]QuickReply{})
+	}
+	return json.Marshal(q)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (q *QuickReplies) Scan(value interface{}) error {
+	if value == nil {
+		*q = QuickReplies{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, q)
+}
+
+// CannedResponse câu trả lời soạn sẵn
+type CannedResponse struct {
+	BaseModel
+
+	// WorkspaceID workspace sở hữu
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// CreatedBy người tạo (chủ sở hữu với câu trả lời personal)
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null;index" json:"created_by"`
+
+	// Visibility phạm vi: shared, personal
+	Visibility CannedVisibility `gorm:"size:20;not null;default:'shared'" json:"visibility"`
+
+	// Title tên hiển thị trong danh sách gợi ý
+	Title string `gorm:"size:255;not null" json:"title"`
+
+	// Shortcut từ khóa gõ nhanh, lưu không kèm dấu "/" (VD: "ship")
+	Shortcut string `gorm:"size:50;not null;index" json:"shortcut"`
+
+	// Body nội dung tin nhắn, có thể chứa biến template
+	Body string `gorm:"type:text" json:"body"`
+
+	// Attachments file đính kèm đã lưu trong storage, gửi kèm mỗi lần dùng
+	Attachments Attachments `gorm:"type:jsonb;default:'[]'" json:"attachments"`
+
+	// QuickReplies các nút trả lời nhanh gửi kèm
+	QuickReplies QuickReplies `gorm:"type:jsonb;default:'[]'" json:"quick_replies"`
+
+	// UsageCount số lần đã gửi, LastUsedAt lần gửi gần nhất
+	UsageCount int        `gorm:"default:0" json:"usage_count"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (CannedResponse) TableName() string {
+	return "canned_responses"
+}
+
+// IsOwner kiểm tra user có phải người tạo không
+func (r *CannedResponse) IsOwner(userID uuid.UUID) bool { return r.CreatedBy == userID }
+
+// IsPersonal kiểm tra câu trả lời có phải của riêng người tạo không
+func (r *CannedResponse) IsPersonal() bool { return r.Visibility == CannedPersonal }
+
+// VisibleTo kiểm tra user có thấy câu trả lời không
+// Câu trả lời personal chỉ người tạo thấy (kể cả admin cũng không thấy)
+func (r *CannedResponse) VisibleTo(userID uuid.UUID) bool {
+	return !r.IsPersonal() || r.IsOwner(userID)
+}
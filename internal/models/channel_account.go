@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -89,6 +90,48 @@ type ChannelSettings struct {
 
 	// RetryDelayMs delay giữa các lần retry (milliseconds)
 	RetryDelayMs int `json:"retry_delay_ms"`
+
+	// BotTyping hiển thị "đang soạn tin" trước khi bot trả lời
+	// Thời gian chờ tỉ lệ với độ dài câu trả lời để giống người thật
+	BotTyping bool `json:"bot_typing,omitempty"`
+
+	// TypingCharsPerSecond tốc độ "gõ" của bot (mặc định 20 ký tự/giây)
+	TypingCharsPerSecond int `json:"typing_chars_per_second,omitempty"`
+
+	// TypingMaxDelayMs thời gian chờ tối đa (mặc định 4000ms)
+	TypingMaxDelayMs int `json:"typing_max_delay_ms,omitempty"`
+}
+
+// Giới hạn thời gian "gõ" của bot
+const (
+	defaultTypingCharsPerSecond = 20
+	defaultTypingMaxDelay       = 4 * time.Second
+	minTypingDelay              = 500 * time.Millisecond
+)
+
+// TypingDelay thời gian bot hiển thị "đang soạn tin" trước khi gửi text
+// Trả về 0 nếu không bật BotTyping
+func (s ChannelSettings) TypingDelay(text string) time.Duration {
+	if !s.BotTyping {
+		return 0
+	}
+	cps := s.TypingCharsPerSecond
+	if cps <= 0 {
+		cps = defaultTypingCharsPerSecond
+	}
+	maxDelay := defaultTypingMaxDelay
+	if s.TypingMaxDelayMs > 0 {
+		maxDelay = time.Duration(s.TypingMaxDelayMs) * time.Millisecond
+	}
+
+	delay := time.Duration(utf8.RuneCountInString(text)) * time.Second / time.Duration(cps)
+	if delay < minTypingDelay {
+		delay = minTypingDelay
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
 }
 
 // Value implement driver.Valuer cho JSONB
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Contact Job (Nhập/xuất danh sách khách)
+// Nhập khách từ file CSV (ánh xạ cột, chống trùng theo số điện thoại/email, chạy thử)
+// hoặc xuất khách khớp bộ lọc ra CSV
+// Chạy nền theo từng lô, lưu tiến độ để dashboard theo dõi và chạy tiếp khi restart
+// ===========================================================================
+
+// ContactJobType loại job
+type ContactJobType string
+
+const (
+	// ContactJobImport nhập khách từ file CSV đã tải lên
+	ContactJobImport ContactJobType = "import"
+
+	// ContactJobExport xuất khách khớp bộ lọc ra file CSV
+	ContactJobExport ContactJobType = "export"
+)
+
+// ContactJobStatus trạng thái job
+type ContactJobStatus string
+
+const (
+	// ContactJobPending chờ worker nhận
+	ContactJobPending ContactJobStatus = "pending"
+
+	// ContactJobRunning đang xử lý
+	ContactJobRunning ContactJobStatus = "running"
+
+	// ContactJobCompleted đã xử lý hết (import có thể có dòng lỗi)
+	ContactJobCompleted ContactJobStatus = "completed"
+
+	// ContactJobFailed dừng giữa chừng do lỗi hệ thống
+	ContactJobFailed ContactJobStatus = "failed"
+)
+
+// Trường đích của một cột CSV khi nhập khách
+// Cột trường tùy chỉnh dùng ContactFieldTargetPrefix + key (VD: field.plan)
+const (
+	ContactTargetName  = "name"
+	ContactTargetPhone = "phone"
+	ContactTargetEmail = "email"
+
+	// ContactTargetTags danh sách tag phân cách bằng dấu phẩy hoặc chấm phẩy
+	ContactTargetTags = "tags"
+
+	// ContactTargetIgnore bỏ qua cột
+	ContactTargetIgnore = "ignore"
+
+	// ContactFieldTargetPrefix tiền tố cột trường tùy chỉnh
+	ContactFieldTargetPrefix = "field."
+)
+
+// ImportedChannelUserPrefix tiền tố ChannelUserID của khách tạo từ file nhập
+// Khách này chưa có danh tính thật trên kênh, được gộp khi khách nhắn tin (gợi ý gộp theo SĐT/email)
+const ImportedChannelUserPrefix = "import:"
+
+// MaxContactJobErrors số dòng lỗi tối đa lưu lại cho một job import
+const MaxContactJobErrors = 1000
+
+// ContactImportColumn ánh xạ một cột của file CSV (theo tên cột ở dòng tiêu đề) vào trường của khách
+type ContactImportColumn struct {
+	Column string `json:"column"`
+	Target string `json:"target"`
+}
+
+// ContactImportColumns danh sách ánh xạ cột cho JSONB
+type ContactImportColumns []ContactImportColumn
+
+// Value implement driver.Valuer cho JSONB
+func (c ContactImportColumns) Value() (driver.Value, error) {
+	if c == nil {
+		return json.Marshal([]ContactImportColumn{})
+	}
+	return json.Marshal([]ContactImportColumn(c))
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (c *ContactImportColumns) Scan(value interface{}) error {
+	if value == nil {
+		*c = ContactImportColumns{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// ContactExportFilter điều kiện chọn khách cần xuất (giống bộ lọc danh sách khách)
+type ContactExportFilter struct {
+	Query            string           `json:"q,omitempty"`
+	ChannelAccountID *uuid.UUID       `json:"channel_account_id,omitempty"`
+	ChannelType      string           `json:"channel,omitempty"`
+	Tag              string           `json:"tag,omitempty"`
+	SeenFrom         *time.Time       `json:"seen_from,omitempty"`
+	SeenTo           *time.Time       `json:"seen_to,omitempty"`
+	Fields           []FieldCondition `json:"fields,omitempty"`
+}
+
+// Value implement driver.Valuer cho JSONB
+func (f ContactExportFilter) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (f *ContactExportFilter) Scan(value interface{}) error {
+	if value == nil {
+		*f = ContactExportFilter{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// ContactJobError lỗi của một dòng trong file nhập (Row tính cả dòng tiêu đề, bắt đầu từ 1)
+type ContactJobError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ContactJobErrors danh sách lỗi cho JSONB
+type ContactJobErrors []ContactJobError
+
+// Value implement driver.Valuer cho JSONB
+func (e ContactJobErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return json.Marshal([]ContactJobError{})
+	}
+	return json.Marshal([]ContactJobError(e))
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (e *ContactJobErrors) Scan(value interface{}) error {
+	if value == nil {
+		*e = ContactJobErrors{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+// ContactJob một lần nhập hoặc xuất danh sách khách
+type ContactJob struct {
+	BaseModel
+
+	// WorkspaceID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// CreatedBy người tạo job
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+
+	// Type import hoặc export
+	Type ContactJobType `gorm:"size:20;not null" json:"type"`
+
+	// FileName tên file gốc (import) hoặc tên file kết quả (export)
+	FileName string `gorm:"size:255" json:"file_name,omitempty"`
+
+	// SourceKey key của file CSV đã tải lên trong storage (import)
+	SourceKey string `gorm:"size:500" json:"-"`
+
+	// ResultKey key của file CSV kết quả trong storage (export)
+	ResultKey string `gorm:"size:500" json:"-"`
+
+	// Columns ánh xạ cột CSV vào trường của khách (import)
+	Columns ContactImportColumns `gorm:"type:jsonb;default:'[]'" json:"columns,omitempty"`
+
+	// ChannelAccountID kênh gắn cho khách mới tạo từ file (import)
+	ChannelAccountID *uuid.UUID `gorm:"type:uuid" json:"channel_account_id,omitempty"`
+
+	// DryRun chỉ kiểm tra file, không ghi khách (import)
+	DryRun bool `gorm:"not null;default:false" json:"dry_run"`
+
+	// Filter điều kiện chọn khách (export)
+	Filter ContactExportFilter `gorm:"type:jsonb;default:'{}'" json:"filter"`
+
+	// Status trạng thái: pending, running, completed, failed
+	Status ContactJobStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+
+	// Total tổng số dòng dữ liệu (import) hoặc số khách khớp bộ lọc (export)
+	// Processed số đã xử lý (import: vị trí tiếp tục khi restart)
+	Total     int `gorm:"not null;default:0" json:"total"`
+	Processed int `gorm:"not null;default:0" json:"processed"`
+
+	// Created khách mới, Updated khách đã có được cập nhật, Skipped không có gì thay đổi, Failed dòng lỗi
+	// Khi chạy thử các bộ đếm là kết quả dự kiến
+	Created int `gorm:"not null;default:0" json:"created"`
+	Updated int `gorm:"not null;default:0" json:"updated"`
+	Skipped int `gorm:"not null;default:0" json:"skipped"`
+	Failed  int `gorm:"not null;default:0" json:"failed"`
+
+	// Errors lỗi theo dòng (tối đa MaxContactJobErrors)
+	Errors ContactJobErrors `gorm:"type:jsonb;default:'[]'" json:"errors"`
+
+	// Error lỗi hệ thống khiến job dừng (status failed)
+	Error string `gorm:"type:text" json:"error,omitempty"`
+
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (ContactJob) TableName() string {
+	return "contact_jobs"
+}
+
+// IsFinished kiểm tra job đã kết thúc chưa
+func (j *ContactJob) IsFinished() bool {
+	return j.Status == ContactJobCompleted || j.Status == ContactJobFailed
+}
+
+// AddError ghi nhận một dòng lỗi
+func (j *ContactJob) AddError(row int, err error) {
+	j.Failed++
+	if len(j.Errors) < MaxContactJobErrors {
+		j.Errors = append(j.Errors, ContactJobError{Row: row, Error: err.Error()})
+	}
+}
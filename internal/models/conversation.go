@@ -30,8 +30,14 @@ const (
 
 	// StatusBotPaused bot tạm dừng, chờ agent xử lý
 	StatusBotPaused ConversationStatus = "bot_paused"
+
+	// StatusSnoozed tạm ẩn khỏi inbox đến SnoozedUntil hoặc khi khách nhắn lại
+	StatusSnoozed ConversationStatus = "snoozed"
 )
 
+// ClosedReasonInactivity lý do đóng khi khách không hoạt động quá lâu
+const ClosedReasonInactivity = "inactivity"
+
 // Priority mức độ ưu tiên
 type Priority string
 
@@ -50,11 +56,29 @@ type ConversationMetadata struct {
 	// BotHandoffReason lý do chuyển cho agent
 	BotHandoffReason string `json:"bot_handoff_reason,omitempty"`
 
-	// ClosedReason lý do đóng hội thoại
+	// ClosedReason lý do đóng hội thoại (VD: "inactivity" khi tự động đóng)
 	ClosedReason string `json:"closed_reason,omitempty"`
 
 	// SLABreached đã vi phạm SLA chưa
 	SLABreached bool `json:"sla_breached,omitempty"`
+
+	// SLABreachedTargets các mục tiêu đã vi phạm (first_response, resolution)
+	SLABreachedTargets []string `json:"sla_breached_targets,omitempty"`
+
+	// SLAEscalatedAt thời điểm đã escalate do vi phạm SLA
+	SLAEscalatedAt *time.Time `json:"sla_escalated_at,omitempty"`
+
+	// Referral nguồn giới thiệu gần nhất (m.me link, quảng cáo, plugin chat)
+	Referral *ConversationReferral `json:"referral,omitempty"`
+}
+
+// ConversationReferral thông tin nguồn giới thiệu của hội thoại
+type ConversationReferral struct {
+	Ref        string    `json:"ref,omitempty"`
+	Source     string    `json:"source"`
+	Type       string    `json:"type,omitempty"`
+	AdID       string    `json:"ad_id,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
 }
 
 // Value implement driver.Valuer cho JSONB
@@ -91,7 +115,7 @@ type Conversation struct {
 	// ChannelThreadID ID thread trên channel (nếu có)
 	ChannelThreadID *string `gorm:"size:255;index" json:"channel_thread_id,omitempty"`
 
-	// Status trạng thái: open, pending, closed, bot_paused
+	// Status trạng thái: open, pending, closed, bot_paused, snoozed
 	Status ConversationStatus `gorm:"size:50;not null;default:'open';index" json:"status"`
 
 	// AssignedTo ID agent được assign (nullable)
@@ -106,6 +130,9 @@ type Conversation struct {
 	// LastMessageAt thời điểm tin nhắn cuối cùng
 	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
 
+	// LastInboundAt thời điểm khách nhắn tin gần nhất
+	LastInboundAt *time.Time `gorm:"index" json:"last_inbound_at,omitempty"`
+
 	// LastMessagePreview preview tin nhắn cuối (max 500 ký tự)
 	LastMessagePreview *string `gorm:"size:500" json:"last_message_preview,omitempty"`
 
@@ -115,9 +142,32 @@ type Conversation struct {
 	// ResolvedAt thời điểm đóng hội thoại
 	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
 
+	// SnoozedUntil thời điểm hết tạm ẩn, SnoozedAt thời điểm bắt đầu tạm ẩn
+	// (khách nhắn sau SnoozedAt thì hội thoại được mở lại sớm)
+	SnoozedUntil *time.Time `gorm:"index" json:"snoozed_until,omitempty"`
+	SnoozedAt    *time.Time `json:"snoozed_at,omitempty"`
+
+	// SnoozedFromStatus trạng thái trước khi tạm ẩn, khôi phục khi mở lại
+	SnoozedFromStatus ConversationStatus `gorm:"size:20" json:"snoozed_from_status,omitempty"`
+
+	// SLAStatus trạng thái SLA (on_track, warning, breached), rỗng = không áp dụng
+	SLAStatus SLAStatus `gorm:"column:sla_status;size:20;index" json:"sla_status,omitempty"`
+
+	// SLAFirstResponseDueAt hạn trả lời lần đầu
+	SLAFirstResponseDueAt *time.Time `gorm:"column:sla_first_response_due_at" json:"sla_first_response_due_at,omitempty"`
+
+	// SLAResolutionDueAt hạn giải quyết
+	SLAResolutionDueAt *time.Time `gorm:"column:sla_resolution_due_at" json:"sla_resolution_due_at,omitempty"`
+
 	// Metadata thông tin bổ sung
 	Metadata ConversationMetadata `gorm:"type:jsonb;default:'{}'" json:"metadata"`
 
+	// UnreadCount số tin nhắn của khách chưa đọc (tính khi trả về API, không lưu DB)
+	UnreadCount int64 `gorm:"-" json:"unread_count"`
+
+	// MessagingWindow trạng thái cửa sổ nhắn tin của channel (tính khi trả về API, không lưu DB)
+	MessagingWindow *MessagingWindow `gorm:"-" json:"messaging_window,omitempty"`
+
 	// Relations
 	Workspace      Workspace      `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
 	ChannelAccount ChannelAccount `gorm:"foreignKey:ChannelAccountID" json:"channel_account,omitempty"`
@@ -142,6 +192,9 @@ func (c *Conversation) IsClosed() bool { return c.Status == StatusClosed }
 // IsBotPaused kiểm tra bot có đang tạm dừng không
 func (c *Conversation) IsBotPaused() bool { return c.Status == StatusBotPaused }
 
+// IsSnoozed kiểm tra hội thoại đang tạm ẩn
+func (c *Conversation) IsSnoozed() bool { return c.Status == StatusSnoozed }
+
 // IsAssigned kiểm tra đã được assign cho agent chưa
 func (c *Conversation) IsAssigned() bool { return c.AssignedTo != nil }
 
@@ -151,6 +204,9 @@ func (c *Conversation) Assign(userID uuid.UUID) {
 	if c.Status == StatusOpen {
 		c.Status = StatusPending
 	}
+	if c.IsSnoozed() && c.SnoozedFromStatus == StatusOpen {
+		c.SnoozedFromStatus = StatusPending
+	}
 }
 
 // Unassign bỏ gán agent
@@ -160,6 +216,7 @@ func (c *Conversation) Unassign() {
 
 // Close đóng hội thoại với lý do
 func (c *Conversation) Close(reason string) {
+	c.clearSnooze()
 	c.Status = StatusClosed
 	now := time.Now()
 	c.ResolvedAt = &now
@@ -175,16 +232,52 @@ func (c *Conversation) Reopen() {
 
 // PauseBot tạm dừng bot, chờ agent xử lý
 func (c *Conversation) PauseBot(reason string) {
+	c.clearSnooze()
 	c.Status = StatusBotPaused
 	c.Metadata.BotHandoffReason = reason
 }
 
 // ResumeBot tiếp tục bot
 func (c *Conversation) ResumeBot() {
+	c.clearSnooze()
 	c.Status = StatusOpen
 	c.Metadata.BotHandoffReason = ""
 }
 
+// Snooze tạm ẩn hội thoại đến until, ghi nhớ trạng thái hiện tại để mở lại
+// Tạm ẩn lại hội thoại đang tạm ẩn chỉ đổi thời hạn
+func (c *Conversation) Snooze(until time.Time) {
+	now := time.Now()
+	if !c.IsSnoozed() {
+		c.SnoozedFromStatus = c.Status
+	}
+	c.Status = StatusSnoozed
+	c.SnoozedUntil = &until
+	c.SnoozedAt = &now
+}
+
+// Unsnooze mở lại hội thoại đang tạm ẩn về trạng thái trước khi tạm ẩn (bot_paused, pending, ...)
+func (c *Conversation) Unsnooze() {
+	status := c.SnoozedFromStatus
+	if status == "" || status == StatusSnoozed || status == StatusClosed {
+		status = StatusOpen
+	}
+	c.clearSnooze()
+	c.Status = status
+}
+
+// RepliedWhileSnoozed kiểm tra khách đã nhắn lại kể từ lúc tạm ẩn chưa
+func (c *Conversation) RepliedWhileSnoozed() bool {
+	return c.SnoozedAt != nil && c.LastInboundAt != nil && c.LastInboundAt.After(*c.SnoozedAt)
+}
+
+// clearSnooze xóa thông tin tạm ẩn khi hội thoại chuyển trạng thái
+func (c *Conversation) clearSnooze() {
+	c.SnoozedUntil = nil
+	c.SnoozedAt = nil
+	c.SnoozedFromStatus = ""
+}
+
 // UpdateLastMessage cập nhật thông tin tin nhắn cuối
 func (c *Conversation) UpdateLastMessage(content string, at time.Time) {
 	c.LastMessageAt = &at
@@ -196,9 +289,36 @@ func (c *Conversation) UpdateLastMessage(content string, at time.Time) {
 	}
 }
 
+// RecordInbound ghi nhận khách vừa nhắn tin
+func (c *Conversation) RecordInbound(at time.Time) {
+	c.LastInboundAt = &at
+}
+
+// CustomerLastInboundAt lần cuối khách nhắn tin
+// Lấy mốc muộn hơn giữa hội thoại và participant (khách có thể nhắn ở hội thoại khác cùng kênh)
+func (c *Conversation) CustomerLastInboundAt() *time.Time {
+	last := c.LastInboundAt
+	if p := c.Participant.LastInboundAt; p != nil && (last == nil || p.After(*last)) {
+		last = p
+	}
+	return last
+}
+
 // SetFirstResponse đánh dấu thời điểm trả lời đầu tiên
 func (c *Conversation) SetFirstResponse(at time.Time) {
 	if c.FirstResponseAt == nil {
 		c.FirstResponseAt = &at
 	}
 }
+
+// Raise trả về mức ưu tiên cao hơn một bậc (urgent giữ nguyên)
+func (p Priority) Raise() Priority {
+	switch p {
+	case PriorityLow:
+		return PriorityNormal
+	case PriorityNormal:
+		return PriorityHigh
+	default:
+		return PriorityUrgent
+	}
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// CSAT (Customer Satisfaction)
+// Khảo sát mức độ hài lòng gửi cho khách khi hội thoại được đóng
+// Mỗi conversation có tối đa một khảo sát
+// ===========================================================================
+
+// CSATStatus trạng thái khảo sát
+type CSATStatus string
+
+const (
+	// CSATPending đã gửi câu hỏi, chờ khách chấm điểm
+	CSATPending CSATStatus = "pending"
+
+	// CSATAwaitingComment đã chấm điểm, chờ khách góp ý thêm
+	CSATAwaitingComment CSATStatus = "awaiting_comment"
+
+	// CSATCompleted khách đã trả lời xong
+	CSATCompleted CSATStatus = "completed"
+
+	// CSATIgnored khách nhắn nội dung khác thay vì trả lời khảo sát
+	CSATIgnored CSATStatus = "ignored"
+)
+
+// CSATPayloadPrefix tiền tố payload của quick reply chấm điểm (VD: "CSAT_5")
+const CSATPayloadPrefix = "CSAT_"
+
+// CSATMinScore, CSATMaxScore thang điểm khảo sát
+const (
+	CSATMinScore = 1
+	CSATMaxScore = 5
+)
+
+// CSATSettings cấu hình khảo sát của workspace
+type CSATSettings struct {
+	// Enabled gửi khảo sát khi đóng hội thoại
+	Enabled bool `json:"enabled"`
+
+	// Prompt câu hỏi chấm điểm
+	Prompt string `json:"prompt"`
+
+	// AskComment hỏi thêm góp ý sau khi khách chấm điểm
+	AskComment bool `json:"ask_comment"`
+
+	// CommentPrompt câu hỏi góp ý
+	CommentPrompt string `json:"comment_prompt"`
+
+	// ThankYouMessage lời cảm ơn sau khi khách trả lời xong
+	ThankYouMessage string `json:"thank_you_message"`
+
+	// ExpireHours khảo sát hết hạn sau N giờ (mặc định 24)
+	ExpireHours int `json:"expire_hours"`
+}
+
+// CSATConfig trả về cấu hình khảo sát với giá trị mặc định
+// Trả về Enabled = false nếu workspace chưa cấu hình
+func (s WorkspaceSettings) CSATConfig() CSATSettings {
+	if s.CSAT == nil {
+		return CSATSettings{}
+	}
+	cfg := *s.CSAT
+	if cfg.Prompt == "" {
+		cfg.Prompt = "Bạn đánh giá thế nào về cuộc trò chuyện vừa rồi? (1 = rất tệ, 5 = rất tốt)"
+	}
+	if cfg.CommentPrompt == "" {
+		cfg.CommentPrompt = "Bạn có góp ý gì thêm cho chúng tôi không?"
+	}
+	if cfg.ThankYouMessage == "" {
+		cfg.ThankYouMessage = "Cảm ơn bạn đã đánh giá!"
+	}
+	if cfg.ExpireHours <= 0 {
+		cfg.ExpireHours = 24
+	}
+	return cfg
+}
+
+// CSATResponse một khảo sát gửi cho khách
+type CSATResponse struct {
+	BaseModel
+
+	// WorkspaceID ID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// ConversationID conversation được khảo sát
+	ConversationID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"conversation_id"`
+
+	// ChannelAccountID kênh của conversation (cho báo cáo theo kênh)
+	ChannelAccountID uuid.UUID `gorm:"type:uuid;not null;index" json:"channel_account_id"`
+
+	// ParticipantID khách hàng được khảo sát
+	ParticipantID uuid.UUID `gorm:"type:uuid;not null;index" json:"participant_id"`
+
+	// AgentID agent phụ trách conversation lúc đóng (nil = bot xử lý)
+	AgentID *uuid.UUID `gorm:"type:uuid;index" json:"agent_id,omitempty"`
+
+	// Status trạng thái khảo sát
+	Status CSATStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+
+	// Score điểm 1-5 (nil = chưa trả lời)
+	Score *int `json:"score,omitempty"`
+
+	// Comment góp ý của khách
+	Comment string `gorm:"type:text" json:"comment,omitempty"`
+
+	// SentAt thời điểm gửi khảo sát
+	SentAt time.Time `gorm:"not null;index" json:"sent_at"`
+
+	// RespondedAt thời điểm khách chấm điểm
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+
+	// ExpiresAt sau thời điểm này không nhận câu trả lời nữa
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+
+	// Relations
+	Agent *User `gorm:"foreignKey:AgentID" json:"agent,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (CSATResponse) TableName() string {
+	return "csat_responses"
+}
+
+// IsOpen kiểm tra khảo sát còn chờ khách trả lời không
+func (r *CSATResponse) IsOpen(now time.Time) bool {
+	return (r.Status == CSATPending || r.Status == CSATAwaitingComment) && now.Before(r.ExpiresAt)
+}
+
+// Rate ghi nhận điểm của khách
+func (r *CSATResponse) Rate(score int, comment string, at time.Time, awaitComment bool) {
+	r.Score = &score
+	r.RespondedAt = &at
+	r.Comment = comment
+	r.Status = CSATCompleted
+	if awaitComment && comment == "" {
+		r.Status = CSATAwaitingComment
+	}
+}
+
+// AddComment ghi nhận góp ý và hoàn tất khảo sát
+func (r *CSATResponse) AddComment(comment string) {
+	r.Comment = comment
+	r.Status = CSATCompleted
+}
+
+// ParseCSATAnswer đọc điểm từ tin nhắn của khách
+// Chấp nhận payload quick reply ("CSAT_4") hoặc text bắt đầu bằng số 1-5 ("4", "5 rất tốt")
+// Phần text sau điểm được trả về làm góp ý
+func ParseCSATAnswer(content string) (score int, comment string, ok bool) {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, CSATPayloadPrefix) {
+		score, err := strconv.Atoi(strings.TrimPrefix(content, CSATPayloadPrefix))
+		if err != nil || score < CSATMinScore || score > CSATMaxScore {
+			return 0, "", false
+		}
+		return score, "", true
+	}
+
+	if content == "" || content[0] < '0'+CSATMinScore || content[0] > '0'+CSATMaxScore {
+		return 0, "", false
+	}
+	// Chỉ nhận một chữ số đứng riêng, tránh nhầm "100k" hay "2h" là điểm
+	rest := content[1:]
+	if rest != "" && !strings.ContainsAny(rest[:1], " .,!-*/\n") {
+		return 0, "", false
+	}
+	score = int(content[0] - '0')
+	comment = strings.TrimSpace(strings.TrimLeft(rest, " .,!-*/\n"))
+	return score, comment, true
+}
@@ -0,0 +1,386 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Custom Field Definition (Định nghĩa trường tùy chỉnh)
+// Workspace khai báo các trường tùy chỉnh của khách (key, kiểu, bắt buộc, lựa chọn)
+// Giá trị lưu trong participants.metadata.custom_fields theo key
+// ===========================================================================
+
+// CustomFieldType kiểu dữ liệu của trường tùy chỉnh
+type CustomFieldType string
+
+const (
+	// FieldText chuỗi tự do
+	FieldText CustomFieldType = "text"
+
+	// FieldNumber số (lưu dạng số JSON)
+	FieldNumber CustomFieldType = "number"
+
+	// FieldDate ngày, lưu dạng "2006-01-02"
+	FieldDate CustomFieldType = "date"
+
+	// FieldSelect một trong các lựa chọn khai báo sẵn
+	FieldSelect CustomFieldType = "select"
+
+	// FieldBoolean true/false
+	FieldBoolean CustomFieldType = "boolean"
+)
+
+// CustomFieldDateLayout định dạng lưu trường kiểu date
+const CustomFieldDateLayout = "2006-01-02"
+
+// MaxCustomFieldTextLength độ dài tối đa giá trị text của trường tùy chỉnh
+const MaxCustomFieldTextLength = 1000
+
+// CustomFieldKeyPattern key hợp lệ: chữ thường, số, gạch dưới, bắt đầu bằng chữ
+// Key được dùng trực tiếp trong biến template ({{contact.fields.key}}) và sắp xếp
+var CustomFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// CustomFieldDefinition định nghĩa một trường tùy chỉnh của workspace
+type CustomFieldDefinition struct {
+	BaseModel
+
+	// WorkspaceID workspace sở hữu
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index:idx_custom_field_key" json:"workspace_id"`
+
+	// Key tên trường trong custom_fields (không đổi sau khi tạo)
+	Key string `gorm:"size:50;not null;index:idx_custom_field_key" json:"key"`
+
+	// Label tên hiển thị
+	Label string `gorm:"size:255;not null" json:"label"`
+
+	// Type kiểu dữ liệu (không đổi sau khi tạo)
+	Type CustomFieldType `gorm:"size:20;not null" json:"type"`
+
+	// Required không được xóa giá trị khi sửa khách
+	Required bool `gorm:"not null;default:false" json:"required"`
+
+	// Options các lựa chọn (chỉ dùng cho kiểu select)
+	Options StringList `gorm:"type:jsonb;default:'[]'" json:"options"`
+
+	// Position thứ tự hiển thị
+	Position int `gorm:"not null;default:0" json:"position"`
+}
+
+// TableName trả về tên bảng
+func (CustomFieldDefinition) TableName() string {
+	return "custom_field_definitions"
+}
+
+// IsValidType kiểm tra kiểu trường có được hỗ trợ không
+func (t CustomFieldType) IsValidType() bool {
+	switch t {
+	case FieldText, FieldNumber, FieldDate, FieldSelect, FieldBoolean:
+		return true
+	}
+	return false
+}
+
+// Parse kiểm tra và chuẩn hóa giá trị theo kiểu trường
+// Nhận cả giá trị đúng kiểu (số, true/false) lẫn dạng chuỗi (query string, CSV)
+// Trả về nil nếu giá trị rỗng (xóa trường)
+func (d *CustomFieldDefinition) Parse(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if s, ok := value.(string); ok {
+		value = strings.TrimSpace(s)
+		if value == "" {
+			return nil, nil
+		}
+	}
+
+	switch d.Type {
+	case FieldText:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("cần giá trị text")
+		}
+		if len([]rune(s)) > MaxCustomFieldTextLength {
+			return nil, errors.New("giá trị quá dài")
+		}
+		return s, nil
+
+	case FieldNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			n, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64)
+			if err != nil {
+				return nil, errors.New("cần giá trị số")
+			}
+			return n, nil
+		}
+		return nil, errors.New("cần giá trị số")
+
+	case FieldBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.ToLower(v))
+			if err != nil {
+				return nil, errors.New("cần giá trị true/false")
+			}
+			return b, nil
+		}
+		return nil, errors.New("cần giá trị true/false")
+
+	case FieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("cần ngày dạng YYYY-MM-DD")
+		}
+		if t, err := time.Parse(CustomFieldDateLayout, s); err == nil {
+			return t.Format(CustomFieldDateLayout), nil
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t.Format(CustomFieldDateLayout), nil
+		}
+		return nil, errors.New("cần ngày dạng YYYY-MM-DD")
+
+	case FieldSelect:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("cần một trong các lựa chọn")
+		}
+		for _, option := range d.Options {
+			if strings.EqualFold(option, s) {
+				return option, nil
+			}
+		}
+		return nil, fmt.Errorf("chỉ nhận một trong: %s", strings.Join(d.Options, ", "))
+	}
+	return nil, errors.New("kiểu trường không được hỗ trợ")
+}
+
+// SupportsOperator kiểm tra toán tử điều kiện có dùng được với kiểu trường không
+func (d *CustomFieldDefinition) SupportsOperator(op FieldOperator) bool {
+	switch op {
+	case OpEquals, OpNotEquals, OpExists, OpNotExists:
+		return true
+	case OpGreater, OpGreaterOrEqual, OpLess, OpLessOrEqual:
+		return d.Type == FieldNumber || d.Type == FieldDate
+	case OpContains:
+		return d.Type == FieldText
+	case OpIn:
+		return d.Type != FieldBoolean
+	}
+	return false
+}
+
+// ParseCondition kiểm tra toán tử và chuẩn hóa giá trị điều kiện theo kiểu trường
+// Toán tử in nhận mảng hoặc chuỗi phân cách bằng dấu phẩy
+func (d *CustomFieldDefinition) ParseCondition(cond FieldCondition) (FieldCondition, error) {
+	if cond.Operator == "" {
+		cond.Operator = OpEquals
+	}
+	if !d.SupportsOperator(cond.Operator) {
+		return cond, fmt.Errorf("toán tử %s không dùng được với kiểu %s", cond.Operator, d.Type)
+	}
+	if cond.Operator == OpExists || cond.Operator == OpNotExists {
+		cond.Value = nil
+		return cond, nil
+	}
+
+	if cond.Operator == OpIn {
+		var raw []interface{}
+		switch v := cond.Value.(type) {
+		case []interface{}:
+			raw = v
+		case []string:
+			for _, s := range v {
+				raw = append(raw, s)
+			}
+		case string:
+			for _, s := range strings.Split(v, ",") {
+				raw = append(raw, s)
+			}
+		default:
+			return cond, errors.New("toán tử in cần danh sách giá trị")
+		}
+		values := make([]interface{}, 0, len(raw))
+		for _, item := range raw {
+			value, err := d.Parse(item)
+			if err != nil {
+				return cond, err
+			}
+			if value != nil {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return cond, errors.New("toán tử in cần danh sách giá trị")
+		}
+		cond.Value = values
+		return cond, nil
+	}
+
+	value, err := d.Parse(cond.Value)
+	if err != nil {
+		return cond, err
+	}
+	if value == nil {
+		return cond, errors.New("thiếu giá trị so sánh")
+	}
+	cond.Value = value
+	return cond, nil
+}
+
+// ===========================================================================
+// Field Condition (Điều kiện theo trường tùy chỉnh)
+// Dùng cho lọc khách/hội thoại và điều kiện của bot rule
+// ===========================================================================
+
+// FieldOperator toán tử so sánh
+type FieldOperator string
+
+const (
+	OpEquals         FieldOperator = "eq"
+	OpNotEquals      FieldOperator = "neq"
+	OpGreater        FieldOperator = "gt"
+	OpGreaterOrEqual FieldOperator = "gte"
+	OpLess           FieldOperator = "lt"
+	OpLessOrEqual    FieldOperator = "lte"
+	OpContains       FieldOperator = "contains"
+	OpIn             FieldOperator = "in"
+	OpExists         FieldOperator = "exists"
+	OpNotExists      FieldOperator = "not_exists"
+)
+
+// FieldCondition điều kiện trên một trường tùy chỉnh của khách
+type FieldCondition struct {
+	// Field key của trường tùy chỉnh
+	Field string `json:"field"`
+
+	// Operator toán tử (mặc định eq)
+	Operator FieldOperator `json:"operator"`
+
+	// Value giá trị so sánh (mảng với toán tử in, bỏ trống với exists/not_exists)
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Matches kiểm tra giá trị trường tùy chỉnh của khách có thỏa điều kiện không
+// Khách chưa có giá trị chỉ thỏa not_exists và neq
+func (c FieldCondition) Matches(fields map[string]interface{}) bool {
+	value, present := fields[c.Field]
+	present = present && value != nil && value != ""
+
+	switch c.Operator {
+	case OpExists:
+		return present
+	case OpNotExists:
+		return !present
+	case OpNotEquals:
+		if !present {
+			return true
+		}
+		cmp, ok := compareFieldValues(value, c.Value)
+		return !ok || cmp != 0
+	}
+	if !present {
+		return false
+	}
+
+	switch c.Operator {
+	case OpEquals, "":
+		cmp, ok := compareFieldValues(value, c.Value)
+		return ok && cmp == 0
+	case OpGreater, OpGreaterOrEqual, OpLess, OpLessOrEqual:
+		cmp, ok := compareFieldValues(value, c.Value)
+		if !ok {
+			return false
+		}
+		switch c.Operator {
+		case OpGreater:
+			return cmp > 0
+		case OpGreaterOrEqual:
+			return cmp >= 0
+		case OpLess:
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	case OpContains:
+		s, ok := value.(string)
+		sub, subOK := c.Value.(string)
+		return ok && subOK && strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+	case OpIn:
+		values, _ := c.Value.([]interface{})
+		for _, candidate := range values {
+			if cmp, ok := compareFieldValues(value, candidate); ok && cmp == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareFieldValues so sánh hai giá trị cùng kiểu (số, chuỗi, true/false)
+// Trả về false nếu khác kiểu
+func compareFieldValues(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	case bool:
+		y, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x == y:
+			return 0, true
+		case !x:
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+// FormatCustomFieldValue giá trị hiển thị của trường tùy chỉnh (biến template, CSV)
+func FormatCustomFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Macro (Thao tác nhanh)
+// Gom nhiều thao tác trên hội thoại (trả lời, gắn tag, đổi ưu tiên, assign,
+// dừng/bật bot, đóng) để agent thực hiện bằng một cú click
+// ===========================================================================
+
+// MacroActionType loại thao tác của macro
+type MacroActionType string
+
+const (
+	// MacroSendReply gửi tin nhắn (Content hoặc CannedResponseID)
+	MacroSendReply MacroActionType = "send_reply"
+
+	// MacroAddTags gắn tag (Tags, tự tạo tag chưa có)
+	MacroAddTags MacroActionType = "add_tags"
+
+	// MacroRemoveTags gỡ tag (Tags)
+	MacroRemoveTags MacroActionType = "remove_tags"
+
+	// MacroSetPriority đổi mức ưu tiên (Priority)
+	MacroSetPriority MacroActionType = "set_priority"
+
+	// MacroAssign assign cho agent (UserID), cho team (Team = kỹ năng của agent)
+	// hoặc bỏ assign nếu không có cả hai
+	MacroAssign MacroActionType = "assign"
+
+	// MacroPauseBot dừng bot (Reason)
+	MacroPauseBot MacroActionType = "pause_bot"
+
+	// MacroResumeBot bật lại bot
+	MacroResumeBot MacroActionType = "resume_bot"
+
+	// MacroClose đóng hội thoại (Reason)
+	MacroClose MacroActionType = "close"
+)
+
+// MacroAction một thao tác trong macro, các trường dùng tùy theo Type
+type MacroAction struct {
+	Type MacroActionType `json:"type"`
+
+	// Content, CannedResponseID nội dung trả lời (send_reply), Content hỗ trợ biến template
+	Content          string     `json:"content,omitempty"`
+	CannedResponseID *uuid.UUID `json:"canned_response_id,omitempty"`
+
+	// Tags tên tag (add_tags, remove_tags)
+	Tags []string `json:"tags,omitempty"`
+
+	// Priority mức ưu tiên (set_priority)
+	Priority Priority `json:"priority,omitempty"`
+
+	// UserID, Team người hoặc team nhận hội thoại (assign)
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	Team   string     `json:"team,omitempty"`
+
+	// Reason lý do (pause_bot, close)
+	Reason string `json:"reason,omitempty"`
+}
+
+// MacroActions danh sách thao tác cho JSONB
+type MacroActions []MacroAction
+
+// Value implement driver.Valuer cho JSONB
+func (a MacroActions) Value() (driver.Value, error) {
+	if a == nil {
+		return json.Marshal([]MacroAction{})
+	}
+	return json.Marshal(a)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (a *MacroActions) Scan(value interface{}) error {
+	if value == nil {
+		*a = MacroActions{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// Macro thao tác nhanh của workspace
+type Macro struct {
+	BaseModel
+
+	// WorkspaceID workspace sở hữu
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// CreatedBy người tạo
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+
+	// Name tên hiển thị, Description mô tả
+	Name        string `gorm:"size:255;not null" json:"name"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+
+	// Actions các thao tác, thực hiện theo thứ tự
+	Actions MacroActions `gorm:"type:jsonb;default:'[]'" json:"actions"`
+
+	// IsActive macro có đang dùng được không
+	IsActive bool `gorm:"default:true" json:"is_active"`
+
+	// UsageCount số lần đã chạy, LastUsedAt lần chạy gần nhất
+	UsageCount int        `gorm:"default:0" json:"usage_count"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (Macro) TableName() string {
+	return "macros"
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Media (File đính kèm đã lưu)
+// File của tin nhắn được tải từ channel về blob storage của hệ thống
+// URL gốc từ channel (VD: CDN của Facebook) hết hạn sau vài ngày nên không dùng lâu dài được
+// ===========================================================================
+
+// MediaSource nguồn của file
+type MediaSource string
+
+const (
+	// MediaSourceInbound file khách gửi, tải từ URL của channel
+	MediaSourceInbound MediaSource = "inbound"
+
+	// MediaSourceUpload file agent upload để gửi cho khách
+	MediaSourceUpload MediaSource = "upload"
+)
+
+// MediaStatus trạng thái lưu file
+type MediaStatus string
+
+const (
+	// MediaPending chưa tải xong (đang chờ hoặc sẽ thử lại)
+	MediaPending MediaStatus = "pending"
+
+	// MediaStored đã lưu vào storage
+	MediaStored MediaStatus = "stored"
+
+	// MediaFailed đã thử tối đa số lần mà vẫn lỗi
+	MediaFailed MediaStatus = "failed"
+)
+
+// Media file đính kèm được lưu trong storage
+type Media struct {
+	BaseModel
+
+	// WorkspaceID workspace sở hữu file (kiểm tra quyền truy cập)
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// ConversationID, MessageID tin nhắn chứa file
+	ConversationID *uuid.UUID `gorm:"type:uuid;index" json:"conversation_id,omitempty"`
+	MessageID      *uuid.UUID `gorm:"type:uuid;index" json:"message_id,omitempty"`
+
+	// AttachmentIndex vị trí của file trong Message.Attachments
+	AttachmentIndex int `gorm:"default:0" json:"attachment_index"`
+
+	// UploadedBy agent upload file (với Source = upload)
+	UploadedBy *uuid.UUID `gorm:"type:uuid" json:"uploaded_by,omitempty"`
+
+	// Source nguồn file (inbound, upload)
+	Source MediaSource `gorm:"size:20;not null" json:"source"`
+
+	// Status trạng thái lưu file
+	Status MediaStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+
+	// OriginalURL URL gốc trên channel
+	OriginalURL string `gorm:"type:text" json:"-"`
+
+	// StorageKey, ThumbnailKey key của file và ảnh thu nhỏ trong storage
+	StorageKey   string `gorm:"size:255" json:"-"`
+	ThumbnailKey string `gorm:"size:255" json:"-"`
+
+	// Name, Size, MimeType thông tin file
+	Name     string `gorm:"size:255" json:"name"`
+	Size     int64  `json:"size"`
+	MimeType string `gorm:"size:100" json:"mime_type"`
+
+	// Width, Height kích thước ảnh (0 với file không phải ảnh)
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// Attempts số lần đã thử tải, LastError lỗi gần nhất
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+}
+
+// TableName trả về tên bảng trong database
+func (Media) TableName() string {
+	return "media"
+}
+
+// URL đường dẫn API tải file
+func (m *Media) URL() string {
+	return fmt.Sprintf("/api/v1/media/%s", m.ID)
+}
+
+// ThumbnailURL đường dẫn API tải ảnh thu nhỏ (rỗng nếu không có)
+func (m *Media) ThumbnailURL() string {
+	if m.ThumbnailKey == "" {
+		return ""
+	}
+	return m.URL() + "?thumbnail=true"
+}
+
+// Attachment attachment của tin nhắn trỏ về file đã lưu
+func (m *Media) Attachment(attachmentType string) Attachment {
+	id := m.ID
+	return Attachment{
+		Type:         attachmentType,
+		URL:          m.URL(),
+		Name:         m.Name,
+		Size:         m.Size,
+		MimeType:     m.MimeType,
+		MediaID:      &id,
+		ThumbnailURL: m.ThumbnailURL(),
+		Width:        m.Width,
+		Height:       m.Height,
+	}
+}
+
+// IsStored kiểm tra file đã lưu xong chưa
+func (m *Media) IsStored() bool {
+	return m.Status == MediaStored
+}
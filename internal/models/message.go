@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -58,6 +59,12 @@ type Attachment struct {
 	Name     string `json:"name"`      // Tên file
 	Size     int64  `json:"size"`      // Kích thước (bytes)
 	MimeType string `json:"mime_type"` // MIME type
+
+	// MediaID file đã lưu trong storage, URL trỏ về /api/v1/media/:id khi lưu xong
+	MediaID      *uuid.UUID `json:"media_id,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"` // Ảnh thu nhỏ (chỉ với ảnh)
+	Width        int        `json:"width,omitempty"`         // Kích thước ảnh
+	Height       int        `json:"height,omitempty"`
 }
 
 // Attachments danh sách attachments cho JSONB
@@ -84,6 +91,21 @@ func (a *Attachments) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, a)
 }
 
+// Preview nội dung xem trước của tin nhắn chỉ có file đính kèm (VD: "📎 invoice.pdf")
+func (a Attachments) Preview() string {
+	if len(a) == 0 {
+		return ""
+	}
+	name := a[0].Name
+	if name == "" {
+		name = a[0].Type
+	}
+	if len(a) > 1 {
+		return fmt.Sprintf("📎 %s (+%d)", name, len(a)-1)
+	}
+	return "📎 " + name
+}
+
 // QuickReply nút quick reply
 type QuickReply struct {
 	Title   string `json:"title"`   // Text hiển thị
@@ -109,15 +131,43 @@ type MessageMetadata struct {
 	// MatchedRuleID ID rule đã match (nếu bot trả lời)
 	MatchedRuleID *uuid.UUID `json:"matched_rule_id,omitempty"`
 
+	// CannedResponseID câu trả lời soạn sẵn agent đã dùng (nếu có)
+	CannedResponseID *uuid.UUID `json:"canned_response_id,omitempty"`
+
+	// ScheduledMessageID tin nhắn hẹn giờ tạo ra tin nhắn này (hoặc đang chờ gửi)
+	ScheduledMessageID *uuid.UUID `json:"scheduled_message_id,omitempty"`
+
+	// ScheduledAt giờ hẹn gửi (chỉ có ở tin nhắn hẹn giờ đang chờ gửi)
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+
+	// CampaignID chiến dịch gửi tin nhắn này (nếu có)
+	CampaignID *uuid.UUID `json:"campaign_id,omitempty"`
+
+	// MessageTag message tag dùng khi gửi ngoài cửa sổ nhắn tin của channel
+	MessageTag string `json:"message_tag,omitempty"`
+
+	// OutsideWindow agent gửi khi khách đã ngoài cửa sổ nhắn tin và không có message tag
+	// (chính sách warn, channel có thể từ chối tin nhắn)
+	OutsideWindow bool `json:"outside_window,omitempty"`
+
 	// MatchedKeyword keyword đã match
 	MatchedKeyword string `json:"matched_keyword,omitempty"`
 
 	// Confidence độ tin cậy của match
 	Confidence float64 `json:"confidence,omitempty"`
 
-	// DeliveredAt thời điểm đã gửi đến channel
+	// SentAt thời điểm channel nhận tin nhắn (Send API trả về thành công)
+	SentAt *time.Time `json:"sent_at,omitempty"`
+
+	// DeliveredAt thời điểm khách đã nhận (chỉ ghi khi channel báo nhận)
 	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
 
+	// SeenAt thời điểm khách đã xem (channel có báo đã xem)
+	SeenAt *time.Time `json:"seen_at,omitempty"`
+
+	// Reactions reaction của khách trên tin nhắn
+	Reactions []MessageReaction `json:"reactions,omitempty"`
+
 	// FailedAt thời điểm gửi thất bại
 	FailedAt *time.Time `json:"failed_at,omitempty"`
 
@@ -126,6 +176,25 @@ type MessageMetadata struct {
 
 	// RetryCount số lần đã retry
 	RetryCount int `json:"retry_count,omitempty"`
+
+	// Degradations các điều chỉnh khi gửi do giới hạn của channel
+	// (VD: tách text dài, chuyển nút thành lựa chọn đánh số)
+	Degradations []string `json:"degradations,omitempty"`
+}
+
+// MessageReaction reaction của một người trên tin nhắn
+type MessageReaction struct {
+	// By ID người reaction trên channel
+	By string `json:"by"`
+
+	// Emoji ký tự emoji
+	Emoji string `json:"emoji"`
+
+	// Reaction tên reaction (love, like, ...)
+	Reaction string `json:"reaction,omitempty"`
+
+	// ReactedAt thời điểm reaction
+	ReactedAt time.Time `json:"reacted_at"`
 }
 
 // Value implement driver.Valuer cho JSONB
@@ -217,6 +286,23 @@ func (m *Message) MarkAsRead() {
 	}
 }
 
+// SetReaction thêm hoặc thay reaction của một người (mỗi người một reaction)
+func (m *Message) SetReaction(reaction MessageReaction) {
+	m.RemoveReaction(reaction.By)
+	m.Metadata.Reactions = append(m.Metadata.Reactions, reaction)
+}
+
+// RemoveReaction bỏ reaction của một người
+func (m *Message) RemoveReaction(by string) {
+	kept := m.Metadata.Reactions[:0]
+	for _, r := range m.Metadata.Reactions {
+		if r.By != by {
+			kept = append(kept, r)
+		}
+	}
+	m.Metadata.Reactions = kept
+}
+
 // GetContentPreview trả về preview nội dung
 func (m *Message) GetContentPreview(maxLen int) string {
 	if m.Content == nil {
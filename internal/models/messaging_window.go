@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ===========================================================================
+// Messaging Window (Cửa sổ nhắn tin)
+// Một số channel chỉ cho doanh nghiệp nhắn tin trong một khoảng thời gian
+// sau tin nhắn cuối của khách (Facebook: 24 giờ). Ngoài cửa sổ cần kèm message tag
+// ===========================================================================
+
+// MessagingWindow trạng thái cửa sổ nhắn tin của một hội thoại
+type MessagingWindow struct {
+	// Hours độ dài cửa sổ của channel (0 = channel không giới hạn)
+	Hours int `json:"hours"`
+
+	// Open còn trong cửa sổ, agent trả lời bình thường
+	Open bool `json:"open"`
+
+	// LastInboundAt lần cuối khách nhắn tin
+	LastInboundAt *time.Time `json:"last_inbound_at,omitempty"`
+
+	// ExpiresAt thời điểm cửa sổ đóng (nil nếu channel không giới hạn hoặc khách chưa nhắn)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// MessageTags các message tag còn dùng được khi ngoài cửa sổ (đã bỏ tag quá hạn)
+	MessageTags []string `json:"message_tags,omitempty"`
+}
@@ -9,16 +9,30 @@ package models
 // Dùng cho database.AutoMigrate() để tự động tạo/update tables
 func AllModels() []interface{} {
 	return []interface{}{
-		&Workspace{},       // Không gian làm việc
-		&User{},            // Người dùng hệ thống
-		&ChannelAccount{},  // Tài khoản kênh chat
-		&Participant{},     // Khách hàng
-		&Conversation{},    // Cuộc hội thoại
-		&Message{},         // Tin nhắn
-		&Rule{},            // Quy tắc bot
-		&WebhookEvent{},    // Sự kiện webhook
-		&Tag{},             // Nhãn
-		&ConversationTag{}, // Liên kết conversation-tag
-		&Note{},            // Ghi chú nội bộ
+		&Workspace{},             // Không gian làm việc
+		&User{},                  // Người dùng hệ thống
+		&ChannelAccount{},        // Tài khoản kênh chat
+		&Person{},                // Khách hợp nhất nhiều kênh
+		&Participant{},           // Khách hàng
+		&Conversation{},          // Cuộc hội thoại
+		&Message{},               // Tin nhắn
+		&Rule{},                  // Quy tắc bot
+		&WebhookEvent{},          // Sự kiện webhook
+		&Tag{},                   // Nhãn
+		&ConversationTag{},       // Liên kết conversation-tag
+		&Note{},                  // Ghi chú nội bộ
+		&Notification{},          // Thông báo cho user
+		&RoutingLog{},            // Nhật ký phân công agent
+		&CSATResponse{},          // Khảo sát mức độ hài lòng
+		&Media{},                 // File đính kèm đã lưu
+		&CannedResponse{},        // Câu trả lời soạn sẵn
+		&Macro{},                 // Thao tác nhanh trên hội thoại
+		&AuditLog{},              // Nhật ký thao tác
+		&BulkJob{},               // Thao tác hàng loạt trên hội thoại
+		&ScheduledMessage{},      // Tin nhắn hẹn giờ
+		&Campaign{},              // Chiến dịch gửi tin hàng loạt
+		&CampaignRecipient{},     // Kết quả gửi theo từng khách
+		&CustomFieldDefinition{}, // Định nghĩa trường tùy chỉnh
+		&ContactJob{},            // Nhập/xuất danh sách khách
 	}
 }
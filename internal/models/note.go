@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -26,6 +28,12 @@ type Note struct {
 	// IsPinned có ghim ghi chú không
 	IsPinned bool `gorm:"default:false" json:"is_pinned"`
 
+	// Mentions danh sách user được @mention trong ghi chú
+	Mentions UUIDList `gorm:"type:jsonb;default:'[]'" json:"mentions"`
+
+	// EditedAt thời điểm chỉnh sửa gần nhất (nil = chưa sửa)
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+
 	// Relations
 	Conversation Conversation `gorm:"foreignKey:ConversationID" json:"conversation,omitempty"`
 	User         User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -41,3 +49,13 @@ func (n *Note) Pin() { n.IsPinned = true }
 
 // Unpin bỏ ghim ghi chú
 func (n *Note) Unpin() { n.IsPinned = false }
+
+// Edit cập nhật nội dung ghi chú và đánh dấu thời điểm sửa
+func (n *Note) Edit(content string) {
+	n.Content = content
+	now := time.Now()
+	n.EditedAt = &now
+}
+
+// IsAuthor kiểm tra user có phải người tạo ghi chú không
+func (n *Note) IsAuthor(userID uuid.UUID) bool { return n.UserID == userID }
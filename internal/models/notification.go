@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Notification (Thông báo)
+// Thông báo gửi đến một user cụ thể trong dashboard
+// VD: được @mention trong ghi chú nội bộ
+// ===========================================================================
+
+// NotificationType loại thông báo
+type NotificationType string
+
+const (
+	// NotificationMention user được @mention trong ghi chú
+	NotificationMention NotificationType = "mention"
+
+	// NotificationSLAWarning hội thoại sắp vi phạm SLA
+	NotificationSLAWarning NotificationType = "sla_warning"
+
+	// NotificationSLABreach hội thoại đã vi phạm SLA
+	NotificationSLABreach NotificationType = "sla_breach"
+
+	// NotificationSnoozeEnded hội thoại tạm ẩn được mở lại (hết hạn hoặc khách nhắn lại)
+	NotificationSnoozeEnded NotificationType = "snooze_ended"
+
+	// NotificationScheduledFailed tin nhắn hẹn giờ không gửi được (VD: khách đã ngoài cửa sổ nhắn tin)
+	NotificationScheduledFailed NotificationType = "scheduled_message_failed"
+)
+
+// NotificationData dữ liệu bổ sung để FE điều hướng
+type NotificationData struct {
+	// ConversationID hội thoại liên quan
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+
+	// NoteID ghi chú liên quan
+	NoteID *uuid.UUID `json:"note_id,omitempty"`
+
+	// ActorID user gây ra thông báo
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+}
+
+// Value implement driver.Valuer cho JSONB
+func (d NotificationData) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (d *NotificationData) Scan(value interface{}) error {
+	if value == nil {
+		*d = NotificationData{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+// Notification đại diện cho một thông báo gửi đến user
+type Notification struct {
+	BaseModel
+
+	// WorkspaceID ID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// UserID ID user nhận thông báo
+	UserID uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+
+	// Type loại thông báo
+	Type NotificationType `gorm:"size:50;not null" json:"type"`
+
+	// Title tiêu đề ngắn
+	Title string `gorm:"size:255;not null" json:"title"`
+
+	// Body nội dung thông báo
+	Body string `gorm:"type:text" json:"body"`
+
+	// Data dữ liệu bổ sung
+	Data NotificationData `gorm:"type:jsonb;default:'{}'" json:"data"`
+
+	// ReadAt thời điểm đã đọc (nil = chưa đọc)
+	ReadAt *time.Time `gorm:"index" json:"read_at,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// IsRead kiểm tra thông báo đã đọc chưa
+func (n *Notification) IsRead() bool { return n.ReadAt != nil }
+
+// MarkAsRead đánh dấu đã đọc
+func (n *Notification) MarkAsRead() {
+	if n.ReadAt == nil {
+		now := time.Now()
+		n.ReadAt = &now
+	}
+}
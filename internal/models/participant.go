@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -48,6 +49,39 @@ func (m *ParticipantMetadata) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, m)
 }
 
+// HasTag kiểm tra khách có tag (không phân biệt hoa thường)
+func (m *ParticipantMetadata) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags gắn thêm tag, trả về các tag mới gắn (tag đã có được bỏ qua)
+func (m *ParticipantMetadata) AddTags(tags []string) []string {
+	var added []string
+	for _, tag := range tags {
+		if !m.HasTag(tag) {
+			m.Tags = append(m.Tags, tag)
+			added = append(added, tag)
+		}
+	}
+	return added
+}
+
+// RemoveTag gỡ tag, trả về false nếu khách không có tag
+func (m *ParticipantMetadata) RemoveTag(tag string) bool {
+	for i, t := range m.Tags {
+		if strings.EqualFold(t, tag) {
+			m.Tags = append(m.Tags[:i], m.Tags[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // Participant đại diện cho khách hàng chat
 type Participant struct {
 	BaseModel
@@ -61,6 +95,9 @@ type Participant struct {
 	// ChannelUserID ID của khách hàng trên channel (FB PSID, Zalo UID)
 	ChannelUserID string `gorm:"size:255;not null;index" json:"channel_user_id"`
 
+	// PersonID khách hợp nhất mà participant thuộc về (nil = chưa gộp với danh tính nào khác)
+	PersonID *uuid.UUID `gorm:"type:uuid;index" json:"person_id,omitempty"`
+
 	// Name tên khách hàng (lấy từ FB/Zalo profile)
 	Name *string `gorm:"size:255" json:"name,omitempty"`
 
@@ -82,6 +119,9 @@ type Participant struct {
 	// LastSeenAt lần cuối cùng liên hệ
 	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
 
+	// LastInboundAt lần cuối khách nhắn tin (dùng cho cửa sổ nhắn tin của channel)
+	LastInboundAt *time.Time `gorm:"index" json:"last_inbound_at,omitempty"`
+
 	// Relations
 	Workspace      Workspace      `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
 	ChannelAccount ChannelAccount `gorm:"foreignKey:ChannelAccountID" json:"channel_account,omitempty"`
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Person (Khách hàng hợp nhất)
+// Gom các participant của cùng một người trên nhiều kênh (VD: Facebook và Zalo)
+// Participant chưa gộp với ai thì không có person
+// ===========================================================================
+
+// Person nhóm các danh tính (participant) của cùng một khách
+type Person struct {
+	BaseModel
+
+	// WorkspaceID ID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// CreatedBy agent gộp lần đầu
+	CreatedBy *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+
+	// Relations
+	Participants []Participant `gorm:"foreignKey:PersonID" json:"participants,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (Person) TableName() string {
+	return "people"
+}
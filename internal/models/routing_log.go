@@ -0,0 +1,99 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// RoutingLog (Nhật ký phân công)
+// Ghi lại mỗi lần routing engine chọn agent cho conversation
+// Giải thích vì sao agent được chọn hoặc bị loại
+// ===========================================================================
+
+// RoutingTrigger nguyên nhân kích hoạt routing
+type RoutingTrigger string
+
+const (
+	// RoutingTriggerNewConversation conversation mới được tạo
+	RoutingTriggerNewConversation RoutingTrigger = "new_conversation"
+
+	// RoutingTriggerHandoff bot chuyển cho agent
+	RoutingTriggerHandoff RoutingTrigger = "handoff"
+
+	// RoutingTriggerManual gọi thủ công từ dashboard
+	RoutingTriggerManual RoutingTrigger = "manual"
+
+	// RoutingTriggerMacro macro assign hội thoại cho team
+	RoutingTriggerMacro RoutingTrigger = "macro"
+)
+
+// RoutingCandidate đánh giá một agent trong lần routing
+type RoutingCandidate struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	OpenCount int64     `json:"open_count"`
+	Eligible  bool      `json:"eligible"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// RoutingCandidates danh sách candidates cho JSONB
+type RoutingCandidates []RoutingCandidate
+
+// Value implement driver.Valuer cho JSONB
+func (c RoutingCandidates) Value() (driver.Value, error) {
+	if c == nil {
+		return json.Marshal([]RoutingCandidate{})
+	}
+	return json.Marshal(c)
+}
+
+// Scan implement sql.Scanner cho JSONB
+func (c *RoutingCandidates) Scan(value interface{}) error {
+	if value == nil {
+		*c = RoutingCandidates{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// RoutingLog một quyết định routing
+type RoutingLog struct {
+	BaseModel
+
+	// WorkspaceID ID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// ConversationID conversation được routing
+	ConversationID uuid.UUID `gorm:"type:uuid;not null;index" json:"conversation_id"`
+
+	// Trigger nguyên nhân kích hoạt
+	Trigger RoutingTrigger `gorm:"size:50;not null" json:"trigger"`
+
+	// Strategy chiến lược đã dùng để chọn agent
+	Strategy string `gorm:"size:50;not null" json:"strategy"`
+
+	// AssignedTo agent được chọn (nil = không tìm được agent phù hợp)
+	AssignedTo *uuid.UUID `gorm:"type:uuid;index" json:"assigned_to,omitempty"`
+
+	// Reason giải thích quyết định
+	Reason string `gorm:"type:text" json:"reason"`
+
+	// Candidates đánh giá từng agent
+	Candidates RoutingCandidates `gorm:"type:jsonb;default:'[]'" json:"candidates"`
+}
+
+// TableName trả về tên bảng
+func (RoutingLog) TableName() string {
+	return "routing_logs"
+}
+
+// IsAssigned kiểm tra lần routing có chọn được agent không
+func (l *RoutingLog) IsAssigned() bool { return l.AssignedTo != nil }
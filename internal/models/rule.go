@@ -72,6 +72,10 @@ type TriggerConfig struct {
 
 	// Confidence ngưỡng confidence
 	Confidence float64 `json:"confidence,omitempty"`
+
+	// Conditions điều kiện trên trường tùy chỉnh của khách (tất cả phải thỏa)
+	// Áp dụng cho mọi loại trigger, kể cả fallback
+	Conditions []FieldCondition `json:"conditions,omitempty"`
 }
 
 // Value implement driver.Valuer cho JSONB
@@ -114,6 +118,9 @@ type ResponseConfig struct {
 
 	// Priority priority để set
 	Priority string `json:"priority,omitempty"`
+
+	// SetFields các trường tùy chỉnh đặt cho khách khi rule kích hoạt (null = xóa)
+	SetFields map[string]interface{} `json:"set_fields,omitempty"`
 }
 
 // Value implement driver.Valuer cho JSONB
@@ -261,6 +268,22 @@ func (r *Rule) MatchesTimeWindow(now time.Time) bool {
 	return currentTime >= tc.StartTime && currentTime <= tc.EndTime
 }
 
+// MatchesConditions kiểm tra trường tùy chỉnh của khách thỏa mọi điều kiện của rule
+func (r *Rule) MatchesConditions(participant *Participant) bool {
+	if len(r.TriggerConfig.Conditions) == 0 {
+		return true
+	}
+	if participant == nil {
+		return false
+	}
+	for _, cond := range r.TriggerConfig.Conditions {
+		if !cond.Matches(participant.Metadata.CustomFields) {
+			return false
+		}
+	}
+	return true
+}
+
 // IncrementHitCount tăng số lần kích hoạt
 func (r *Rule) IncrementHitCount() {
 	r.HitCount++
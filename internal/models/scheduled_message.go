@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Scheduled Message (Tin nhắn hẹn giờ)
+// Agent soạn trước tin nhắn và hẹn thời điểm gửi (VD: "gửi link theo dõi 9h sáng mai")
+// Job định kỳ gửi qua channel khi đến hạn, tự hủy nếu hội thoại đã đóng
+// ===========================================================================
+
+// ScheduledMessageStatus trạng thái tin nhắn hẹn giờ
+type ScheduledMessageStatus string
+
+const (
+	// ScheduledPending chờ đến giờ gửi
+	ScheduledPending ScheduledMessageStatus = "pending"
+
+	// ScheduledSending worker đã nhận và đang gửi
+	ScheduledSending ScheduledMessageStatus = "sending"
+
+	// ScheduledSent đã tạo tin nhắn và gửi qua channel (xem MessageID)
+	ScheduledSent ScheduledMessageStatus = "sent"
+
+	// ScheduledCancelled đã hủy (agent hủy hoặc hội thoại đã đóng)
+	ScheduledCancelled ScheduledMessageStatus = "cancelled"
+
+	// ScheduledFailed không gửi được
+	ScheduledFailed ScheduledMessageStatus = "failed"
+)
+
+// Lý do hủy tin nhắn hẹn giờ
+const (
+	// CancelReasonAgent agent hủy
+	CancelReasonAgent = "cancelled_by_agent"
+
+	// CancelReasonConversationClosed hội thoại đã đóng trước giờ gửi
+	CancelReasonConversationClosed = "conversation_closed"
+)
+
+// ScheduledMessage tin nhắn hẹn giờ gửi trong một hội thoại
+type ScheduledMessage struct {
+	BaseModel
+
+	// WorkspaceID workspace
+	WorkspaceID uuid.UUID `gorm:"type:uuid;not null;index" json:"workspace_id"`
+
+	// ConversationID hội thoại nhận tin
+	ConversationID uuid.UUID `gorm:"type:uuid;not null;index" json:"conversation_id"`
+
+	// CreatedBy agent hẹn giờ (người gửi khi đến hạn)
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+
+	// Content nội dung text
+	Content string `gorm:"type:text;not null" json:"content"`
+
+	// SendAt thời điểm gửi (UTC)
+	SendAt time.Time `gorm:"not null;index" json:"send_at"`
+
+	// Timezone múi giờ workspace dùng để hiểu thời điểm agent nhập
+	Timezone string `gorm:"size:64" json:"timezone"`
+
+	// MessageTag message tag gửi kèm nếu đến giờ khách đã ngoài cửa sổ nhắn tin
+	MessageTag string `gorm:"size:50" json:"message_tag,omitempty"`
+
+	// Status trạng thái: pending, sending, sent, cancelled, failed
+	Status ScheduledMessageStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+
+	// MessageID tin nhắn đã tạo khi gửi
+	MessageID *uuid.UUID `gorm:"type:uuid" json:"message_id,omitempty"`
+
+	// SentAt thời điểm thực tế đã gửi
+	SentAt *time.Time `json:"sent_at,omitempty"`
+
+	// CancelReason lý do hủy, CancelledBy agent hủy (nếu có)
+	CancelReason string     `gorm:"size:50" json:"cancel_reason,omitempty"`
+	CancelledBy  *uuid.UUID `gorm:"type:uuid" json:"cancelled_by,omitempty"`
+
+	// Error lỗi khi gửi (status failed, hoặc sent nhưng channel báo lỗi)
+	Error string `gorm:"type:text" json:"error,omitempty"`
+}
+
+// TableName trả về tên bảng
+func (ScheduledMessage) TableName() string {
+	return "scheduled_messages"
+}
+
+// IsPending kiểm tra tin nhắn còn chờ gửi
+func (s *ScheduledMessage) IsPending() bool {
+	return s.Status == ScheduledPending
+}
+
+// LocalSendAt thời điểm gửi theo múi giờ đã lưu
+func (s *ScheduledMessage) LocalSendAt() time.Time {
+	if loc, err := time.LoadLocation(s.Timezone); s.Timezone != "" && err == nil {
+		return s.SendAt.In(loc)
+	}
+	return s.SendAt
+}
+
+// PendingMessage hiển thị tin nhắn hẹn giờ như một tin nhắn đang chờ gửi
+// trong danh sách tin nhắn (ID là ID của tin hẹn giờ, CreatedAt là giờ gửi)
+func (s *ScheduledMessage) PendingMessage() Message {
+	content := s.Content
+	createdBy := s.CreatedBy
+	scheduledID := s.ID
+	sendAt := s.SendAt
+	return Message{
+		BaseModel: BaseModel{
+			ID:        s.ID,
+			CreatedAt: s.SendAt,
+			UpdatedAt: s.UpdatedAt,
+		},
+		ConversationID: s.ConversationID,
+		Direction:      DirectionOut,
+		SenderType:     SenderAgent,
+		SenderID:       &createdBy,
+		Content:        &content,
+		ContentType:    ContentText,
+		Attachments:    Attachments{},
+		Metadata: MessageMetadata{
+			ScheduledMessageID: &scheduledID,
+			ScheduledAt:        &sendAt,
+			MessageTag:         s.MessageTag,
+		},
+	}
+}
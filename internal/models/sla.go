@@ -0,0 +1,86 @@
+package models
+
+// ===========================================================================
+// SLA (Service Level Agreement)
+// Chính sách thời gian phản hồi/giải quyết theo mức ưu tiên
+// Lưu trong WorkspaceSettings, thời gian tính theo giờ làm việc của workspace
+// ===========================================================================
+
+// SLAStatus trạng thái SLA của conversation
+type SLAStatus string
+
+const (
+	// SLANone workspace không bật SLA hoặc không có target cho priority
+	SLANone SLAStatus = ""
+
+	// SLAOnTrack còn trong hạn
+	SLAOnTrack SLAStatus = "on_track"
+
+	// SLAWarning sắp vi phạm (đã dùng quá ngưỡng cảnh báo)
+	SLAWarning SLAStatus = "warning"
+
+	// SLABreached đã vi phạm
+	SLABreached SLAStatus = "breached"
+)
+
+// Severity thứ tự mức độ để so sánh trạng thái
+func (s SLAStatus) Severity() int {
+	switch s {
+	case SLAOnTrack:
+		return 1
+	case SLAWarning:
+		return 2
+	case SLABreached:
+		return 3
+	}
+	return 0
+}
+
+// SLATarget mục tiêu SLA cho một mức ưu tiên (phút làm việc, 0 = không áp dụng)
+type SLATarget struct {
+	// FirstResponseMinutes thời gian trả lời lần đầu tối đa
+	FirstResponseMinutes int `json:"first_response_minutes"`
+
+	// ResolutionMinutes thời gian giải quyết (đóng hội thoại) tối đa
+	ResolutionMinutes int `json:"resolution_minutes"`
+}
+
+// SLAPolicy chính sách SLA của workspace
+type SLAPolicy struct {
+	// Enabled bật/tắt SLA
+	Enabled bool `json:"enabled"`
+
+	// Targets mục tiêu theo priority (low, normal, high, urgent)
+	Targets map[Priority]SLATarget `json:"targets"`
+
+	// WarningPercent cảnh báo khi đã dùng N% thời gian (mặc định 80)
+	WarningPercent int `json:"warning_percent"`
+
+	// RaisePriority tăng priority một bậc khi vi phạm
+	RaisePriority bool `json:"raise_priority"`
+
+	// NotifyAdmins thông báo cho owner/admin khi vi phạm
+	NotifyAdmins bool `json:"notify_admins"`
+}
+
+// SLAConfig trả về chính sách SLA với giá trị mặc định
+// Trả về Enabled = false nếu workspace chưa cấu hình
+func (s WorkspaceSettings) SLAConfig() SLAPolicy {
+	if s.SLA == nil {
+		return SLAPolicy{}
+	}
+	policy := *s.SLA
+	if policy.WarningPercent <= 0 || policy.WarningPercent >= 100 {
+		policy.WarningPercent = 80
+	}
+	return policy
+}
+
+// TargetFor lấy mục tiêu SLA cho priority
+func (p SLAPolicy) TargetFor(priority Priority) (SLATarget, bool) {
+	target, ok := p.Targets[priority]
+	if !ok || (target.FirstResponseMinutes <= 0 && target.ResolutionMinutes <= 0) {
+		return SLATarget{}, false
+	}
+	return target, true
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,6 +28,20 @@ const (
 	RoleAgent UserRole = "agent"
 )
 
+// AgentStatus trạng thái làm việc của agent
+type AgentStatus string
+
+const (
+	// AgentOnline sẵn sàng nhận hội thoại
+	AgentOnline AgentStatus = "online"
+
+	// AgentAway tạm vắng, không nhận hội thoại mới
+	AgentAway AgentStatus = "away"
+
+	// AgentOffline không làm việc
+	AgentOffline AgentStatus = "offline"
+)
+
 // User đại diện cho người dùng hệ thống (agent, admin, owner)
 type User struct {
 	BaseModel
@@ -59,6 +74,22 @@ type User struct {
 	// LastSeenAt lần cuối online
 	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
 
+	// Status trạng thái làm việc: online, away, offline
+	Status AgentStatus `gorm:"size:20;not null;default:'offline';index" json:"status"`
+
+	// StatusAuto trạng thái hiện tại do hệ thống tự đặt (idle)
+	// Khi agent hoạt động trở lại sẽ được tự chuyển về online
+	StatusAuto bool `gorm:"default:false" json:"status_auto"`
+
+	// StatusChangedAt thời điểm đổi trạng thái gần nhất
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+
+	// MaxConcurrent số conversation mở tối đa (0 = theo cấu hình workspace)
+	MaxConcurrent int `gorm:"default:0" json:"max_concurrent"`
+
+	// Skills kỹ năng của agent (so khớp với tag của conversation khi routing)
+	Skills StringList `gorm:"type:jsonb;default:'[]'" json:"skills"`
+
 	// Relations
 	Workspace             Workspace      `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
 	AssignedConversations []Conversation `gorm:"foreignKey:AssignedTo" json:"assigned_conversations,omitempty"`
@@ -102,3 +133,39 @@ func (u *User) UpdateLastSeen() {
 	now := time.Now()
 	u.LastSeenAt = &now
 }
+
+// SetStatus đổi trạng thái làm việc
+// auto = true khi hệ thống tự đổi (idle), false khi agent tự chọn
+func (u *User) SetStatus(status AgentStatus, auto bool) {
+	now := time.Now()
+	u.Status = status
+	u.StatusAuto = auto
+	u.StatusChangedAt = &now
+}
+
+// IsAvailable kiểm tra agent có đang nhận hội thoại mới không
+func (u *User) IsAvailable() bool {
+	return u.IsActive && u.Status == AgentOnline
+}
+
+// Capacity số conversation mở tối đa của agent
+// Dùng giới hạn riêng nếu có, ngược lại dùng mặc định của workspace (0 = không giới hạn)
+func (u *User) Capacity(workspaceDefault int) int {
+	if u.MaxConcurrent > 0 {
+		return u.MaxConcurrent
+	}
+	return workspaceDefault
+}
+
+// HasAnySkill kiểm tra agent có ít nhất một kỹ năng trong danh sách không
+// So sánh không phân biệt hoa thường
+func (u *User) HasAnySkill(skills []string) bool {
+	for _, want := range skills {
+		for _, have := range u.Skills {
+			if strings.EqualFold(strings.TrimSpace(want), strings.TrimSpace(have)) {
+				return true
+			}
+		}
+	}
+	return false
+}
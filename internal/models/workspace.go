@@ -26,6 +26,100 @@ type WorkspaceSettings struct {
 
 	// Language ngôn ngữ mặc định (vi, en)
 	Language string `json:"language"`
+
+	// Routing cấu hình tự động phân công conversation cho agent
+	Routing *RoutingSettings `json:"routing,omitempty"`
+
+	// SLA chính sách thời gian phản hồi/giải quyết
+	SLA *SLAPolicy `json:"sla,omitempty"`
+
+	// AutoClose tự động đóng hội thoại khi khách không hoạt động
+	AutoClose *AutoCloseSettings `json:"auto_close,omitempty"`
+
+	// CSAT khảo sát mức độ hài lòng khi đóng hội thoại
+	CSAT *CSATSettings `json:"csat,omitempty"`
+}
+
+// RoutingStrategy chiến lược chọn agent
+type RoutingStrategy string
+
+const (
+	// RoutingRoundRobin lần lượt từng agent
+	RoutingRoundRobin RoutingStrategy = "round_robin"
+
+	// RoutingLeastOpen agent đang có ít conversation mở nhất
+	RoutingLeastOpen RoutingStrategy = "least_open"
+
+	// RoutingSkill agent có kỹ năng khớp tag của conversation
+	RoutingSkill RoutingStrategy = "skill"
+)
+
+// RoutingSettings cấu hình routing của workspace
+type RoutingSettings struct {
+	// Enabled bật/tắt tự động phân công
+	Enabled bool `json:"enabled"`
+
+	// Strategy chiến lược chọn agent (round_robin, least_open, skill)
+	Strategy RoutingStrategy `json:"strategy"`
+
+	// Sticky ưu tiên agent đã xử lý participant trước đó
+	Sticky bool `json:"sticky"`
+
+	// AssignOnNewConversation phân công khi có conversation mới
+	AssignOnNewConversation bool `json:"assign_on_new_conversation"`
+
+	// AssignOnHandoff phân công khi bot chuyển cho agent
+	AssignOnHandoff bool `json:"assign_on_handoff"`
+
+	// MaxConcurrent số conversation mở tối đa mỗi agent (0 = không giới hạn)
+	// Agent có giới hạn riêng (User.MaxConcurrent) sẽ dùng giới hạn riêng
+	MaxConcurrent int `json:"max_concurrent"`
+
+	// IncludeAdmins cho phép phân công cả owner/admin
+	IncludeAdmins bool `json:"include_admins"`
+}
+
+// RoutingConfig trả về cấu hình routing với giá trị mặc định
+// Trả về Enabled = false nếu workspace chưa cấu hình
+func (s WorkspaceSettings) RoutingConfig() RoutingSettings {
+	if s.Routing == nil {
+		return RoutingSettings{}
+	}
+	cfg := *s.Routing
+	if cfg.Strategy == "" {
+		cfg.Strategy = RoutingLeastOpen
+	}
+	return cfg
+}
+
+// AutoCloseSettings cấu hình tự động đóng hội thoại không hoạt động
+type AutoCloseSettings struct {
+	// Enabled bật/tắt tự động đóng
+	Enabled bool `json:"enabled"`
+
+	// InactiveHours số giờ khách không nhắn tin thì đóng (mặc định 24)
+	InactiveHours int `json:"inactive_hours"`
+
+	// ClosingMessage tin nhắn gửi cho khách trước khi đóng (rỗng = không gửi)
+	ClosingMessage string `json:"closing_message,omitempty"`
+
+	// SendCSAT gửi khảo sát CSAT sau khi tự động đóng, kể cả khi workspace chưa bật CSAT
+	SendCSAT bool `json:"send_csat"`
+
+	// CSATPrompt câu hỏi khảo sát khi tự động đóng (rỗng = dùng câu hỏi trong cài đặt CSAT)
+	CSATPrompt string `json:"csat_prompt,omitempty"`
+}
+
+// AutoCloseConfig trả về cấu hình auto-close với giá trị mặc định
+func (s WorkspaceSettings) AutoCloseConfig() AutoCloseSettings {
+	if s.AutoClose == nil {
+		return AutoCloseSettings{}
+	}
+	cfg := *s.AutoClose
+	if cfg.InactiveHours <= 0 {
+		cfg.InactiveHours = 24
+	}
+	return cfg
 }
 
 // WorkingHours cấu hình giờ làm việc
@@ -23,6 +23,33 @@ type Publisher interface {
 
 	// PublishConversationUpdate publishes conversation update event
 	PublishConversationUpdate(workspaceID uuid.UUID, event *ConversationEvent) error
+
+	// PublishNoteEvent publishes internal note change (created/updated/deleted)
+	PublishNoteEvent(workspaceID uuid.UUID, event *NoteEvent) error
+
+	// PublishNotification publishes notification to a single user channel
+	PublishNotification(userID uuid.UUID, event *NotificationEvent) error
+
+	// PublishPresence publishes agent status change to workspace channel
+	PublishPresence(workspaceID uuid.UUID, event *PresenceEvent) error
+
+	// PublishUnread publishes unread count change of a conversation to workspace channel
+	PublishUnread(workspaceID uuid.UUID, event *UnreadEvent) error
+
+	// PublishTyping publishes agent typing state to workspace channel
+	PublishTyping(workspaceID uuid.UUID, event *TypingEvent) error
+
+	// PublishMedia publishes attachment storage result to workspace channel
+	PublishMedia(workspaceID uuid.UUID, event *MediaEvent) error
+
+	// PublishBulkJob publishes bulk operation progress to workspace channel
+	PublishBulkJob(workspaceID uuid.UUID, event *BulkJobEvent) error
+
+	// PublishCampaign publishes campaign sending progress to workspace channel
+	PublishCampaign(workspaceID uuid.UUID, event *CampaignEvent) error
+
+	// PublishContactJob publishes contact import/export progress to workspace channel
+	PublishContactJob(workspaceID uuid.UUID, event *ContactJobEvent) error
 }
 
 // MessageEvent event khi có tin nhắn mới
@@ -45,6 +72,135 @@ type ConversationEvent struct {
 	ConversationID uuid.UUID `json:"conversation_id"`
 	Status         string    `json:"status,omitempty"`
 	AssignedTo     string    `json:"assigned_to,omitempty"`
+	Priority       string    `json:"priority,omitempty"`
+	SLAStatus      string    `json:"sla_status,omitempty"`
+
+	// SnoozedUntil hạn tạm ẩn (chỉ có khi status = snoozed)
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+
+	// MacroID macro vừa chạy (chỉ có với macro)
+	// Tags danh sách tag sau khi thay đổi (chỉ có với macro và gắn/gỡ tag hàng loạt)
+	MacroID string   `json:"macro_id,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Note event types
+const (
+	NoteCreated = "note_created"
+	NoteUpdated = "note_updated"
+	NoteDeleted = "note_deleted"
+)
+
+// NoteEvent event khi ghi chú nội bộ thay đổi
+// Type do caller set (note_created, note_updated, note_deleted)
+type NoteEvent struct {
+	Type           string      `json:"type"`
+	NoteID         uuid.UUID   `json:"note_id"`
+	ConversationID uuid.UUID   `json:"conversation_id"`
+	UserID         uuid.UUID   `json:"user_id"`
+	UserName       string      `json:"user_name,omitempty"`
+	Content        string      `json:"content,omitempty"`
+	IsPinned       bool        `json:"is_pinned"`
+	Mentions       []uuid.UUID `json:"mentions,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// NotificationEvent event thông báo gửi đến một user
+type NotificationEvent struct {
+	Type           string     `json:"type"`
+	NotificationID uuid.UUID  `json:"notification_id"`
+	Kind           string     `json:"kind"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body,omitempty"`
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PresenceEvent event khi trạng thái làm việc của agent thay đổi
+type PresenceEvent struct {
+	Type       string     `json:"type"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Status     string     `json:"status"`
+	Auto       bool       `json:"auto"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	ChangedAt  time.Time  `json:"changed_at"`
+}
+
+// UnreadEvent event khi số tin nhắn chưa đọc của conversation thay đổi
+// ReadBy rỗng khi tăng do khách nhắn tin mới
+type UnreadEvent struct {
+	Type           string     `json:"type"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	UnreadCount    int64      `json:"unread_count"`
+	AssignedTo     string     `json:"assigned_to,omitempty"`
+	ReadBy         *uuid.UUID `json:"read_by,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TypingEvent event khi agent đang soạn tin trong conversation
+type TypingEvent struct {
+	Type           string    `json:"type"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	UserName       string    `json:"user_name,omitempty"`
+	Typing         bool      `json:"typing"`
+	At             time.Time `json:"at"`
+}
+
+// MediaEvent event khi file đính kèm của tin nhắn đã lưu xong (hoặc lưu thất bại)
+// FE thay URL của attachment thứ AttachmentIndex trong tin nhắn MessageID
+type MediaEvent struct {
+	Type            string    `json:"type"`
+	MediaID         uuid.UUID `json:"media_id"`
+	MessageID       uuid.UUID `json:"message_id"`
+	ConversationID  uuid.UUID `json:"conversation_id"`
+	AttachmentIndex int       `json:"attachment_index"`
+	Status          string    `json:"status"`
+	URL             string    `json:"url,omitempty"`
+	ThumbnailURL    string    `json:"thumbnail_url,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	Size            int64     `json:"size,omitempty"`
+	MimeType        string    `json:"mime_type,omitempty"`
+}
+
+// BulkJobEvent event tiến độ của job thao tác hàng loạt (sau mỗi lô và khi kết thúc)
+type BulkJobEvent struct {
+	Type      string    `json:"type"`
+	JobID     uuid.UUID `json:"job_id"`
+	Action    string    `json:"action"`
+	Status    string    `json:"status"`
+	Total     int       `json:"total"`
+	Processed int       `json:"processed"`
+	Succeeded int       `json:"succeeded"`
+	Skipped   int       `json:"skipped"`
+	Failed    int       `json:"failed"`
+}
+
+// ContactJobEvent event tiến độ của job nhập/xuất khách (sau mỗi lô và khi kết thúc)
+type ContactJobEvent struct {
+	Type      string    `json:"type"`
+	JobID     uuid.UUID `json:"job_id"`
+	JobType   string    `json:"job_type"`
+	Status    string    `json:"status"`
+	DryRun    bool      `json:"dry_run"`
+	Total     int       `json:"total"`
+	Processed int       `json:"processed"`
+	Created   int       `json:"created"`
+	Updated   int       `json:"updated"`
+	Skipped   int       `json:"skipped"`
+	Failed    int       `json:"failed"`
+}
+
+// CampaignEvent event tiến độ gửi của chiến dịch (sau mỗi lượt gửi và khi kết thúc)
+type CampaignEvent struct {
+	Type       string    `json:"type"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+	Status     string    `json:"status"`
+	Total      int       `json:"total"`
+	Sent       int       `json:"sent"`
+	Failed     int       `json:"failed"`
+	Skipped    int       `json:"skipped"`
+	Cancelled  int       `json:"cancelled"`
 }
 
 // CentrifugoClient implements Publisher
@@ -134,6 +290,71 @@ func (c *CentrifugoClient) PublishConversationUpdate(workspaceID uuid.UUID, even
 	return c.publish(channel, event)
 }
 
+// PublishNoteEvent publishes internal note event to workspace channel
+func (c *CentrifugoClient) PublishNoteEvent(workspaceID uuid.UUID, event *NoteEvent) error {
+	if event.Type == "" {
+		event.Type = NoteCreated
+	}
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
+// PublishNotification publishes notification to user's personal channel
+func (c *CentrifugoClient) PublishNotification(userID uuid.UUID, event *NotificationEvent) error {
+	event.Type = "notification"
+	channel := fmt.Sprintf("chat:user_%s", userID.String())
+	return c.publish(channel, event)
+}
+
+// PublishPresence publishes agent presence event to workspace channel
+func (c *CentrifugoClient) PublishPresence(workspaceID uuid.UUID, event *PresenceEvent) error {
+	event.Type = "presence"
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
+// PublishUnread publishes unread count event to workspace channel
+func (c *CentrifugoClient) PublishUnread(workspaceID uuid.UUID, event *UnreadEvent) error {
+	event.Type = "unread_update"
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
+// PublishTyping publishes agent typing event to workspace channel
+func (c *CentrifugoClient) PublishTyping(workspaceID uuid.UUID, event *TypingEvent) error {
+	event.Type = "typing"
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
+// PublishMedia publishes media stored event to workspace channel
+func (c *CentrifugoClient) PublishMedia(workspaceID uuid.UUID, event *MediaEvent) error {
+	event.Type = "media_update"
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
+// PublishBulkJob publishes bulk job progress event to workspace channel
+func (c *CentrifugoClient) PublishBulkJob(workspaceID uuid.UUID, event *BulkJobEvent) error {
+	event.Type = "bulk_job_update"
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
+// PublishCampaign publishes campaign progress event to workspace channel
+func (c *CentrifugoClient) PublishCampaign(workspaceID uuid.UUID, event *CampaignEvent) error {
+	event.Type = "campaign_update"
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
+// PublishContactJob publishes contact job progress event to workspace channel
+func (c *CentrifugoClient) PublishContactJob(workspaceID uuid.UUID, event *ContactJobEvent) error {
+	event.Type = "contact_job_update"
+	channel := fmt.Sprintf("chat:workspace_%s", workspaceID.String())
+	return c.publish(channel, event)
+}
+
 // ===========================================================================
 // Noop Publisher (for when Centrifugo is not configured)
 // ===========================================================================
@@ -152,3 +373,39 @@ func (n *NoopPublisher) PublishNewMessage(workspaceID uuid.UUID, event *MessageE
 func (n *NoopPublisher) PublishConversationUpdate(workspaceID uuid.UUID, event *ConversationEvent) error {
 	return nil
 }
+
+func (n *NoopPublisher) PublishNoteEvent(workspaceID uuid.UUID, event *NoteEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishNotification(userID uuid.UUID, event *NotificationEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishPresence(workspaceID uuid.UUID, event *PresenceEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishUnread(workspaceID uuid.UUID, event *UnreadEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishTyping(workspaceID uuid.UUID, event *TypingEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishMedia(workspaceID uuid.UUID, event *MediaEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishBulkJob(workspaceID uuid.UUID, event *BulkJobEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishCampaign(workspaceID uuid.UUID, event *CampaignEvent) error {
+	return nil
+}
+
+func (n *NoopPublisher) PublishContactJob(workspaceID uuid.UUID, event *ContactJobEvent) error {
+	return nil
+}
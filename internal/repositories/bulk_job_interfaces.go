@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Bulk Job Repository Interface
+// Lưu job thao tác hàng loạt và tiến độ xử lý
+// ===========================================================================
+
+// BulkJobRepository interface cho bulk job data access
+type BulkJobRepository interface {
+	// Create tạo job mới
+	Create(ctx context.Context, job *models.BulkJob) error
+
+	// Update lưu tiến độ/kết quả của job
+	Update(ctx context.Context, job *models.BulkJob) error
+
+	// FindByID tìm job theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.BulkJob, error)
+
+	// FindByWorkspace lấy các job gần nhất của workspace, mới nhất trước
+	FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, limit int) ([]models.BulkJob, error)
+
+	// FindRunnable lấy job cũ nhất cần xử lý: đang chờ, hoặc đang chạy nhưng
+	// không cập nhật tiến độ kể từ staleBefore (worker trước đó đã dừng giữa chừng)
+	FindRunnable(ctx context.Context, staleBefore time.Time) (*models.BulkJob, error)
+
+	// Claim đánh dấu job đang chạy nếu job chưa bị worker khác nhận
+	// (updated_at chưa đổi so với lúc đọc), trả về false nếu đã bị nhận
+	Claim(ctx context.Context, job *models.BulkJob) (bool, error)
+}
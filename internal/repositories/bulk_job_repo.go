@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Bulk Job Repository GORM Implementation
+// ===========================================================================
+
+// bulkJobRepo triển khai BulkJobRepository với GORM
+type bulkJobRepo struct {
+	db *gorm.DB
+}
+
+// NewBulkJobRepository tạo instance mới của BulkJobRepository
+func NewBulkJobRepository(db *gorm.DB) BulkJobRepository {
+	return &bulkJobRepo{db: db}
+}
+
+// Create tạo job mới
+func (r *bulkJobRepo) Create(ctx context.Context, job *models.BulkJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// Update lưu tiến độ/kết quả của job
+func (r *bulkJobRepo) Update(ctx context.Context, job *models.BulkJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// FindByID tìm job theo ID
+func (r *bulkJobRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.BulkJob, error) {
+	var job models.BulkJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindByWorkspace lấy các job gần nhất của workspace (không tải danh sách hội thoại)
+func (r *bulkJobRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, limit int) ([]models.BulkJob, error) {
+	var jobs []models.BulkJob
+	err := r.db.WithContext(ctx).
+		Omit("conversation_ids").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// FindRunnable lấy job cũ nhất đang chờ hoặc đang chạy nhưng đã dừng cập nhật
+func (r *bulkJobRepo) FindRunnable(ctx context.Context, staleBefore time.Time) (*models.BulkJob, error) {
+	var job models.BulkJob
+	err := r.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND updated_at < ?)", models.BulkPending, models.BulkRunning, staleBefore).
+		Order("created_at ASC").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Claim đánh dấu job đang chạy nếu chưa có worker khác nhận
+func (r *bulkJobRepo) Claim(ctx context.Context, job *models.BulkJob) (bool, error) {
+	now := time.Now()
+	startedAt := job.StartedAt
+	if startedAt == nil {
+		startedAt = &now
+	}
+	result := r.db.WithContext(ctx).
+		Model(&models.BulkJob{}).
+		Where("id = ? AND updated_at = ?", job.ID, job.UpdatedAt).
+		Updates(map[string]interface{}{
+			"status":     models.BulkRunning,
+			"started_at": startedAt,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	job.Status = models.BulkRunning
+	job.StartedAt = startedAt
+	job.UpdatedAt = now
+	return true, nil
+}
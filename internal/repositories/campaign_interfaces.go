@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Campaign Repository Interface
+// Lưu chiến dịch gửi tin, chọn tệp khách và kết quả gửi theo từng người nhận
+// ===========================================================================
+
+// AudienceMember một khách khớp điều kiện chiến dịch
+type AudienceMember struct {
+	ParticipantID    uuid.UUID
+	ChannelAccountID uuid.UUID
+
+	// LastInboundAt lần cuối khách nhắn tin (trên mọi hội thoại), nil nếu chưa từng nhắn
+	LastInboundAt *time.Time
+}
+
+// CampaignRepository interface cho campaign data access
+type CampaignRepository interface {
+	// Create tạo chiến dịch
+	Create(ctx context.Context, campaign *models.Campaign) error
+
+	// Update lưu toàn bộ chiến dịch (dùng khi sửa bản nháp)
+	Update(ctx context.Context, campaign *models.Campaign) error
+
+	// Delete xóa chiến dịch
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// FindByID tìm chiến dịch theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Campaign, error)
+
+	// FindByWorkspace lấy các chiến dịch gần nhất của workspace, mới nhất trước
+	FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, status models.CampaignStatus, limit int) ([]models.Campaign, error)
+
+	// FindActive lấy các chiến dịch đang gửi và các chiến dịch đã lên lịch đến giờ bắt đầu
+	FindActive(ctx context.Context, now time.Time) ([]models.Campaign, error)
+
+	// Transition đổi trạng thái (kèm thời điểm bắt đầu/kết thúc, tổng số, lỗi)
+	// chỉ khi trạng thái hiện tại là from, trả về false nếu đã bị đổi trước đó
+	Transition(ctx context.Context, campaign *models.Campaign, from models.CampaignStatus) (bool, error)
+
+	// UpdateProgress chỉ lưu các bộ đếm tiến độ
+	UpdateProgress(ctx context.Context, campaign *models.Campaign) error
+
+	// FindAudience lấy các khách khớp điều kiện, tối đa limit khách
+	FindAudience(ctx context.Context, workspaceID uuid.UUID, audience models.CampaignAudience, limit int) ([]AudienceMember, error)
+
+	// CreateRecipients lưu danh sách người nhận (theo lô)
+	CreateRecipients(ctx context.Context, recipients []models.CampaignRecipient) error
+
+	// PendingChannels các kênh còn người nhận chờ gửi
+	PendingChannels(ctx context.Context, campaignID uuid.UUID) ([]uuid.UUID, error)
+
+	// CountAttempts số người nhận của kênh đã bắt đầu gửi từ since (giới hạn tốc độ)
+	CountAttempts(ctx context.Context, campaignID, channelAccountID uuid.UUID, since time.Time) (int64, error)
+
+	// FindPendingRecipients lấy người nhận chờ gửi của một kênh
+	FindPendingRecipients(ctx context.Context, campaignID, channelAccountID uuid.UUID, limit int) ([]models.CampaignRecipient, error)
+
+	// ClaimRecipient chuyển người nhận từ pending sang sending, trả về false
+	// nếu đã bị worker khác nhận hoặc chiến dịch đã hủy
+	ClaimRecipient(ctx context.Context, recipient *models.CampaignRecipient) (bool, error)
+
+	// UpdateRecipient lưu kết quả gửi
+	UpdateRecipient(ctx context.Context, recipient *models.CampaignRecipient) error
+
+	// FailStaleRecipients đánh dấu lỗi người nhận kẹt ở sending từ trước staleBefore
+	// (worker dừng giữa chừng, không gửi lại để tránh gửi trùng)
+	FailStaleRecipients(ctx context.Context, campaignID uuid.UUID, staleBefore time.Time) (int64, error)
+
+	// CancelPendingRecipients đánh dấu cancelled các người nhận chưa gửi
+	CancelPendingRecipients(ctx context.Context, campaignID uuid.UUID) (int64, error)
+
+	// CountRecipientsByStatus đếm người nhận theo trạng thái
+	CountRecipientsByStatus(ctx context.Context, campaignID uuid.UUID) (map[models.RecipientStatus]int, error)
+
+	// FindRecipients lấy người nhận kèm thông tin khách (phân trang), status rỗng = tất cả
+	FindRecipients(ctx context.Context, campaignID uuid.UUID, status models.RecipientStatus, offset, limit int) ([]models.CampaignRecipient, int64, error)
+}
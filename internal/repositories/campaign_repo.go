@@ -0,0 +1,307 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ===========================================================================
+// Campaign Repository GORM Implementation
+// ===========================================================================
+
+// participantLastInboundExpr lần cuối khách nhắn tin
+// Lấy mốc muộn hơn giữa participant và các hội thoại của khách (dữ liệu trước khi participant lưu mốc này)
+const participantLastInboundExpr = `GREATEST(p.last_inbound_at, (SELECT MAX(c.last_inbound_at) FROM conversations c
+	WHERE c.participant_id = p.id AND c.deleted_at IS NULL))`
+
+// campaignRepo triển khai CampaignRepository với GORM
+type campaignRepo struct {
+	db *gorm.DB
+}
+
+// NewCampaignRepository tạo instance mới của CampaignRepository
+func NewCampaignRepository(db *gorm.DB) CampaignRepository {
+	return &campaignRepo{db: db}
+}
+
+// Create tạo chiến dịch
+func (r *campaignRepo) Create(ctx context.Context, campaign *models.Campaign) error {
+	return r.db.WithContext(ctx).Create(campaign).Error
+}
+
+// Update lưu toàn bộ chiến dịch
+func (r *campaignRepo) Update(ctx context.Context, campaign *models.Campaign) error {
+	return r.db.WithContext(ctx).Save(campaign).Error
+}
+
+// Delete xóa chiến dịch
+func (r *campaignRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Campaign{}, id).Error
+}
+
+// FindByID tìm chiến dịch theo ID
+func (r *campaignRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Campaign, error) {
+	var campaign models.Campaign
+	if err := r.db.WithContext(ctx).First(&campaign, id).Error; err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// FindByWorkspace lấy các chiến dịch gần nhất của workspace
+func (r *campaignRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, status models.CampaignStatus, limit int) ([]models.Campaign, error) {
+	var campaigns []models.Campaign
+	query := r.db.WithContext(ctx).Where("workspace_id = ?", workspaceID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Limit(limit).Find(&campaigns).Error
+	return campaigns, err
+}
+
+// FindActive lấy các chiến dịch đang gửi và đã đến giờ bắt đầu
+func (r *campaignRepo) FindActive(ctx context.Context, now time.Time) ([]models.Campaign, error) {
+	var campaigns []models.Campaign
+	err := r.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND (scheduled_at IS NULL OR scheduled_at <= ?))",
+			models.CampaignRunning, models.CampaignScheduled, now).
+		Order("created_at ASC").
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// Transition đổi trạng thái nếu trạng thái hiện tại là from
+func (r *campaignRepo) Transition(ctx context.Context, campaign *models.Campaign, from models.CampaignStatus) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.Campaign{}).
+		Where("id = ? AND status = ?", campaign.ID, from).
+		Updates(map[string]interface{}{
+			"status":       campaign.Status,
+			"scheduled_at": campaign.ScheduledAt,
+			"total":        campaign.Total,
+			"error":        campaign.Error,
+			"started_at":   campaign.StartedAt,
+			"finished_at":  campaign.FinishedAt,
+			"cancelled_by": campaign.CancelledBy,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	campaign.UpdatedAt = now
+	return true, nil
+}
+
+// UpdateProgress chỉ lưu các bộ đếm tiến độ
+func (r *campaignRepo) UpdateProgress(ctx context.Context, campaign *models.Campaign) error {
+	return r.db.WithContext(ctx).
+		Model(campaign).
+		Select("total", "sent", "failed", "skipped", "cancelled", "updated_at").
+		Updates(campaign).Error
+}
+
+// FindAudience lấy các khách khớp điều kiện
+func (r *campaignRepo) FindAudience(ctx context.Context, workspaceID uuid.UUID, audience models.CampaignAudience, limit int) ([]AudienceMember, error) {
+	query := r.db.WithContext(ctx).
+		Table("participants AS p").
+		Select("p.id AS participant_id, p.channel_account_id, "+participantLastInboundExpr+" AS last_inbound_at").
+		Where("p.workspace_id = ? AND p.deleted_at IS NULL", workspaceID)
+
+	if len(audience.ChannelAccountIDs) > 0 {
+		query = query.Where("p.channel_account_id IN ?", audience.ChannelAccountIDs)
+	}
+	if len(audience.Tags) > 0 {
+		tags := make([]string, len(audience.Tags))
+		for i, tag := range audience.Tags {
+			tags[i] = strings.ToLower(tag)
+		}
+		query = query.Where(`(
+			EXISTS (
+				SELECT 1 FROM jsonb_array_elements_text(COALESCE(p.metadata->'tags', '[]'::jsonb)) AS pt(name)
+				WHERE LOWER(pt.name) IN ?
+			) OR EXISTS (
+				SELECT 1 FROM conversations c
+				JOIN conversation_tags ct ON ct.conversation_id = c.id
+				JOIN tags t ON t.id = ct.tag_id
+				WHERE c.participant_id = p.id AND LOWER(t.name) IN ?
+			)
+		)`, tags, tags)
+	}
+
+	keys := make([]string, 0, len(audience.CustomFields))
+	for key := range audience.CustomFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		query = query.Where("p.metadata->'custom_fields'->>? = ?", key, audience.CustomFields[key])
+	}
+
+	if audience.LastSeenWithinDays > 0 {
+		since := time.Now().AddDate(0, 0, -audience.LastSeenWithinDays)
+		query = query.Where(participantLastInboundExpr+" >= ?", since)
+	}
+	if audience.LastSeenBeforeDays > 0 {
+		before := time.Now().AddDate(0, 0, -audience.LastSeenBeforeDays)
+		query = query.Where("COALESCE("+participantLastInboundExpr+", p.created_at) < ?", before)
+	}
+
+	var members []AudienceMember
+	err := query.Order("p.created_at ASC").Limit(limit).Scan(&members).Error
+	return members, err
+}
+
+// CreateRecipients lưu danh sách người nhận theo lô
+// Người nhận đã có (chạy lại sau khi restart) được bỏ qua
+func (r *campaignRepo) CreateRecipients(ctx context.Context, recipients []models.CampaignRecipient) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(recipients, 500).Error
+}
+
+// PendingChannels các kênh còn người nhận chờ gửi
+func (r *campaignRepo) PendingChannels(ctx context.Context, campaignID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ? AND status = ?", campaignID, models.RecipientPending).
+		Distinct().
+		Pluck("channel_account_id", &ids).Error
+	return ids, err
+}
+
+// CountAttempts số người nhận của kênh đã bắt đầu gửi từ since
+func (r *campaignRepo) CountAttempts(ctx context.Context, campaignID, channelAccountID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ? AND channel_account_id = ? AND attempted_at >= ?", campaignID, channelAccountID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// FindPendingRecipients lấy người nhận chờ gửi của một kênh
+func (r *campaignRepo) FindPendingRecipients(ctx context.Context, campaignID, channelAccountID uuid.UUID, limit int) ([]models.CampaignRecipient, error) {
+	var recipients []models.CampaignRecipient
+	err := r.db.WithContext(ctx).
+		Where("campaign_id = ? AND channel_account_id = ? AND status = ?", campaignID, channelAccountID, models.RecipientPending).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&recipients).Error
+	return recipients, err
+}
+
+// ClaimRecipient chuyển người nhận từ pending sang sending
+func (r *campaignRepo) ClaimRecipient(ctx context.Context, recipient *models.CampaignRecipient) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Where("id = ? AND status = ?", recipient.ID, models.RecipientPending).
+		Updates(map[string]interface{}{
+			"status":       models.RecipientSending,
+			"attempted_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	recipient.Status = models.RecipientSending
+	recipient.AttemptedAt = &now
+	recipient.UpdatedAt = now
+	return true, nil
+}
+
+// UpdateRecipient lưu kết quả gửi
+func (r *campaignRepo) UpdateRecipient(ctx context.Context, recipient *models.CampaignRecipient) error {
+	return r.db.WithContext(ctx).Omit("Participant").Save(recipient).Error
+}
+
+// FailStaleRecipients đánh dấu lỗi người nhận kẹt ở sending
+func (r *campaignRepo) FailStaleRecipients(ctx context.Context, campaignID uuid.UUID, staleBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ? AND status = ? AND updated_at < ?", campaignID, models.RecipientSending, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     models.RecipientFailed,
+			"error":      "Gửi bị gián đoạn, không rõ khách đã nhận hay chưa",
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CancelPendingRecipients đánh dấu cancelled các người nhận chưa gửi
+func (r *campaignRepo) CancelPendingRecipients(ctx context.Context, campaignID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ? AND status = ?", campaignID, models.RecipientPending).
+		Updates(map[string]interface{}{
+			"status":     models.RecipientCancelled,
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CountRecipientsByStatus đếm người nhận theo trạng thái
+func (r *campaignRepo) CountRecipientsByStatus(ctx context.Context, campaignID uuid.UUID) (map[models.RecipientStatus]int, error) {
+	var rows []struct {
+		Status models.RecipientStatus
+		Count  int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Select("status, COUNT(*) AS count").
+		Where("campaign_id = ?", campaignID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.RecipientStatus]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// FindRecipients lấy người nhận kèm thông tin khách (phân trang)
+func (r *campaignRepo) FindRecipients(ctx context.Context, campaignID uuid.UUID, status models.RecipientStatus, offset, limit int) ([]models.CampaignRecipient, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ?", campaignID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var recipients []models.CampaignRecipient
+	err := query.
+		Preload("Participant").
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&recipients).Error
+	return recipients, total, err
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Canned Response Repository Interface
+// Thư viện câu trả lời soạn sẵn của workspace
+// ===========================================================================
+
+// CannedResponseFilter điều kiện lọc câu trả lời soạn sẵn
+type CannedResponseFilter struct {
+	// WorkspaceID workspace cần lấy (bắt buộc)
+	WorkspaceID uuid.UUID
+
+	// UserID người xem: chỉ lấy câu trả lời shared và personal của user này
+	UserID uuid.UUID
+
+	// Shortcut lọc theo tiền tố shortcut (composer gõ "/sh")
+	Shortcut string
+
+	// Query tìm theo tiền tố shortcut hoặc chứa trong title
+	Query string
+
+	// Visibility lọc theo phạm vi (rỗng = tất cả)
+	Visibility models.CannedVisibility
+
+	// Limit số kết quả tối đa
+	Limit int
+}
+
+// CannedResponseRepository interface cho canned response data access
+type CannedResponseRepository interface {
+	// Create tạo câu trả lời mới
+	Create(ctx context.Context, response *models.CannedResponse) error
+
+	// Update cập nhật câu trả lời
+	Update(ctx context.Context, response *models.CannedResponse) error
+
+	// Delete soft delete câu trả lời
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// FindByID tìm câu trả lời theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.CannedResponse, error)
+
+	// List lấy câu trả lời user thấy được theo filter
+	// Khớp đúng shortcut lên đầu, sau đó theo số lần dùng
+	List(ctx context.Context, filter CannedResponseFilter) ([]models.CannedResponse, error)
+
+	// FindByShortcut lấy các câu trả lời user thấy được có đúng shortcut
+	FindByShortcut(ctx context.Context, workspaceID, userID uuid.UUID, shortcut string) ([]models.CannedResponse, error)
+
+	// IncrementUsage tăng số lần dùng và cập nhật last_used_at
+	IncrementUsage(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Canned Response Repository GORM Implementation
+// ===========================================================================
+
+// cannedResponseRepo triển khai CannedResponseRepository với GORM
+type cannedResponseRepo struct {
+	db *gorm.DB
+}
+
+// NewCannedResponseRepository tạo instance mới của CannedResponseRepository
+func NewCannedResponseRepository(db *gorm.DB) CannedResponseRepository {
+	return &cannedResponseRepo{db: db}
+}
+
+// Create tạo câu trả lời mới
+func (r *cannedResponseRepo) Create(ctx context.Context, response *models.CannedResponse) error {
+	return r.db.WithContext(ctx).Create(response).Error
+}
+
+// Update cập nhật câu trả lời
+func (r *cannedResponseRepo) Update(ctx context.Context, response *models.CannedResponse) error {
+	return r.db.WithContext(ctx).Save(response).Error
+}
+
+// Delete soft delete câu trả lời
+func (r *cannedResponseRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.CannedResponse{}, id).Error
+}
+
+// FindByID tìm câu trả lời theo ID
+func (r *cannedResponseRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.CannedResponse, error) {
+	var response models.CannedResponse
+	if err := r.db.WithContext(ctx).First(&response, id).Error; err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// List lấy câu trả lời user thấy được theo filter
+func (r *cannedResponseRepo) List(ctx context.Context, filter CannedResponseFilter) ([]models.CannedResponse, error) {
+	query := r.visible(ctx, filter.WorkspaceID, filter.UserID)
+
+	if filter.Visibility != "" {
+		query = query.Where("visibility = ?", filter.Visibility)
+	}
+	if filter.Shortcut != "" {
+		query = query.Where("shortcut LIKE ?", escapeLike(filter.Shortcut)+"%")
+	}
+	if filter.Query != "" {
+		query = query.Where("(shortcut LIKE ? OR title ILIKE ?)",
+			escapeLike(strings.ToLower(filter.Query))+"%", "%"+escapeLike(filter.Query)+"%")
+	}
+
+	// Khớp đúng shortcut lên đầu để Enter trong composer chọn đúng câu
+	exact := filter.Shortcut
+	if exact == "" {
+		exact = strings.ToLower(filter.Query)
+	}
+
+	var responses []models.CannedResponse
+	err := query.
+		Order(gorm.Expr("shortcut = ? DESC", exact)).
+		Order("usage_count DESC, title ASC").
+		Limit(filter.Limit).
+		Find(&responses).Error
+	return responses, err
+}
+
+// FindByShortcut lấy các câu trả lời user thấy được có đúng shortcut
+func (r *cannedResponseRepo) FindByShortcut(ctx context.Context, workspaceID, userID uuid.UUID, shortcut string) ([]models.CannedResponse, error) {
+	var responses []models.CannedResponse
+	err := r.visible(ctx, workspaceID, userID).
+		Where("shortcut = ?", shortcut).
+		Find(&responses).Error
+	return responses, err
+}
+
+// IncrementUsage tăng số lần dùng và cập nhật last_used_at
+func (r *cannedResponseRepo) IncrementUsage(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.CannedResponse{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"usage_count":  gorm.Expr("usage_count + 1"),
+			"last_used_at": time.Now(),
+		}).Error
+}
+
+// visible query các câu trả lời shared của workspace và personal của user
+func (r *cannedResponseRepo) visible(ctx context.Context, workspaceID, userID uuid.UUID) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&models.CannedResponse{}).
+		Where("workspace_id = ?", workspaceID).
+		Where("(visibility = ? OR created_by = ?)", models.CannedShared, userID)
+}
+
+// escapeLike escape ký tự đặc biệt của LIKE trong chuỗi người dùng nhập
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Contact Repository Interface
+// Quản lý khách hàng (participants) cho dashboard: tìm kiếm, sửa thông tin, lịch sử
+// ===========================================================================
+
+// Sắp xếp danh sách khách
+const (
+	// ContactSortLastSeen khách nhắn tin gần nhất trước (mặc định)
+	ContactSortLastSeen = "last_seen"
+
+	// ContactSortCreated khách mới nhất trước
+	ContactSortCreated = "created"
+
+	// ContactSortName theo tên A-Z
+	ContactSortName = "name"
+)
+
+// ContactQuery điều kiện lọc danh sách khách
+type ContactQuery struct {
+	WorkspaceID uuid.UUID
+
+	// Text tìm theo tên, email, số điện thoại (khớp một phần)
+	Text string
+
+	// ChannelAccountID chỉ khách của một kênh
+	ChannelAccountID *uuid.UUID
+
+	// ChannelType chỉ khách của loại kênh (facebook, zalo, ...)
+	ChannelType string
+
+	// Tag khách có tag (không phân biệt hoa thường)
+	Tag string
+
+	// SeenFrom, SeenTo khoảng thời gian khách nhắn tin lần cuối
+	SeenFrom *time.Time
+	SeenTo   *time.Time
+
+	// Fields điều kiện trên trường tùy chỉnh, giá trị đã chuẩn hóa theo kiểu trường
+	Fields []models.FieldCondition
+
+	// CreatedBefore chỉ khách tạo trước thời điểm này (chốt danh sách khi đọc theo trang)
+	CreatedBefore *time.Time
+
+	// Sort last_seen, created hoặc name
+	Sort string
+
+	// SortField sắp xếp theo trường tùy chỉnh (thay cho Sort), khách chưa có giá trị xếp cuối
+	SortField string
+	SortDesc  bool
+
+	Offset int
+	Limit  int
+}
+
+// Loại hoạt động trong lịch sử của khách
+// Ngoài các loại dưới đây, thao tác sửa khách dùng tên action của audit log (contact.updated, ...)
+const (
+	// ActivityConversationStarted khách bắt đầu hội thoại
+	ActivityConversationStarted = "conversation_started"
+
+	// ActivityConversationClosed hội thoại được đóng
+	ActivityConversationClosed = "conversation_closed"
+
+	// ActivityCSATRated khách chấm điểm khảo sát
+	ActivityCSATRated = "csat_rated"
+
+	// ActivityCampaignSent khách nhận tin của chiến dịch
+	ActivityCampaignSent = "campaign_sent"
+)
+
+// ContactActivity một mục trong lịch sử hoạt động của khách
+type ContactActivity struct {
+	Type string    `json:"type"`
+	At   time.Time `json:"at"`
+
+	// ConversationID hội thoại liên quan (nếu có)
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+
+	// ActorID người thực hiện (agent sửa thông tin, agent được chấm điểm, người tạo chiến dịch)
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+
+	// Data chi tiết theo loại hoạt động
+	Data models.AuditData `json:"data"`
+}
+
+// MergeCandidate cặp danh tính có thể là cùng một khách
+type MergeCandidate struct {
+	ParticipantID uuid.UUID
+	CandidateID   uuid.UUID
+
+	// PhoneMatch, EmailMatch tiêu chí trùng khớp
+	PhoneMatch bool
+	EmailMatch bool
+}
+
+// ContactRepository interface cho quản lý khách hàng
+type ContactRepository interface {
+	// FindByWorkspace lấy danh sách khách theo điều kiện, kèm channel account
+	FindByWorkspace(ctx context.Context, q ContactQuery) ([]models.Participant, int64, error)
+
+	// FindByID tìm khách theo ID, kèm channel account
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Participant, error)
+
+	// FindByIDs lấy nhiều khách của workspace kèm channel account (ID không tồn tại bị bỏ qua)
+	FindByIDs(ctx context.Context, workspaceID uuid.UUID, ids []uuid.UUID) ([]models.Participant, error)
+
+	// FindIdentities lấy mọi participant đã gộp vào person, cũ nhất trước, kèm channel account
+	FindIdentities(ctx context.Context, personID uuid.UUID) ([]models.Participant, error)
+
+	// FindConversations lấy mọi hội thoại của các participant (mới nhất trước) kèm channel account, agent và tags
+	FindConversations(ctx context.Context, participantIDs []uuid.UUID) ([]models.Conversation, error)
+
+	// FindMessages lấy tin nhắn trong mọi hội thoại của các participant theo cursor (keyset), mới nhất trước
+	FindMessages(ctx context.Context, participantIDs []uuid.UUID, q CursorQuery) ([]models.Message, bool, error)
+
+	// FindByPhoneOrEmail tìm khách của workspace trùng số điện thoại (9 chữ số cuối) hoặc email
+	// (không phân biệt hoa thường), cũ nhất trước; phone/email rỗng thì bỏ qua tiêu chí đó
+	FindByPhoneOrEmail(ctx context.Context, workspaceID uuid.UUID, phone, email string) ([]models.Participant, error)
+
+	// Create tạo khách mới và audit log trong một transaction
+	Create(ctx context.Context, participant *models.Participant, audit *models.AuditLog) error
+
+	// Update lưu tên, email, số điện thoại, metadata của khách và audit log trong một transaction
+	Update(ctx context.Context, participant *models.Participant, audit *models.AuditLog) error
+
+	// Merge gộp các participant (và mọi danh tính đã gộp trước đó của chúng) vào một person
+	// Giữ person cũ nhất nếu đã có, không thì tạo mới; các person còn lại bị xóa
+	// Trả về ID person, audit log được ghi kèm với data.person_id
+	Merge(ctx context.Context, workspaceID, createdBy uuid.UUID, participantIDs []uuid.UUID, audit *models.AuditLog) (uuid.UUID, error)
+
+	// Unmerge tách participant khỏi person, person chỉ còn một danh tính thì bị xóa
+	// Trả về false nếu participant không còn thuộc person
+	Unmerge(ctx context.Context, participant *models.Participant, audit *models.AuditLog) (bool, error)
+
+	// FindMergeSuggestions tìm các cặp danh tính chưa gộp trùng số điện thoại hoặc email
+	// participantID khác nil thì chỉ tìm danh tính trùng với khách đó
+	FindMergeSuggestions(ctx context.Context, workspaceID uuid.UUID, participantID *uuid.UUID, limit int) ([]MergeCandidate, error)
+
+	// Timeline lịch sử hoạt động của các participant trước thời điểm before, mới nhất trước
+	Timeline(ctx context.Context, participantIDs []uuid.UUID, before time.Time, limit int) ([]ContactActivity, error)
+}
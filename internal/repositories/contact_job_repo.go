@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Contact Job Repository GORM Implementation
+// ===========================================================================
+
+// contactJobRepo triển khai ContactJobRepository với GORM
+type contactJobRepo struct {
+	db *gorm.DB
+}
+
+// NewContactJobRepository tạo instance mới của ContactJobRepository
+func NewContactJobRepository(db *gorm.DB) ContactJobRepository {
+	return &contactJobRepo{db: db}
+}
+
+// Create tạo job mới
+func (r *contactJobRepo) Create(ctx context.Context, job *models.ContactJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// Update lưu tiến độ/kết quả của job
+func (r *contactJobRepo) Update(ctx context.Context, job *models.ContactJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// FindByID tìm job theo ID
+func (r *contactJobRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.ContactJob, error) {
+	var job models.ContactJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindByWorkspace lấy các job gần nhất của workspace (không tải danh sách dòng lỗi)
+func (r *contactJobRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, limit int) ([]models.ContactJob, error) {
+	var jobs []models.ContactJob
+	err := r.db.WithContext(ctx).
+		Omit("errors").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// FindRunnable lấy job cũ nhất đang chờ hoặc đang chạy nhưng đã dừng cập nhật
+func (r *contactJobRepo) FindRunnable(ctx context.Context, staleBefore time.Time) (*models.ContactJob, error) {
+	var job models.ContactJob
+	err := r.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND updated_at < ?)", models.ContactJobPending, models.ContactJobRunning, staleBefore).
+		Order("created_at ASC").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Claim đánh dấu job đang chạy nếu chưa có worker khác nhận
+func (r *contactJobRepo) Claim(ctx context.Context, job *models.ContactJob) (bool, error) {
+	now := time.Now()
+	startedAt := job.StartedAt
+	if startedAt == nil {
+		startedAt = &now
+	}
+	result := r.db.WithContext(ctx).
+		Model(&models.ContactJob{}).
+		Where("id = ? AND updated_at = ?", job.ID, job.UpdatedAt).
+		Updates(map[string]interface{}{
+			"status":     models.ContactJobRunning,
+			"started_at": startedAt,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	job.Status = models.ContactJobRunning
+	job.StartedAt = startedAt
+	job.UpdatedAt = now
+	return true, nil
+}
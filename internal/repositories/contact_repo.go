@@ -0,0 +1,441 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"chatbox-gin/internal/database"
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ===========================================================================
+// Contact Repository GORM Implementation
+// ===========================================================================
+
+// contactRepo triển khai ContactRepository với GORM
+type contactRepo struct {
+	db *gorm.DB
+}
+
+// NewContactRepository tạo instance mới của ContactRepository
+func NewContactRepository(db *gorm.DB) ContactRepository {
+	return &contactRepo{db: db}
+}
+
+// contactTimelineSQL gộp các nguồn hoạt động của khách, mỗi nguồn là một nhánh UNION ALL
+const contactTimelineSQL = `SELECT * FROM (
+	SELECT 'conversation_started' AS type, c.created_at AS at, c.id AS conversation_id, NULL::uuid AS actor_id,
+		jsonb_build_object('channel_account_id', c.channel_account_id, 'subject', c.subject) AS data
+	FROM conversations c
+	WHERE c.participant_id IN @participants AND c.deleted_at IS NULL
+	UNION ALL
+	SELECT 'conversation_closed', c.resolved_at, c.id, c.assigned_to,
+		jsonb_build_object('channel_account_id', c.channel_account_id, 'reason', c.metadata->>'closed_reason')
+	FROM conversations c
+	WHERE c.participant_id IN @participants AND c.deleted_at IS NULL AND c.resolved_at IS NOT NULL
+	UNION ALL
+	SELECT 'csat_rated', r.responded_at, r.conversation_id, r.agent_id,
+		jsonb_build_object('score', r.score, 'comment', r.comment)
+	FROM csat_responses r
+	WHERE r.participant_id IN @participants AND r.deleted_at IS NULL AND r.responded_at IS NOT NULL
+	UNION ALL
+	SELECT 'campaign_sent', cr.sent_at, cr.conversation_id, cp.created_by,
+		jsonb_build_object('campaign_id', cp.id, 'campaign_name', cp.name, 'message_id', cr.message_id)
+	FROM campaign_recipients cr
+	JOIN campaigns cp ON cp.id = cr.campaign_id
+	WHERE cr.participant_id IN @participants AND cr.deleted_at IS NULL AND cr.sent_at IS NOT NULL
+	UNION ALL
+	SELECT a.action, a.created_at, NULL::uuid, a.actor_id, a.data
+	FROM audit_logs a
+	WHERE a.entity_type = @entity AND a.entity_id IN @participants AND a.deleted_at IS NULL
+) t
+WHERE t.at < @before
+ORDER BY t.at DESC
+LIMIT @limit`
+
+// contactMergeSuggestionsSQL tìm các cặp danh tính thuộc hai khách khác nhau có cùng số điện thoại hoặc email
+// Số điện thoại so theo 9 chữ số cuối để +84 912 345 678 khớp với 0912345678
+// Mỗi cặp khách (theo person, hoặc participant nếu chưa gộp) chỉ trả về một lần
+const contactMergeSuggestionsSQL = `WITH identities AS (
+	SELECT p.id, p.created_at, p.last_seen_at, COALESCE(p.person_id, p.id) AS group_id,
+		CASE WHEN length(regexp_replace(COALESCE(p.phone, ''), '\D', '', 'g')) >= 9
+			THEN right(regexp_replace(p.phone, '\D', '', 'g'), 9) END AS phone_key,
+		NULLIF(lower(trim(COALESCE(p.email, ''))), '') AS email_key
+	FROM participants p
+	WHERE p.workspace_id = @workspace AND p.deleted_at IS NULL
+		AND (p.phone IS NOT NULL OR p.email IS NOT NULL)
+)
+SELECT participant_id, candidate_id, phone_match, email_match FROM (
+	SELECT DISTINCT ON (a.group_id, b.group_id)
+		a.id AS participant_id, b.id AS candidate_id,
+		COALESCE(a.phone_key = b.phone_key, false) AS phone_match,
+		COALESCE(a.email_key = b.email_key, false) AS email_match,
+		GREATEST(a.last_seen_at, b.last_seen_at) AS last_seen_at
+	FROM identities a
+	JOIN identities b ON b.group_id <> a.group_id
+		AND (a.phone_key = b.phone_key OR a.email_key = b.email_key)
+	WHERE (@scoped AND a.group_id = @group) OR (NOT @scoped AND a.group_id < b.group_id)
+	ORDER BY a.group_id, b.group_id, a.created_at, b.created_at
+) s
+ORDER BY s.last_seen_at DESC NULLS LAST
+LIMIT @limit`
+
+// FindByWorkspace lấy danh sách khách theo điều kiện
+func (r *contactRepo) FindByWorkspace(ctx context.Context, q ContactQuery) ([]models.Participant, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Participant{}).
+		Where("participants.workspace_id = ?", q.WorkspaceID)
+
+	if text := strings.TrimSpace(q.Text); text != "" {
+		pattern := "%" + escapeLike(text) + "%"
+		if digits := phoneDigits(text); digits != "" {
+			query = query.Where("(participants.name ILIKE ? OR participants.email ILIKE ? OR "+
+				"regexp_replace(COALESCE(participants.phone, ''), '\\D', '', 'g') LIKE ?)", pattern, pattern, "%"+digits+"%")
+		} else {
+			query = query.Where("(participants.name ILIKE ? OR participants.email ILIKE ? OR participants.phone ILIKE ?)",
+				pattern, pattern, pattern)
+		}
+	}
+	if q.ChannelAccountID != nil {
+		query = query.Where("participants.channel_account_id = ?", *q.ChannelAccountID)
+	}
+	if q.ChannelType != "" {
+		query = query.Where("participants.channel_account_id IN (SELECT id FROM channel_accounts WHERE channel_type = ?)", q.ChannelType)
+	}
+	if q.Tag != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(COALESCE(participants.metadata->'tags', '[]'::jsonb)) AS pt(name)
+			WHERE LOWER(pt.name) = ?
+		)`, strings.ToLower(q.Tag))
+	}
+	if q.SeenFrom != nil {
+		query = query.Where("participants.last_seen_at >= ?", *q.SeenFrom)
+	}
+	if q.SeenTo != nil {
+		query = query.Where("participants.last_seen_at <= ?", *q.SeenTo)
+	}
+	if q.CreatedBefore != nil {
+		query = query.Where("participants.created_at < ?", *q.CreatedBefore)
+	}
+	query = applyFieldConditions(query, "participants.metadata", q.Fields)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case q.SortField != "":
+		if expr := fieldValueSQL("participants.metadata", q.SortField); expr != "" {
+			query = query.Order(fieldOrderClause(expr, q.SortDesc))
+		}
+		query = query.Order("participants.created_at DESC")
+	case q.Sort == ContactSortCreated:
+		query = query.Order("participants.created_at DESC")
+	case q.Sort == ContactSortName:
+		query = query.Order("participants.name ASC NULLS LAST").Order("participants.created_at DESC")
+	default:
+		query = query.Order("participants.last_seen_at DESC NULLS LAST").Order("participants.created_at DESC")
+	}
+
+	var participants []models.Participant
+	err := query.
+		Preload("ChannelAccount").
+		Offset(q.Offset).
+		Limit(q.Limit).
+		Find(&participants).Error
+	return participants, total, err
+}
+
+// FindByID tìm khách theo ID
+func (r *contactRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Participant, error) {
+	var participant models.Participant
+	if err := r.db.WithContext(ctx).
+		Preload("ChannelAccount").
+		First(&participant, id).Error; err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// FindByIDs lấy nhiều khách của workspace
+func (r *contactRepo) FindByIDs(ctx context.Context, workspaceID uuid.UUID, ids []uuid.UUID) ([]models.Participant, error) {
+	var participants []models.Participant
+	if len(ids) == 0 {
+		return participants, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("ChannelAccount").
+		Where("workspace_id = ? AND id IN ?", workspaceID, ids).
+		Find(&participants).Error
+	return participants, err
+}
+
+// FindIdentities lấy mọi participant đã gộp vào person
+func (r *contactRepo) FindIdentities(ctx context.Context, personID uuid.UUID) ([]models.Participant, error) {
+	var participants []models.Participant
+	err := r.db.WithContext(ctx).
+		Preload("ChannelAccount").
+		Where("person_id = ?", personID).
+		Order("created_at ASC").
+		Find(&participants).Error
+	return participants, err
+}
+
+// FindConversations lấy mọi hội thoại của các participant
+func (r *contactRepo) FindConversations(ctx context.Context, participantIDs []uuid.UUID) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	err := r.db.WithContext(ctx).
+		Preload("ChannelAccount").
+		Preload("AssignedUser").
+		Preload("Tags").
+		Where("participant_id IN ?", participantIDs).
+		Order("created_at DESC").
+		Find(&conversations).Error
+	return conversations, err
+}
+
+// FindMessages lấy tin nhắn của các participant theo cursor
+func (r *contactRepo) FindMessages(ctx context.Context, participantIDs []uuid.UUID, q CursorQuery) ([]models.Message, bool, error) {
+	var messages []models.Message
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Where("conversation_id IN (?)", r.db.Model(&models.Conversation{}).
+			Select("id").
+			Where("participant_id IN ?", participantIDs))
+
+	if err := q.apply(query, "created_at", "id").Find(&messages).Error; err != nil {
+		return nil, false, err
+	}
+
+	messages, hasMore := trimPage(messages, q)
+	return messages, hasMore, nil
+}
+
+// FindByPhoneOrEmail tìm khách trùng số điện thoại hoặc email
+// Số điện thoại so theo 9 chữ số cuối (như gợi ý gộp) để 0912... và +84912... là một
+// Điều kiện dùng đúng biểu thức của expression index (database.SetupContactIndexes)
+func (r *contactRepo) FindByPhoneOrEmail(ctx context.Context, workspaceID uuid.UUID, phone, email string) ([]models.Participant, error) {
+	var participants []models.Participant
+
+	var conditions []string
+	var args []interface{}
+	if digits := phoneDigits(phone); digits != "" {
+		if len(digits) > 9 {
+			digits = digits[len(digits)-9:]
+		}
+		conditions = append(conditions, "(phone IS NOT NULL AND "+database.ParticipantPhoneKey+" = ?)")
+		args = append(args, digits)
+	}
+	if email = strings.TrimSpace(email); email != "" {
+		conditions = append(conditions, "(email IS NOT NULL AND "+database.ParticipantEmailKey+" = ?)")
+		args = append(args, strings.ToLower(email))
+	}
+	if len(conditions) == 0 {
+		return participants, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order("created_at ASC").
+		Find(&participants).Error
+	return participants, err
+}
+
+// Create tạo khách mới và audit log
+func (r *contactRepo) Create(ctx context.Context, participant *models.Participant, audit *models.AuditLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Workspace", "ChannelAccount", "Conversations").Create(participant).Error; err != nil {
+			return err
+		}
+		if audit != nil {
+			audit.EntityID = participant.ID
+			return tx.Create(audit).Error
+		}
+		return nil
+	})
+}
+
+// Update lưu thông tin khách và audit log
+func (r *contactRepo) Update(ctx context.Context, participant *models.Participant, audit *models.AuditLog) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Chỉ ghi các cột được sửa: last_seen_at/last_inbound_at do webhook ghi song song,
+		// person_id chỉ đổi qua Merge/Unmerge
+		if err := tx.Model(participant).
+			Select("name", "email", "phone", "metadata", "updated_at").
+			Updates(participant).Error; err != nil {
+			return err
+		}
+		if audit != nil {
+			return tx.Create(audit).Error
+		}
+		return nil
+	})
+}
+
+// Merge gộp các participant vào một person
+func (r *contactRepo) Merge(ctx context.Context, workspaceID, createdBy uuid.UUID, participantIDs []uuid.UUID, audit *models.AuditLog) (uuid.UUID, error) {
+	var personID uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Khóa các participant để hai thao tác gộp song song không tạo hai person
+		var participants []models.Participant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("workspace_id = ? AND id IN ?", workspaceID, participantIDs).
+			Find(&participants).Error; err != nil {
+			return err
+		}
+		if len(participants) != len(participantIDs) {
+			return gorm.ErrRecordNotFound
+		}
+
+		var existing []uuid.UUID
+		for _, p := range participants {
+			if p.PersonID != nil {
+				existing = append(existing, *p.PersonID)
+			}
+		}
+
+		var people []models.Person
+		if len(existing) > 0 {
+			if err := tx.Where("id IN ?", existing).Order("created_at ASC").Find(&people).Error; err != nil {
+				return err
+			}
+		}
+		if len(people) == 0 {
+			person := models.Person{WorkspaceID: workspaceID, CreatedBy: &createdBy}
+			if err := tx.Create(&person).Error; err != nil {
+				return err
+			}
+			people = append(people, person)
+		}
+		personID = people[0].ID
+
+		absorbed := make([]uuid.UUID, 0, len(people)-1)
+		for _, person := range people[1:] {
+			absorbed = append(absorbed, person.ID)
+		}
+
+		update := tx.Model(&models.Participant{}).Where("id IN ?", participantIDs)
+		if len(absorbed) > 0 {
+			update = tx.Model(&models.Participant{}).Where("id IN ? OR person_id IN ?", participantIDs, absorbed)
+		}
+		if err := update.Updates(map[string]interface{}{
+			"person_id":  personID,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		if len(absorbed) > 0 {
+			if err := tx.Delete(&models.Person{}, absorbed).Error; err != nil {
+				return err
+			}
+		}
+
+		if audit == nil {
+			return nil
+		}
+		if audit.Data == nil {
+			audit.Data = models.AuditData{}
+		}
+		audit.Data["person_id"] = personID
+		return tx.Create(audit).Error
+	})
+	return personID, err
+}
+
+// Unmerge tách participant khỏi person
+func (r *contactRepo) Unmerge(ctx context.Context, participant *models.Participant, audit *models.AuditLog) (bool, error) {
+	if participant.PersonID == nil {
+		return false, nil
+	}
+	personID := *participant.PersonID
+
+	detached := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Participant{}).
+			Where("id = ? AND person_id = ?", participant.ID, personID).
+			Updates(map[string]interface{}{"person_id": nil, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		detached = true
+
+		// Person chỉ còn một danh tính thì không cần giữ
+		var remaining int64
+		if err := tx.Model(&models.Participant{}).Where("person_id = ?", personID).Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining <= 1 {
+			if err := tx.Model(&models.Participant{}).
+				Where("person_id = ?", personID).
+				Updates(map[string]interface{}{"person_id": nil, "updated_at": now}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&models.Person{}, personID).Error; err != nil {
+				return err
+			}
+		}
+
+		if audit != nil {
+			return tx.Create(audit).Error
+		}
+		return nil
+	})
+	if err != nil || !detached {
+		return false, err
+	}
+	participant.PersonID = nil
+	return true, nil
+}
+
+// FindMergeSuggestions tìm các cặp danh tính trùng số điện thoại hoặc email
+func (r *contactRepo) FindMergeSuggestions(ctx context.Context, workspaceID uuid.UUID, participantID *uuid.UUID, limit int) ([]MergeCandidate, error) {
+	group := uuid.Nil
+	if participantID != nil {
+		var participant models.Participant
+		if err := r.db.WithContext(ctx).Select("id", "person_id").First(&participant, *participantID).Error; err != nil {
+			return nil, err
+		}
+		group = participant.ID
+		if participant.PersonID != nil {
+			group = *participant.PersonID
+		}
+	}
+
+	var candidates []MergeCandidate
+	err := r.db.WithContext(ctx).
+		Raw(contactMergeSuggestionsSQL,
+			sql.Named("workspace", workspaceID),
+			sql.Named("scoped", participantID != nil),
+			sql.Named("group", group),
+			sql.Named("limit", limit),
+		).
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+// Timeline lịch sử hoạt động của các participant
+func (r *contactRepo) Timeline(ctx context.Context, participantIDs []uuid.UUID, before time.Time, limit int) ([]ContactActivity, error) {
+	var activities []ContactActivity
+	err := r.db.WithContext(ctx).
+		Raw(contactTimelineSQL,
+			sql.Named("participants", participantIDs),
+			sql.Named("entity", models.AuditEntityContact),
+			sql.Named("before", before),
+			sql.Named("limit", limit),
+		).
+		Scan(&activities).Error
+	return activities, err
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"chatbox-gin/internal/models"
 
@@ -28,6 +29,12 @@ type ParticipantRepository interface {
 
 	// Update cập nhật participant
 	Update(ctx context.Context, participant *models.Participant) error
+
+	// RecordInbound ghi nhận lần cuối khách nhắn tin (bỏ qua nếu đã lưu mốc muộn hơn)
+	RecordInbound(ctx context.Context, participantID uuid.UUID, at time.Time) error
+
+	// UpdateMetadata chỉ cập nhật metadata (tags, trường tùy chỉnh) của participant
+	UpdateMetadata(ctx context.Context, participant *models.Participant) error
 }
 
 // ===========================================================================
@@ -40,6 +47,17 @@ type ConversationRepository interface {
 	// FindByID tìm conversation theo ID
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Conversation, error)
 
+	// FindPageByWorkspace lấy conversations theo cursor (keyset), hoạt động gần nhất trước
+	// Trả về thêm cờ còn trang tiếp theo theo hướng của cursor
+	FindPageByWorkspace(ctx context.Context, workspaceID uuid.UUID, filters map[string]interface{}, q CursorQuery) ([]models.Conversation, bool, error)
+
+	// FindByIDs lấy nhiều conversation của workspace kèm participant, channel và tags
+	FindByIDs(ctx context.Context, workspaceID uuid.UUID, ids []uuid.UUID) ([]models.Conversation, error)
+
+	// FindDetailByID tìm conversation kèm đầy đủ quan hệ cho màn hình chi tiết
+	// (participant, channel, agent được assign, tags, notes)
+	FindDetailByID(ctx context.Context, id uuid.UUID) (*models.Conversation, error)
+
 	// FindByThreadID tìm conversation theo channel thread ID
 	FindByThreadID(ctx context.Context, channelAccountID uuid.UUID, threadID string) (*models.Conversation, error)
 
@@ -51,13 +69,70 @@ type ConversationRepository interface {
 	FindOrCreate(ctx context.Context, conv *models.Conversation) (*models.Conversation, bool, error)
 
 	// FindOpenByParticipant tìm conversation đang mở của participant
+	// Conversation đang tạm ẩn vẫn tính là mở (tin nhắn mới của khách vào lại conversation đó)
 	FindOpenByParticipant(ctx context.Context, participantID uuid.UUID) (*models.Conversation, error)
 
+	// FindLatestByParticipant tìm conversation để gửi tin chủ động cho participant:
+	// conversation đang mở nếu có, không thì conversation gần nhất (kể cả đã đóng)
+	FindLatestByParticipant(ctx context.Context, participantID uuid.UUID) (*models.Conversation, error)
+
+	// CountOpenByAssignees đếm số conversation chưa đóng của từng agent
+	// Agent không có conversation nào sẽ không có trong map
+	CountOpenByAssignees(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+
+	// FindLastAssigneeByParticipant tìm agent gần nhất đã xử lý participant
+	// Trả về nil nếu participant chưa từng được assign
+	FindLastAssigneeByParticipant(ctx context.Context, participantID uuid.UUID, excludeConversationID uuid.UUID) (*uuid.UUID, error)
+
+	// FindInactive lấy các conversation chưa đóng (trừ đang tạm ẩn) mà khách không nhắn tin kể từ before
+	// Conversation chưa có tin nhắn của khách tính từ thời điểm tạo
+	FindInactive(ctx context.Context, workspaceID uuid.UUID, before time.Time, limit int) ([]models.Conversation, error)
+
+	// CloseInactive đóng conversation (status, resolved_at, closed_reason) chỉ khi vẫn chưa đóng,
+	// không tạm ẩn và khách chưa nhắn tin kể từ before. Trả về false nếu không còn thỏa điều kiện
+	CloseInactive(ctx context.Context, conv *models.Conversation, before time.Time) (bool, error)
+
+	// FindUnresolved lấy các conversation chưa đóng của workspace (cho SLA evaluator)
+	FindUnresolved(ctx context.Context, workspaceID uuid.UUID) ([]models.Conversation, error)
+
+	// FindSnoozedDue lấy các conversation tạm ẩn cần mở lại: đã hết hạn tạm ẩn
+	// hoặc khách đã nhắn lại kể từ lúc tạm ẩn (kèm participant)
+	FindSnoozedDue(ctx context.Context, now time.Time, limit int) ([]models.Conversation, error)
+
+	// Wake mở lại conversation tạm ẩn về trạng thái trước khi tạm ẩn (open nếu không hợp lệ)
+	// chỉ khi vẫn đang tạm ẩn và vẫn cần mở lại như FindSnoozedDue. Không ghi metadata
+	// Ghi status, assigned_to hiện tại vào conv; trả về false nếu đã bị đóng/mở lại/tạm ẩn lại
+	Wake(ctx context.Context, conv *models.Conversation, now time.Time) (bool, error)
+
+	// FindIDsByFilter lấy ID các conversation của workspace khớp bộ lọc thao tác hàng loạt
+	// Hoạt động gần nhất trước, tối đa limit kết quả
+	FindIDsByFilter(ctx context.Context, workspaceID uuid.UUID, filter models.BulkFilter, limit int) ([]uuid.UUID, error)
+
 	// Create tạo conversation mới
 	Create(ctx context.Context, conv *models.Conversation) error
 
 	// Update cập nhật conversation
 	Update(ctx context.Context, conv *models.Conversation) error
+
+	// UpdateSLA chỉ cập nhật các trường SLA, priority và các key SLA trong metadata
+	// để không ghi đè tin nhắn cuối do luồng nhận tin cập nhật song song
+	UpdateSLA(ctx context.Context, conv *models.Conversation) error
+
+	// UpdateState chỉ cập nhật status, assign, priority, resolved_at, tạm ẩn và metadata
+	// để không ghi đè tin nhắn cuối và quan hệ (tags, participant) đã tải kèm
+	UpdateState(ctx context.Context, conv *models.Conversation) error
+
+	// Assign gán agent nếu assigned_to vẫn là expected (nil = chưa có agent), open chuyển sang pending
+	// như Conversation.Assign. Chỉ ghi assigned_to, status, snoozed_from_status tính trên bản ghi hiện tại
+	// Trả về false nếu luồng khác đã assign trước
+	Assign(ctx context.Context, id, userID uuid.UUID, expected *uuid.UUID) (bool, error)
+
+	// Bump đưa conversation lên đầu inbox (last_message_at = at nếu muộn hơn)
+	Bump(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// UpdateLastMessage ghi tin nhắn cuối (preview, thời điểm) nếu at muộn hơn tin nhắn cuối hiện tại
+	// Chỉ ghi hai cột này để không ghi đè trạng thái, assign, tạm ẩn do luồng khác cập nhật song song
+	UpdateLastMessage(ctx context.Context, id uuid.UUID, preview string, at time.Time) error
 }
 
 // ===========================================================================
@@ -73,6 +148,10 @@ type MessageRepository interface {
 	// FindByConversation lấy danh sách messages trong conversation
 	FindByConversation(ctx context.Context, conversationID uuid.UUID, opts FindOptions) ([]models.Message, int64, error)
 
+	// FindPageByConversation lấy messages theo cursor (keyset), mới nhất trước
+	// Trả về thêm cờ còn trang tiếp theo theo hướng của cursor
+	FindPageByConversation(ctx context.Context, conversationID uuid.UUID, q CursorQuery) ([]models.Message, bool, error)
+
 	// FindByChannelMessageID tìm message theo channel message ID
 	// Dùng để check duplicate
 	FindByChannelMessageID(ctx context.Context, channelMessageID string) (*models.Message, error)
@@ -85,4 +164,50 @@ type MessageRepository interface {
 
 	// MarkAsRead đánh dấu message đã đọc
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
+
+	// MarkReadUpTo đánh dấu đã đọc các tin nhắn của khách trong conversation
+	// đến hết cursor upTo (nil = tất cả), trả về số tin nhắn được đánh dấu
+	MarkReadUpTo(ctx context.Context, conversationID uuid.UUID, upTo *Cursor) (int64, error)
+
+	// CountUnreadByConversations đếm tin nhắn chưa đọc của khách theo từng conversation
+	CountUnreadByConversations(ctx context.Context, conversationIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+
+	// CountUnread đếm tin nhắn chưa đọc trong các conversation chưa đóng của workspace
+	// assignedTo khác nil thì chỉ đếm conversation được assign cho agent đó
+	CountUnread(ctx context.Context, workspaceID uuid.UUID, assignedTo *uuid.UUID) (UnreadCount, error)
+
+	// MarkReceipt ghi nhận khách đã nhận/đã xem các tin nhắn gửi đi
+	// Chỉ cập nhật tin nhắn chưa có mốc thời gian tương ứng
+	MarkReceipt(ctx context.Context, receipt ReceiptUpdate) (int64, error)
+
+	// PatchAttachment ghi đè một số trường của attachment thứ index trong message
+	// Cập nhật trực tiếp trong database nên không ghi đè các attachment/trường khác
+	PatchAttachment(ctx context.Context, messageID uuid.UUID, index int, patch map[string]interface{}) error
+}
+
+// ReceiptField mốc thời gian trong metadata của tin nhắn gửi đi
+type ReceiptField string
+
+const (
+	// ReceiptDelivered khách đã nhận (metadata.delivered_at)
+	ReceiptDelivered ReceiptField = "delivered_at"
+
+	// ReceiptSeen khách đã xem (metadata.seen_at)
+	ReceiptSeen ReceiptField = "seen_at"
+)
+
+// ReceiptUpdate điều kiện cập nhật báo nhận/đã xem
+// Tin nhắn khớp nếu nằm trong ChannelMessageIDs hoặc được tạo trước Watermark
+type ReceiptUpdate struct {
+	ParticipantID     uuid.UUID
+	Field             ReceiptField
+	ChannelMessageIDs []string
+	Watermark         *time.Time
+	At                time.Time
+}
+
+// UnreadCount tổng số tin nhắn và số conversation có tin nhắn chưa đọc
+type UnreadCount struct {
+	Messages      int64 `json:"messages"`
+	Conversations int64 `json:"conversations"`
 }
@@ -2,11 +2,15 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"chatbox-gin/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ===========================================================================
@@ -35,6 +39,39 @@ func (r *conversationRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.
 	return &conv, nil
 }
 
+// FindByIDs lấy nhiều conversation của workspace kèm participant, channel và tags
+func (r *conversationRepo) FindByIDs(ctx context.Context, workspaceID uuid.UUID, ids []uuid.UUID) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	if len(ids) == 0 {
+		return conversations, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Participant").
+		Preload("ChannelAccount").
+		Preload("Tags").
+		Where("workspace_id = ? AND id IN ?", workspaceID, ids).
+		Find(&conversations).Error
+	return conversations, err
+}
+
+// FindDetailByID tìm conversation kèm đầy đủ quan hệ cho màn hình chi tiết
+func (r *conversationRepo) FindDetailByID(ctx context.Context, id uuid.UUID) (*models.Conversation, error) {
+	var conv models.Conversation
+	if err := r.db.WithContext(ctx).
+		Preload("Participant").
+		Preload("ChannelAccount").
+		Preload("AssignedUser").
+		Preload("Tags").
+		Preload("Notes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("is_pinned DESC, created_at ASC")
+		}).
+		Preload("Notes.User").
+		First(&conv, id).Error; err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
 // FindByThreadID tìm conversation theo channel thread ID
 func (r *conversationRepo) FindByThreadID(ctx context.Context, channelAccountID uuid.UUID, threadID string) (*models.Conversation, error) {
 	var conv models.Conversation
@@ -54,17 +91,21 @@ func (r *conversationRepo) FindByWorkspace(ctx context.Context, workspaceID uuid
 	var conversations []models.Conversation
 	var total int64
 
-	query := r.db.WithContext(ctx).
+	query := applyConversationFilters(r.db.WithContext(ctx).
 		Model(&models.Conversation{}).
-		Where("workspace_id = ?", workspaceID)
+		Where("workspace_id = ?", workspaceID), opts.Filters)
 
-	// Apply filters
-	if opts.Filters != nil {
-		if status, ok := opts.Filters["status"]; ok {
-			query = query.Where("status = ?", status)
-		}
-		if assignedTo, ok := opts.Filters["assigned_to"]; ok {
-			query = query.Where("assigned_to = ?", assignedTo)
+	// Sắp xếp theo hạn SLA gần nhất: hạn trả lời đầu nếu chưa trả lời, ngược lại hạn giải quyết
+	orderClause := opts.GetOrderClause()
+	if opts.OrderBy == "sla_due" {
+		orderClause = "CASE WHEN first_response_at IS NULL THEN sla_first_response_due_at ELSE sla_resolution_due_at END ASC NULLS LAST"
+	}
+	// Sắp xếp theo trường tùy chỉnh của khách, hoạt động gần nhất trước nếu cùng giá trị
+	if key, ok := strings.CutPrefix(opts.OrderBy, FieldSortPrefix); ok {
+		orderClause = "last_message_at DESC NULLS LAST"
+		if expr := fieldValueSQL("participants.metadata", key); expr != "" {
+			orderClause = fieldOrderClause("(SELECT "+expr+" FROM participants WHERE participants.id = conversations.participant_id)",
+				opts.OrderDir == "desc") + ", " + orderClause
 		}
 	}
 
@@ -77,7 +118,7 @@ func (r *conversationRepo) FindByWorkspace(ctx context.Context, workspaceID uuid
 	err := query.
 		Preload("Participant").
 		Preload("ChannelAccount").
-		Order(opts.GetOrderClause()).
+		Order(orderClause).
 		Offset(opts.Offset).
 		Limit(opts.Limit).
 		Find(&conversations).Error
@@ -85,6 +126,63 @@ func (r *conversationRepo) FindByWorkspace(ctx context.Context, workspaceID uuid
 	return conversations, total, err
 }
 
+// conversationActivityColumn thời điểm hoạt động dùng cho keyset pagination
+// Conversation chưa có tin nhắn lấy theo thời điểm tạo
+const conversationActivityColumn = "COALESCE(last_message_at, created_at)"
+
+// FindPageByWorkspace lấy conversations theo cursor, hoạt động gần nhất trước
+func (r *conversationRepo) FindPageByWorkspace(ctx context.Context, workspaceID uuid.UUID, filters map[string]interface{}, q CursorQuery) ([]models.Conversation, bool, error) {
+	var conversations []models.Conversation
+
+	query := applyConversationFilters(r.db.WithContext(ctx).
+		Model(&models.Conversation{}).
+		Where("workspace_id = ?", workspaceID), filters)
+
+	err := q.apply(query, conversationActivityColumn, "id").
+		Preload("Participant").
+		Preload("ChannelAccount").
+		Find(&conversations).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	conversations, hasMore := trimPage(conversations, q)
+	return conversations, hasMore, nil
+}
+
+// ConversationCursor cursor của conversation theo thời điểm hoạt động gần nhất
+func ConversationCursor(conv *models.Conversation) Cursor {
+	at := conv.CreatedAt
+	if conv.LastMessageAt != nil {
+		at = *conv.LastMessageAt
+	}
+	return NewCursor(at, conv.ID)
+}
+
+// applyConversationFilters áp dụng các filter của danh sách conversation
+func applyConversationFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+	if assignedTo, ok := filters["assigned_to"]; ok {
+		query = query.Where("assigned_to = ?", assignedTo)
+	}
+	if priority, ok := filters["priority"]; ok {
+		query = query.Where("priority = ?", priority)
+	}
+	if slaStatus, ok := filters["sla_status"]; ok {
+		query = query.Where("sla_status = ?", slaStatus)
+	}
+	// Điều kiện trên trường tùy chỉnh của khách
+	if fields, ok := filters["fields"].([]models.FieldCondition); ok && len(fields) > 0 {
+		participants := applyFieldConditions(query.Session(&gorm.Session{NewDB: true}).
+			Model(&models.Participant{}).
+			Select("participants.id"), "participants.metadata", fields)
+		query = query.Where("participant_id IN (?)", participants)
+	}
+	return query
+}
+
 // FindOrCreate tìm hoặc tạo mới conversation
 func (r *conversationRepo) FindOrCreate(ctx context.Context, conv *models.Conversation) (*models.Conversation, bool, error) {
 	// Thử tìm conversation đang mở của participant
@@ -106,7 +204,7 @@ func (r *conversationRepo) FindOpenByParticipant(ctx context.Context, participan
 	var conv models.Conversation
 	err := r.db.WithContext(ctx).
 		Where("participant_id = ?", participantID).
-		Where("status IN ?", []models.ConversationStatus{models.StatusOpen, models.StatusPending, models.StatusBotPaused}).
+		Where("status IN ?", []models.ConversationStatus{models.StatusOpen, models.StatusPending, models.StatusBotPaused, models.StatusSnoozed}).
 		Order("created_at DESC").
 		First(&conv).Error
 	if err != nil {
@@ -115,6 +213,174 @@ func (r *conversationRepo) FindOpenByParticipant(ctx context.Context, participan
 	return &conv, nil
 }
 
+// FindLatestByParticipant tìm conversation đang mở, không có thì conversation gần nhất
+func (r *conversationRepo) FindLatestByParticipant(ctx context.Context, participantID uuid.UUID) (*models.Conversation, error) {
+	var conv models.Conversation
+	err := r.db.WithContext(ctx).
+		Where("participant_id = ?", participantID).
+		Order(clause.Expr{SQL: "CASE WHEN status = ? THEN 1 ELSE 0 END", Vars: []interface{}{models.StatusClosed}}).
+		Order("created_at DESC").
+		First(&conv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+// CountOpenByAssignees đếm số conversation chưa đóng của từng agent
+func (r *conversationRepo) CountOpenByAssignees(ctx context.Context, workspaceID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		AssignedTo uuid.UUID
+		Total      int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.Conversation{}).
+		Select("assigned_to, COUNT(*) AS total").
+		Where("workspace_id = ? AND assigned_to IN ?", workspaceID, userIDs).
+		Where("status <> ?", models.StatusClosed).
+		Group("assigned_to").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.AssignedTo] = row.Total
+	}
+	return counts, nil
+}
+
+// FindLastAssigneeByParticipant tìm agent gần nhất đã xử lý participant
+func (r *conversationRepo) FindLastAssigneeByParticipant(ctx context.Context, participantID uuid.UUID, excludeConversationID uuid.UUID) (*uuid.UUID, error) {
+	var conv models.Conversation
+	err := r.db.WithContext(ctx).
+		Select("assigned_to").
+		Where("participant_id = ? AND id <> ? AND assigned_to IS NOT NULL", participantID, excludeConversationID).
+		Order("created_at DESC").
+		First(&conv).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return conv.AssignedTo, nil
+}
+
+// FindInactive lấy các conversation chưa đóng (trừ đang tạm ẩn) mà khách không nhắn tin kể từ before
+func (r *conversationRepo) FindInactive(ctx context.Context, workspaceID uuid.UUID, before time.Time, limit int) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	err := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND status NOT IN ?", workspaceID, []models.ConversationStatus{models.StatusClosed, models.StatusSnoozed}).
+		Where("COALESCE(last_inbound_at, created_at) < ?", before).
+		Order("COALESCE(last_inbound_at, created_at) ASC").
+		Limit(limit).
+		Find(&conversations).Error
+	return conversations, err
+}
+
+// CloseInactive đóng conversation nếu vẫn không hoạt động kể từ before
+func (r *conversationRepo) CloseInactive(ctx context.Context, conv *models.Conversation, before time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Conversation{}).
+		Where("id = ? AND status NOT IN ?", conv.ID, []models.ConversationStatus{models.StatusClosed, models.StatusSnoozed}).
+		Where("COALESCE(last_inbound_at, created_at) < ?", before).
+		Updates(map[string]interface{}{
+			"status":      conv.Status,
+			"resolved_at": conv.ResolvedAt,
+			"metadata": gorm.Expr(
+				"COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('closed_reason', ?::text)",
+				conv.Metadata.ClosedReason,
+			),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindUnresolved lấy các conversation chưa đóng của workspace
+func (r *conversationRepo) FindUnresolved(ctx context.Context, workspaceID uuid.UUID) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	err := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND status <> ?", workspaceID, models.StatusClosed).
+		Find(&conversations).Error
+	return conversations, err
+}
+
+// FindSnoozedDue lấy các conversation tạm ẩn đã hết hạn hoặc khách đã nhắn lại
+func (r *conversationRepo) FindSnoozedDue(ctx context.Context, now time.Time, limit int) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	err := r.db.WithContext(ctx).
+		Preload("Participant").
+		Where("status = ?", models.StatusSnoozed).
+		Where("snoozed_until <= ? OR last_inbound_at > snoozed_at", now).
+		Order("snoozed_until ASC").
+		Limit(limit).
+		Find(&conversations).Error
+	return conversations, err
+}
+
+// Wake mở lại conversation tạm ẩn nếu vẫn cần mở lại
+func (r *conversationRepo) Wake(ctx context.Context, conv *models.Conversation, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(conv).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "status"}, {Name: "assigned_to"}}}).
+		Where("status = ?", models.StatusSnoozed).
+		Where("snoozed_until <= ? OR last_inbound_at > snoozed_at", now).
+		Updates(map[string]interface{}{
+			"status": gorm.Expr("CASE WHEN COALESCE(snoozed_from_status, '') IN ('', ?, ?) THEN ? ELSE snoozed_from_status END",
+				models.StatusSnoozed, models.StatusClosed, models.StatusOpen),
+			"snoozed_until":       nil,
+			"snoozed_at":          nil,
+			"snoozed_from_status": "",
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindIDsByFilter lấy ID các conversation khớp bộ lọc thao tác hàng loạt
+func (r *conversationRepo) FindIDsByFilter(ctx context.Context, workspaceID uuid.UUID, filter models.BulkFilter, limit int) ([]uuid.UUID, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Conversation{}).
+		Where("workspace_id = ?", workspaceID)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ChannelAccountID != nil {
+		query = query.Where("channel_account_id = ?", *filter.ChannelAccountID)
+	}
+	if filter.AssignedTo != nil {
+		query = query.Where("assigned_to = ?", *filter.AssignedTo)
+	}
+	if filter.Unassigned {
+		query = query.Where("assigned_to IS NULL")
+	}
+	if filter.Tag != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM conversation_tags ct JOIN tags t ON t.id = ct.tag_id
+			WHERE ct.conversation_id = conversations.id AND LOWER(t.name) = LOWER(?)
+		)`, filter.Tag)
+	}
+	if filter.OlderThanHours > 0 {
+		before := time.Now().Add(-time.Duration(filter.OlderThanHours) * time.Hour)
+		query = query.Where(conversationActivityColumn+" < ?", before)
+	}
+	if filter.NewerThanHours > 0 {
+		after := time.Now().Add(-time.Duration(filter.NewerThanHours) * time.Hour)
+		query = query.Where(conversationActivityColumn+" >= ?", after)
+	}
+
+	var ids []uuid.UUID
+	err := query.
+		Order(conversationActivityColumn+" DESC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
 // Create tạo conversation mới
 func (r *conversationRepo) Create(ctx context.Context, conv *models.Conversation) error {
 	return r.db.WithContext(ctx).Create(conv).Error
@@ -124,3 +390,77 @@ func (r *conversationRepo) Create(ctx context.Context, conv *models.Conversation
 func (r *conversationRepo) Update(ctx context.Context, conv *models.Conversation) error {
 	return r.db.WithContext(ctx).Save(conv).Error
 }
+
+// UpdateSLA chỉ cập nhật các trường SLA, priority và các key SLA trong metadata
+// (các key khác như closed_reason, referral do luồng khác ghi song song được giữ nguyên)
+func (r *conversationRepo) UpdateSLA(ctx context.Context, conv *models.Conversation) error {
+	slaMetadata := models.ConversationMetadata{
+		SLABreached:        conv.Metadata.SLABreached,
+		SLABreachedTargets: conv.Metadata.SLABreachedTargets,
+		SLAEscalatedAt:     conv.Metadata.SLAEscalatedAt,
+	}
+	data, err := json.Marshal(slaMetadata)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Model(conv).
+		Updates(map[string]interface{}{
+			"sla_status":                conv.SLAStatus,
+			"sla_first_response_due_at": conv.SLAFirstResponseDueAt,
+			"sla_resolution_due_at":     conv.SLAResolutionDueAt,
+			"priority":                  conv.Priority,
+			"metadata": gorm.Expr(
+				"(COALESCE(metadata, '{}'::jsonb) - 'sla_breached' - 'sla_breached_targets' - 'sla_escalated_at') || ?::jsonb",
+				string(data),
+			),
+		}).Error
+}
+
+// UpdateState chỉ cập nhật trạng thái xử lý của conversation
+func (r *conversationRepo) UpdateState(ctx context.Context, conv *models.Conversation) error {
+	return r.db.WithContext(ctx).
+		Model(conv).
+		Select("status", "assigned_to", "priority", "resolved_at", "snoozed_until", "snoozed_at", "snoozed_from_status", "metadata").
+		Updates(conv).Error
+}
+
+// Assign gán agent nếu assigned_to vẫn là expected
+func (r *conversationRepo) Assign(ctx context.Context, id, userID uuid.UUID, expected *uuid.UUID) (bool, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Conversation{}).
+		Where("id = ?", id)
+	if expected == nil {
+		query = query.Where("assigned_to IS NULL")
+	} else {
+		query = query.Where("assigned_to = ?", *expected)
+	}
+	result := query.Updates(map[string]interface{}{
+		"assigned_to": userID,
+		"status": gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END",
+			models.StatusOpen, models.StatusPending),
+		"snoozed_from_status": gorm.Expr("CASE WHEN status = ? AND snoozed_from_status = ? THEN ? ELSE snoozed_from_status END",
+			models.StatusSnoozed, models.StatusOpen, models.StatusPending),
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Bump đưa conversation lên đầu inbox
+func (r *conversationRepo) Bump(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Conversation{}).
+		Where("id = ?", id).
+		Update("last_message_at", gorm.Expr("GREATEST(COALESCE(last_message_at, created_at), ?)", at)).Error
+}
+
+// UpdateLastMessage ghi tin nhắn cuối nếu at muộn hơn tin nhắn cuối hiện tại
+func (r *conversationRepo) UpdateLastMessage(ctx context.Context, id uuid.UUID, preview string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Conversation{}).
+		Where("id = ?", id).
+		Where("last_message_at IS NULL OR last_message_at <= ?", at).
+		Updates(map[string]interface{}{
+			"last_message_preview": preview,
+			"last_message_at":      at,
+		}).Error
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// CSAT Repository Interface
+// Lưu khảo sát mức độ hài lòng và tổng hợp báo cáo
+// ===========================================================================
+
+// CSATGroupBy chiều gom nhóm của báo cáo CSAT
+type CSATGroupBy string
+
+const (
+	// CSATGroupNone chỉ lấy số tổng
+	CSATGroupNone CSATGroupBy = ""
+
+	// CSATGroupAgent theo agent phụ trách
+	CSATGroupAgent CSATGroupBy = "agent"
+
+	// CSATGroupChannelAccount theo kênh
+	CSATGroupChannelAccount CSATGroupBy = "channel_account"
+
+	// CSATGroupDay, CSATGroupWeek, CSATGroupMonth theo kỳ (tính theo timezone workspace)
+	CSATGroupDay   CSATGroupBy = "day"
+	CSATGroupWeek  CSATGroupBy = "week"
+	CSATGroupMonth CSATGroupBy = "month"
+)
+
+// CSATReportQuery điều kiện báo cáo CSAT
+type CSATReportQuery struct {
+	WorkspaceID      uuid.UUID
+	From             time.Time
+	To               time.Time
+	AgentID          *uuid.UUID
+	ChannelAccountID *uuid.UUID
+	GroupBy          CSATGroupBy
+
+	// Timezone dùng khi gom nhóm theo kỳ (VD: "Asia/Ho_Chi_Minh")
+	Timezone string
+}
+
+// CSATReportRow một dòng báo cáo
+type CSATReportRow struct {
+	// Key giá trị nhóm: agent ID, channel account ID hoặc kỳ ("2024-05-01")
+	// Rỗng với báo cáo tổng hoặc conversation không có agent
+	Key string `json:"key"`
+
+	// Sent số khảo sát đã gửi
+	Sent int64 `json:"sent"`
+
+	// Responded số khảo sát khách đã chấm điểm
+	Responded int64 `json:"responded"`
+
+	// Satisfied số câu trả lời 4-5 điểm
+	Satisfied int64 `json:"satisfied"`
+
+	// AverageScore điểm trung bình
+	AverageScore float64 `json:"average_score"`
+}
+
+// CSATRepository interface cho CSAT data access
+type CSATRepository interface {
+	// Create tạo khảo sát mới
+	Create(ctx context.Context, response *models.CSATResponse) error
+
+	// Update cập nhật khảo sát
+	Update(ctx context.Context, response *models.CSATResponse) error
+
+	// FindByConversation lấy khảo sát của conversation
+	FindByConversation(ctx context.Context, conversationID uuid.UUID) (*models.CSATResponse, error)
+
+	// FindOpenByParticipant lấy khảo sát gần nhất còn chờ participant trả lời
+	FindOpenByParticipant(ctx context.Context, participantID uuid.UUID, now time.Time) (*models.CSATResponse, error)
+
+	// FindByWorkspace lấy danh sách khảo sát (mới nhất trước)
+	// Filters: agent_id, channel_account_id, from, to, score
+	FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, opts FindOptions) ([]models.CSATResponse, int64, error)
+
+	// Report tổng hợp số liệu CSAT theo chiều gom nhóm
+	Report(ctx context.Context, query CSATReportQuery) ([]CSATReportRow, error)
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// CSAT Repository GORM Implementation
+// ===========================================================================
+
+// csatRepo triển khai CSATRepository với GORM
+type csatRepo struct {
+	db *gorm.DB
+}
+
+// NewCSATRepository tạo instance mới của CSATRepository
+func NewCSATRepository(db *gorm.DB) CSATRepository {
+	return &csatRepo{db: db}
+}
+
+// Create tạo khảo sát mới
+func (r *csatRepo) Create(ctx context.Context, response *models.CSATResponse) error {
+	return r.db.WithContext(ctx).Create(response).Error
+}
+
+// Update cập nhật khảo sát
+func (r *csatRepo) Update(ctx context.Context, response *models.CSATResponse) error {
+	return r.db.WithContext(ctx).Save(response).Error
+}
+
+// FindByConversation lấy khảo sát của conversation
+func (r *csatRepo) FindByConversation(ctx context.Context, conversationID uuid.UUID) (*models.CSATResponse, error) {
+	var response models.CSATResponse
+	err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		First(&response).Error
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// FindOpenByParticipant lấy khảo sát gần nhất còn chờ participant trả lời
+func (r *csatRepo) FindOpenByParticipant(ctx context.Context, participantID uuid.UUID, now time.Time) (*models.CSATResponse, error) {
+	var response models.CSATResponse
+	err := r.db.WithContext(ctx).
+		Where("participant_id = ? AND expires_at > ?", participantID, now).
+		Where("status IN ?", []models.CSATStatus{models.CSATPending, models.CSATAwaitingComment}).
+		Order("sent_at DESC").
+		First(&response).Error
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// FindByWorkspace lấy danh sách khảo sát
+func (r *csatRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, opts FindOptions) ([]models.CSATResponse, int64, error) {
+	opts.SetDefaults()
+
+	var responses []models.CSATResponse
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Model(&models.CSATResponse{}).
+		Where("workspace_id = ?", workspaceID)
+
+	if opts.Filters != nil {
+		if agentID, ok := opts.Filters["agent_id"]; ok {
+			query = query.Where("agent_id = ?", agentID)
+		}
+		if channelAccountID, ok := opts.Filters["channel_account_id"]; ok {
+			query = query.Where("channel_account_id = ?", channelAccountID)
+		}
+		if from, ok := opts.Filters["from"]; ok {
+			query = query.Where("sent_at >= ?", from)
+		}
+		if to, ok := opts.Filters["to"]; ok {
+			query = query.Where("sent_at < ?", to)
+		}
+		if score, ok := opts.Filters["score"]; ok {
+			query = query.Where("score = ?", score)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Agent").
+		Order("sent_at DESC").
+		Offset(opts.Offset).
+		Limit(opts.Limit).
+		Find(&responses).Error
+
+	return responses, total, err
+}
+
+// Report tổng hợp số liệu CSAT
+func (r *csatRepo) Report(ctx context.Context, q CSATReportQuery) ([]CSATReportRow, error) {
+	var rows []CSATReportRow
+
+	query := r.db.WithContext(ctx).
+		Model(&models.CSATResponse{}).
+		Where("workspace_id = ? AND sent_at >= ? AND sent_at < ?", q.WorkspaceID, q.From, q.To)
+	if q.AgentID != nil {
+		query = query.Where("agent_id = ?", *q.AgentID)
+	}
+	if q.ChannelAccountID != nil {
+		query = query.Where("channel_account_id = ?", *q.ChannelAccountID)
+	}
+
+	const metrics = "COUNT(*) AS sent, COUNT(score) AS responded, " +
+		"COUNT(*) FILTER (WHERE score >= 4) AS satisfied, COALESCE(AVG(score), 0) AS average_score"
+
+	switch q.GroupBy {
+	case CSATGroupAgent:
+		query = query.Select("COALESCE(agent_id::text, '') AS key, " + metrics)
+	case CSATGroupChannelAccount:
+		query = query.Select("channel_account_id::text AS key, " + metrics)
+	case CSATGroupDay, CSATGroupWeek, CSATGroupMonth:
+		query = query.Select("to_char(date_trunc(?, sent_at AT TIME ZONE ?), 'YYYY-MM-DD') AS key, "+metrics, string(q.GroupBy), q.Timezone)
+	default:
+		query = query.Select("'' AS key, " + metrics)
+	}
+	if q.GroupBy != CSATGroupNone {
+		// Gom nhóm theo vị trí cột để dùng lại biểu thức có tham số trong SELECT
+		query = query.Group("1").Order("1")
+	}
+
+	err := query.Scan(&rows).Error
+	return rows, err
+}
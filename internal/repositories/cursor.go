@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Cursor (Keyset Pagination)
+// Vị trí trong danh sách sắp xếp theo (thời gian, id)
+// Không bị lệch trang khi có bản ghi mới chèn vào như phân trang offset
+// ===========================================================================
+
+// ErrInvalidCursor cursor không giải mã được
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor vị trí (thời gian, id) của một bản ghi
+type Cursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// NewCursor tạo cursor, làm tròn về micro giây giống độ chính xác timestamp của Postgres
+func NewCursor(t time.Time, id uuid.UUID) Cursor {
+	return Cursor{Time: t.Round(time.Microsecond), ID: id}
+}
+
+// Encode mã hóa cursor thành chuỗi opaque cho client
+func (c Cursor) Encode() string {
+	raw := c.Time.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor giải mã cursor từ client
+func ParseCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	timePart, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, timePart)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{Time: t, ID: id}, nil
+}
+
+// CursorQuery điều kiện keyset pagination
+// Chỉ dùng một trong Before / After; không có cả hai = trang mới nhất
+type CursorQuery struct {
+	// Before lấy các bản ghi cũ hơn cursor
+	Before *Cursor
+
+	// After lấy các bản ghi mới hơn cursor
+	After *Cursor
+
+	// Limit số bản ghi tối đa
+	Limit int
+}
+
+// apply thêm điều kiện keyset và thứ tự vào query
+// Kết quả theo thứ tự gần cursor nhất trước: DESC khi đi lùi, ASC khi đi tới
+// Lấy dư một bản ghi để biết còn trang tiếp theo không
+func (q CursorQuery) apply(db *gorm.DB, timeColumn, idColumn string) *gorm.DB {
+	if q.After != nil {
+		return db.
+			Where("("+timeColumn+", "+idColumn+") > (?, ?)", q.After.Time, q.After.ID).
+			Order(timeColumn + " ASC, " + idColumn + " ASC").
+			Limit(q.Limit + 1)
+	}
+	if q.Before != nil {
+		db = db.Where("("+timeColumn+", "+idColumn+") < (?, ?)", q.Before.Time, q.Before.ID)
+	}
+	return db.
+		Order(timeColumn + " DESC, " + idColumn + " DESC").
+		Limit(q.Limit + 1)
+}
+
+// trimPage cắt bản ghi dư và đưa kết quả về thứ tự mới nhất trước
+// Trả về còn bản ghi theo hướng đang đi (cũ hơn, hoặc mới hơn với After)
+func trimPage[T any](items []T, q CursorQuery) ([]T, bool) {
+	hasMore := len(items) > q.Limit
+	if hasMore {
+		items = items[:q.Limit]
+	}
+	if q.After != nil {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	return items, hasMore
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Custom Field Repository Interface
+// Định nghĩa trường tùy chỉnh của khách theo workspace
+// ===========================================================================
+
+// CustomFieldRepository interface cho custom field definition data access
+type CustomFieldRepository interface {
+	// Create tạo định nghĩa trường
+	Create(ctx context.Context, field *models.CustomFieldDefinition) error
+
+	// Update cập nhật định nghĩa trường
+	Update(ctx context.Context, field *models.CustomFieldDefinition) error
+
+	// Delete soft delete định nghĩa trường (giá trị đã lưu của khách được giữ nguyên)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// FindByID tìm định nghĩa trường theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.CustomFieldDefinition, error)
+
+	// FindByWorkspace lấy mọi định nghĩa trường của workspace theo thứ tự hiển thị
+	FindByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]models.CustomFieldDefinition, error)
+
+	// FindByKey tìm định nghĩa trường theo key trong workspace
+	FindByKey(ctx context.Context, workspaceID uuid.UUID, key string) (*models.CustomFieldDefinition, error)
+}
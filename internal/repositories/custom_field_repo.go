@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Custom Field Repository GORM Implementation
+// ===========================================================================
+
+// customFieldRepo triển khai CustomFieldRepository với GORM
+type customFieldRepo struct {
+	db *gorm.DB
+}
+
+// NewCustomFieldRepository tạo instance mới của CustomFieldRepository
+func NewCustomFieldRepository(db *gorm.DB) CustomFieldRepository {
+	return &customFieldRepo{db: db}
+}
+
+// Create tạo định nghĩa trường
+func (r *customFieldRepo) Create(ctx context.Context, field *models.CustomFieldDefinition) error {
+	return r.db.WithContext(ctx).Create(field).Error
+}
+
+// Update cập nhật định nghĩa trường
+func (r *customFieldRepo) Update(ctx context.Context, field *models.CustomFieldDefinition) error {
+	return r.db.WithContext(ctx).Save(field).Error
+}
+
+// Delete soft delete định nghĩa trường
+func (r *customFieldRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.CustomFieldDefinition{}, id).Error
+}
+
+// FindByID tìm định nghĩa trường theo ID
+func (r *customFieldRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.CustomFieldDefinition, error) {
+	var field models.CustomFieldDefinition
+	if err := r.db.WithContext(ctx).First(&field, id).Error; err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// FindByWorkspace lấy mọi định nghĩa trường của workspace
+func (r *customFieldRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]models.CustomFieldDefinition, error) {
+	var fields []models.CustomFieldDefinition
+	err := r.db.WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("position ASC, created_at ASC").
+		Find(&fields).Error
+	return fields, err
+}
+
+// FindByKey tìm định nghĩa trường theo key
+func (r *customFieldRepo) FindByKey(ctx context.Context, workspaceID uuid.UUID, key string) (*models.CustomFieldDefinition, error) {
+	var field models.CustomFieldDefinition
+	if err := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND key = ?", workspaceID, key).
+		First(&field).Error; err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// ===========================================================================
+// Lọc và sắp xếp theo trường tùy chỉnh
+// Dùng chung cho danh sách khách và danh sách hội thoại
+// ===========================================================================
+
+// applyFieldConditions thêm điều kiện trên metadata.custom_fields của khách
+// metadataColumn là cột metadata của participant (VD: "participants.metadata")
+// Giá trị điều kiện phải được chuẩn hóa theo kiểu trường trước (CustomFieldDefinition.ParseCondition)
+func applyFieldConditions(query *gorm.DB, metadataColumn string, conditions []models.FieldCondition) *gorm.DB {
+	value := metadataColumn + "->'custom_fields'->?"
+	present := "(" + value + " IS NOT NULL AND " + value + " NOT IN ('null'::jsonb, '\"\"'::jsonb))"
+
+	for _, cond := range conditions {
+		switch cond.Operator {
+		case models.OpExists:
+			query = query.Where(present, cond.Field, cond.Field, cond.Field)
+		case models.OpNotExists:
+			query = query.Where("NOT "+present, cond.Field, cond.Field, cond.Field)
+		case models.OpContains:
+			query = query.Where(metadataColumn+"->'custom_fields'->>? ILIKE ?",
+				cond.Field, "%"+escapeLike(models.FormatCustomFieldValue(cond.Value))+"%")
+		case models.OpIn:
+			query = query.Where("CAST(? AS jsonb) @> "+value+" AND "+value+" IS NOT NULL",
+				jsonValue(cond.Value), cond.Field, cond.Field)
+		case models.OpNotEquals:
+			query = query.Where("("+value+" IS NULL OR "+value+" <> CAST(? AS jsonb))",
+				cond.Field, cond.Field, jsonValue(cond.Value))
+		case models.OpGreater, models.OpGreaterOrEqual, models.OpLess, models.OpLessOrEqual:
+			// Chỉ so sánh cùng kiểu JSON (số với số, ngày với ngày)
+			query = query.Where("jsonb_typeof("+value+") = jsonb_typeof(CAST(? AS jsonb)) AND "+value+" "+comparisonSQL[cond.Operator]+" CAST(? AS jsonb)",
+				cond.Field, jsonValue(cond.Value), cond.Field, jsonValue(cond.Value))
+		default:
+			query = query.Where(value+" = CAST(? AS jsonb)", cond.Field, jsonValue(cond.Value))
+		}
+	}
+	return query
+}
+
+// comparisonSQL toán tử SQL tương ứng
+var comparisonSQL = map[models.FieldOperator]string{
+	models.OpGreater:        ">",
+	models.OpGreaterOrEqual: ">=",
+	models.OpLess:           "<",
+	models.OpLessOrEqual:    "<=",
+}
+
+// FieldSortPrefix tiền tố của FindOptions.OrderBy khi sắp xếp theo trường tùy chỉnh (VD: "field.plan")
+const FieldSortPrefix = "field."
+
+// fieldValueSQL biểu thức giá trị trường tùy chỉnh trong metadata, rỗng nếu key không hợp lệ
+// key phải khớp CustomFieldKeyPattern (được ghép trực tiếp vào SQL)
+func fieldValueSQL(metadataColumn, key string) string {
+	if !models.CustomFieldKeyPattern.MatchString(key) {
+		return ""
+	}
+	return metadataColumn + "->'custom_fields'->'" + key + "'"
+}
+
+// fieldOrderClause sắp xếp theo biểu thức giá trị trường, bản ghi chưa có giá trị xếp cuối
+func fieldOrderClause(expr string, desc bool) string {
+	if desc {
+		return expr + " DESC NULLS LAST"
+	}
+	return expr + " ASC NULLS LAST"
+}
+
+// jsonValue giá trị điều kiện dạng JSON để so sánh với jsonb
+func jsonValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
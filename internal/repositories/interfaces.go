@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"chatbox-gin/internal/models"
 
@@ -21,6 +22,9 @@ type WorkspaceRepository interface {
 	// FindBySlug tìm workspace theo slug
 	FindBySlug(ctx context.Context, slug string) (*models.Workspace, error)
 
+	// FindActive lấy tất cả workspaces đang hoạt động (cho background jobs)
+	FindActive(ctx context.Context) ([]models.Workspace, error)
+
 	// Create tạo workspace mới
 	Create(ctx context.Context, workspace *models.Workspace) error
 
@@ -44,11 +48,22 @@ type UserRepository interface {
 	// FindByWorkspace lấy danh sách users trong workspace
 	FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, opts FindOptions) ([]models.User, int64, error)
 
+	// FindByIDs lấy danh sách users active theo IDs trong workspace
+	FindByIDs(ctx context.Context, workspaceID uuid.UUID, ids []uuid.UUID) ([]models.User, error)
+
+	// FindByStatusSeenBefore lấy users đang ở các trạng thái cho trước
+	// nhưng không hoạt động kể từ thời điểm before (dùng cho auto-away)
+	FindByStatusSeenBefore(ctx context.Context, statuses []models.AgentStatus, before time.Time) ([]models.User, error)
+
 	// Create tạo user mới
 	Create(ctx context.Context, user *models.User) error
 
 	// Update cập nhật user
 	Update(ctx context.Context, user *models.User) error
+
+	// UpdatePresence chỉ cập nhật các trường presence (status, last_seen_at, ...)
+	// để không ghi đè thay đổi khác của user
+	UpdatePresence(ctx context.Context, user *models.User) error
 }
 
 // ===========================================================================
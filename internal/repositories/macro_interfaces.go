@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Macro Repository Interface
+// Quản lý macro và ghi kết quả chạy macro trong một transaction
+// ===========================================================================
+
+// MacroApplication các thay đổi khi chạy macro trên một conversation
+// Được ghi trong cùng một transaction: lỗi ở bất kỳ bước nào thì không có gì thay đổi
+type MacroApplication struct {
+	// MacroID macro được chạy (tăng usage_count)
+	MacroID uuid.UUID
+
+	// Conversation conversation đã được cập nhật (status, priority, assign, ...)
+	Conversation *models.Conversation
+
+	// Message tin nhắn trả lời cần tạo (nil nếu macro không gửi tin)
+	Message *models.Message
+
+	// AddTags tag cần gắn, tag chưa có ID (workspace chưa có tag này) được tạo trong transaction
+	AddTags []*models.Tag
+
+	// RemoveTagIDs tag cần gỡ, TaggedBy người gắn
+	RemoveTagIDs []uuid.UUID
+	TaggedBy     *uuid.UUID
+
+	// RoutingLog quyết định chọn agent khi assign cho team (nil nếu không có)
+	RoutingLog *models.RoutingLog
+
+	// Audit bản ghi nhật ký của lần chạy
+	Audit *models.AuditLog
+}
+
+// MacroRepository interface cho macro data access
+type MacroRepository interface {
+	// Create tạo macro mới
+	Create(ctx context.Context, macro *models.Macro) error
+
+	// Update cập nhật macro
+	Update(ctx context.Context, macro *models.Macro) error
+
+	// Delete soft delete macro
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// FindByID tìm macro theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Macro, error)
+
+	// FindByWorkspace lấy danh sách macro của workspace theo tên
+	FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, activeOnly bool) ([]models.Macro, error)
+
+	// Apply ghi toàn bộ thay đổi của một lần chạy macro trong một transaction
+	Apply(ctx context.Context, app *MacroApplication) error
+}
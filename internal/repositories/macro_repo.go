@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Macro Repository GORM Implementation
+// ===========================================================================
+
+// macroRepo triển khai MacroRepository với GORM
+type macroRepo struct {
+	db *gorm.DB
+}
+
+// NewMacroRepository tạo instance mới của MacroRepository
+func NewMacroRepository(db *gorm.DB) MacroRepository {
+	return &macroRepo{db: db}
+}
+
+// Create tạo macro mới
+func (r *macroRepo) Create(ctx context.Context, macro *models.Macro) error {
+	return r.db.WithContext(ctx).Create(macro).Error
+}
+
+// Update cập nhật macro
+func (r *macroRepo) Update(ctx context.Context, macro *models.Macro) error {
+	return r.db.WithContext(ctx).Save(macro).Error
+}
+
+// Delete soft delete macro
+func (r *macroRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Macro{}, id).Error
+}
+
+// FindByID tìm macro theo ID
+func (r *macroRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Macro, error) {
+	var macro models.Macro
+	if err := r.db.WithContext(ctx).First(&macro, id).Error; err != nil {
+		return nil, err
+	}
+	return &macro, nil
+}
+
+// FindByWorkspace lấy danh sách macro của workspace theo tên
+func (r *macroRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, activeOnly bool) ([]models.Macro, error) {
+	query := r.db.WithContext(ctx).Where("workspace_id = ?", workspaceID)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var macros []models.Macro
+	err := query.Order("name ASC").Find(&macros).Error
+	return macros, err
+}
+
+// Apply ghi toàn bộ thay đổi của một lần chạy macro trong một transaction
+func (r *macroRepo) Apply(ctx context.Context, app *MacroApplication) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if app.Message != nil {
+			if err := tx.Create(app.Message).Error; err != nil {
+				return fmt.Errorf("create message: %w", err)
+			}
+		}
+
+		if err := updateMacroState(tx, app.Conversation); err != nil {
+			return fmt.Errorf("update conversation: %w", err)
+		}
+
+		for _, tag := range app.AddTags {
+			if tag.ID == uuid.Nil {
+				if err := tx.Create(tag).Error; err != nil {
+					return fmt.Errorf("create tag: %w", err)
+				}
+			}
+			if err := addConversationTag(tx, app.Conversation.ID, tag.ID, app.TaggedBy); err != nil {
+				return fmt.Errorf("add tag: %w", err)
+			}
+		}
+		for _, tagID := range app.RemoveTagIDs {
+			if err := removeConversationTag(tx, app.Conversation.ID, tagID); err != nil {
+				return fmt.Errorf("remove tag: %w", err)
+			}
+		}
+
+		if app.RoutingLog != nil {
+			if err := tx.Create(app.RoutingLog).Error; err != nil {
+				return fmt.Errorf("create routing log: %w", err)
+			}
+		}
+
+		if app.Audit != nil {
+			if err := tx.Create(app.Audit).Error; err != nil {
+				return fmt.Errorf("create audit log: %w", err)
+			}
+		}
+
+		return tx.Model(&models.Macro{}).
+			Where("id = ?", app.MacroID).
+			UpdateColumns(map[string]interface{}{
+				"usage_count":  gorm.Expr("usage_count + 1"),
+				"last_used_at": time.Now(),
+			}).Error
+	})
+}
+
+// updateMacroState chỉ ghi các cột mà thao tác của macro thay đổi (status, assign, priority,
+// resolved_at, tạm ẩn) và hai key closed_reason, bot_handoff_reason trong metadata
+// Tin nhắn cuối, last_inbound_at và các key SLA do luồng khác ghi song song được giữ nguyên
+func updateMacroState(tx *gorm.DB, conv *models.Conversation) error {
+	data, err := json.Marshal(models.ConversationMetadata{
+		ClosedReason:     conv.Metadata.ClosedReason,
+		BotHandoffReason: conv.Metadata.BotHandoffReason,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Model(conv).
+		Updates(map[string]interface{}{
+			"status":              conv.Status,
+			"assigned_to":         conv.AssignedTo,
+			"priority":            conv.Priority,
+			"resolved_at":         conv.ResolvedAt,
+			"snoozed_until":       conv.SnoozedUntil,
+			"snoozed_at":          conv.SnoozedAt,
+			"snoozed_from_status": conv.SnoozedFromStatus,
+			"metadata": gorm.Expr(
+				"(COALESCE(metadata, '{}'::jsonb) - 'closed_reason' - 'bot_handoff_reason') || ?::jsonb",
+				string(data),
+			),
+		}).Error
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Media Repository Interface
+// Quản lý file đính kèm đã lưu vào storage
+// ===========================================================================
+
+// MediaRepository interface cho media data access
+type MediaRepository interface {
+	// Create tạo bản ghi media mới
+	Create(ctx context.Context, media *models.Media) error
+
+	// Update cập nhật media
+	Update(ctx context.Context, media *models.Media) error
+
+	// Delete xóa media
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// FindByID tìm media theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Media, error)
+
+	// FindRetryable lấy media chưa tải xong, còn lượt thử và lần thử gần nhất trước before
+	// Media chưa thử lần nào luôn được lấy (VD: server tắt khi còn trong hàng đợi)
+	FindRetryable(ctx context.Context, maxAttempts int, before time.Time, limit int) ([]models.Media, error)
+}
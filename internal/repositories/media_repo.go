@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Media Repository GORM Implementation
+// ===========================================================================
+
+// mediaRepo triển khai MediaRepository với GORM
+type mediaRepo struct {
+	db *gorm.DB
+}
+
+// NewMediaRepository tạo instance mới của MediaRepository
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepo{db: db}
+}
+
+// Create tạo bản ghi media mới
+func (r *mediaRepo) Create(ctx context.Context, media *models.Media) error {
+	return r.db.WithContext(ctx).Create(media).Error
+}
+
+// Update cập nhật media
+func (r *mediaRepo) Update(ctx context.Context, media *models.Media) error {
+	return r.db.WithContext(ctx).Save(media).Error
+}
+
+// Delete xóa media
+func (r *mediaRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Media{}, id).Error
+}
+
+// FindByID tìm media theo ID
+func (r *mediaRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Media, error) {
+	var media models.Media
+	if err := r.db.WithContext(ctx).First(&media, id).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// FindRetryable lấy media cần tải lại, cũ nhất trước
+func (r *mediaRepo) FindRetryable(ctx context.Context, maxAttempts int, before time.Time, limit int) ([]models.Media, error) {
+	var media []models.Media
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND attempts < ?", models.MediaPending, maxAttempts).
+		Where("last_attempt_at IS NULL OR last_attempt_at < ?", before).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&media).Error
+	return media, err
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"chatbox-gin/internal/models"
@@ -64,6 +66,27 @@ func (r *messageRepo) FindByConversation(ctx context.Context, conversationID uui
 	return messages, total, err
 }
 
+// FindPageByConversation lấy messages theo cursor, mới nhất trước
+func (r *messageRepo) FindPageByConversation(ctx context.Context, conversationID uuid.UUID, q CursorQuery) ([]models.Message, bool, error) {
+	var messages []models.Message
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Where("conversation_id = ?", conversationID)
+
+	if err := q.apply(query, "created_at", "id").Find(&messages).Error; err != nil {
+		return nil, false, err
+	}
+
+	messages, hasMore := trimPage(messages, q)
+	return messages, hasMore, nil
+}
+
+// MessageCursor cursor của message theo thời điểm tạo
+func MessageCursor(msg *models.Message) Cursor {
+	return NewCursor(msg.CreatedAt, msg.ID)
+}
+
 // FindByChannelMessageID tìm message theo channel message ID
 func (r *messageRepo) FindByChannelMessageID(ctx context.Context, channelMessageID string) (*models.Message, error) {
 	var msg models.Message
@@ -97,3 +120,115 @@ func (r *messageRepo) MarkAsRead(ctx context.Context, id uuid.UUID) error {
 			"read_at": now,
 		}).Error
 }
+
+// unreadCondition điều kiện tin nhắn của khách chưa được agent đọc
+const unreadCondition = "messages.direction = ? AND messages.is_read = ?"
+
+// MarkReadUpTo đánh dấu đã đọc các tin nhắn của khách đến hết cursor upTo
+func (r *messageRepo) MarkReadUpTo(ctx context.Context, conversationID uuid.UUID, upTo *Cursor) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Where("conversation_id = ?", conversationID).
+		Where(unreadCondition, models.DirectionIn, false)
+	if upTo != nil {
+		query = query.Where("(created_at, id) <= (?, ?)", upTo.Time, upTo.ID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
+
+// CountUnreadByConversations đếm tin nhắn chưa đọc theo từng conversation
+func (r *messageRepo) CountUnreadByConversations(ctx context.Context, conversationIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ConversationID uuid.UUID
+		Total          int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Select("conversation_id, COUNT(*) AS total").
+		Where("conversation_id IN ?", conversationIDs).
+		Where(unreadCondition, models.DirectionIn, false).
+		Group("conversation_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ConversationID] = row.Total
+	}
+	return counts, nil
+}
+
+// CountUnread đếm tin nhắn chưa đọc trong các conversation chưa đóng của workspace
+func (r *messageRepo) CountUnread(ctx context.Context, workspaceID uuid.UUID, assignedTo *uuid.UUID) (UnreadCount, error) {
+	var count UnreadCount
+	query := r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Select("COUNT(*) AS messages, COUNT(DISTINCT messages.conversation_id) AS conversations").
+		Joins("JOIN conversations ON conversations.id = messages.conversation_id").
+		Where("conversations.workspace_id = ? AND conversations.status <> ?", workspaceID, models.StatusClosed).
+		Where(unreadCondition, models.DirectionIn, false)
+	if assignedTo != nil {
+		query = query.Where("conversations.assigned_to = ?", *assignedTo)
+	}
+	err := query.Scan(&count).Error
+	return count, err
+}
+
+// MarkReceipt ghi mốc delivered_at/seen_at vào metadata các tin nhắn gửi cho participant
+// Bỏ qua tin nhắn gửi thất bại (watermark có thể phủ cả tin channel đã từ chối)
+func (r *messageRepo) MarkReceipt(ctx context.Context, receipt ReceiptUpdate) (int64, error) {
+	if len(receipt.ChannelMessageIDs) == 0 && receipt.Watermark == nil {
+		return 0, nil
+	}
+
+	field := string(receipt.Field)
+	query := r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Where("conversation_id IN (?)", r.db.Model(&models.Conversation{}).
+			Select("id").
+			Where("participant_id = ?", receipt.ParticipantID)).
+		Where("direction = ?", models.DirectionOut).
+		Where("COALESCE(metadata, '{}'::jsonb) ->> ? IS NULL", field).
+		Where("COALESCE(metadata, '{}'::jsonb) ->> 'failed_at' IS NULL")
+
+	switch {
+	case len(receipt.ChannelMessageIDs) > 0 && receipt.Watermark != nil:
+		query = query.Where("(channel_message_id IN ? OR created_at <= ?)", receipt.ChannelMessageIDs, *receipt.Watermark)
+	case len(receipt.ChannelMessageIDs) > 0:
+		query = query.Where("channel_message_id IN ?", receipt.ChannelMessageIDs)
+	default:
+		query = query.Where("created_at <= ?", *receipt.Watermark)
+	}
+
+	result := query.Update("metadata", gorm.Expr(
+		"COALESCE(metadata, '{}'::jsonb) || jsonb_build_object(?::text, ?::text)",
+		field, receipt.At.Format(time.RFC3339Nano),
+	))
+	return result.RowsAffected, result.Error
+}
+
+// PatchAttachment merge patch vào phần tử index của cột attachments (jsonb)
+func (r *messageRepo) PatchAttachment(ctx context.Context, messageID uuid.UUID, index int, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.Message{}).
+		Where("id = ? AND jsonb_array_length(attachments) > ?", messageID, index).
+		Update("attachments", gorm.Expr(
+			"jsonb_set(attachments, ?::text[], (attachments -> ?::int) || ?::jsonb)",
+			fmt.Sprintf("{%d}", index), index, string(data),
+		)).Error
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Note Repository GORM Implementation
+// ===========================================================================
+
+// noteRepo triển khai NoteRepository với GORM
+type noteRepo struct {
+	db *gorm.DB
+}
+
+// NewNoteRepository tạo instance mới của NoteRepository
+func NewNoteRepository(db *gorm.DB) NoteRepository {
+	return &noteRepo{db: db}
+}
+
+// FindByID tìm note theo ID (kèm thông tin người tạo)
+func (r *noteRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Note, error) {
+	var note models.Note
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		First(&note, id).Error; err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// FindByConversation lấy danh sách notes của conversation
+// Note được ghim lên đầu, còn lại theo thứ tự thời gian
+func (r *noteRepo) FindByConversation(ctx context.Context, conversationID uuid.UUID) ([]models.Note, error) {
+	var notes []models.Note
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("conversation_id = ?", conversationID).
+		Order("is_pinned DESC, created_at ASC").
+		Find(&notes).Error
+	return notes, err
+}
+
+// Create tạo note mới
+func (r *noteRepo) Create(ctx context.Context, note *models.Note) error {
+	return r.db.WithContext(ctx).Create(note).Error
+}
+
+// Update cập nhật note
+func (r *noteRepo) Update(ctx context.Context, note *models.Note) error {
+	return r.db.WithContext(ctx).Omit("User", "Conversation").Save(note).Error
+}
+
+// Delete soft delete note
+func (r *noteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Note{}, id).Error
+}
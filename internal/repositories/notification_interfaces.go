@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Notification Repository Interface
+// Quản lý thông báo gửi đến từng user
+// ===========================================================================
+
+// NotificationRepository interface cho notification data access
+type NotificationRepository interface {
+	// FindByID tìm notification theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Notification, error)
+
+	// FindByUser lấy danh sách notifications của user (mới nhất trước)
+	// unreadOnly = true chỉ lấy thông báo chưa đọc
+	FindByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, opts FindOptions) ([]models.Notification, int64, error)
+
+	// CountUnread đếm số thông báo chưa đọc của user
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// CreateBatch tạo nhiều notifications cùng lúc
+	CreateBatch(ctx context.Context, notifications []models.Notification) error
+
+	// MarkAsRead đánh dấu một notification đã đọc
+	MarkAsRead(ctx context.Context, id uuid.UUID) error
+
+	// MarkAllAsRead đánh dấu tất cả notifications của user đã đọc
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
+}
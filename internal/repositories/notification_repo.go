@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Notification Repository GORM Implementation
+// ===========================================================================
+
+// notificationRepo triển khai NotificationRepository với GORM
+type notificationRepo struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository tạo instance mới của NotificationRepository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+// FindByID tìm notification theo ID
+func (r *notificationRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
+	var notification models.Notification
+	if err := r.db.WithContext(ctx).First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// FindByUser lấy danh sách notifications của user
+func (r *notificationRepo) FindByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, opts FindOptions) ([]models.Notification, int64, error) {
+	opts.SetDefaults()
+
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ?", userID)
+
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order(opts.GetOrderClause()).
+		Offset(opts.Offset).
+		Limit(opts.Limit).
+		Find(&notifications).Error
+
+	return notifications, total, err
+}
+
+// CountUnread đếm số thông báo chưa đọc của user
+func (r *notificationRepo) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateBatch tạo nhiều notifications cùng lúc
+func (r *notificationRepo) CreateBatch(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&notifications).Error
+}
+
+// MarkAsRead đánh dấu một notification đã đọc
+func (r *notificationRepo) MarkAsRead(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? AND read_at IS NULL", id).
+		Update("read_at", time.Now()).Error
+}
+
+// MarkAllAsRead đánh dấu tất cả notifications của user đã đọc
+func (r *notificationRepo) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
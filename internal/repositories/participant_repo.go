@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"chatbox-gin/internal/models"
 
@@ -64,3 +65,23 @@ func (r *participantRepo) FindOrCreate(ctx context.Context, participant *models.
 func (r *participantRepo) Update(ctx context.Context, participant *models.Participant) error {
 	return r.db.WithContext(ctx).Save(participant).Error
 }
+
+// RecordInbound ghi nhận lần cuối khách nhắn tin
+// Điều kiện so sánh giữ mốc muộn nhất khi webhook đến không theo thứ tự
+func (r *participantRepo) RecordInbound(ctx context.Context, participantID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Participant{}).
+		Where("id = ? AND (last_inbound_at IS NULL OR last_inbound_at < ?)", participantID, at).
+		Updates(map[string]interface{}{
+			"last_inbound_at": at,
+			"last_seen_at":    at,
+		}).Error
+}
+
+// UpdateMetadata chỉ cập nhật metadata của participant
+// Không dùng Save để tránh ghi đè last_inbound_at vừa được RecordInbound cập nhật
+func (r *participantRepo) UpdateMetadata(ctx context.Context, participant *models.Participant) error {
+	return r.db.WithContext(ctx).
+		Model(participant).
+		Update("metadata", participant.Metadata).Error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// RoutingLog Repository Interface
+// Lưu vết các quyết định phân công agent
+// ===========================================================================
+
+// RoutingLogRepository interface cho routing log data access
+type RoutingLogRepository interface {
+	// Create ghi một quyết định routing
+	Create(ctx context.Context, log *models.RoutingLog) error
+
+	// FindByConversation lấy lịch sử routing của conversation (mới nhất trước)
+	FindByConversation(ctx context.Context, conversationID uuid.UUID) ([]models.RoutingLog, error)
+
+	// FindLastAssigned lấy lần routing gần nhất có chọn được agent trong workspace
+	// Dùng làm con trỏ cho chiến lược round-robin
+	FindLastAssigned(ctx context.Context, workspaceID uuid.UUID) (*models.RoutingLog, error)
+}
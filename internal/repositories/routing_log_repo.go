@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// RoutingLog Repository GORM Implementation
+// ===========================================================================
+
+// routingLogRepo triển khai RoutingLogRepository với GORM
+type routingLogRepo struct {
+	db *gorm.DB
+}
+
+// NewRoutingLogRepository tạo instance mới của RoutingLogRepository
+func NewRoutingLogRepository(db *gorm.DB) RoutingLogRepository {
+	return &routingLogRepo{db: db}
+}
+
+// Create ghi một quyết định routing
+func (r *routingLogRepo) Create(ctx context.Context, log *models.RoutingLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// FindByConversation lấy lịch sử routing của conversation
+func (r *routingLogRepo) FindByConversation(ctx context.Context, conversationID uuid.UUID) ([]models.RoutingLog, error) {
+	var logs []models.RoutingLog
+	err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
+// FindLastAssigned lấy lần routing gần nhất có chọn được agent
+func (r *routingLogRepo) FindLastAssigned(ctx context.Context, workspaceID uuid.UUID) (*models.RoutingLog, error) {
+	var log models.RoutingLog
+	err := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND assigned_to IS NOT NULL", workspaceID).
+		Order("created_at DESC").
+		First(&log).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
@@ -110,7 +110,11 @@ type WebhookEventRepository interface {
 
 // NoteRepository interface cho note data access
 type NoteRepository interface {
+	// FindByID tìm note theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Note, error)
+
 	// FindByConversation lấy danh sách notes của conversation
+	// Note được ghim lên đầu, còn lại theo thứ tự thời gian
 	FindByConversation(ctx context.Context, conversationID uuid.UUID) ([]models.Note, error)
 
 	// Create tạo note mới
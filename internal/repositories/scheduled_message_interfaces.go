@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Scheduled Message Repository Interface
+// Lưu tin nhắn hẹn giờ và trạng thái gửi
+// ===========================================================================
+
+// ScheduledMessageRepository interface cho scheduled message data access
+type ScheduledMessageRepository interface {
+	// Create tạo tin nhắn hẹn giờ
+	Create(ctx context.Context, msg *models.ScheduledMessage) error
+
+	// Update lưu trạng thái/kết quả gửi
+	Update(ctx context.Context, msg *models.ScheduledMessage) error
+
+	// FindByID tìm tin nhắn hẹn giờ theo ID
+	FindByID(ctx context.Context, id uuid.UUID) (*models.ScheduledMessage, error)
+
+	// FindByConversation lấy tin nhắn hẹn giờ của hội thoại theo giờ gửi
+	// statuses rỗng = mọi trạng thái
+	FindByConversation(ctx context.Context, conversationID uuid.UUID, statuses []models.ScheduledMessageStatus) ([]models.ScheduledMessage, error)
+
+	// FindDue lấy các tin nhắn đang chờ đã đến giờ gửi, sớm nhất trước
+	FindDue(ctx context.Context, now time.Time, limit int) ([]models.ScheduledMessage, error)
+
+	// Claim chuyển tin nhắn từ pending sang sending, trả về false nếu
+	// đã bị worker khác nhận hoặc đã bị hủy
+	Claim(ctx context.Context, msg *models.ScheduledMessage) (bool, error)
+
+	// Cancel hủy tin nhắn (status, cancel_reason, cancelled_by của msg) nếu vẫn đang chờ,
+	// trả về false nếu worker đã nhận gửi hoặc tin nhắn đã bị hủy
+	Cancel(ctx context.Context, msg *models.ScheduledMessage) (bool, error)
+
+	// CancelForClosedConversations hủy các tin nhắn đang chờ của hội thoại đã đóng,
+	// trả về số tin nhắn đã hủy
+	CancelForClosedConversations(ctx context.Context) (int64, error)
+
+	// FailStale đánh dấu lỗi các tin nhắn kẹt ở sending từ trước staleBefore
+	// (worker dừng giữa chừng, không gửi lại để tránh gửi trùng)
+	FailStale(ctx context.Context, staleBefore time.Time) (int64, error)
+}
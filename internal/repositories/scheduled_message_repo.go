@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Scheduled Message Repository GORM Implementation
+// ===========================================================================
+
+// scheduledMessageRepo triển khai ScheduledMessageRepository với GORM
+type scheduledMessageRepo struct {
+	db *gorm.DB
+}
+
+// NewScheduledMessageRepository tạo instance mới của ScheduledMessageRepository
+func NewScheduledMessageRepository(db *gorm.DB) ScheduledMessageRepository {
+	return &scheduledMessageRepo{db: db}
+}
+
+// Create tạo tin nhắn hẹn giờ
+func (r *scheduledMessageRepo) Create(ctx context.Context, msg *models.ScheduledMessage) error {
+	return r.db.WithContext(ctx).Create(msg).Error
+}
+
+// Update lưu trạng thái/kết quả gửi
+func (r *scheduledMessageRepo) Update(ctx context.Context, msg *models.ScheduledMessage) error {
+	return r.db.WithContext(ctx).Save(msg).Error
+}
+
+// FindByID tìm tin nhắn hẹn giờ theo ID
+func (r *scheduledMessageRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.ScheduledMessage, error) {
+	var msg models.ScheduledMessage
+	if err := r.db.WithContext(ctx).First(&msg, id).Error; err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// FindByConversation lấy tin nhắn hẹn giờ của hội thoại theo giờ gửi
+func (r *scheduledMessageRepo) FindByConversation(ctx context.Context, conversationID uuid.UUID, statuses []models.ScheduledMessageStatus) ([]models.ScheduledMessage, error) {
+	var msgs []models.ScheduledMessage
+	query := r.db.WithContext(ctx).Where("conversation_id = ?", conversationID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("send_at ASC").Find(&msgs).Error
+	return msgs, err
+}
+
+// FindDue lấy các tin nhắn đang chờ đã đến giờ gửi
+func (r *scheduledMessageRepo) FindDue(ctx context.Context, now time.Time, limit int) ([]models.ScheduledMessage, error) {
+	var msgs []models.ScheduledMessage
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND send_at <= ?", models.ScheduledPending, now).
+		Order("send_at ASC").
+		Limit(limit).
+		Find(&msgs).Error
+	return msgs, err
+}
+
+// Claim chuyển tin nhắn từ pending sang sending
+func (r *scheduledMessageRepo) Claim(ctx context.Context, msg *models.ScheduledMessage) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.ScheduledMessage{}).
+		Where("id = ? AND status = ?", msg.ID, models.ScheduledPending).
+		Updates(map[string]interface{}{
+			"status":     models.ScheduledSending,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	msg.Status = models.ScheduledSending
+	msg.UpdatedAt = now
+	return true, nil
+}
+
+// Cancel hủy tin nhắn nếu vẫn đang chờ
+func (r *scheduledMessageRepo) Cancel(ctx context.Context, msg *models.ScheduledMessage) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&models.ScheduledMessage{}).
+		Where("id = ? AND status = ?", msg.ID, models.ScheduledPending).
+		Updates(map[string]interface{}{
+			"status":        models.ScheduledCancelled,
+			"cancel_reason": msg.CancelReason,
+			"cancelled_by":  msg.CancelledBy,
+			"updated_at":    now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	msg.Status = models.ScheduledCancelled
+	msg.UpdatedAt = now
+	return true, nil
+}
+
+// CancelForClosedConversations hủy các tin nhắn đang chờ của hội thoại đã đóng
+func (r *scheduledMessageRepo) CancelForClosedConversations(ctx context.Context) (int64, error) {
+	closed := r.db.Model(&models.Conversation{}).
+		Select("id").
+		Where("status = ?", models.StatusClosed)
+	result := r.db.WithContext(ctx).
+		Model(&models.ScheduledMessage{}).
+		Where("status = ? AND conversation_id IN (?)", models.ScheduledPending, closed).
+		Updates(map[string]interface{}{
+			"status":        models.ScheduledCancelled,
+			"cancel_reason": models.CancelReasonConversationClosed,
+			"updated_at":    time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FailStale đánh dấu lỗi các tin nhắn kẹt ở sending
+func (r *scheduledMessageRepo) FailStale(ctx context.Context, staleBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ScheduledMessage{}).
+		Where("status = ? AND updated_at < ?", models.ScheduledSending, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     models.ScheduledFailed,
+			"error":      "Gửi bị gián đoạn, không rõ khách đã nhận hay chưa",
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Search Repository Interface
+// Full-text search trên tin nhắn, ghi chú và thông tin khách hàng
+// ===========================================================================
+
+// SearchHitType loại kết quả tìm kiếm
+type SearchHitType string
+
+const (
+	// SearchHitMessage khớp nội dung tin nhắn
+	SearchHitMessage SearchHitType = "message"
+
+	// SearchHitNote khớp ghi chú nội bộ
+	SearchHitNote SearchHitType = "note"
+
+	// SearchHitParticipant khớp tên/email/số điện thoại khách hàng
+	SearchHitParticipant SearchHitType = "participant"
+)
+
+// SearchQuery điều kiện tìm kiếm
+type SearchQuery struct {
+	WorkspaceID uuid.UUID
+
+	// Text từ khóa (cú pháp websearch: "cụm từ", -loại trừ, OR)
+	Text string
+
+	// Types loại kết quả cần tìm (rỗng = tất cả)
+	Types []SearchHitType
+
+	// ChannelAccountID chỉ tìm trong một kênh
+	ChannelAccountID *uuid.UUID
+
+	// ChannelType chỉ tìm trong loại kênh (facebook, zalo, ...)
+	ChannelType string
+
+	// From, To khoảng thời gian tạo tin nhắn/ghi chú, lần liên hệ cuối của khách
+	From *time.Time
+	To   *time.Time
+
+	// SenderType chỉ tìm tin nhắn của customer/agent/bot (bỏ qua ghi chú và khách hàng)
+	SenderType string
+
+	// Tags conversation phải có ít nhất một trong các tag
+	Tags []string
+
+	Offset int
+	Limit  int
+}
+
+// SearchHit một kết quả tìm kiếm
+type SearchHit struct {
+	Type SearchHitType `json:"type"`
+
+	// ID ID của message / note / participant
+	ID uuid.UUID `json:"id"`
+
+	// ConversationID conversation chứa kết quả
+	// Với participant là conversation gần nhất (nil nếu chưa có)
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+
+	// SenderType người gửi (chỉ với message)
+	SenderType string `json:"sender_type,omitempty"`
+
+	// Snippet đoạn trích đã escape HTML, từ khóa được đánh dấu bằng <mark></mark>
+	Snippet string `json:"snippet"`
+
+	// Rank điểm liên quan
+	Rank float64 `json:"rank"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Total tổng số kết quả (giống nhau ở mọi dòng)
+	Total int64 `json:"-"`
+}
+
+// SearchRepository interface cho full-text search
+type SearchRepository interface {
+	// Search tìm kiếm, sắp xếp theo độ liên quan rồi thời gian (mới trước)
+	Search(ctx context.Context, query SearchQuery) ([]SearchHit, int64, error)
+}
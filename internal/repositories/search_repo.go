@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"chatbox-gin/internal/database"
+
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Search Repository GORM Implementation
+// Dùng Postgres full-text search với configuration bỏ dấu (database.SearchConfig)
+// Mỗi loại kết quả là một nhánh UNION ALL, phân trang trên tập đã gộp
+// ===========================================================================
+
+// searchRepo triển khai SearchRepository với GORM
+type searchRepo struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository tạo instance mới của SearchRepository
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepo{db: db}
+}
+
+const (
+	// tsConfig text search configuration dạng literal SQL
+	tsConfig = "'" + database.SearchConfig + "'"
+
+	// headlineStart, headlineStop ký tự đánh dấu từ khóa trong ts_headline (vùng Private Use, không phải HTML)
+	// ts_headline không escape nội dung nên đoạn trích được escape rồi mới đổi sang <mark></mark>
+	headlineStart = "\uE000"
+	headlineStop  = "\uE001"
+
+	// headlineOptions cấu hình đoạn trích của ts_headline
+	headlineOptions = `'StartSel=` + headlineStart + `, StopSel=` + headlineStop + `, MaxWords=35, MinWords=12, MaxFragments=2, FragmentDelimiter=" … "'`
+
+	messageVector     = "to_tsvector(" + tsConfig + ", COALESCE(m.content, ''))"
+	noteVector        = "to_tsvector(" + tsConfig + ", n.content)"
+	participantVector = "to_tsvector(" + tsConfig + ", COALESCE(p.name, '') || ' ' || COALESCE(p.email, '') || ' ' || COALESCE(p.phone, ''))"
+)
+
+// sqlPart một đoạn SQL kèm tham số theo đúng thứ tự placeholder
+type sqlPart struct {
+	sql  strings.Builder
+	args []interface{}
+}
+
+// add nối thêm điều kiện và tham số
+func (p *sqlPart) add(sql string, args ...interface{}) {
+	p.sql.WriteString(sql)
+	p.args = append(p.args, args...)
+}
+
+// Search tìm kiếm trên các nguồn được chọn
+func (r *searchRepo) Search(ctx context.Context, q SearchQuery) ([]SearchHit, int64, error) {
+	branches := make([]*sqlPart, 0, 3)
+	if q.wants(SearchHitMessage) {
+		branches = append(branches, r.messageBranch(q))
+	}
+	// Ghi chú và khách hàng không có người gửi nên bỏ qua khi lọc theo sender_type
+	if q.wants(SearchHitNote) && q.SenderType == "" {
+		branches = append(branches, r.noteBranch(q))
+	}
+	if q.wants(SearchHitParticipant) && q.SenderType == "" {
+		branches = append(branches, r.participantBranch(q))
+	}
+	if len(branches) == 0 {
+		return []SearchHit{}, 0, nil
+	}
+
+	query := &sqlPart{}
+	query.add("WITH q AS (SELECT websearch_to_tsquery("+tsConfig+", ?) AS query) ", q.Text)
+	query.add("SELECT h.type, h.id, h.conversation_id, h.sender_type, h.created_at, h.rank, h.total, " +
+		"ts_headline(" + tsConfig + ", h.body, q.query, " + headlineOptions + ") AS snippet " +
+		"FROM (SELECT u.*, COUNT(*) OVER () AS total FROM (")
+	for i, branch := range branches {
+		if i > 0 {
+			query.add(" UNION ALL ")
+		}
+		query.add(branch.sql.String(), branch.args...)
+	}
+	query.add(") u ORDER BY u.rank DESC, u.created_at DESC LIMIT ? OFFSET ?) h CROSS JOIN q "+
+		"ORDER BY h.rank DESC, h.created_at DESC", q.Limit, q.Offset)
+
+	var hits []SearchHit
+	if err := r.db.WithContext(ctx).Raw(query.sql.String(), query.args...).Scan(&hits).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if len(hits) > 0 {
+		total = hits[0].Total
+	}
+	for i := range hits {
+		hits[i].Snippet = highlightSnippet(hits[i].Snippet)
+	}
+	return hits, total, nil
+}
+
+// snippetMarks đổi ký tự đánh dấu của ts_headline sang thẻ <mark>
+var snippetMarks = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// highlightSnippet escape HTML nội dung do khách/agent nhập, chỉ giữ thẻ <mark> quanh từ khóa
+func highlightSnippet(snippet string) string {
+	return snippetMarks.Replace(html.EscapeString(snippet))
+}
+
+// messageBranch tìm trong nội dung tin nhắn
+func (r *searchRepo) messageBranch(q SearchQuery) *sqlPart {
+	part := &sqlPart{}
+	part.add("SELECT 'message' AS type, m.id, m.conversation_id, m.sender_type, m.created_at, " +
+		"ts_rank(" + messageVector + ", q.query) AS rank, COALESCE(m.content, '') AS body " +
+		"FROM messages m JOIN conversations c ON c.id = m.conversation_id CROSS JOIN q " +
+		"WHERE m.deleted_at IS NULL AND c.deleted_at IS NULL AND " + messageVector + " @@ q.query")
+	part.add(" AND c.workspace_id = ?", q.WorkspaceID)
+	addConversationFilters(part, q)
+	addDateFilters(part, "m.created_at", q)
+	if q.SenderType != "" {
+		part.add(" AND m.sender_type = ?", q.SenderType)
+	}
+	return part
+}
+
+// noteBranch tìm trong ghi chú nội bộ
+func (r *searchRepo) noteBranch(q SearchQuery) *sqlPart {
+	part := &sqlPart{}
+	part.add("SELECT 'note' AS type, n.id, n.conversation_id, '' AS sender_type, n.created_at, " +
+		"ts_rank(" + noteVector + ", q.query) AS rank, n.content AS body " +
+		"FROM notes n JOIN conversations c ON c.id = n.conversation_id CROSS JOIN q " +
+		"WHERE n.deleted_at IS NULL AND c.deleted_at IS NULL AND " + noteVector + " @@ q.query")
+	part.add(" AND c.workspace_id = ?", q.WorkspaceID)
+	addConversationFilters(part, q)
+	addDateFilters(part, "n.created_at", q)
+	return part
+}
+
+// participantBranch tìm theo tên, email, số điện thoại của khách
+// Từ khóa toàn chữ số còn được so khớp một phần với số điện thoại
+func (r *searchRepo) participantBranch(q SearchQuery) *sqlPart {
+	part := &sqlPart{}
+	part.add("SELECT 'participant' AS type, p.id, " +
+		"(SELECT c.id FROM conversations c WHERE c.participant_id = p.id AND c.deleted_at IS NULL ORDER BY c.created_at DESC LIMIT 1) AS conversation_id, " +
+		"'' AS sender_type, COALESCE(p.last_seen_at, p.created_at) AS created_at, " +
+		"ts_rank(" + participantVector + ", q.query) AS rank, concat_ws(' · ', p.name, p.email, p.phone) AS body " +
+		"FROM participants p CROSS JOIN q " +
+		"WHERE p.deleted_at IS NULL")
+	part.add(" AND p.workspace_id = ?", q.WorkspaceID)
+	if digits := phoneDigits(q.Text); digits != "" {
+		part.add(" AND ("+participantVector+" @@ q.query OR regexp_replace(COALESCE(p.phone, ''), '\\D', '', 'g') LIKE ?)", "%"+digits+"%")
+	} else {
+		part.add(" AND " + participantVector + " @@ q.query")
+	}
+
+	if q.ChannelAccountID != nil {
+		part.add(" AND p.channel_account_id = ?", *q.ChannelAccountID)
+	}
+	if q.ChannelType != "" {
+		part.add(" AND p.channel_account_id IN (SELECT id FROM channel_accounts WHERE channel_type = ?)", q.ChannelType)
+	}
+	if len(q.Tags) > 0 {
+		part.add(" AND EXISTS (SELECT 1 FROM conversations c JOIN conversation_tags ct ON ct.conversation_id = c.id "+
+			"JOIN tags t ON t.id = ct.tag_id WHERE c.participant_id = p.id AND t.name IN ?)", q.Tags)
+	}
+	addDateFilters(part, "COALESCE(p.last_seen_at, p.created_at)", q)
+	return part
+}
+
+// addConversationFilters lọc theo kênh và tag của conversation (alias c)
+func addConversationFilters(part *sqlPart, q SearchQuery) {
+	if q.ChannelAccountID != nil {
+		part.add(" AND c.channel_account_id = ?", *q.ChannelAccountID)
+	}
+	if q.ChannelType != "" {
+		part.add(" AND c.channel_account_id IN (SELECT id FROM channel_accounts WHERE channel_type = ?)", q.ChannelType)
+	}
+	if len(q.Tags) > 0 {
+		part.add(" AND EXISTS (SELECT 1 FROM conversation_tags ct JOIN tags t ON t.id = ct.tag_id "+
+			"WHERE ct.conversation_id = c.id AND t.name IN ?)", q.Tags)
+	}
+}
+
+// addDateFilters lọc theo khoảng thời gian trên cột cho trước
+func addDateFilters(part *sqlPart, column string, q SearchQuery) {
+	if q.From != nil {
+		part.add(" AND "+column+" >= ?", *q.From)
+	}
+	if q.To != nil {
+		part.add(" AND "+column+" < ?", *q.To)
+	}
+}
+
+// wants kiểm tra loại kết quả có được yêu cầu không
+func (q SearchQuery) wants(t SearchHitType) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, want := range q.Types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// phoneDigits trả về chữ số nếu từ khóa trông như số điện thoại (ít nhất 4 số)
+func phoneDigits(text string) string {
+	var digits strings.Builder
+	for _, r := range text {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case r == ' ' || r == '+' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return ""
+		}
+	}
+	if digits.Len() < 4 {
+		return ""
+	}
+	return digits.String()
+}
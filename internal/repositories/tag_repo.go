@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Tag Repository GORM Implementation
+// ===========================================================================
+
+// tagRepo triển khai TagRepository với GORM
+type tagRepo struct {
+	db *gorm.DB
+}
+
+// NewTagRepository tạo instance mới của TagRepository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepo{db: db}
+}
+
+// FindByID tìm tag theo ID
+func (r *tagRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).First(&tag, id).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByWorkspace lấy danh sách tags trong workspace
+func (r *tagRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).
+		Where("workspace_id = ?", workspaceID).
+		Order("name ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
+// FindByName tìm tag theo tên trong workspace (không phân biệt hoa thường)
+func (r *tagRepo) FindByName(ctx context.Context, workspaceID uuid.UUID, name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND LOWER(name) = LOWER(?)", workspaceID, name).
+		First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// Create tạo tag mới
+func (r *tagRepo) Create(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+// Update cập nhật tag
+func (r *tagRepo) Update(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Omit("Workspace", "Conversations").Save(tag).Error
+}
+
+// Delete xóa tag
+func (r *tagRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Tag{}, id).Error
+}
+
+// AddToConversation thêm tag vào conversation (bỏ qua nếu đã có)
+func (r *tagRepo) AddToConversation(ctx context.Context, conversationID, tagID uuid.UUID, createdBy *uuid.UUID) error {
+	return addConversationTag(r.db.WithContext(ctx), conversationID, tagID, createdBy)
+}
+
+// RemoveFromConversation xóa tag khỏi conversation
+func (r *tagRepo) RemoveFromConversation(ctx context.Context, conversationID, tagID uuid.UUID) error {
+	return removeConversationTag(r.db.WithContext(ctx), conversationID, tagID)
+}
+
+// addConversationTag gắn tag cho conversation nếu chưa có (dùng được trong transaction)
+func addConversationTag(db *gorm.DB, conversationID, tagID uuid.UUID, createdBy *uuid.UUID) error {
+	link := models.ConversationTag{
+		ConversationID: conversationID,
+		TagID:          tagID,
+		CreatedBy:      createdBy,
+	}
+	return db.
+		Where("conversation_id = ? AND tag_id = ?", conversationID, tagID).
+		Attrs(link).
+		FirstOrCreate(&link).Error
+}
+
+// removeConversationTag gỡ tag khỏi conversation (dùng được trong transaction)
+func removeConversationTag(db *gorm.DB, conversationID, tagID uuid.UUID) error {
+	return db.
+		Where("conversation_id = ? AND tag_id = ?", conversationID, tagID).
+		Delete(&models.ConversationTag{}).Error
+}
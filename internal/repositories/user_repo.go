@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"chatbox-gin/internal/models"
 
@@ -77,6 +78,28 @@ func (r *userRepo) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, o
 	return users, total, nil
 }
 
+// FindByIDs lấy danh sách users active theo IDs trong workspace
+func (r *userRepo) FindByIDs(ctx context.Context, workspaceID uuid.UUID, ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("workspace_id = ? AND is_active = ? AND id IN ?", workspaceID, true, ids).
+		Find(&users).Error
+	return users, err
+}
+
+// FindByStatusSeenBefore lấy users theo trạng thái, không hoạt động kể từ before
+func (r *userRepo) FindByStatusSeenBefore(ctx context.Context, statuses []models.AgentStatus, before time.Time) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND status IN ?", true, statuses).
+		Where("(last_seen_at IS NULL OR last_seen_at < ?)", before).
+		Find(&users).Error
+	return users, err
+}
+
 // Create tạo user mới
 func (r *userRepo) Create(ctx context.Context, user *models.User) error {
 	return r.db.WithContext(ctx).Create(user).Error
@@ -86,3 +109,11 @@ func (r *userRepo) Create(ctx context.Context, user *models.User) error {
 func (r *userRepo) Update(ctx context.Context, user *models.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
+
+// UpdatePresence chỉ cập nhật các trường presence
+func (r *userRepo) UpdatePresence(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).
+		Model(user).
+		Select("status", "status_auto", "status_changed_at", "last_seen_at").
+		Updates(user).Error
+}
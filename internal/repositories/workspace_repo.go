@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Workspace Repository GORM Implementation
+// (interface defined in interfaces.go)
+// ===========================================================================
+
+// workspaceRepo triển khai WorkspaceRepository với GORM
+type workspaceRepo struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceRepository tạo instance mới của WorkspaceRepository
+func NewWorkspaceRepository(db *gorm.DB) WorkspaceRepository {
+	return &workspaceRepo{db: db}
+}
+
+// FindByID tìm workspace theo ID
+func (r *workspaceRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := r.db.WithContext(ctx).First(&workspace, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// FindBySlug tìm workspace theo slug
+func (r *workspaceRepo) FindBySlug(ctx context.Context, slug string) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&workspace).Error; err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// FindActive lấy tất cả workspaces đang hoạt động
+func (r *workspaceRepo) FindActive(ctx context.Context) ([]models.Workspace, error) {
+	var workspaces []models.Workspace
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("created_at ASC").
+		Find(&workspaces).Error
+	return workspaces, err
+}
+
+// Create tạo workspace mới
+func (r *workspaceRepo) Create(ctx context.Context, workspace *models.Workspace) error {
+	return r.db.WithContext(ctx).Create(workspace).Error
+}
+
+// Update cập nhật workspace
+func (r *workspaceRepo) Update(ctx context.Context, workspace *models.Workspace) error {
+	return r.db.WithContext(ctx).Save(workspace).Error
+}
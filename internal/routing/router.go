@@ -0,0 +1,388 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Router
+// Tự động phân công conversation cho agent theo cấu hình của workspace
+// Mỗi quyết định (kể cả khi không chọn được ai) đều được ghi vào routing log
+// ===========================================================================
+
+// ErrRoutingSkipped routing không chạy vì workspace không bật trigger tương ứng
+// hoặc conversation đã có agent
+var ErrRoutingSkipped = errors.New("routing skipped")
+
+// Router interface cho routing engine
+type Router interface {
+	// Route chọn agent cho conversation và assign nếu tìm được
+	// Trả về ErrRoutingSkipped nếu routing không áp dụng cho trigger này
+	Route(ctx context.Context, conv *models.Conversation, trigger models.RoutingTrigger) (*models.RoutingLog, error)
+
+	// PickByTeam chọn agent đủ điều kiện có kỹ năng team (ít hội thoại mở nhất)
+	// Không assign và không lưu log: caller lưu log cùng các thay đổi khác của mình
+	PickByTeam(ctx context.Context, conv *models.Conversation, team string, trigger models.RoutingTrigger) (*models.RoutingLog, error)
+}
+
+// ===========================================================================
+// Router Implementation
+// ===========================================================================
+
+// router triển khai Router
+type router struct {
+	workspaceRepo    repositories.WorkspaceRepository
+	userRepo         repositories.UserRepository
+	conversationRepo repositories.ConversationRepository
+	routingLogRepo   repositories.RoutingLogRepository
+	publisher        realtime.Publisher
+	logger           *zap.Logger
+
+	// mu tuần tự hóa việc chọn + assign để 2 conversation đến cùng lúc
+	// không cùng nhìn thấy một agent còn trống slot cuối
+	mu sync.Mutex
+}
+
+// NewRouter tạo instance mới của Router
+func NewRouter(
+	workspaceRepo repositories.WorkspaceRepository,
+	userRepo repositories.UserRepository,
+	conversationRepo repositories.ConversationRepository,
+	routingLogRepo repositories.RoutingLogRepository,
+	publisher realtime.Publisher,
+	logger *zap.Logger,
+) Router {
+	return &router{
+		workspaceRepo:    workspaceRepo,
+		userRepo:         userRepo,
+		conversationRepo: conversationRepo,
+		routingLogRepo:   routingLogRepo,
+		publisher:        publisher,
+		logger:           logger,
+	}
+}
+
+// Route chọn agent cho conversation
+func (r *router) Route(ctx context.Context, conv *models.Conversation, trigger models.RoutingTrigger) (*models.RoutingLog, error) {
+	workspace, err := r.workspaceRepo.FindByID(ctx, conv.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find workspace: %w", err)
+	}
+	cfg := workspace.Settings.RoutingConfig()
+
+	// 1. Kiểm tra trigger có được bật không (manual luôn được phép)
+	switch trigger {
+	case models.RoutingTriggerNewConversation:
+		if !cfg.Enabled || !cfg.AssignOnNewConversation || conv.IsAssigned() {
+			return nil, ErrRoutingSkipped
+		}
+	case models.RoutingTriggerHandoff:
+		if !cfg.Enabled || !cfg.AssignOnHandoff || conv.IsAssigned() {
+			return nil, ErrRoutingSkipped
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 2. Đánh giá từng agent
+	candidates, err := r.evaluateCandidates(ctx, conv, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Chọn agent theo chiến lược
+	selected, strategy, reason := r.selectAgent(ctx, conv, cfg, candidates)
+
+	log := newRoutingLog(conv, trigger, strategy, reason, candidates, selected)
+
+	// 4. Assign nếu chọn được agent
+	// Chỉ ghi khi assigned_to chưa đổi kể từ lúc tải: mutex chỉ chặn trong process,
+	// agent hoặc instance khác có thể đã assign trước
+	if selected != nil {
+		assigned, err := r.conversationRepo.Assign(ctx, conv.ID, selected.UserID, conv.AssignedTo)
+		if err != nil {
+			return nil, fmt.Errorf("assign conversation: %w", err)
+		}
+		if assigned {
+			conv.Assign(selected.UserID)
+		} else {
+			selected = nil
+			reason = "hội thoại đã được assign bởi thao tác khác"
+			log = newRoutingLog(conv, trigger, strategy, reason, candidates, nil)
+		}
+	}
+
+	if err := r.routingLogRepo.Create(ctx, log); err != nil {
+		r.logger.Warn("failed to save routing log",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	if selected != nil {
+		r.publishAssignment(conv)
+	}
+
+	r.logger.Info("conversation routed",
+		zap.String("conversation_id", conv.ID.String()),
+		zap.String("trigger", string(trigger)),
+		zap.String("strategy", strategy),
+		zap.Bool("assigned", selected != nil),
+		zap.String("reason", reason),
+	)
+
+	return log, nil
+}
+
+// PickByTeam chọn agent có kỹ năng team cho conversation
+func (r *router) PickByTeam(ctx context.Context, conv *models.Conversation, team string, trigger models.RoutingTrigger) (*models.RoutingLog, error) {
+	workspace, err := r.workspaceRepo.FindByID(ctx, conv.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find workspace: %w", err)
+	}
+	cfg := workspace.Settings.RoutingConfig()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	candidates, err := r.evaluateCandidates(ctx, conv, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.Eligible && !c.user.HasAnySkill([]string{team}) {
+			c.Eligible = false
+			c.Reason = "không thuộc team " + team
+		}
+		if c.Eligible {
+			eligible = append(eligible, c)
+		}
+	}
+
+	var selected *candidate
+	reason := "không có agent nào của team " + team + " đủ điều kiện"
+	if len(eligible) > 0 {
+		selected = selectLeastOpen(eligible)
+		reason = "agent của team " + team + " có ít hội thoại mở nhất"
+	}
+	return newRoutingLog(conv, trigger, "team", reason, candidates, selected), nil
+}
+
+// newRoutingLog tạo routing log cho một quyết định (selected nil = không chọn được ai)
+func newRoutingLog(conv *models.Conversation, trigger models.RoutingTrigger, strategy, reason string, candidates []*candidate, selected *candidate) *models.RoutingLog {
+	log := &models.RoutingLog{
+		WorkspaceID:    conv.WorkspaceID,
+		ConversationID: conv.ID,
+		Trigger:        trigger,
+		Strategy:       strategy,
+		Reason:         reason,
+		Candidates:     make(models.RoutingCandidates, 0, len(candidates)),
+	}
+	for _, c := range candidates {
+		log.Candidates = append(log.Candidates, c.RoutingCandidate)
+	}
+	if selected != nil {
+		log.AssignedTo = &selected.UserID
+	}
+	return log
+}
+
+// candidate agent đang được xét kèm thông tin phục vụ chọn lựa
+type candidate struct {
+	models.RoutingCandidate
+	user *models.User
+}
+
+// evaluateCandidates lấy agents của workspace và đánh dấu ai đủ điều kiện
+// Điều kiện: đúng role, trạng thái online, chưa vượt quá capacity của agent
+func (r *router) evaluateCandidates(ctx context.Context, conv *models.Conversation, cfg models.RoutingSettings) ([]*candidate, error) {
+	users, _, err := r.userRepo.FindByWorkspace(ctx, conv.WorkspaceID, repositories.FindOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("find agents: %w", err)
+	}
+
+	userIDs := make([]uuid.UUID, len(users))
+	for i := range users {
+		userIDs[i] = users[i].ID
+	}
+	openCounts, err := r.conversationRepo.CountOpenByAssignees(ctx, conv.WorkspaceID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("count open conversations: %w", err)
+	}
+
+	candidates := make([]*candidate, 0, len(users))
+	for i := range users {
+		user := &users[i]
+		c := &candidate{
+			RoutingCandidate: models.RoutingCandidate{
+				UserID:    user.ID,
+				Name:      user.Name,
+				OpenCount: openCounts[user.ID],
+			},
+			user: user,
+		}
+		capacity := user.Capacity(cfg.MaxConcurrent)
+
+		switch {
+		case user.Role != models.RoleAgent && !cfg.IncludeAdmins:
+			c.Reason = "không phải agent"
+		case !user.IsAvailable():
+			c.Reason = "trạng thái " + string(user.Status)
+		case capacity > 0 && c.OpenCount >= int64(capacity):
+			c.Reason = fmt.Sprintf("đã đủ tải (%d/%d)", c.OpenCount, capacity)
+		default:
+			c.Eligible = true
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// selectAgent chọn agent theo chiến lược, trả về (agent, strategy thực tế, lý do)
+func (r *router) selectAgent(ctx context.Context, conv *models.Conversation, cfg models.RoutingSettings, candidates []*candidate) (*candidate, string, string) {
+	eligible := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.Eligible {
+			eligible = append(eligible, c)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, string(cfg.Strategy), "không có agent nào đủ điều kiện"
+	}
+
+	// Sticky: ưu tiên agent đã xử lý participant trước đó
+	if cfg.Sticky {
+		if c := r.findSticky(ctx, conv, candidates); c != nil && c.Eligible {
+			return c, "sticky", "agent đã xử lý khách hàng này trước đó"
+		}
+	}
+
+	switch cfg.Strategy {
+	case models.RoutingRoundRobin:
+		return r.selectRoundRobin(ctx, conv.WorkspaceID, candidates), string(models.RoutingRoundRobin), "lượt tiếp theo trong vòng"
+
+	case models.RoutingSkill:
+		required := r.requiredSkills(ctx, conv)
+		if len(required) > 0 {
+			matched := make([]*candidate, 0, len(eligible))
+			for _, c := range eligible {
+				if c.user.HasAnySkill(required) {
+					matched = append(matched, c)
+				}
+			}
+			if len(matched) > 0 {
+				return selectLeastOpen(matched), string(models.RoutingSkill), "kỹ năng khớp tag của hội thoại"
+			}
+		}
+		return selectLeastOpen(eligible), string(models.RoutingLeastOpen), "không agent nào khớp kỹ năng, chọn agent ít việc nhất"
+	}
+
+	return selectLeastOpen(eligible), string(models.RoutingLeastOpen), "agent có ít hội thoại mở nhất"
+}
+
+// findSticky tìm candidate là agent gần nhất đã xử lý participant
+func (r *router) findSticky(ctx context.Context, conv *models.Conversation, candidates []*candidate) *candidate {
+	lastAgent, err := r.conversationRepo.FindLastAssigneeByParticipant(ctx, conv.ParticipantID, conv.ID)
+	if err != nil {
+		r.logger.Warn("failed to find previous agent", zap.Error(err))
+		return nil
+	}
+	if lastAgent == nil {
+		return nil
+	}
+	for _, c := range candidates {
+		if c.UserID == *lastAgent {
+			return c
+		}
+	}
+	return nil
+}
+
+// selectRoundRobin chọn agent đủ điều kiện kế tiếp sau agent được assign gần nhất
+// Danh sách agent giữ thứ tự ổn định (created_at ASC) nên vòng quay không bị xáo trộn
+// kể cả khi agent trước đó đã offline
+func (r *router) selectRoundRobin(ctx context.Context, workspaceID uuid.UUID, candidates []*candidate) *candidate {
+	start := 0
+	last, err := r.routingLogRepo.FindLastAssigned(ctx, workspaceID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.logger.Warn("failed to find last routing", zap.Error(err))
+	}
+	if err == nil {
+		for i, c := range candidates {
+			if c.UserID == *last.AssignedTo {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	for i := 0; i < len(candidates); i++ {
+		c := candidates[(start+i)%len(candidates)]
+		if c.Eligible {
+			return c
+		}
+	}
+	return nil
+}
+
+// selectLeastOpen chọn agent có ít conversation mở nhất
+// Bằng nhau thì giữ thứ tự trong danh sách
+func selectLeastOpen(eligible []*candidate) *candidate {
+	best := eligible[0]
+	for _, c := range eligible[1:] {
+		if c.OpenCount < best.OpenCount {
+			best = c
+		}
+	}
+	return best
+}
+
+// requiredSkills lấy danh sách kỹ năng cần thiết từ tag của conversation và participant
+// Conversation truyền vào thường chưa preload tags nên đọc lại bản chi tiết
+func (r *router) requiredSkills(ctx context.Context, conv *models.Conversation) []string {
+	detail, err := r.conversationRepo.FindDetailByID(ctx, conv.ID)
+	if err != nil {
+		r.logger.Warn("failed to load conversation tags", zap.Error(err))
+		return nil
+	}
+
+	skills := make([]string, 0, len(detail.Tags)+len(detail.Participant.Metadata.Tags))
+	for _, tag := range detail.Tags {
+		skills = append(skills, tag.Name)
+	}
+	skills = append(skills, detail.Participant.Metadata.Tags...)
+	return skills
+}
+
+// publishAssignment gửi realtime event khi conversation được assign
+func (r *router) publishAssignment(conv *models.Conversation) {
+	if r.publisher == nil || conv.AssignedTo == nil {
+		return
+	}
+	event := &realtime.ConversationEvent{
+		ConversationID: conv.ID,
+		Status:         string(conv.Status),
+		AssignedTo:     conv.AssignedTo.String(),
+	}
+	go func() {
+		if err := r.publisher.PublishConversationUpdate(conv.WorkspaceID, event); err != nil {
+			r.logger.Warn("failed to publish routing assignment", zap.Error(err))
+		}
+	}()
+}
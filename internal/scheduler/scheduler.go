@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Scheduler
+// Chạy các tác vụ nền định kỳ (quét agent idle, SLA, ...)
+// Mỗi job chạy trên goroutine riêng, lỗi/panic của job không ảnh hưởng job khác
+// ===========================================================================
+
+// JobFunc hàm thực thi một lần chạy của job
+type JobFunc func(ctx context.Context) error
+
+// job một tác vụ định kỳ đã đăng ký
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+}
+
+// Scheduler quản lý vòng đời các job định kỳ
+type Scheduler struct {
+	jobs   []job
+	logger *zap.Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New tạo Scheduler mới
+func New(logger *zap.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Every đăng ký job chạy mỗi interval (phải gọi trước Start)
+func (s *Scheduler) Every(name string, interval time.Duration, fn JobFunc) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// Start khởi động tất cả jobs
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+
+	s.logger.Info("scheduler started", zap.Int("jobs", len(s.jobs)))
+}
+
+// Stop dừng tất cả jobs và chờ lần chạy hiện tại kết thúc
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	s.logger.Info("scheduler stopped")
+}
+
+// loop chạy job theo chu kỳ cho đến khi context bị hủy
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, j)
+		}
+	}
+}
+
+// run chạy một lần job, bắt panic để không làm chết scheduler
+func (s *Scheduler) run(ctx context.Context, j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("scheduled job panicked",
+				zap.String("job", j.name),
+				zap.Any("panic", r),
+			)
+		}
+	}()
+
+	start := time.Now()
+	if err := j.fn(ctx); err != nil {
+		s.logger.Warn("scheduled job failed",
+			zap.String("job", j.name),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Actor
+// Thông tin user đang thao tác (lấy từ JWT), truyền từ handler xuống service
+// ===========================================================================
+
+// Actor user thực hiện thao tác
+type Actor struct {
+	UserID      uuid.UUID
+	WorkspaceID uuid.UUID
+	Role        models.UserRole
+}
+
+// IsAdmin kiểm tra actor có quyền admin không (owner hoặc admin)
+func (a Actor) IsAdmin() bool {
+	return a.Role == models.RoleOwner || a.Role == models.RoleAdmin
+}
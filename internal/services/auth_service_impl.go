@@ -145,6 +145,9 @@ func (s *authServiceImpl) RefreshTokens(ctx context.Context, refreshToken string
 	newTokenHash := hashToken(tokens.RefreshToken)
 	user.RefreshTokenHash = &newTokenHash
 
+	// Refresh token định kỳ = agent vẫn đang mở dashboard
+	user.UpdateLastSeen()
+
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		s.logger.Error("update refresh token hash failed",
 			zap.Error(err),
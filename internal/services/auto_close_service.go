@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+)
+
+// ===========================================================================
+// Auto-Close Service Interface
+// Tự động đóng hội thoại khi khách không nhắn tin quá N giờ
+// ===========================================================================
+
+// AutoCloseService interface cho auto-close
+type AutoCloseService interface {
+	// GetSettings lấy cấu hình auto-close của workspace
+	GetSettings(ctx context.Context, actor Actor) (models.AutoCloseSettings, error)
+
+	// UpdateSettings cập nhật cấu hình auto-close (chỉ admin)
+	UpdateSettings(ctx context.Context, actor Actor, settings models.AutoCloseSettings) (models.AutoCloseSettings, error)
+
+	// SweepInactive đóng các conversation không hoạt động (chạy định kỳ)
+	SweepInactive(ctx context.Context) error
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Auto-Close Service Implementation
+// ===========================================================================
+
+// autoCloseService triển khai AutoCloseService
+type autoCloseService struct {
+	workspaceRepo    repositories.WorkspaceRepository
+	conversationRepo repositories.ConversationRepository
+	outboundService  OutboundService
+	csatService      CSATService
+	publisher        realtime.Publisher
+	batchSize        int
+	logger           *zap.Logger
+}
+
+// NewAutoCloseService tạo instance mới của AutoCloseService
+func NewAutoCloseService(
+	workspaceRepo repositories.WorkspaceRepository,
+	conversationRepo repositories.ConversationRepository,
+	outboundService OutboundService,
+	csatService CSATService,
+	publisher realtime.Publisher,
+	batchSize int,
+	logger *zap.Logger,
+) AutoCloseService {
+	return &autoCloseService{
+		workspaceRepo:    workspaceRepo,
+		conversationRepo: conversationRepo,
+		outboundService:  outboundService,
+		csatService:      csatService,
+		publisher:        publisher,
+		batchSize:        batchSize,
+		logger:           logger,
+	}
+}
+
+// GetSettings lấy cấu hình auto-close của workspace
+func (s *autoCloseService) GetSettings(ctx context.Context, actor Actor) (models.AutoCloseSettings, error) {
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.AutoCloseSettings{}, fmt.Errorf("find workspace: %w", err)
+	}
+	return workspace.Settings.AutoCloseConfig(), nil
+}
+
+// UpdateSettings cập nhật cấu hình auto-close
+func (s *autoCloseService) UpdateSettings(ctx context.Context, actor Actor, settings models.AutoCloseSettings) (models.AutoCloseSettings, error) {
+	if !actor.IsAdmin() {
+		return models.AutoCloseSettings{}, apperrors.New(apperrors.ErrForbidden, "Chỉ admin được cấu hình tự động đóng hội thoại")
+	}
+	if settings.InactiveHours < 0 {
+		return models.AutoCloseSettings{}, apperrors.New(apperrors.ErrInvalidInput, "Số giờ không hoạt động không hợp lệ")
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.AutoCloseSettings{}, fmt.Errorf("find workspace: %w", err)
+	}
+
+	workspace.Settings.AutoClose = &settings
+	if err := s.workspaceRepo.Update(ctx, workspace); err != nil {
+		return models.AutoCloseSettings{}, fmt.Errorf("update workspace: %w", err)
+	}
+
+	s.logger.Info("auto-close settings updated",
+		zap.String("workspace_id", workspace.ID.String()),
+		zap.Bool("enabled", settings.Enabled),
+		zap.Int("inactive_hours", settings.InactiveHours),
+		zap.Bool("send_csat", settings.SendCSAT),
+	)
+	return workspace.Settings.AutoCloseConfig(), nil
+}
+
+// SweepInactive đóng các conversation không hoạt động của mọi workspace
+func (s *autoCloseService) SweepInactive(ctx context.Context) error {
+	workspaces, err := s.workspaceRepo.FindActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find workspaces: %w", err)
+	}
+
+	for i := range workspaces {
+		if err := s.sweepWorkspace(ctx, &workspaces[i]); err != nil {
+			s.logger.Warn("auto-close sweep failed",
+				zap.String("workspace_id", workspaces[i].ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// sweepWorkspace đóng các conversation không hoạt động của một workspace
+func (s *autoCloseService) sweepWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	cfg := workspace.Settings.AutoCloseConfig()
+	if !cfg.Enabled {
+		return nil
+	}
+
+	before := time.Now().Add(-time.Duration(cfg.InactiveHours) * time.Hour)
+	conversations, err := s.conversationRepo.FindInactive(ctx, workspace.ID, before, s.batchSize)
+	if err != nil {
+		return fmt.Errorf("find inactive conversations: %w", err)
+	}
+
+	closed := 0
+	for i := range conversations {
+		conv := &conversations[i]
+		ok, err := s.closeConversation(ctx, conv, cfg, before)
+		if err != nil {
+			s.logger.Warn("failed to auto-close conversation",
+				zap.String("conversation_id", conv.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+
+	if closed > 0 {
+		s.logger.Info("inactive conversations closed",
+			zap.String("workspace_id", workspace.ID.String()),
+			zap.Int("count", closed),
+		)
+	}
+	return nil
+}
+
+// closeConversation gửi tin nhắn đóng rồi đóng conversation, sau đó gửi khảo sát CSAT
+// Gửi qua channel thất bại không chặn việc đóng (tin nhắn vẫn được lưu kèm lỗi)
+// Trả về false nếu khách đã nhắn lại hoặc conversation đã được xử lý sau lần quét
+func (s *autoCloseService) closeConversation(ctx context.Context, conv *models.Conversation, cfg models.AutoCloseSettings, before time.Time) (bool, error) {
+	if cfg.ClosingMessage != "" {
+		// Tải lại trước khi gửi: bản quét có thể đã cũ khi các conversation trước gửi chậm
+		current, err := s.conversationRepo.FindByID(ctx, conv.ID)
+		if err != nil {
+			return false, fmt.Errorf("reload conversation: %w", err)
+		}
+		if !inactiveSince(current, before) {
+			return false, nil
+		}
+		conv = current
+
+		_, err = s.outboundService.Send(ctx, OutboundInput{
+			Conversation: conv,
+			SenderType:   models.SenderBot,
+			Content:      cfg.ClosingMessage,
+		})
+		switch {
+		case apperrors.Is(err, apperrors.ErrMessagingWindowClosed):
+			s.logger.Info("closing message skipped: messaging window closed",
+				zap.String("conversation_id", conv.ID.String()),
+			)
+		case err != nil:
+			s.logger.Warn("failed to send closing message",
+				zap.String("conversation_id", conv.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// Chỉ đóng nếu vẫn không hoạt động: tin nhắn của khách đến trong lúc gửi giữ conversation mở
+	conv.Close(models.ClosedReasonInactivity)
+	closed, err := s.conversationRepo.CloseInactive(ctx, conv, before)
+	if err != nil {
+		return false, fmt.Errorf("close conversation: %w", err)
+	}
+	if !closed {
+		return false, nil
+	}
+
+	s.publishUpdate(conv)
+
+	// send_csat gửi khảo sát cả khi workspace chưa bật CSAT, csat_prompt thay câu hỏi mặc định
+	survey := SurveyOptions{}
+	if cfg.SendCSAT {
+		survey = SurveyOptions{Force: true, Prompt: cfg.CSATPrompt}
+	}
+	if err := s.csatService.SendSurveyWith(ctx, conv, survey); err != nil {
+		s.logger.Warn("failed to send csat survey",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+	return true, nil
+}
+
+// inactiveSince kiểm tra conversation chưa đóng, không tạm ẩn và khách không nhắn tin kể từ before
+func inactiveSince(conv *models.Conversation, before time.Time) bool {
+	if conv.Status == models.StatusClosed || conv.Status == models.StatusSnoozed {
+		return false
+	}
+	last := conv.CreatedAt
+	if conv.LastInboundAt != nil {
+		last = *conv.LastInboundAt
+	}
+	return last.Before(before)
+}
+
+// publishUpdate gửi realtime event khi conversation bị đóng
+func (s *autoCloseService) publishUpdate(conv *models.Conversation) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.ConversationEvent{
+		ConversationID: conv.ID,
+		Status:         string(conv.Status),
+	}
+	if conv.AssignedTo != nil {
+		event.AssignedTo = conv.AssignedTo.String()
+	}
+	go func() {
+		if err := s.publisher.PublishConversationUpdate(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish auto-close update", zap.Error(err))
+		}
+	}()
+}
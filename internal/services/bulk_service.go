@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Bulk Service Interface
+// Thao tác hàng loạt trên hội thoại (dọn dẹp sau chiến dịch, chuyển agent, ...)
+// Job được tạo ngay, xử lý nền theo từng lô và báo tiến độ qua realtime
+// ===========================================================================
+
+// CreateBulkJobInput dữ liệu tạo job
+// Chọn hội thoại bằng ConversationIDs hoặc Filter (chỉ một trong hai)
+type CreateBulkJobInput struct {
+	ConversationIDs []uuid.UUID
+	Filter          *models.BulkFilter
+	Action          models.BulkAction
+	Params          models.BulkParams
+}
+
+// BulkService interface cho thao tác hàng loạt
+type BulkService interface {
+	// Create kiểm tra tham số, chốt danh sách hội thoại và tạo job chờ xử lý (chỉ admin)
+	Create(ctx context.Context, actor Actor, input CreateBulkJobInput) (*models.BulkJob, error)
+
+	// Get lấy tiến độ và kết quả của job
+	Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.BulkJob, error)
+
+	// List lấy các job gần nhất của workspace
+	List(ctx context.Context, actor Actor) ([]models.BulkJob, error)
+
+	// RunPending xử lý các job đang chờ hoặc bị gián đoạn (chạy định kỳ)
+	RunPending(ctx context.Context) error
+}
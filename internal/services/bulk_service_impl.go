@@ -0,0 +1,557 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chatbox-gin/internal/config"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Bulk Service Implementation
+// ===========================================================================
+
+const (
+	// bulkJobHistory số job gần nhất trả về trong danh sách
+	bulkJobHistory = 50
+
+	// bulkJobStaleAfter job đang chạy không cập nhật tiến độ quá lâu được coi là
+	// bị gián đoạn (server restart) và được chạy tiếp từ lô chưa xong
+	bulkJobStaleAfter = 5 * time.Minute
+)
+
+// errBulkConversationNotFound hội thoại trong danh sách không còn/không thuộc workspace
+var errBulkConversationNotFound = errors.New("không tìm thấy hội thoại")
+
+// bulkService triển khai BulkService
+type bulkService struct {
+	bulkRepo         repositories.BulkJobRepository
+	conversationRepo repositories.ConversationRepository
+	tagRepo          repositories.TagRepository
+	userRepo         repositories.UserRepository
+	csatService      CSATService
+	publisher        realtime.Publisher
+	cfg              config.BulkConfig
+	logger           *zap.Logger
+}
+
+// NewBulkService tạo instance mới của BulkService
+func NewBulkService(
+	bulkRepo repositories.BulkJobRepository,
+	conversationRepo repositories.ConversationRepository,
+	tagRepo repositories.TagRepository,
+	userRepo repositories.UserRepository,
+	csatService CSATService,
+	publisher realtime.Publisher,
+	cfg config.BulkConfig,
+	logger *zap.Logger,
+) BulkService {
+	return &bulkService{
+		bulkRepo:         bulkRepo,
+		conversationRepo: conversationRepo,
+		tagRepo:          tagRepo,
+		userRepo:         userRepo,
+		csatService:      csatService,
+		publisher:        publisher,
+		cfg:              cfg,
+		logger:           logger,
+	}
+}
+
+// Create kiểm tra tham số, chốt danh sách hội thoại và tạo job
+func (s *bulkService) Create(ctx context.Context, actor Actor, input CreateBulkJobInput) (*models.BulkJob, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được thao tác hàng loạt")
+	}
+
+	params, err := s.validateParams(ctx, actor, input.Action, input.Params)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.selectConversations(ctx, actor, input)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.BulkJob{
+		WorkspaceID:     actor.WorkspaceID,
+		CreatedBy:       actor.UserID,
+		Action:          input.Action,
+		Params:          params,
+		ConversationIDs: ids,
+		Status:          models.BulkPending,
+		Total:           len(ids),
+	}
+	if input.Filter != nil {
+		job.Filter = *input.Filter
+	}
+	if err := s.bulkRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("create bulk job: %w", err)
+	}
+
+	s.logger.Info("bulk job created",
+		zap.String("job_id", job.ID.String()),
+		zap.String("workspace_id", actor.WorkspaceID.String()),
+		zap.String("action", string(job.Action)),
+		zap.Int("total", job.Total),
+	)
+	return job, nil
+}
+
+// Get lấy tiến độ và kết quả của job
+func (s *bulkService) Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.BulkJob, error) {
+	job, err := s.bulkRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy job")
+		}
+		return nil, fmt.Errorf("find bulk job: %w", err)
+	}
+	if job.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy job")
+	}
+	return job, nil
+}
+
+// List lấy các job gần nhất của workspace
+func (s *bulkService) List(ctx context.Context, actor Actor) ([]models.BulkJob, error) {
+	jobs, err := s.bulkRepo.FindByWorkspace(ctx, actor.WorkspaceID, bulkJobHistory)
+	if err != nil {
+		return nil, fmt.Errorf("find bulk jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// RunPending nhận và xử lý lần lượt các job cần chạy cho đến khi hết
+func (s *bulkService) RunPending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		job, err := s.bulkRepo.FindRunnable(ctx, time.Now().Add(-bulkJobStaleAfter))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("find runnable bulk job: %w", err)
+		}
+
+		claimed, err := s.bulkRepo.Claim(ctx, job)
+		if err != nil {
+			return fmt.Errorf("claim bulk job: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+		s.run(ctx, job)
+	}
+	return nil
+}
+
+// ===========================================================================
+// Validation & selection
+// ===========================================================================
+
+// validateParams kiểm tra tham số theo thao tác, trả về tham số đã chuẩn hóa
+func (s *bulkService) validateParams(ctx context.Context, actor Actor, action models.BulkAction, params models.BulkParams) (models.BulkParams, error) {
+	invalid := func(msg string) (models.BulkParams, error) {
+		return params, apperrors.New(apperrors.ErrInvalidInput, msg)
+	}
+
+	switch action {
+	case models.BulkAssign:
+		if params.UserID != nil {
+			users, err := s.userRepo.FindByIDs(ctx, actor.WorkspaceID, []uuid.UUID{*params.UserID})
+			if err != nil {
+				return params, fmt.Errorf("find assignee: %w", err)
+			}
+			if len(users) == 0 {
+				return invalid("Agent được assign không tồn tại hoặc đã ngừng hoạt động")
+			}
+		}
+
+	case models.BulkTag, models.BulkUntag:
+		tags := make([]string, 0, len(params.Tags))
+		seen := make(map[string]bool, len(params.Tags))
+		for _, name := range params.Tags {
+			name = strings.TrimSpace(name)
+			if name == "" || len(name) > 100 {
+				return invalid("Tên tag không được để trống và tối đa 100 ký tự")
+			}
+			if key := strings.ToLower(name); !seen[key] {
+				seen[key] = true
+				tags = append(tags, name)
+			}
+		}
+		if len(tags) == 0 {
+			return invalid("Cần ít nhất một tag")
+		}
+		params.Tags = tags
+
+	case models.BulkSetPriority:
+		switch params.Priority {
+		case models.PriorityLow, models.PriorityNormal, models.PriorityHigh, models.PriorityUrgent:
+		default:
+			return invalid("Mức ưu tiên không hợp lệ")
+		}
+
+	case models.BulkClose, models.BulkReopen, models.BulkPauseBot:
+
+	default:
+		return invalid(fmt.Sprintf("Thao tác %q không được hỗ trợ", action))
+	}
+	return params, nil
+}
+
+// selectConversations chốt danh sách hội thoại của job từ danh sách ID hoặc bộ lọc
+// Hội thoại không thuộc workspace được ghi lỗi khi xử lý
+func (s *bulkService) selectConversations(ctx context.Context, actor Actor, input CreateBulkJobInput) (models.UUIDList, error) {
+	hasIDs := len(input.ConversationIDs) > 0
+	hasFilter := input.Filter != nil && !input.Filter.IsEmpty()
+	if hasIDs == hasFilter {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Cần chọn hội thoại bằng conversation_ids hoặc filter (chỉ một trong hai)")
+	}
+
+	if hasIDs {
+		ids := make(models.UUIDList, 0, len(input.ConversationIDs))
+		seen := make(map[uuid.UUID]bool, len(input.ConversationIDs))
+		for _, id := range input.ConversationIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > s.cfg.MaxConversations {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Tối đa %d hội thoại mỗi lần", s.cfg.MaxConversations))
+		}
+		return ids, nil
+	}
+
+	filter := *input.Filter
+	switch filter.Status {
+	case "", models.StatusOpen, models.StatusPending, models.StatusClosed, models.StatusBotPaused, models.StatusSnoozed:
+	default:
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Trạng thái trong bộ lọc không hợp lệ")
+	}
+	if filter.Unassigned && filter.AssignedTo != nil {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Không thể lọc cùng lúc assigned_to và unassigned")
+	}
+	if filter.OlderThanHours < 0 || filter.NewerThanHours < 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Số giờ trong bộ lọc không hợp lệ")
+	}
+
+	ids, err := s.conversationRepo.FindIDsByFilter(ctx, actor.WorkspaceID, filter, s.cfg.MaxConversations+1)
+	if err != nil {
+		return nil, fmt.Errorf("find conversations by filter: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Không có hội thoại nào khớp bộ lọc")
+	}
+	if len(ids) > s.cfg.MaxConversations {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Bộ lọc khớp hơn %d hội thoại, hãy thu hẹp điều kiện", s.cfg.MaxConversations))
+	}
+	return ids, nil
+}
+
+// ===========================================================================
+// Processing
+// ===========================================================================
+
+// run xử lý job từ lô chưa xong, lưu tiến độ sau mỗi lô
+// Context bị hủy giữa chừng thì job giữ trạng thái running và được chạy tiếp sau bulkJobStaleAfter
+func (s *bulkService) run(ctx context.Context, job *models.BulkJob) {
+	tags, err := s.resolveTags(ctx, job)
+	if err != nil {
+		s.fail(ctx, job, err)
+		return
+	}
+
+	for job.Processed < len(job.ConversationIDs) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		end := job.Processed + s.cfg.BatchSize
+		if end > len(job.ConversationIDs) {
+			end = len(job.ConversationIDs)
+		}
+		s.processBatch(ctx, job, job.ConversationIDs[job.Processed:end], tags)
+		job.Processed = end
+
+		if err := s.bulkRepo.Update(ctx, job); err != nil {
+			s.logger.Warn("failed to save bulk job progress",
+				zap.String("job_id", job.ID.String()),
+				zap.Error(err),
+			)
+			return
+		}
+		s.publishProgress(job)
+	}
+
+	now := time.Now()
+	job.Status = models.BulkCompleted
+	job.FinishedAt = &now
+	if err := s.bulkRepo.Update(ctx, job); err != nil {
+		s.logger.Warn("failed to complete bulk job",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	s.publishProgress(job)
+
+	s.logger.Info("bulk job completed",
+		zap.String("job_id", job.ID.String()),
+		zap.String("action", string(job.Action)),
+		zap.Int("total", job.Total),
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("skipped", job.Skipped),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// fail dừng job do lỗi hệ thống
+func (s *bulkService) fail(ctx context.Context, job *models.BulkJob, cause error) {
+	now := time.Now()
+	job.Status = models.BulkFailed
+	job.Error = cause.Error()
+	job.FinishedAt = &now
+	if err := s.bulkRepo.Update(ctx, job); err != nil {
+		s.logger.Warn("failed to save failed bulk job",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	s.publishProgress(job)
+
+	s.logger.Warn("bulk job failed",
+		zap.String("job_id", job.ID.String()),
+		zap.Error(cause),
+	)
+}
+
+// resolveTags lấy tag cần gắn/gỡ một lần cho cả job
+// Gắn tag thì tạo tag chưa có, gỡ tag thì bỏ qua tag không tồn tại
+func (s *bulkService) resolveTags(ctx context.Context, job *models.BulkJob) ([]models.Tag, error) {
+	var tags []models.Tag
+	switch job.Action {
+	case models.BulkTag:
+		for _, name := range job.Params.Tags {
+			tag, err := findOrCreateTag(ctx, s.tagRepo, job.WorkspaceID, name)
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, *tag)
+		}
+	case models.BulkUntag:
+		for _, name := range job.Params.Tags {
+			tag, err := s.tagRepo.FindByName(ctx, job.WorkspaceID, name)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("find tag: %w", err)
+			}
+			tags = append(tags, *tag)
+		}
+	}
+	return tags, nil
+}
+
+// processBatch áp dụng thao tác lên một lô hội thoại và cập nhật bộ đếm của job
+func (s *bulkService) processBatch(ctx context.Context, job *models.BulkJob, ids []uuid.UUID, tags []models.Tag) {
+	conversations, err := s.conversationRepo.FindByIDs(ctx, job.WorkspaceID, ids)
+	if err != nil {
+		for _, id := range ids {
+			job.AddError(id, fmt.Errorf("find conversation: %w", err))
+		}
+		return
+	}
+	byID := make(map[uuid.UUID]*models.Conversation, len(conversations))
+	for i := range conversations {
+		byID[conversations[i].ID] = &conversations[i]
+	}
+
+	for _, id := range ids {
+		conv, ok := byID[id]
+		if !ok {
+			job.AddError(id, errBulkConversationNotFound)
+			continue
+		}
+
+		changed, err := s.apply(ctx, job, conv, tags)
+		switch {
+		case err != nil:
+			job.AddError(id, err)
+		case changed:
+			job.Succeeded++
+			s.publishUpdate(job, conv)
+		default:
+			job.Skipped++
+		}
+	}
+}
+
+// apply áp dụng thao tác lên một hội thoại
+// Trả về false nếu hội thoại đã ở trạng thái mong muốn (chạy lại một lô không đổi kết quả)
+func (s *bulkService) apply(ctx context.Context, job *models.BulkJob, conv *models.Conversation, tags []models.Tag) (bool, error) {
+	params := job.Params
+	justClosed := false
+
+	switch job.Action {
+	case models.BulkAssign:
+		if params.UserID == nil {
+			if !conv.IsAssigned() {
+				return false, nil
+			}
+			conv.Unassign()
+		} else {
+			if conv.AssignedTo != nil && *conv.AssignedTo == *params.UserID {
+				return false, nil
+			}
+			conv.Assign(*params.UserID)
+		}
+
+	case models.BulkClose:
+		if conv.IsClosed() {
+			return false, nil
+		}
+		conv.Close(params.Reason)
+		justClosed = true
+
+	case models.BulkReopen:
+		if !conv.IsClosed() {
+			return false, nil
+		}
+		conv.Reopen()
+
+	case models.BulkSetPriority:
+		if conv.Priority == params.Priority {
+			return false, nil
+		}
+		conv.Priority = params.Priority
+
+	case models.BulkPauseBot:
+		if conv.IsClosed() || conv.IsBotPaused() {
+			return false, nil
+		}
+		conv.PauseBot(params.Reason)
+
+	case models.BulkTag, models.BulkUntag:
+		return s.applyTags(ctx, job, conv, tags)
+	}
+
+	if err := s.conversationRepo.UpdateState(ctx, conv); err != nil {
+		return false, fmt.Errorf("update conversation: %w", err)
+	}
+
+	if justClosed && params.SendSurvey {
+		if err := s.csatService.SendSurvey(ctx, conv); err != nil {
+			s.logger.Warn("failed to send csat survey",
+				zap.String("conversation_id", conv.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return true, nil
+}
+
+// applyTags gắn/gỡ tag, bỏ qua tag hội thoại đã có (gắn) hoặc chưa có (gỡ)
+func (s *bulkService) applyTags(ctx context.Context, job *models.BulkJob, conv *models.Conversation, tags []models.Tag) (bool, error) {
+	current := make(map[uuid.UUID]bool, len(conv.Tags))
+	for _, tag := range conv.Tags {
+		current[tag.ID] = true
+	}
+
+	changed := false
+	for _, tag := range tags {
+		switch {
+		case job.Action == models.BulkTag && !current[tag.ID]:
+			if err := s.tagRepo.AddToConversation(ctx, conv.ID, tag.ID, &job.CreatedBy); err != nil {
+				return changed, fmt.Errorf("add tag: %w", err)
+			}
+			conv.Tags = append(conv.Tags, tag)
+			changed = true
+
+		case job.Action == models.BulkUntag && current[tag.ID]:
+			if err := s.tagRepo.RemoveFromConversation(ctx, conv.ID, tag.ID); err != nil {
+				return changed, fmt.Errorf("remove tag: %w", err)
+			}
+			conv.Tags = removeTag(conv.Tags, tag.ID)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// removeTag bỏ tag khỏi danh sách
+func removeTag(tags []models.Tag, id uuid.UUID) []models.Tag {
+	kept := tags[:0]
+	for _, tag := range tags {
+		if tag.ID != id {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// ===========================================================================
+// Realtime
+// ===========================================================================
+
+// publishUpdate gửi conversation_update cho hội thoại vừa thay đổi
+func (s *bulkService) publishUpdate(job *models.BulkJob, conv *models.Conversation) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.ConversationEvent{
+		ConversationID: conv.ID,
+		Status:         string(conv.Status),
+		Priority:       string(conv.Priority),
+	}
+	if conv.AssignedTo != nil {
+		event.AssignedTo = conv.AssignedTo.String()
+	}
+	if job.Action == models.BulkTag || job.Action == models.BulkUntag {
+		event.Tags = make([]string, 0, len(conv.Tags))
+		for _, tag := range conv.Tags {
+			event.Tags = append(event.Tags, tag.Name)
+		}
+	}
+	go func() {
+		if err := s.publisher.PublishConversationUpdate(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish bulk conversation update", zap.Error(err))
+		}
+	}()
+}
+
+// publishProgress gửi tiến độ job cho dashboard
+func (s *bulkService) publishProgress(job *models.BulkJob) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.BulkJobEvent{
+		JobID:     job.ID,
+		Action:    string(job.Action),
+		Status:    string(job.Status),
+		Total:     job.Total,
+		Processed: job.Processed,
+		Succeeded: job.Succeeded,
+		Skipped:   job.Skipped,
+		Failed:    job.Failed,
+	}
+	go func() {
+		if err := s.publisher.PublishBulkJob(job.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish bulk job progress", zap.Error(err))
+		}
+	}()
+}
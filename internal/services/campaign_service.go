@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Campaign Service Interface
+// Chiến dịch gửi tin cho nhiều khách (khuyến mãi, thông báo sự cố, ...)
+// Admin soạn nội dung và tệp khách, launch để gửi ngay hoặc hẹn giờ,
+// job nền gửi có giới hạn tốc độ theo kênh và tuân thủ cửa sổ nhắn tin của channel
+// ===========================================================================
+
+// CampaignVariables các biến template hỗ trợ trong nội dung chiến dịch
+var CampaignVariables = []string{
+	"contact.name",
+	"contact.email",
+	"contact.phone",
+	"workspace.name",
+}
+
+// CampaignInput dữ liệu tạo chiến dịch
+type CampaignInput struct {
+	Name          string
+	Content       string
+	Audience      models.CampaignAudience
+	MessageTag    string
+	RatePerMinute int
+}
+
+// UpdateCampaignInput dữ liệu sửa chiến dịch (nil = giữ nguyên)
+type UpdateCampaignInput struct {
+	Name          *string
+	Content       *string
+	Audience      *models.CampaignAudience
+	MessageTag    *string
+	RatePerMinute *int
+}
+
+// ChannelAudience số khách của một kênh trong tệp khách
+type ChannelAudience struct {
+	ChannelAccountID uuid.UUID `json:"channel_account_id"`
+	ChannelType      string    `json:"channel_type"`
+	Name             string    `json:"name"`
+	Total            int       `json:"total"`
+
+	// InWindow khách còn trong cửa sổ nhắn tin (gửi tự do)
+	InWindow int `json:"in_window"`
+
+	// Tagged khách ngoài cửa sổ gửi được nhờ message tag
+	Tagged int `json:"tagged"`
+
+	// OutsideWindow khách ngoài cửa sổ sẽ bị bỏ qua
+	OutsideWindow int `json:"outside_window"`
+}
+
+// AudiencePreview ước tính tệp khách tại thời điểm xem trước
+// Cửa sổ nhắn tin được kiểm tra lại lúc gửi từng khách
+type AudiencePreview struct {
+	Total         int               `json:"total"`
+	Sendable      int               `json:"sendable"`
+	OutsideWindow int               `json:"outside_window"`
+	Channels      []ChannelAudience `json:"channels"`
+
+	// MaxRecipients giới hạn người nhận, Exceeded tệp khách vượt giới hạn (không launch được)
+	MaxRecipients int  `json:"max_recipients"`
+	Exceeded      bool `json:"exceeded"`
+}
+
+// CampaignService interface cho chiến dịch gửi tin
+type CampaignService interface {
+	// List lấy các chiến dịch gần nhất của workspace (status rỗng = tất cả)
+	List(ctx context.Context, actor Actor, status models.CampaignStatus) ([]models.Campaign, error)
+
+	// Get lấy chiến dịch kèm tiến độ
+	Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.Campaign, error)
+
+	// Create tạo chiến dịch nháp (chỉ admin)
+	Create(ctx context.Context, actor Actor, input CampaignInput) (*models.Campaign, error)
+
+	// Update sửa chiến dịch nháp (chỉ admin)
+	Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateCampaignInput) (*models.Campaign, error)
+
+	// Delete xóa chiến dịch nháp (chỉ admin)
+	Delete(ctx context.Context, actor Actor, id uuid.UUID) error
+
+	// Preview ước tính số khách nhận được tin theo tệp khách và message tag
+	Preview(ctx context.Context, actor Actor, audience models.CampaignAudience, messageTag string) (*AudiencePreview, error)
+
+	// Launch lên lịch gửi chiến dịch nháp: scheduledAt nil = gửi ngay (chỉ admin)
+	Launch(ctx context.Context, actor Actor, id uuid.UUID, scheduledAt *time.Time) (*models.Campaign, error)
+
+	// Cancel hủy chiến dịch đã lên lịch hoặc đang gửi, khách chưa gửi sẽ không nhận tin (chỉ admin)
+	Cancel(ctx context.Context, actor Actor, id uuid.UUID) (*models.Campaign, error)
+
+	// Recipients kết quả gửi theo từng khách (phân trang)
+	Recipients(ctx context.Context, actor Actor, id uuid.UUID, status models.RecipientStatus, page, limit int) ([]models.CampaignRecipient, int64, error)
+
+	// RunActive bắt đầu các chiến dịch đến giờ và gửi tiếp các chiến dịch đang chạy (chạy định kỳ)
+	RunActive(ctx context.Context) error
+}
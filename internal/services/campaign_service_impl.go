@@ -0,0 +1,811 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chatbox-gin/internal/channel"
+	"chatbox-gin/internal/config"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/template"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Campaign Service Implementation
+// ===========================================================================
+
+const (
+	// campaignHistory số chiến dịch gần nhất trả về trong danh sách
+	campaignHistory = 50
+
+	// maxCampaignContentLength độ dài nội dung tối đa (giống gửi tin nhắn thường)
+	maxCampaignContentLength = 5000
+
+	// maxCampaignScheduleAhead thời gian hẹn giờ trước tối đa
+	maxCampaignScheduleAhead = 90 * 24 * time.Hour
+
+	// campaignSendingStaleAfter người nhận ở trạng thái sending quá lâu coi như worker đã dừng
+	campaignSendingStaleAfter = 5 * time.Minute
+)
+
+// campaignExcludedTags message tag không dùng cho chiến dịch
+// HUMAN_AGENT chỉ dành cho agent trả lời từng khách, không dùng gửi hàng loạt
+var campaignExcludedTags = map[string]bool{
+	channel.FBTagHumanAgent: true,
+}
+
+// campaignChannel thông tin kênh dùng khi gửi (cache trong một lượt chạy)
+type campaignChannel struct {
+	account      *models.ChannelAccount
+	capabilities channel.Capabilities
+}
+
+// campaignService triển khai CampaignService
+type campaignService struct {
+	campaignRepo       repositories.CampaignRepository
+	conversationRepo   repositories.ConversationRepository
+	participantRepo    repositories.ParticipantRepository
+	channelAccountRepo repositories.ChannelAccountRepository
+	workspaceRepo      repositories.WorkspaceRepository
+	channelRegistry    *channel.Registry
+	outboundService    OutboundService
+	publisher          realtime.Publisher
+	cfg                config.CampaignConfig
+	logger             *zap.Logger
+}
+
+// NewCampaignService tạo instance mới của CampaignService
+func NewCampaignService(
+	campaignRepo repositories.CampaignRepository,
+	conversationRepo repositories.ConversationRepository,
+	participantRepo repositories.ParticipantRepository,
+	channelAccountRepo repositories.ChannelAccountRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	channelRegistry *channel.Registry,
+	outboundService OutboundService,
+	publisher realtime.Publisher,
+	cfg config.CampaignConfig,
+	logger *zap.Logger,
+) CampaignService {
+	return &campaignService{
+		campaignRepo:       campaignRepo,
+		conversationRepo:   conversationRepo,
+		participantRepo:    participantRepo,
+		channelAccountRepo: channelAccountRepo,
+		workspaceRepo:      workspaceRepo,
+		channelRegistry:    channelRegistry,
+		outboundService:    outboundService,
+		publisher:          publisher,
+		cfg:                cfg,
+		logger:             logger,
+	}
+}
+
+// ===========================================================================
+// Quản lý chiến dịch
+// ===========================================================================
+
+// List lấy các chiến dịch gần nhất của workspace
+func (s *campaignService) List(ctx context.Context, actor Actor, status models.CampaignStatus) ([]models.Campaign, error) {
+	campaigns, err := s.campaignRepo.FindByWorkspace(ctx, actor.WorkspaceID, status, campaignHistory)
+	if err != nil {
+		return nil, fmt.Errorf("find campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+// Get lấy chiến dịch kèm tiến độ
+func (s *campaignService) Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.Campaign, error) {
+	return s.loadCampaign(ctx, actor, id)
+}
+
+// Create tạo chiến dịch nháp
+func (s *campaignService) Create(ctx context.Context, actor Actor, input CampaignInput) (*models.Campaign, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được tạo chiến dịch")
+	}
+
+	campaign := &models.Campaign{
+		WorkspaceID:   actor.WorkspaceID,
+		CreatedBy:     actor.UserID,
+		Name:          strings.TrimSpace(input.Name),
+		Content:       strings.TrimSpace(input.Content),
+		Audience:      input.Audience,
+		MessageTag:    strings.TrimSpace(input.MessageTag),
+		RatePerMinute: input.RatePerMinute,
+		Status:        models.CampaignDraft,
+	}
+	if campaign.RatePerMinute == 0 {
+		campaign.RatePerMinute = s.cfg.DefaultRatePerMinute
+	}
+	if err := s.validate(ctx, actor, campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("create campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// Update sửa chiến dịch nháp
+func (s *campaignService) Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateCampaignInput) (*models.Campaign, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được sửa chiến dịch")
+	}
+	campaign, err := s.loadCampaign(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !campaign.IsDraft() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Chỉ sửa được chiến dịch chưa launch")
+	}
+
+	if input.Name != nil {
+		campaign.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Content != nil {
+		campaign.Content = strings.TrimSpace(*input.Content)
+	}
+	if input.Audience != nil {
+		campaign.Audience = *input.Audience
+	}
+	if input.MessageTag != nil {
+		campaign.MessageTag = strings.TrimSpace(*input.MessageTag)
+	}
+	if input.RatePerMinute != nil {
+		campaign.RatePerMinute = *input.RatePerMinute
+		if campaign.RatePerMinute == 0 {
+			campaign.RatePerMinute = s.cfg.DefaultRatePerMinute
+		}
+	}
+	if err := s.validate(ctx, actor, campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("update campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// Delete xóa chiến dịch nháp
+func (s *campaignService) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	if !actor.IsAdmin() {
+		return apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được xóa chiến dịch")
+	}
+	campaign, err := s.loadCampaign(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if !campaign.IsDraft() {
+		return apperrors.New(apperrors.ErrInvalidInput, "Chỉ xóa được chiến dịch chưa launch, chiến dịch đang chạy thì hủy")
+	}
+
+	if err := s.campaignRepo.Delete(ctx, campaign.ID); err != nil {
+		return fmt.Errorf("delete campaign: %w", err)
+	}
+	return nil
+}
+
+// Preview ước tính số khách nhận được tin
+func (s *campaignService) Preview(ctx context.Context, actor Actor, audience models.CampaignAudience, messageTag string) (*AudiencePreview, error) {
+	messageTag = strings.TrimSpace(messageTag)
+	if err := s.validateAudience(ctx, actor, audience); err != nil {
+		return nil, err
+	}
+	if err := s.validateMessageTag(messageTag); err != nil {
+		return nil, err
+	}
+
+	members, err := s.campaignRepo.FindAudience(ctx, actor.WorkspaceID, audience, s.cfg.MaxRecipients+1)
+	if err != nil {
+		return nil, fmt.Errorf("find audience: %w", err)
+	}
+
+	preview := &AudiencePreview{
+		MaxRecipients: s.cfg.MaxRecipients,
+		Exceeded:      len(members) > s.cfg.MaxRecipients,
+		Channels:      []ChannelAudience{},
+	}
+	if preview.Exceeded {
+		members = members[:s.cfg.MaxRecipients]
+	}
+
+	now := time.Now()
+	channels := make(map[uuid.UUID]*campaignChannel)
+	byChannel := make(map[uuid.UUID]*ChannelAudience)
+	var order []uuid.UUID
+	for _, member := range members {
+		stats, ok := byChannel[member.ChannelAccountID]
+		if !ok {
+			stats = &ChannelAudience{ChannelAccountID: member.ChannelAccountID}
+			if ch, err := s.resolveChannel(ctx, channels, member.ChannelAccountID); err == nil {
+				stats.ChannelType = string(ch.account.ChannelType)
+				stats.Name = ch.account.Name
+			}
+			byChannel[member.ChannelAccountID] = stats
+			order = append(order, member.ChannelAccountID)
+		}
+		stats.Total++
+
+		ch := channels[member.ChannelAccountID]
+		switch {
+		case ch == nil:
+			stats.OutsideWindow++
+		case ch.capabilities.InMessagingWindow(member.LastInboundAt, now):
+			stats.InWindow++
+		case messageTag != "" && ch.capabilities.SupportsMessageTag(messageTag):
+			stats.Tagged++
+		default:
+			stats.OutsideWindow++
+		}
+	}
+
+	for _, id := range order {
+		stats := byChannel[id]
+		preview.Total += stats.Total
+		preview.Sendable += stats.InWindow + stats.Tagged
+		preview.OutsideWindow += stats.OutsideWindow
+		preview.Channels = append(preview.Channels, *stats)
+	}
+	return preview, nil
+}
+
+// Launch lên lịch gửi chiến dịch nháp
+func (s *campaignService) Launch(ctx context.Context, actor Actor, id uuid.UUID, scheduledAt *time.Time) (*models.Campaign, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được launch chiến dịch")
+	}
+	campaign, err := s.loadCampaign(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !campaign.IsDraft() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Chiến dịch đã được launch")
+	}
+	if scheduledAt != nil {
+		now := time.Now()
+		if !scheduledAt.After(now) {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "Thời điểm gửi phải ở tương lai")
+		}
+		if scheduledAt.Sub(now) > maxCampaignScheduleAhead {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "Chỉ được hẹn giờ gửi trước tối đa 90 ngày")
+		}
+	}
+	if err := s.validate(ctx, actor, campaign); err != nil {
+		return nil, err
+	}
+
+	// Kiểm tra sớm tệp khách, danh sách người nhận được chốt lại lúc bắt đầu gửi
+	members, err := s.campaignRepo.FindAudience(ctx, actor.WorkspaceID, campaign.Audience, s.cfg.MaxRecipients+1)
+	if err != nil {
+		return nil, fmt.Errorf("find audience: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Không có khách nào khớp điều kiện")
+	}
+	if len(members) > s.cfg.MaxRecipients {
+		return nil, apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("Tệp khách vượt quá %d người, hãy thu hẹp điều kiện", s.cfg.MaxRecipients))
+	}
+
+	campaign.Status = models.CampaignScheduled
+	campaign.ScheduledAt = scheduledAt
+	ok, err := s.campaignRepo.Transition(ctx, campaign, models.CampaignDraft)
+	if err != nil {
+		return nil, fmt.Errorf("launch campaign: %w", err)
+	}
+	if !ok {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Chiến dịch đã được launch")
+	}
+
+	s.logger.Info("campaign launched",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+		zap.Int("audience", len(members)),
+	)
+	s.publishProgress(campaign)
+	return campaign, nil
+}
+
+// Cancel hủy chiến dịch đã lên lịch hoặc đang gửi
+func (s *campaignService) Cancel(ctx context.Context, actor Actor, id uuid.UUID) (*models.Campaign, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được hủy chiến dịch")
+	}
+	campaign, err := s.loadCampaign(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.CampaignScheduled && campaign.Status != models.CampaignRunning {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Chỉ hủy được chiến dịch đã lên lịch hoặc đang gửi")
+	}
+
+	from := campaign.Status
+	now := time.Now()
+	cancelledBy := actor.UserID
+	campaign.Status = models.CampaignCancelled
+	campaign.FinishedAt = &now
+	campaign.CancelledBy = &cancelledBy
+	ok, err := s.campaignRepo.Transition(ctx, campaign, from)
+	if err != nil {
+		return nil, fmt.Errorf("cancel campaign: %w", err)
+	}
+	if !ok {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Chiến dịch vừa đổi trạng thái, hãy tải lại")
+	}
+
+	// Người nhận đang gửi dở vẫn được worker ghi nhận kết quả
+	if _, err := s.campaignRepo.CancelPendingRecipients(ctx, campaign.ID); err != nil {
+		return nil, fmt.Errorf("cancel campaign recipients: %w", err)
+	}
+	if _, err := s.refreshProgress(ctx, campaign); err != nil {
+		s.logger.Warn("failed to update cancelled campaign progress",
+			zap.String("campaign_id", campaign.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	s.logger.Info("campaign cancelled",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+	)
+	s.publishProgress(campaign)
+	return campaign, nil
+}
+
+// Recipients kết quả gửi theo từng khách
+func (s *campaignService) Recipients(ctx context.Context, actor Actor, id uuid.UUID, status models.RecipientStatus, page, limit int) ([]models.CampaignRecipient, int64, error) {
+	campaign, err := s.loadCampaign(ctx, actor, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	recipients, total, err := s.campaignRepo.FindRecipients(ctx, campaign.ID, status, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find campaign recipients: %w", err)
+	}
+	return recipients, total, nil
+}
+
+// ===========================================================================
+// Gửi nền
+// ===========================================================================
+
+// RunActive bắt đầu các chiến dịch đến giờ và gửi tiếp các chiến dịch đang chạy
+func (s *campaignService) RunActive(ctx context.Context) error {
+	campaigns, err := s.campaignRepo.FindActive(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("find active campaigns: %w", err)
+	}
+
+	channels := make(map[uuid.UUID]*campaignChannel)
+	for i := range campaigns {
+		campaign := &campaigns[i]
+		if campaign.Status == models.CampaignScheduled {
+			started, err := s.start(ctx, campaign)
+			if err != nil {
+				s.logger.Error("failed to start campaign",
+					zap.String("campaign_id", campaign.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			if !started {
+				continue
+			}
+		}
+
+		if err := s.process(ctx, campaign, channels); err != nil {
+			s.logger.Error("failed to process campaign",
+				zap.String("campaign_id", campaign.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// start chốt danh sách người nhận và chuyển chiến dịch sang running
+// Trả về false nếu chiến dịch đã bị hủy hoặc dừng vì lỗi
+func (s *campaignService) start(ctx context.Context, campaign *models.Campaign) (bool, error) {
+	members, err := s.campaignRepo.FindAudience(ctx, campaign.WorkspaceID, campaign.Audience, s.cfg.MaxRecipients+1)
+	if err != nil {
+		return false, fmt.Errorf("find audience: %w", err)
+	}
+	if len(members) > s.cfg.MaxRecipients {
+		return false, s.fail(ctx, campaign, fmt.Sprintf("Tệp khách vượt quá %d người", s.cfg.MaxRecipients))
+	}
+
+	recipients := make([]models.CampaignRecipient, len(members))
+	for i, member := range members {
+		recipients[i] = models.CampaignRecipient{
+			CampaignID:       campaign.ID,
+			ParticipantID:    member.ParticipantID,
+			ChannelAccountID: member.ChannelAccountID,
+			Status:           models.RecipientPending,
+		}
+	}
+	// Người nhận đã lưu ở lần chạy trước (restart giữa chừng) được bỏ qua
+	if err := s.campaignRepo.CreateRecipients(ctx, recipients); err != nil {
+		return false, fmt.Errorf("create campaign recipients: %w", err)
+	}
+
+	now := time.Now()
+	campaign.Status = models.CampaignRunning
+	campaign.Total = len(recipients)
+	campaign.StartedAt = &now
+	ok, err := s.campaignRepo.Transition(ctx, campaign, models.CampaignScheduled)
+	if err != nil {
+		return false, fmt.Errorf("start campaign: %w", err)
+	}
+	if !ok {
+		// Bị hủy trong lúc chốt danh sách
+		if _, err := s.campaignRepo.CancelPendingRecipients(ctx, campaign.ID); err != nil {
+			return false, fmt.Errorf("cancel campaign recipients: %w", err)
+		}
+		return false, nil
+	}
+
+	s.logger.Info("campaign started",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.Int("recipients", campaign.Total),
+	)
+	return true, nil
+}
+
+// process gửi cho các người nhận đang chờ trong giới hạn tốc độ của từng kênh
+func (s *campaignService) process(ctx context.Context, campaign *models.Campaign, channels map[uuid.UUID]*campaignChannel) error {
+	now := time.Now()
+	if _, err := s.campaignRepo.FailStaleRecipients(ctx, campaign.ID, now.Add(-campaignSendingStaleAfter)); err != nil {
+		return fmt.Errorf("fail stale recipients: %w", err)
+	}
+
+	channelIDs, err := s.campaignRepo.PendingChannels(ctx, campaign.ID)
+	if err != nil {
+		return fmt.Errorf("find pending channels: %w", err)
+	}
+
+	vars := template.Vars{}
+	if workspace, err := s.workspaceRepo.FindByID(ctx, campaign.WorkspaceID); err == nil {
+		vars["workspace.name"] = workspace.Name
+	}
+
+	for _, channelAccountID := range channelIDs {
+		// Giới hạn tốc độ: số người nhận bắt đầu gửi trong một phút gần nhất
+		attempts, err := s.campaignRepo.CountAttempts(ctx, campaign.ID, channelAccountID, time.Now().Add(-time.Minute))
+		if err != nil {
+			return fmt.Errorf("count attempts: %w", err)
+		}
+		budget := campaign.RatePerMinute - int(attempts)
+		if budget <= 0 {
+			continue
+		}
+
+		recipients, err := s.campaignRepo.FindPendingRecipients(ctx, campaign.ID, channelAccountID, budget)
+		if err != nil {
+			return fmt.Errorf("find pending recipients: %w", err)
+		}
+		ch, chErr := s.resolveChannel(ctx, channels, channelAccountID)
+		for i := range recipients {
+			if err := s.sendRecipient(ctx, campaign, &recipients[i], ch, chErr, vars); err != nil {
+				s.logger.Warn("failed to save campaign recipient",
+					zap.String("campaign_id", campaign.ID.String()),
+					zap.String("participant_id", recipients[i].ParticipantID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	remaining, err := s.refreshProgress(ctx, campaign)
+	if err != nil {
+		return fmt.Errorf("update campaign progress: %w", err)
+	}
+	if remaining == 0 {
+		finishedAt := time.Now()
+		campaign.Status = models.CampaignCompleted
+		campaign.FinishedAt = &finishedAt
+		ok, err := s.campaignRepo.Transition(ctx, campaign, models.CampaignRunning)
+		if err != nil {
+			return fmt.Errorf("complete campaign: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		s.logger.Info("campaign completed",
+			zap.String("campaign_id", campaign.ID.String()),
+			zap.Int("sent", campaign.Sent),
+			zap.Int("failed", campaign.Failed),
+			zap.Int("skipped", campaign.Skipped),
+		)
+	}
+
+	s.publishProgress(campaign)
+	return nil
+}
+
+// sendRecipient gửi tin cho một khách và lưu kết quả
+// Lỗi trả về chỉ là lỗi lưu kết quả, lỗi gửi được ghi vào người nhận
+func (s *campaignService) sendRecipient(ctx context.Context, campaign *models.Campaign, recipient *models.CampaignRecipient, ch *campaignChannel, chErr error, vars template.Vars) error {
+	claimed, err := s.campaignRepo.ClaimRecipient(ctx, recipient)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if chErr != nil {
+		return s.failRecipient(ctx, recipient, chErr)
+	}
+
+	conv, err := s.conversationRepo.FindLatestByParticipant(ctx, recipient.ParticipantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.skipRecipient(ctx, recipient, models.SkipNoConversation)
+		}
+		return s.failRecipient(ctx, recipient, fmt.Errorf("find conversation: %w", err))
+	}
+	conversationID := conv.ID
+	recipient.ConversationID = &conversationID
+
+	participant, err := s.participantRepo.FindByID(ctx, recipient.ParticipantID)
+	if err != nil {
+		return s.failRecipient(ctx, recipient, fmt.Errorf("find participant: %w", err))
+	}
+	conv.Participant = *participant
+	conv.ChannelAccount = *ch.account
+
+	// Ngoài cửa sổ nhắn tin: chỉ gửi khi có message tag channel chấp nhận
+	messageTag := ""
+	if !ch.capabilities.InMessagingWindow(conv.CustomerLastInboundAt(), time.Now()) {
+		if campaign.MessageTag == "" || !ch.capabilities.SupportsMessageTag(campaign.MessageTag) {
+			return s.skipRecipient(ctx, recipient, models.SkipOutsideWindow)
+		}
+		messageTag = campaign.MessageTag
+	}
+
+	senderID := campaign.CreatedBy
+	campaignID := campaign.ID
+	message, sendErr := s.outboundService.Send(ctx, OutboundInput{
+		Conversation: conv,
+		SenderType:   models.SenderAgent,
+		SenderID:     &senderID,
+		Content:      template.Render(campaign.Content, contactVars(participant, vars)),
+		CampaignID:   &campaignID,
+		MessageTag:   messageTag,
+	})
+	if message != nil {
+		messageID := message.ID
+		recipient.MessageID = &messageID
+	}
+	recipient.MessageTag = messageTag
+	if sendErr != nil {
+		return s.failRecipient(ctx, recipient, sendErr)
+	}
+
+	now := time.Now()
+	recipient.Status = models.RecipientSent
+	recipient.SentAt = &now
+	return s.campaignRepo.UpdateRecipient(ctx, recipient)
+}
+
+// skipRecipient đánh dấu bỏ qua người nhận
+func (s *campaignService) skipRecipient(ctx context.Context, recipient *models.CampaignRecipient, reason string) error {
+	recipient.Status = models.RecipientSkipped
+	recipient.Reason = reason
+	return s.campaignRepo.UpdateRecipient(ctx, recipient)
+}
+
+// failRecipient đánh dấu gửi lỗi
+func (s *campaignService) failRecipient(ctx context.Context, recipient *models.CampaignRecipient, cause error) error {
+	recipient.Status = models.RecipientFailed
+	recipient.Error = cause.Error()
+	return s.campaignRepo.UpdateRecipient(ctx, recipient)
+}
+
+// fail dừng chiến dịch vì lỗi không gửi tiếp được
+func (s *campaignService) fail(ctx context.Context, campaign *models.Campaign, reason string) error {
+	from := campaign.Status
+	now := time.Now()
+	campaign.Status = models.CampaignFailed
+	campaign.Error = reason
+	campaign.FinishedAt = &now
+	if _, err := s.campaignRepo.Transition(ctx, campaign, from); err != nil {
+		return fmt.Errorf("fail campaign: %w", err)
+	}
+	s.logger.Warn("campaign failed",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.String("reason", reason),
+	)
+	s.publishProgress(campaign)
+	return nil
+}
+
+// refreshProgress đếm lại kết quả từ danh sách người nhận và lưu vào chiến dịch
+// Trả về số người nhận chưa xử lý xong (pending, sending)
+func (s *campaignService) refreshProgress(ctx context.Context, campaign *models.Campaign) (int, error) {
+	counts, err := s.campaignRepo.CountRecipientsByStatus(ctx, campaign.ID)
+	if err != nil {
+		return 0, err
+	}
+	campaign.Sent = counts[models.RecipientSent]
+	campaign.Failed = counts[models.RecipientFailed]
+	campaign.Skipped = counts[models.RecipientSkipped]
+	campaign.Cancelled = counts[models.RecipientCancelled]
+	remaining := counts[models.RecipientPending] + counts[models.RecipientSending]
+	campaign.Total = campaign.Processed() + remaining
+	return remaining, s.campaignRepo.UpdateProgress(ctx, campaign)
+}
+
+// resolveChannel lấy channel account và capabilities của kênh (có cache)
+func (s *campaignService) resolveChannel(ctx context.Context, cache map[uuid.UUID]*campaignChannel, channelAccountID uuid.UUID) (*campaignChannel, error) {
+	if ch, ok := cache[channelAccountID]; ok {
+		return ch, nil
+	}
+
+	account, err := s.channelAccountRepo.FindByID(ctx, channelAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("find channel account: %w", err)
+	}
+	adapter, err := s.channelRegistry.Get(string(account.ChannelType))
+	if err != nil {
+		return nil, fmt.Errorf("channel %s not registered", account.ChannelType)
+	}
+
+	ch := &campaignChannel{account: account, capabilities: adapter.Capabilities()}
+	cache[channelAccountID] = ch
+	return ch, nil
+}
+
+// contactVars biến template của một khách (kèm biến chung của chiến dịch)
+func contactVars(participant *models.Participant, common template.Vars) template.Vars {
+	vars := template.ContactVars(participant)
+	for name, value := range common {
+		vars[name] = value
+	}
+	return vars
+}
+
+// ===========================================================================
+// Validation
+// ===========================================================================
+
+// validate kiểm tra nội dung, tệp khách, message tag và tốc độ gửi
+func (s *campaignService) validate(ctx context.Context, actor Actor, campaign *models.Campaign) error {
+	if campaign.Name == "" {
+		return apperrors.New(apperrors.ErrInvalidInput, "Tên chiến dịch không được để trống")
+	}
+	if len([]rune(campaign.Name)) > 255 {
+		return apperrors.New(apperrors.ErrInvalidInput, "Tên chiến dịch quá dài")
+	}
+	if campaign.Content == "" {
+		return apperrors.New(apperrors.ErrInvalidInput, "Nội dung tin nhắn không được để trống")
+	}
+	if len([]rune(campaign.Content)) > maxCampaignContentLength {
+		return apperrors.New(apperrors.ErrInvalidInput, "Nội dung tin nhắn quá dài")
+	}
+	for _, name := range template.Names(campaign.Content) {
+		if !isCampaignVariable(name) {
+			return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Biến {{%s}} không được hỗ trợ", name))
+		}
+	}
+	if campaign.RatePerMinute < 1 || campaign.RatePerMinute > s.cfg.MaxRatePerMinute {
+		return apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("Tốc độ gửi phải từ 1 đến %d tin mỗi phút", s.cfg.MaxRatePerMinute))
+	}
+	if err := s.validateMessageTag(campaign.MessageTag); err != nil {
+		return err
+	}
+	return s.validateAudience(ctx, actor, campaign.Audience)
+}
+
+// validateAudience kiểm tra điều kiện tệp khách
+func (s *campaignService) validateAudience(ctx context.Context, actor Actor, audience models.CampaignAudience) error {
+	for _, id := range audience.ChannelAccountIDs {
+		account, err := s.channelAccountRepo.FindByID(ctx, id)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("find channel account: %w", err)
+		}
+		if err != nil || account.WorkspaceID != actor.WorkspaceID {
+			return apperrors.New(apperrors.ErrInvalidInput, "Kênh "+id.String()+" không thuộc workspace")
+		}
+	}
+	for _, tag := range audience.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return apperrors.New(apperrors.ErrInvalidInput, "Tag không được để trống")
+		}
+	}
+	for key := range audience.CustomFields {
+		if strings.TrimSpace(key) == "" {
+			return apperrors.New(apperrors.ErrInvalidInput, "Tên trường tùy chỉnh không được để trống")
+		}
+	}
+	if audience.LastSeenWithinDays < 0 || audience.LastSeenBeforeDays < 0 {
+		return apperrors.New(apperrors.ErrInvalidInput, "Số ngày không hợp lệ")
+	}
+	if audience.LastSeenWithinDays > 0 && audience.LastSeenBeforeDays > 0 &&
+		audience.LastSeenWithinDays <= audience.LastSeenBeforeDays {
+		return apperrors.New(apperrors.ErrInvalidInput, "last_seen_within_days phải lớn hơn last_seen_before_days")
+	}
+	return nil
+}
+
+// validateMessageTag kiểm tra message tag có channel nào hỗ trợ và được dùng cho chiến dịch
+func (s *campaignService) validateMessageTag(tag string) error {
+	if tag == "" {
+		return nil
+	}
+	if campaignExcludedTags[tag] {
+		return apperrors.New(apperrors.ErrInvalidInput, "Message tag "+tag+" chỉ dùng cho agent trả lời, không dùng cho chiến dịch")
+	}
+	for _, channelType := range s.channelRegistry.GetAll() {
+		adapter, err := s.channelRegistry.Get(channelType)
+		if err == nil && adapter.Capabilities().SupportsMessageTag(tag) {
+			return nil
+		}
+	}
+	return apperrors.New(apperrors.ErrInvalidInput, "Message tag "+tag+" không được hỗ trợ")
+}
+
+// isCampaignVariable kiểm tra tên biến có trong CampaignVariables hoặc là trường tùy chỉnh của khách
+func isCampaignVariable(name string) bool {
+	if template.IsFieldVariable(name) {
+		return true
+	}
+	for _, v := range CampaignVariables {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// loadCampaign tải chiến dịch và kiểm tra thuộc workspace của actor
+func (s *campaignService) loadCampaign(ctx context.Context, actor Actor, id uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy chiến dịch")
+		}
+		return nil, fmt.Errorf("find campaign: %w", err)
+	}
+	if campaign.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy chiến dịch")
+	}
+	return campaign, nil
+}
+
+// publishProgress gửi tiến độ chiến dịch cho dashboard
+func (s *campaignService) publishProgress(campaign *models.Campaign) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.CampaignEvent{
+		CampaignID: campaign.ID,
+		Status:     string(campaign.Status),
+		Total:      campaign.Total,
+		Sent:       campaign.Sent,
+		Failed:     campaign.Failed,
+		Skipped:    campaign.Skipped,
+		Cancelled:  campaign.Cancelled,
+	}
+	go func() {
+		if err := s.publisher.PublishCampaign(campaign.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish campaign progress", zap.Error(err))
+		}
+	}()
+}
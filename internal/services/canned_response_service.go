@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Canned Response Service Interface
+// Thư viện câu trả lời soạn sẵn: CRUD, tìm theo shortcut cho composer,
+// thay biến template theo hội thoại khi gửi
+// ===========================================================================
+
+// CannedVariables các biến template hỗ trợ trong nội dung câu trả lời
+var CannedVariables = []string{
+	"contact.name",
+	"contact.email",
+	"contact.phone",
+	"agent.name",
+	"agent.email",
+	"workspace.name",
+	"conversation.id",
+}
+
+// CreateCannedResponseInput dữ liệu tạo câu trả lời
+type CreateCannedResponseInput struct {
+	Title      string
+	Shortcut   string
+	Body       string
+	Visibility models.CannedVisibility
+	// QuickReplies các nút trả lời nhanh gửi kèm
+	QuickReplies []models.QuickReply
+}
+
+// UpdateCannedResponseInput dữ liệu sửa câu trả lời (nil = không đổi)
+type UpdateCannedResponseInput struct {
+	Title        *string
+	Shortcut     *string
+	Body         *string
+	Visibility   *models.CannedVisibility
+	QuickReplies *[]models.QuickReply
+}
+
+// ListCannedResponsesInput điều kiện tìm câu trả lời
+type ListCannedResponsesInput struct {
+	// Query bắt đầu bằng "/" thì chỉ tìm theo tiền tố shortcut (composer),
+	// ngược lại tìm theo tiền tố shortcut hoặc chứa trong title
+	Query string
+
+	// Visibility lọc theo phạm vi (rỗng = tất cả)
+	Visibility models.CannedVisibility
+
+	// Limit số kết quả tối đa
+	Limit int
+}
+
+// CannedExpansion nội dung câu trả lời đã thay biến theo hội thoại
+type CannedExpansion struct {
+	CannedResponse *models.CannedResponse `json:"canned_response"`
+	Content        string                 `json:"content"`
+	Attachments    models.Attachments     `json:"attachments"`
+	QuickReplies   []models.QuickReply    `json:"quick_replies"`
+}
+
+// CannedResponseService interface cho câu trả lời soạn sẵn
+type CannedResponseService interface {
+	// List lấy câu trả lời actor thấy được (shared và personal của actor)
+	List(ctx context.Context, actor Actor, input ListCannedResponsesInput) ([]models.CannedResponse, error)
+
+	// Get lấy chi tiết câu trả lời
+	Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.CannedResponse, error)
+
+	// Create tạo câu trả lời mới (shared chỉ admin được tạo)
+	Create(ctx context.Context, actor Actor, input CreateCannedResponseInput) (*models.CannedResponse, error)
+
+	// Update sửa câu trả lời (personal: người tạo, shared: admin)
+	Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateCannedResponseInput) (*models.CannedResponse, error)
+
+	// Delete xóa câu trả lời (personal: người tạo, shared: admin)
+	Delete(ctx context.Context, actor Actor, id uuid.UUID) error
+
+	// AddAttachments lưu file vào storage và thêm vào danh sách đính kèm
+	AddAttachments(ctx context.Context, actor Actor, id uuid.UUID, files []UploadFile) (*models.CannedResponse, error)
+
+	// RemoveAttachment bỏ file đính kèm khỏi câu trả lời
+	// File vẫn giữ trong storage vì các tin nhắn đã gửi còn tham chiếu
+	RemoveAttachment(ctx context.Context, actor Actor, id, mediaID uuid.UUID) (*models.CannedResponse, error)
+
+	// Expand thay biến template theo hội thoại (không tăng số lần dùng)
+	Expand(ctx context.Context, actor Actor, conv *models.Conversation, id uuid.UUID) (*CannedExpansion, error)
+
+	// RenderText thay biến template trong text bất kỳ theo hội thoại (VD: nội dung trả lời của macro)
+	RenderText(ctx context.Context, actor Actor, conv *models.Conversation, text string) string
+
+	// ValidateText kiểm tra text chỉ dùng các biến template được hỗ trợ
+	ValidateText(text string) error
+
+	// RecordUsage tăng số lần dùng sau khi tin nhắn đã được gửi
+	RecordUsage(ctx context.Context, id uuid.UUID)
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/template"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Canned Response Service Implementation
+// ===========================================================================
+
+const (
+	// defaultCannedLimit, maxCannedLimit số kết quả mặc định/tối đa khi tìm
+	defaultCannedLimit = 20
+	maxCannedLimit     = 200
+
+	// maxCannedAttachments số file đính kèm tối đa của một câu trả lời
+	maxCannedAttachments = 10
+
+	// maxCannedQuickReplies số quick reply tối đa (bằng giới hạn của Facebook)
+	maxCannedQuickReplies = 13
+)
+
+// shortcutPattern shortcut hợp lệ: chữ thường, số, "-" và "_"
+var shortcutPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// cannedResponseService triển khai CannedResponseService
+type cannedResponseService struct {
+	cannedRepo      repositories.CannedResponseRepository
+	participantRepo repositories.ParticipantRepository
+	userRepo        repositories.UserRepository
+	workspaceRepo   repositories.WorkspaceRepository
+	mediaService    MediaService
+	logger          *zap.Logger
+}
+
+// NewCannedResponseService tạo instance mới của CannedResponseService
+func NewCannedResponseService(
+	cannedRepo repositories.CannedResponseRepository,
+	participantRepo repositories.ParticipantRepository,
+	userRepo repositories.UserRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	mediaService MediaService,
+	logger *zap.Logger,
+) CannedResponseService {
+	return &cannedResponseService{
+		cannedRepo:      cannedRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		workspaceRepo:   workspaceRepo,
+		mediaService:    mediaService,
+		logger:          logger,
+	}
+}
+
+// List lấy câu trả lời actor thấy được
+func (s *cannedResponseService) List(ctx context.Context, actor Actor, input ListCannedResponsesInput) ([]models.CannedResponse, error) {
+	filter := repositories.CannedResponseFilter{
+		WorkspaceID: actor.WorkspaceID,
+		UserID:      actor.UserID,
+		Visibility:  input.Visibility,
+		Limit:       input.Limit,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultCannedLimit
+	}
+	if filter.Limit > maxCannedLimit {
+		filter.Limit = maxCannedLimit
+	}
+
+	query := strings.TrimSpace(input.Query)
+	if strings.HasPrefix(query, "/") {
+		filter.Shortcut = normalizeShortcut(query)
+	} else {
+		filter.Query = query
+	}
+
+	responses, err := s.cannedRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list canned responses: %w", err)
+	}
+	return responses, nil
+}
+
+// Get lấy chi tiết câu trả lời
+func (s *cannedResponseService) Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.CannedResponse, error) {
+	return s.load(ctx, actor, id)
+}
+
+// Create tạo câu trả lời mới
+func (s *cannedResponseService) Create(ctx context.Context, actor Actor, input CreateCannedResponseInput) (*models.CannedResponse, error) {
+	response := &models.CannedResponse{
+		WorkspaceID:  actor.WorkspaceID,
+		CreatedBy:    actor.UserID,
+		Visibility:   input.Visibility,
+		Title:        strings.TrimSpace(input.Title),
+		Shortcut:     normalizeShortcut(input.Shortcut),
+		Body:         input.Body,
+		Attachments:  models.Attachments{},
+		QuickReplies: models.QuickReplies(input.QuickReplies),
+	}
+	if response.Visibility == "" {
+		response.Visibility = models.CannedPersonal
+	}
+	if !s.canManage(actor, response) {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được tạo câu trả lời dùng chung")
+	}
+	if err := s.validate(ctx, actor, response); err != nil {
+		return nil, err
+	}
+
+	if err := s.cannedRepo.Create(ctx, response); err != nil {
+		return nil, fmt.Errorf("create canned response: %w", err)
+	}
+
+	s.logger.Info("canned response created",
+		zap.String("canned_response_id", response.ID.String()),
+		zap.String("shortcut", response.Shortcut),
+		zap.String("visibility", string(response.Visibility)),
+	)
+
+	return response, nil
+}
+
+// Update sửa câu trả lời
+func (s *cannedResponseService) Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateCannedResponseInput) (*models.CannedResponse, error) {
+	response, err := s.loadForManage(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		response.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Shortcut != nil {
+		response.Shortcut = normalizeShortcut(*input.Shortcut)
+	}
+	if input.Body != nil {
+		response.Body = *input.Body
+	}
+	if input.QuickReplies != nil {
+		response.QuickReplies = models.QuickReplies(*input.QuickReplies)
+	}
+	if input.Visibility != nil {
+		response.Visibility = *input.Visibility
+		// Chuyển sang shared cần quyền admin, chuyển về personal thì thuộc về người tạo
+		if !s.canManage(actor, response) {
+			return nil, apperrors.New(apperrors.ErrForbidden, "Không có quyền đổi phạm vi câu trả lời này")
+		}
+	}
+	if err := s.validate(ctx, actor, response); err != nil {
+		return nil, err
+	}
+
+	if err := s.cannedRepo.Update(ctx, response); err != nil {
+		return nil, fmt.Errorf("update canned response: %w", err)
+	}
+	return response, nil
+}
+
+// Delete xóa câu trả lời
+func (s *cannedResponseService) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	response, err := s.loadForManage(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cannedRepo.Delete(ctx, response.ID); err != nil {
+		return fmt.Errorf("delete canned response: %w", err)
+	}
+
+	s.logger.Info("canned response deleted",
+		zap.String("canned_response_id", response.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+	)
+	return nil
+}
+
+// AddAttachments lưu file và thêm vào danh sách đính kèm
+func (s *cannedResponseService) AddAttachments(ctx context.Context, actor Actor, id uuid.UUID, files []UploadFile) (*models.CannedResponse, error) {
+	response, err := s.loadForManage(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Attachments)+len(files) > maxCannedAttachments {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Câu trả lời chỉ được đính kèm tối đa %d file", maxCannedAttachments))
+	}
+
+	attachments, err := s.mediaService.UploadLibrary(ctx, actor, files)
+	if err != nil {
+		return nil, err
+	}
+	response.Attachments = append(response.Attachments, attachments...)
+
+	if err := s.cannedRepo.Update(ctx, response); err != nil {
+		return nil, fmt.Errorf("update canned response: %w", err)
+	}
+	return response, nil
+}
+
+// RemoveAttachment bỏ file đính kèm khỏi câu trả lời
+func (s *cannedResponseService) RemoveAttachment(ctx context.Context, actor Actor, id, mediaID uuid.UUID) (*models.CannedResponse, error) {
+	response, err := s.loadForManage(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(models.Attachments, 0, len(response.Attachments))
+	for _, a := range response.Attachments {
+		if a.MediaID == nil || *a.MediaID != mediaID {
+			kept = append(kept, a)
+		}
+	}
+	if len(kept) == len(response.Attachments) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy file đính kèm")
+	}
+	response.Attachments = kept
+
+	if err := s.cannedRepo.Update(ctx, response); err != nil {
+		return nil, fmt.Errorf("update canned response: %w", err)
+	}
+	return response, nil
+}
+
+// Expand thay biến template theo hội thoại
+func (s *cannedResponseService) Expand(ctx context.Context, actor Actor, conv *models.Conversation, id uuid.UUID) (*CannedExpansion, error) {
+	if conv.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+	}
+	response, err := s.load(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := s.variables(ctx, actor, conv)
+
+	quickReplies := make([]models.QuickReply, 0, len(response.QuickReplies))
+	for _, qr := range response.QuickReplies {
+		quickReplies = append(quickReplies, models.QuickReply{
+			Title:   template.Render(qr.Title, vars),
+			Payload: qr.Payload,
+		})
+	}
+
+	return &CannedExpansion{
+		CannedResponse: response,
+		Content:        strings.TrimSpace(template.Render(response.Body, vars)),
+		Attachments:    response.Attachments,
+		QuickReplies:   quickReplies,
+	}, nil
+}
+
+// RenderText thay biến template trong text theo hội thoại
+func (s *cannedResponseService) RenderText(ctx context.Context, actor Actor, conv *models.Conversation, text string) string {
+	return strings.TrimSpace(template.Render(text, s.variables(ctx, actor, conv)))
+}
+
+// ValidateText kiểm tra text chỉ dùng các biến template được hỗ trợ
+func (s *cannedResponseService) ValidateText(text string) error {
+	// Báo lỗi sớm khi gõ sai tên biến thay vì gửi cho khách chuỗi rỗng
+	for _, name := range template.Names(text) {
+		if !isCannedVariable(name) {
+			return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Biến {{%s}} không được hỗ trợ", name))
+		}
+	}
+	return nil
+}
+
+// RecordUsage tăng số lần dùng
+func (s *cannedResponseService) RecordUsage(ctx context.Context, id uuid.UUID) {
+	if err := s.cannedRepo.IncrementUsage(ctx, id); err != nil {
+		s.logger.Warn("failed to record canned response usage",
+			zap.String("canned_response_id", id.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// load lấy câu trả lời actor thấy được
+func (s *cannedResponseService) load(ctx context.Context, actor Actor, id uuid.UUID) (*models.CannedResponse, error) {
+	response, err := s.cannedRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy câu trả lời soạn sẵn")
+		}
+		return nil, fmt.Errorf("find canned response: %w", err)
+	}
+	// Câu trả lời personal của người khác coi như không tồn tại
+	if response.WorkspaceID != actor.WorkspaceID || !response.VisibleTo(actor.UserID) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy câu trả lời soạn sẵn")
+	}
+	return response, nil
+}
+
+// loadForManage lấy câu trả lời và kiểm tra actor được sửa/xóa
+func (s *cannedResponseService) loadForManage(ctx context.Context, actor Actor, id uuid.UUID) (*models.CannedResponse, error) {
+	response, err := s.load(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canManage(actor, response) {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được sửa câu trả lời dùng chung")
+	}
+	return response, nil
+}
+
+// canManage personal do người tạo quản lý, shared do admin quản lý
+func (s *cannedResponseService) canManage(actor Actor, response *models.CannedResponse) bool {
+	if response.IsPersonal() {
+		return response.IsOwner(actor.UserID)
+	}
+	return actor.IsAdmin()
+}
+
+// validate kiểm tra dữ liệu câu trả lời và shortcut không trùng
+func (s *cannedResponseService) validate(ctx context.Context, actor Actor, response *models.CannedResponse) error {
+	if response.Visibility != models.CannedShared && response.Visibility != models.CannedPersonal {
+		return apperrors.New(apperrors.ErrInvalidInput, "visibility phải là shared hoặc personal")
+	}
+	if response.Title == "" {
+		return apperrors.New(apperrors.ErrInvalidInput, "Tiêu đề không được để trống")
+	}
+	if !shortcutPattern.MatchString(response.Shortcut) {
+		return apperrors.New(apperrors.ErrInvalidInput, "Shortcut chỉ gồm chữ thường, số, - và _ (tối đa 50 ký tự)")
+	}
+	if strings.TrimSpace(response.Body) == "" {
+		return apperrors.New(apperrors.ErrInvalidInput, "Nội dung không được để trống")
+	}
+	if len(response.QuickReplies) > maxCannedQuickReplies {
+		return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Chỉ được tối đa %d quick reply", maxCannedQuickReplies))
+	}
+	for _, qr := range response.QuickReplies {
+		if strings.TrimSpace(qr.Title) == "" || qr.Payload == "" {
+			return apperrors.New(apperrors.ErrInvalidInput, "Quick reply cần có title và payload")
+		}
+	}
+
+	if err := s.ValidateText(response.Body); err != nil {
+		return err
+	}
+
+	return s.checkShortcut(ctx, actor, response)
+}
+
+// checkShortcut shortcut không được trùng với câu trả lời shared
+// hoặc câu trả lời personal khác của cùng người tạo
+func (s *cannedResponseService) checkShortcut(ctx context.Context, actor Actor, response *models.CannedResponse) error {
+	existing, err := s.cannedRepo.FindByShortcut(ctx, response.WorkspaceID, response.CreatedBy, response.Shortcut)
+	if err != nil {
+		return fmt.Errorf("find canned response by shortcut: %w", err)
+	}
+	for _, other := range existing {
+		if other.ID == response.ID {
+			continue
+		}
+		// Câu trả lời shared chỉ xung đột với shared (personal của người khác không ảnh hưởng)
+		if !response.IsPersonal() && other.IsPersonal() {
+			continue
+		}
+		return apperrors.New(apperrors.ErrDuplicateEntry, fmt.Sprintf("Shortcut /%s đã được dùng", response.Shortcut))
+	}
+	return nil
+}
+
+// variables giá trị biến template theo hội thoại và agent đang gửi
+// Thiếu dữ liệu (VD: khách chưa có email) thì biến để rỗng, dùng {{biến|mặc định}} để thay thế
+func (s *cannedResponseService) variables(ctx context.Context, actor Actor, conv *models.Conversation) template.Vars {
+	vars := template.Vars{
+		"conversation.id": conv.ID.String(),
+	}
+
+	if participant, err := s.participantRepo.FindByID(ctx, conv.ParticipantID); err == nil {
+		for name, value := range template.ContactVars(participant) {
+			vars[name] = value
+		}
+	} else {
+		s.logger.Warn("canned response: failed to load participant",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	if user, err := s.userRepo.FindByID(ctx, actor.UserID); err == nil {
+		vars["agent.name"] = user.Name
+		vars["agent.email"] = user.Email
+	}
+
+	if workspace, err := s.workspaceRepo.FindByID(ctx, conv.WorkspaceID); err == nil {
+		vars["workspace.name"] = workspace.Name
+	}
+
+	return vars
+}
+
+// isCannedVariable kiểm tra tên biến có trong CannedVariables hoặc là trường tùy chỉnh của khách
+func isCannedVariable(name string) bool {
+	if template.IsFieldVariable(name) {
+		return true
+	}
+	for _, v := range CannedVariables {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// derefString giá trị của con trỏ string (rỗng nếu nil)
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// normalizeShortcut bỏ dấu "/" ở đầu và chuyển về chữ thường
+func normalizeShortcut(shortcut string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(shortcut), "/"))
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Contact Job Service Interface
+// Nhập danh sách khách có sẵn từ file CSV (ánh xạ cột vào tên, số điện thoại, email,
+// tag, trường tùy chỉnh; chống trùng theo số điện thoại/email; chạy thử kèm lỗi theo dòng)
+// và xuất khách khớp bộ lọc ra CSV
+// Job được tạo ngay, xử lý nền theo từng lô và báo tiến độ qua realtime
+// ===========================================================================
+
+// ContactImportInput dữ liệu tạo job nhập khách
+type ContactImportInput struct {
+	// File nội dung CSV (UTF-8, dòng đầu là tiêu đề, phân cách bằng dấu phẩy hoặc chấm phẩy)
+	File UploadFile
+
+	// Columns ánh xạ cột vào trường của khách, rỗng thì tự ánh xạ theo tên cột
+	Columns []models.ContactImportColumn
+
+	// ChannelAccountID kênh gắn cho khách mới (khách chưa có trong workspace)
+	ChannelAccountID uuid.UUID
+
+	// DryRun chỉ kiểm tra và đếm kết quả dự kiến, không ghi khách
+	DryRun bool
+}
+
+// ContactJobFile file tải về của job
+type ContactJobFile struct {
+	Name        string
+	ContentType string
+
+	// Body nội dung file, caller phải Close
+	Body io.ReadCloser
+}
+
+// ContactJobService interface cho nhập/xuất danh sách khách
+type ContactJobService interface {
+	// Import kiểm tra file và ánh xạ cột, lưu file và tạo job nhập chờ xử lý (chỉ admin)
+	Import(ctx context.Context, actor Actor, input ContactImportInput) (*models.ContactJob, error)
+
+	// Export kiểm tra bộ lọc và tạo job xuất chờ xử lý (chỉ admin)
+	Export(ctx context.Context, actor Actor, filter models.ContactExportFilter) (*models.ContactJob, error)
+
+	// Get lấy tiến độ và kết quả của job
+	Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.ContactJob, error)
+
+	// List lấy các job gần nhất của workspace
+	List(ctx context.Context, actor Actor) ([]models.ContactJob, error)
+
+	// Download tải file kết quả của job đã xong (chỉ admin):
+	// file CSV khách (export) hoặc báo cáo các dòng lỗi kèm nội dung dòng gốc (import)
+	Download(ctx context.Context, actor Actor, id uuid.UUID) (*ContactJobFile, error)
+
+	// RunPending xử lý các job đang chờ hoặc bị gián đoạn (chạy định kỳ)
+	RunPending(ctx context.Context) error
+}
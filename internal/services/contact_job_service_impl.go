@@ -0,0 +1,1075 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chatbox-gin/internal/config"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/storage"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Contact Job Service Implementation
+// ===========================================================================
+
+const (
+	// contactJobHistory số job gần nhất trả về trong danh sách
+	contactJobHistory = 50
+
+	// contactJobStaleAfter job đang chạy không cập nhật tiến độ quá lâu được coi là
+	// bị gián đoạn (server restart) và được chạy tiếp (import) hoặc chạy lại (export)
+	contactJobStaleAfter = 5 * time.Minute
+
+	// contactImportSource nguồn ghi vào metadata của khách tạo từ file nhập
+	contactImportSource = "import"
+
+	// csvContentType content type của file CSV tải về
+	csvContentType = "text/csv; charset=utf-8"
+)
+
+// utf8BOM đánh dấu UTF-8 ở đầu file để Excel hiển thị đúng tiếng Việt
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// contactExportColumns các cột cố định của file xuất, sau đó là field.<key> của từng trường tùy chỉnh
+// Tên cột trùng trường đích khi nhập nên file xuất nhập lại được mà không cần ánh xạ
+var contactExportColumns = []string{"id", "name", "phone", "email", "tags", "channel", "first_seen_at", "last_seen_at"}
+
+// contactHeaderAliases tên cột thường gặp được tự ánh xạ khi không truyền ánh xạ
+var contactHeaderAliases = map[string]string{
+	"name":          models.ContactTargetName,
+	"full name":     models.ContactTargetName,
+	"tên":           models.ContactTargetName,
+	"họ tên":        models.ContactTargetName,
+	"phone":         models.ContactTargetPhone,
+	"phone number":  models.ContactTargetPhone,
+	"số điện thoại": models.ContactTargetPhone,
+	"điện thoại":    models.ContactTargetPhone,
+	"sđt":           models.ContactTargetPhone,
+	"sdt":           models.ContactTargetPhone,
+	"email":         models.ContactTargetEmail,
+	"e-mail":        models.ContactTargetEmail,
+	"tags":          models.ContactTargetTags,
+	"tag":           models.ContactTargetTags,
+}
+
+// contactJobService triển khai ContactJobService
+type contactJobService struct {
+	jobRepo            repositories.ContactJobRepository
+	contactRepo        repositories.ContactRepository
+	channelAccountRepo repositories.ChannelAccountRepository
+	fieldRepo          repositories.CustomFieldRepository
+	fieldService       CustomFieldService
+	store              storage.BlobStore
+	publisher          realtime.Publisher
+	cfg                config.ContactJobConfig
+	logger             *zap.Logger
+}
+
+// NewContactJobService tạo instance mới của ContactJobService
+func NewContactJobService(
+	jobRepo repositories.ContactJobRepository,
+	contactRepo repositories.ContactRepository,
+	channelAccountRepo repositories.ChannelAccountRepository,
+	fieldRepo repositories.CustomFieldRepository,
+	fieldService CustomFieldService,
+	store storage.BlobStore,
+	publisher realtime.Publisher,
+	cfg config.ContactJobConfig,
+	logger *zap.Logger,
+) ContactJobService {
+	return &contactJobService{
+		jobRepo:            jobRepo,
+		contactRepo:        contactRepo,
+		channelAccountRepo: channelAccountRepo,
+		fieldRepo:          fieldRepo,
+		fieldService:       fieldService,
+		store:              store,
+		publisher:          publisher,
+		cfg:                cfg,
+		logger:             logger,
+	}
+}
+
+// Import kiểm tra file và ánh xạ cột, lưu file và tạo job nhập
+func (s *contactJobService) Import(ctx context.Context, actor Actor, input ContactImportInput) (*models.ContactJob, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được nhập danh sách khách")
+	}
+
+	account, err := s.channelAccountRepo.FindByID(ctx, input.ChannelAccountID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("find channel account: %w", err)
+	}
+	if account == nil || account.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Kênh gắn cho khách mới không tồn tại")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(input.File.Body, s.cfg.MaxUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read import file: %w", err)
+	}
+	if int64(len(data)) > s.cfg.MaxUploadBytes {
+		return nil, apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("File nhập tối đa %d MB", s.cfg.MaxUploadBytes>>20))
+	}
+
+	records, err := readContactCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	rows := len(records) - 1
+	if rows <= 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "File không có dòng dữ liệu")
+	}
+	if rows > s.cfg.MaxRows {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("File nhập tối đa %d dòng", s.cfg.MaxRows))
+	}
+
+	definitions, err := s.fieldRepo.FindByWorkspace(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find custom fields: %w", err)
+	}
+	columns, err := mapContactColumns(records[0], input.Columns, definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID := uuid.New()
+	channelAccountID := account.ID
+	job := &models.ContactJob{
+		WorkspaceID:      actor.WorkspaceID,
+		CreatedBy:        actor.UserID,
+		Type:             models.ContactJobImport,
+		FileName:         contactFileName(input.File.Name),
+		SourceKey:        fmt.Sprintf("contact-jobs/%s/%s/source.csv", actor.WorkspaceID, jobID),
+		Columns:          columns,
+		ChannelAccountID: &channelAccountID,
+		DryRun:           input.DryRun,
+		Status:           models.ContactJobPending,
+		Total:            rows,
+	}
+	job.ID = jobID
+
+	if err := s.store.Put(ctx, job.SourceKey, bytes.NewReader(data), int64(len(data)), csvContentType); err != nil {
+		return nil, fmt.Errorf("store import file: %w", err)
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("create contact job: %w", err)
+	}
+
+	s.logger.Info("contact import created",
+		zap.String("job_id", job.ID.String()),
+		zap.String("workspace_id", actor.WorkspaceID.String()),
+		zap.Int("rows", rows),
+		zap.Bool("dry_run", job.DryRun),
+	)
+	return job, nil
+}
+
+// Export kiểm tra bộ lọc, đếm số khách khớp và tạo job xuất
+func (s *contactJobService) Export(ctx context.Context, actor Actor, filter models.ContactExportFilter) (*models.ContactJob, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được xuất danh sách khách")
+	}
+
+	fields, err := s.fieldService.ParseConditions(ctx, actor.WorkspaceID, filter.Fields)
+	if err != nil {
+		return nil, err
+	}
+	filter.Fields = fields
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Tag = strings.TrimSpace(filter.Tag)
+
+	_, total, err := s.contactRepo.FindByWorkspace(ctx, exportQuery(actor.WorkspaceID, filter, nil, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("count contacts: %w", err)
+	}
+	if total == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Không có khách nào khớp bộ lọc")
+	}
+	if total > int64(s.cfg.MaxRows) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("Bộ lọc khớp hơn %d khách, hãy thu hẹp điều kiện", s.cfg.MaxRows))
+	}
+
+	jobID := uuid.New()
+	job := &models.ContactJob{
+		WorkspaceID: actor.WorkspaceID,
+		CreatedBy:   actor.UserID,
+		Type:        models.ContactJobExport,
+		FileName:    "contacts-" + time.Now().Format("20060102-150405") + ".csv",
+		ResultKey:   fmt.Sprintf("contact-jobs/%s/%s/contacts.csv", actor.WorkspaceID, jobID),
+		Filter:      filter,
+		Status:      models.ContactJobPending,
+		Total:       int(total),
+	}
+	job.ID = jobID
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("create contact job: %w", err)
+	}
+
+	s.logger.Info("contact export created",
+		zap.String("job_id", job.ID.String()),
+		zap.String("workspace_id", actor.WorkspaceID.String()),
+		zap.Int("total", job.Total),
+	)
+	return job, nil
+}
+
+// Get lấy tiến độ và kết quả của job
+func (s *contactJobService) Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.ContactJob, error) {
+	job, err := s.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy job")
+		}
+		return nil, fmt.Errorf("find contact job: %w", err)
+	}
+	if job.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy job")
+	}
+	return job, nil
+}
+
+// List lấy các job gần nhất của workspace
+func (s *contactJobService) List(ctx context.Context, actor Actor) ([]models.ContactJob, error) {
+	jobs, err := s.jobRepo.FindByWorkspace(ctx, actor.WorkspaceID, contactJobHistory)
+	if err != nil {
+		return nil, fmt.Errorf("find contact jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Download tải file kết quả của job đã xong
+func (s *contactJobService) Download(ctx context.Context, actor Actor, id uuid.UUID) (*ContactJobFile, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được tải file nhập/xuất khách")
+	}
+	job, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.ContactJobCompleted {
+		return nil, apperrors.New(apperrors.ErrConflict, "Job chưa hoàn tất")
+	}
+
+	if job.Type == models.ContactJobExport {
+		body, err := s.store.Get(ctx, job.ResultKey)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "File xuất không còn trong storage")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("open export file: %w", err)
+		}
+		return &ContactJobFile{Name: job.FileName, ContentType: csvContentType, Body: body}, nil
+	}
+
+	report, err := s.errorReport(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return &ContactJobFile{
+		Name:        strings.TrimSuffix(job.FileName, ".csv") + "-errors.csv",
+		ContentType: csvContentType,
+		Body:        io.NopCloser(bytes.NewReader(report)),
+	}, nil
+}
+
+// RunPending nhận và xử lý lần lượt các job cần chạy cho đến khi hết
+func (s *contactJobService) RunPending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		job, err := s.jobRepo.FindRunnable(ctx, time.Now().Add(-contactJobStaleAfter))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("find runnable contact job: %w", err)
+		}
+
+		claimed, err := s.jobRepo.Claim(ctx, job)
+		if err != nil {
+			return fmt.Errorf("claim contact job: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		if job.Type == models.ContactJobExport {
+			s.runExport(ctx, job)
+		} else {
+			s.runImport(ctx, job)
+		}
+	}
+	return nil
+}
+
+// ===========================================================================
+// Import
+// ===========================================================================
+
+// contactRow giá trị đã chuẩn hóa của một dòng file nhập (chuỗi rỗng = không có trong file)
+type contactRow struct {
+	name   string
+	phone  string
+	email  string
+	tags   []string
+	fields map[string]interface{}
+}
+
+// runImport xử lý file nhập từ dòng chưa xong, lưu tiến độ sau mỗi lô
+// Khách đã ghi được tìm thấy lại theo số điện thoại/email nên chạy lại một lô không tạo khách trùng
+func (s *contactJobService) runImport(ctx context.Context, job *models.ContactJob) {
+	records, err := s.loadSource(ctx, job)
+	if err != nil {
+		s.fail(ctx, job, err)
+		return
+	}
+	indexes, err := columnIndexes(records[0], job.Columns)
+	if err != nil {
+		s.fail(ctx, job, err)
+		return
+	}
+	rows := records[1:]
+
+	// Chạy thử không ghi khách nên các dòng trùng nhau trong file được nhận ra qua map này
+	seen := map[string]bool{}
+
+	for job.Processed < len(rows) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		end := job.Processed + s.cfg.BatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		for i := job.Processed; i < end; i++ {
+			// Số dòng tính cả dòng tiêu đề để khớp với số dòng khi mở file
+			s.importRow(ctx, job, i+2, rows[i], indexes, seen)
+		}
+		job.Processed = end
+
+		if err := s.jobRepo.Update(ctx, job); err != nil {
+			s.logger.Warn("failed to save contact job progress",
+				zap.String("job_id", job.ID.String()),
+				zap.Error(err),
+			)
+			return
+		}
+		s.publishProgress(job)
+	}
+
+	s.complete(ctx, job)
+}
+
+// importRow nhập một dòng và cập nhật bộ đếm của job
+func (s *contactJobService) importRow(ctx context.Context, job *models.ContactJob, line int, record []string, indexes []int, seen map[string]bool) {
+	row, empty, err := s.parseRow(job, record, indexes)
+	switch {
+	case err != nil:
+		job.AddError(line, err)
+		return
+	case empty:
+		job.Skipped++
+		return
+	}
+
+	matches, err := s.contactRepo.FindByPhoneOrEmail(ctx, job.WorkspaceID, row.phone, row.email)
+	if err != nil {
+		job.AddError(line, fmt.Errorf("find contact: %w", err))
+		return
+	}
+
+	// Các danh tính đã gộp của cùng một khách được coi là một
+	persons := map[uuid.UUID]bool{}
+	for _, p := range matches {
+		if p.PersonID != nil {
+			persons[*p.PersonID] = true
+		} else {
+			persons[p.ID] = true
+		}
+	}
+	if len(persons) > 1 {
+		job.AddError(line, errors.New("Số điện thoại và email trùng với nhiều khách khác nhau"))
+		return
+	}
+
+	// Chạy thử: dòng trùng số điện thoại/email với dòng trước trong file sẽ cập nhật khách vừa tạo
+	if job.DryRun && len(matches) == 0 {
+		duplicate := false
+		for _, key := range dedupKeys(row) {
+			duplicate = duplicate || seen[key]
+			seen[key] = true
+		}
+		if duplicate {
+			job.Updated++
+			return
+		}
+	}
+
+	var changed, created bool
+	if len(matches) == 0 {
+		created = true
+		changed, err = s.createContact(ctx, job, row)
+	} else {
+		changed, err = s.updateContact(ctx, job, &matches[0], row)
+	}
+	switch {
+	case err != nil:
+		job.AddError(line, err)
+	case !changed:
+		job.Skipped++
+	case created:
+		job.Created++
+	default:
+		job.Updated++
+	}
+}
+
+// parseRow đọc và kiểm tra giá trị các cột đã ánh xạ của một dòng
+// Trả về empty = true nếu mọi cột đã ánh xạ đều trống
+func (s *contactJobService) parseRow(job *models.ContactJob, record []string, indexes []int) (*contactRow, bool, error) {
+	row := &contactRow{fields: map[string]interface{}{}}
+	empty := true
+
+	for i, column := range job.Columns {
+		if indexes[i] < 0 || indexes[i] >= len(record) {
+			continue
+		}
+		value := unescapeCSVCell(strings.TrimSpace(record[indexes[i]]))
+		if value == "" {
+			continue
+		}
+		empty = false
+
+		switch target := column.Target; target {
+		case models.ContactTargetName:
+			if len([]rune(value)) > 255 {
+				return nil, false, apperrors.New(apperrors.ErrInvalidInput, "Tên quá dài")
+			}
+			row.name = value
+		case models.ContactTargetPhone:
+			phone, err := normalizePhone(value)
+			if err != nil {
+				return nil, false, err
+			}
+			row.phone = phone
+		case models.ContactTargetEmail:
+			email, err := normalizeEmail(value)
+			if err != nil {
+				return nil, false, err
+			}
+			row.email = email
+		case models.ContactTargetTags:
+			tags := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+			for _, tag := range tags {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					row.tags = append(row.tags, tag)
+				}
+			}
+		default:
+			if key, ok := strings.CutPrefix(target, models.ContactFieldTargetPrefix); ok {
+				row.fields[key] = value
+			}
+		}
+	}
+
+	if empty {
+		return nil, true, nil
+	}
+	if row.phone == "" && row.email == "" {
+		return nil, false, apperrors.New(apperrors.ErrInvalidInput, "Thiếu số điện thoại và email")
+	}
+	if len(row.tags) > 0 {
+		tags, err := normalizeContactTags(row.tags)
+		if err != nil {
+			return nil, false, err
+		}
+		row.tags = tags
+	}
+	return row, false, nil
+}
+
+// createContact tạo khách mới trên kênh của job (chạy thử thì chỉ kiểm tra)
+func (s *contactJobService) createContact(ctx context.Context, job *models.ContactJob, row *contactRow) (bool, error) {
+	contact := &models.Participant{
+		WorkspaceID:      job.WorkspaceID,
+		ChannelAccountID: *job.ChannelAccountID,
+		ChannelUserID:    models.ImportedChannelUserPrefix + uuid.NewString(),
+		Metadata:         models.ParticipantMetadata{Source: contactImportSource},
+		FirstSeenAt:      time.Now(),
+	}
+	changes := models.AuditData{}
+	if err := s.applyRow(ctx, job, contact, row, changes); err != nil {
+		return false, err
+	}
+
+	if job.DryRun {
+		return true, nil
+	}
+
+	createdBy := job.CreatedBy
+	audit := &models.AuditLog{
+		WorkspaceID: job.WorkspaceID,
+		ActorID:     &createdBy,
+		Action:      models.AuditContactImported,
+		EntityType:  models.AuditEntityContact,
+		Data:        models.AuditData{"job_id": job.ID},
+	}
+	if err := s.contactRepo.Create(ctx, contact, audit); err != nil {
+		return false, fmt.Errorf("create contact: %w", err)
+	}
+	return true, nil
+}
+
+// updateContact ghi giá trị của dòng lên khách đã có (ô trống giữ nguyên giá trị cũ)
+func (s *contactJobService) updateContact(ctx context.Context, job *models.ContactJob, contact *models.Participant, row *contactRow) (bool, error) {
+	changes := models.AuditData{}
+	if err := s.applyRow(ctx, job, contact, row, changes); err != nil {
+		return false, err
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+	if job.DryRun {
+		return true, nil
+	}
+
+	createdBy := job.CreatedBy
+	changes["job_id"] = job.ID
+	audit := &models.AuditLog{
+		WorkspaceID: job.WorkspaceID,
+		ActorID:     &createdBy,
+		Action:      models.AuditContactUpdated,
+		EntityType:  models.AuditEntityContact,
+		EntityID:    contact.ID,
+		Data:        changes,
+	}
+	if err := s.contactRepo.Update(ctx, contact, audit); err != nil {
+		return false, fmt.Errorf("update contact: %w", err)
+	}
+	return true, nil
+}
+
+// applyRow đặt tên, số điện thoại, email, tag và trường tùy chỉnh của dòng lên khách
+func (s *contactJobService) applyRow(ctx context.Context, job *models.ContactJob, contact *models.Participant, row *contactRow, changes models.AuditData) error {
+	for _, field := range []struct {
+		name   string
+		target **string
+		value  string
+	}{
+		{"name", &contact.Name, row.name},
+		{"phone", &contact.Phone, row.phone},
+		{"email", &contact.Email, row.email},
+	} {
+		if field.value != "" {
+			setContactField(changes, field.name, field.target, field.value)
+		}
+	}
+
+	if added := contact.Metadata.AddTags(row.tags); len(added) > 0 {
+		if len(contact.Metadata.Tags) > maxContactTags {
+			return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Mỗi khách có tối đa %d tag", maxContactTags))
+		}
+		changes["tags"] = added
+	}
+
+	if len(row.fields) > 0 {
+		fieldChanges, err := s.fieldService.ApplyValues(ctx, job.WorkspaceID, &contact.Metadata, row.fields)
+		if err != nil {
+			return err
+		}
+		if len(fieldChanges) > 0 {
+			changes["custom_fields"] = fieldChanges
+		}
+	}
+	return nil
+}
+
+// loadSource đọc lại file nhập từ storage
+func (s *contactJobService) loadSource(ctx context.Context, job *models.ContactJob) ([][]string, error) {
+	body, err := s.store.Get(ctx, job.SourceKey)
+	if err != nil {
+		return nil, fmt.Errorf("open import file: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("read import file: %w", err)
+	}
+	records, err := readContactCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("import file is empty")
+	}
+	return records, nil
+}
+
+// errorReport tạo file CSV các dòng lỗi: số dòng, lỗi, rồi nội dung dòng gốc
+func (s *contactJobService) errorReport(ctx context.Context, job *models.ContactJob) ([]byte, error) {
+	records, err := s.loadSource(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(utf8BOM)
+	w := csv.NewWriter(&buf)
+	header := append([]string{"row", "error"}, records[0]...)
+	if err := w.Write(sanitizeCSVRecord(header)); err != nil {
+		return nil, err
+	}
+	for _, rowErr := range job.Errors {
+		record := []string{strconv.Itoa(rowErr.Row), rowErr.Error}
+		if i := rowErr.Row - 1; i > 0 && i < len(records) {
+			record = append(record, records[i]...)
+		}
+		if err := w.Write(sanitizeCSVRecord(record)); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// ===========================================================================
+// Export
+// ===========================================================================
+
+// runExport ghi mọi khách khớp bộ lọc ra file tạm theo từng lô rồi lưu vào storage
+// File chỉ được lưu khi ghi xong nên job bị gián đoạn được chạy lại từ đầu
+func (s *contactJobService) runExport(ctx context.Context, job *models.ContactJob) {
+	job.Processed = 0
+
+	definitions, err := s.fieldRepo.FindByWorkspace(ctx, job.WorkspaceID)
+	if err != nil {
+		s.fail(ctx, job, fmt.Errorf("find custom fields: %w", err))
+		return
+	}
+
+	tmp, err := os.CreateTemp("", "contact-export-*.csv")
+	if err != nil {
+		s.fail(ctx, job, fmt.Errorf("create temp file: %w", err))
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := tmp.Write(utf8BOM); err != nil {
+		s.fail(ctx, job, fmt.Errorf("write export file: %w", err))
+		return
+	}
+	w := csv.NewWriter(tmp)
+	header := append([]string{}, contactExportColumns...)
+	for _, def := range definitions {
+		header = append(header, models.ContactFieldTargetPrefix+def.Key)
+	}
+	if err := w.Write(header); err != nil {
+		s.fail(ctx, job, fmt.Errorf("write export file: %w", err))
+		return
+	}
+
+	// Chốt danh sách theo thời điểm tạo job để khách mới không làm lệch các trang
+	createdBefore := job.CreatedAt
+	for job.Processed < job.Total {
+		if ctx.Err() != nil {
+			return
+		}
+
+		contacts, _, err := s.contactRepo.FindByWorkspace(ctx, exportQuery(job.WorkspaceID, job.Filter, &createdBefore, job.Processed, s.cfg.BatchSize))
+		if err != nil {
+			s.fail(ctx, job, fmt.Errorf("find contacts: %w", err))
+			return
+		}
+		for i := range contacts {
+			if err := w.Write(sanitizeCSVRecord(exportRecord(&contacts[i], definitions))); err != nil {
+				s.fail(ctx, job, fmt.Errorf("write export file: %w", err))
+				return
+			}
+		}
+		job.Processed += len(contacts)
+
+		// Khách bị xóa sau khi tạo job thì danh sách ngắn hơn dự kiến
+		if len(contacts) < s.cfg.BatchSize {
+			job.Total = job.Processed
+		}
+
+		if err := s.jobRepo.Update(ctx, job); err != nil {
+			s.logger.Warn("failed to save contact job progress",
+				zap.String("job_id", job.ID.String()),
+				zap.Error(err),
+			)
+			return
+		}
+		s.publishProgress(job)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		s.fail(ctx, job, fmt.Errorf("write export file: %w", err))
+		return
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		s.fail(ctx, job, fmt.Errorf("write export file: %w", err))
+		return
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		s.fail(ctx, job, fmt.Errorf("write export file: %w", err))
+		return
+	}
+	if err := s.store.Put(ctx, job.ResultKey, tmp, size, csvContentType); err != nil {
+		s.fail(ctx, job, fmt.Errorf("store export file: %w", err))
+		return
+	}
+
+	s.complete(ctx, job)
+}
+
+// exportQuery điều kiện đọc khách của job xuất, mới nhất trước
+func exportQuery(workspaceID uuid.UUID, filter models.ContactExportFilter, createdBefore *time.Time, offset, limit int) repositories.ContactQuery {
+	return repositories.ContactQuery{
+		WorkspaceID:      workspaceID,
+		Text:             filter.Query,
+		ChannelAccountID: filter.ChannelAccountID,
+		ChannelType:      filter.ChannelType,
+		Tag:              filter.Tag,
+		SeenFrom:         filter.SeenFrom,
+		SeenTo:           filter.SeenTo,
+		Fields:           filter.Fields,
+		CreatedBefore:    createdBefore,
+		Sort:             repositories.ContactSortCreated,
+		Offset:           offset,
+		Limit:            limit,
+	}
+}
+
+// exportRecord một dòng của file xuất theo thứ tự contactExportColumns rồi các trường tùy chỉnh
+func exportRecord(contact *models.Participant, definitions []models.CustomFieldDefinition) []string {
+	lastSeen := ""
+	if contact.LastSeenAt != nil {
+		lastSeen = contact.LastSeenAt.Format(time.RFC3339)
+	}
+	record := []string{
+		contact.ID.String(),
+		derefString(contact.Name),
+		derefString(contact.Phone),
+		derefString(contact.Email),
+		strings.Join(contact.Metadata.Tags, ", "),
+		string(contact.ChannelAccount.ChannelType),
+		contact.FirstSeenAt.Format(time.RFC3339),
+		lastSeen,
+	}
+	for _, def := range definitions {
+		value := ""
+		if v, ok := contact.Metadata.CustomFields[def.Key]; ok {
+			value = models.FormatCustomFieldValue(v)
+		}
+		record = append(record, value)
+	}
+	return record
+}
+
+// ===========================================================================
+// Job state & realtime
+// ===========================================================================
+
+// complete đánh dấu job đã xong
+func (s *contactJobService) complete(ctx context.Context, job *models.ContactJob) {
+	now := time.Now()
+	job.Status = models.ContactJobCompleted
+	job.FinishedAt = &now
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		s.logger.Warn("failed to complete contact job",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	s.publishProgress(job)
+
+	s.logger.Info("contact job completed",
+		zap.String("job_id", job.ID.String()),
+		zap.String("type", string(job.Type)),
+		zap.Bool("dry_run", job.DryRun),
+		zap.Int("total", job.Total),
+		zap.Int("created", job.Created),
+		zap.Int("updated", job.Updated),
+		zap.Int("skipped", job.Skipped),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// fail dừng job do lỗi hệ thống
+func (s *contactJobService) fail(ctx context.Context, job *models.ContactJob, cause error) {
+	now := time.Now()
+	job.Status = models.ContactJobFailed
+	job.Error = cause.Error()
+	job.FinishedAt = &now
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		s.logger.Warn("failed to save failed contact job",
+			zap.String("job_id", job.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	s.publishProgress(job)
+
+	s.logger.Warn("contact job failed",
+		zap.String("job_id", job.ID.String()),
+		zap.Error(cause),
+	)
+}
+
+// publishProgress gửi tiến độ job cho dashboard
+func (s *contactJobService) publishProgress(job *models.ContactJob) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.ContactJobEvent{
+		JobID:     job.ID,
+		JobType:   string(job.Type),
+		Status:    string(job.Status),
+		DryRun:    job.DryRun,
+		Total:     job.Total,
+		Processed: job.Processed,
+		Created:   job.Created,
+		Updated:   job.Updated,
+		Skipped:   job.Skipped,
+		Failed:    job.Failed,
+	}
+	go func() {
+		if err := s.publisher.PublishContactJob(job.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish contact job progress", zap.Error(err))
+		}
+	}()
+}
+
+// ===========================================================================
+// CSV helpers
+// ===========================================================================
+
+// readContactCSV đọc file CSV UTF-8 (bỏ BOM), tự nhận dấu phân cách phẩy hoặc chấm phẩy
+// theo dòng tiêu đề (Excel bản địa hóa thường lưu CSV bằng dấu chấm phẩy)
+func readContactCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "File phải dùng mã hóa UTF-8")
+	}
+
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	r := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		r.Comma = ';'
+	}
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "File CSV không hợp lệ: "+err.Error())
+	}
+	if len(records) == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "File không có dòng tiêu đề")
+	}
+	return records, nil
+}
+
+// mapContactColumns kiểm tra ánh xạ cột theo dòng tiêu đề, rỗng thì tự ánh xạ theo tên cột
+// (name, phone, email, tags, field.<key>, key hoặc nhãn của trường tùy chỉnh)
+func mapContactColumns(header []string, columns []models.ContactImportColumn, definitions []models.CustomFieldDefinition) (models.ContactImportColumns, error) {
+	invalid := func(msg string) (models.ContactImportColumns, error) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, msg)
+	}
+
+	names := make(map[string]bool, len(header))
+	for _, name := range header {
+		key := normalizeHeader(name)
+		if key == "" {
+			continue
+		}
+		if names[key] {
+			return invalid(fmt.Sprintf("Cột %q bị trùng tên", strings.TrimSpace(name)))
+		}
+		names[key] = true
+	}
+	defined := make(map[string]bool, len(definitions))
+	for _, def := range definitions {
+		defined[def.Key] = true
+	}
+
+	if len(columns) == 0 {
+		for _, name := range header {
+			if target := autoTarget(name, definitions); target != "" {
+				columns = append(columns, models.ContactImportColumn{Column: strings.TrimSpace(name), Target: target})
+			}
+		}
+	}
+
+	mapped := make(models.ContactImportColumns, 0, len(columns))
+	used := map[string]bool{}
+	columnUsed := map[string]bool{}
+	hasKey := false
+	for _, column := range columns {
+		name := normalizeHeader(column.Column)
+		if !names[name] {
+			return invalid(fmt.Sprintf("File không có cột %q", column.Column))
+		}
+		if columnUsed[name] {
+			return invalid(fmt.Sprintf("Cột %q được ánh xạ nhiều lần", column.Column))
+		}
+		columnUsed[name] = true
+
+		target := strings.TrimSpace(column.Target)
+		switch target {
+		case models.ContactTargetIgnore, "":
+			continue
+		case models.ContactTargetName, models.ContactTargetPhone, models.ContactTargetEmail, models.ContactTargetTags:
+		default:
+			key, ok := strings.CutPrefix(target, models.ContactFieldTargetPrefix)
+			if !ok {
+				return invalid(fmt.Sprintf("Trường đích %q không hợp lệ", target))
+			}
+			if !defined[key] {
+				return invalid(fmt.Sprintf("Trường tùy chỉnh %q chưa được định nghĩa", key))
+			}
+		}
+
+		// Tag được gộp từ nhiều cột, các trường khác chỉ nhận một cột
+		if target != models.ContactTargetTags && used[target] {
+			return invalid(fmt.Sprintf("Trường %q được ánh xạ từ nhiều cột", target))
+		}
+		used[target] = true
+		hasKey = hasKey || target == models.ContactTargetPhone || target == models.ContactTargetEmail
+		mapped = append(mapped, models.ContactImportColumn{Column: strings.TrimSpace(column.Column), Target: target})
+	}
+
+	if !hasKey {
+		return invalid("Cần ánh xạ cột số điện thoại hoặc email để chống trùng khách")
+	}
+	return mapped, nil
+}
+
+// autoTarget trường đích tự nhận theo tên cột, rỗng nếu không nhận ra
+func autoTarget(column string, definitions []models.CustomFieldDefinition) string {
+	name := normalizeHeader(column)
+	if target, ok := contactHeaderAliases[name]; ok {
+		return target
+	}
+	key := strings.TrimPrefix(name, models.ContactFieldTargetPrefix)
+	for _, def := range definitions {
+		if key == def.Key || name == strings.ToLower(strings.TrimSpace(def.Label)) {
+			return models.ContactFieldTargetPrefix + def.Key
+		}
+	}
+	return ""
+}
+
+// columnIndexes vị trí của từng cột đã ánh xạ trong dòng tiêu đề
+func columnIndexes(header []string, columns models.ContactImportColumns) ([]int, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[normalizeHeader(name)] = i
+	}
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		pos, ok := positions[normalizeHeader(column.Column)]
+		if !ok {
+			return nil, fmt.Errorf("column %q not found in import file", column.Column)
+		}
+		indexes[i] = pos
+	}
+	return indexes, nil
+}
+
+// normalizeHeader chuẩn hóa tên cột để so sánh (bỏ khoảng trắng, không phân biệt hoa thường)
+func normalizeHeader(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// dedupKeys khóa chống trùng của dòng: 9 chữ số cuối của số điện thoại và email viết thường
+func dedupKeys(row *contactRow) []string {
+	var keys []string
+	var digits strings.Builder
+	for _, r := range row.phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	if phone := digits.String(); phone != "" {
+		if len(phone) > 9 {
+			phone = phone[len(phone)-9:]
+		}
+		keys = append(keys, "phone:"+phone)
+	}
+	if row.email != "" {
+		keys = append(keys, "email:"+strings.ToLower(row.email))
+	}
+	return keys
+}
+
+// contactFileName tên file nhập để hiển thị (bỏ đường dẫn, mặc định contacts.csv)
+func contactFileName(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "contacts.csv"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+// sanitizeCSVRecord chặn ô bị bảng tính hiểu là công thức (CSV injection) bằng dấu ' ở đầu
+// Số điện thoại (+84...) và số âm được giữ nguyên
+func sanitizeCSVRecord(record []string) []string {
+	for i, value := range record {
+		if value == "" {
+			continue
+		}
+		switch value[0] {
+		case '=', '@', '\t', '\r':
+			record[i] = "'" + value
+		case '+', '-':
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				continue
+			}
+			if _, err := normalizePhone(value); err == nil {
+				continue
+			}
+			record[i] = "'" + value
+		}
+	}
+	return record
+}
+
+// unescapeCSVCell bỏ dấu ' do sanitizeCSVRecord thêm khi nhập lại file đã xuất
+func unescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
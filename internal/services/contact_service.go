@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Contact Service Interface
+// Quản lý khách hàng (participants) của workspace: tìm kiếm, xem hội thoại,
+// sửa thông tin liên hệ và trường tùy chỉnh, gắn tag, xem lịch sử hoạt động,
+// gộp các danh tính của cùng một khách trên nhiều kênh
+// ===========================================================================
+
+// ContactListInput điều kiện lọc danh sách khách
+type ContactListInput struct {
+	Query            string
+	ChannelAccountID *uuid.UUID
+	ChannelType      string
+	Tag              string
+	SeenFrom         *time.Time
+	SeenTo           *time.Time
+
+	// Fields điều kiện trên trường tùy chỉnh (tất cả phải thỏa)
+	Fields []models.FieldCondition
+
+	// Sort last_seen, created, name; SortField khác rỗng thì sắp xếp theo trường tùy chỉnh
+	Sort      string
+	SortField string
+	SortDesc  bool
+
+	Offset int
+	Limit  int
+}
+
+// UpdateContactInput dữ liệu sửa khách (nil = giữ nguyên, chuỗi rỗng = xóa)
+type UpdateContactInput struct {
+	Name  *string
+	Email *string
+	Phone *string
+
+	// CustomFields các trường tùy chỉnh cần đặt, giá trị null thì xóa trường
+	CustomFields map[string]interface{}
+}
+
+// ContactDetail khách kèm mọi hội thoại
+type ContactDetail struct {
+	Contact *models.Participant `json:"contact"`
+
+	// Identities mọi danh tính đã gộp của khách (gồm cả Contact), cũ nhất trước
+	Identities []models.Participant `json:"identities"`
+
+	// Conversations hội thoại của mọi danh tính, mới nhất trước
+	Conversations []models.Conversation `json:"conversations"`
+
+	// OpenConversations số hội thoại chưa đóng
+	OpenConversations int `json:"open_conversations"`
+}
+
+// MergeSuggestion hai danh tính có thể là cùng một khách
+type MergeSuggestion struct {
+	Contact   *models.Participant `json:"contact"`
+	Candidate *models.Participant `json:"candidate"`
+
+	// MatchedOn tiêu chí trùng khớp: phone, email
+	MatchedOn []string `json:"matched_on"`
+}
+
+// ContactService interface cho quản lý khách hàng
+type ContactService interface {
+	// List lấy danh sách khách của workspace theo điều kiện
+	List(ctx context.Context, actor Actor, input ContactListInput) ([]models.Participant, int64, error)
+
+	// Get lấy khách kèm các hội thoại
+	Get(ctx context.Context, actor Actor, id uuid.UUID) (*ContactDetail, error)
+
+	// Update sửa tên, email, số điện thoại, trường tùy chỉnh (ghi audit log)
+	Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateContactInput) (*models.Participant, error)
+
+	// AddTags gắn tag cho khách (tag đã có được bỏ qua)
+	AddTags(ctx context.Context, actor Actor, id uuid.UUID, tags []string) (*models.Participant, error)
+
+	// RemoveTag gỡ tag của khách
+	RemoveTag(ctx context.Context, actor Actor, id uuid.UUID, tag string) (*models.Participant, error)
+
+	// Timeline lịch sử hoạt động của mọi danh tính trước thời điểm before (nil = từ hiện tại), mới nhất trước
+	Timeline(ctx context.Context, actor Actor, id uuid.UUID, before *time.Time, limit int) ([]repositories.ContactActivity, error)
+
+	// Messages tin nhắn trong mọi hội thoại của mọi danh tính theo cursor, mới nhất trước
+	Messages(ctx context.Context, actor Actor, id uuid.UUID, q repositories.CursorQuery) ([]models.Message, bool, error)
+
+	// Merge gộp các khách khác vào khách id (ghi audit log), trả về khách sau khi gộp
+	Merge(ctx context.Context, actor Actor, id uuid.UUID, participantIDs []uuid.UUID) (*ContactDetail, error)
+
+	// Unmerge tách khách id khỏi các danh tính đã gộp (ghi audit log)
+	Unmerge(ctx context.Context, actor Actor, id uuid.UUID) (*models.Participant, error)
+
+	// MergeSuggestions gợi ý gộp các khách trùng số điện thoại hoặc email
+	// id khác nil thì chỉ gợi ý cho khách đó
+	MergeSuggestions(ctx context.Context, actor Actor, id *uuid.UUID, limit int) ([]MergeSuggestion, error)
+}
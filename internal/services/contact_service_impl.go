@@ -0,0 +1,530 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Contact Service Implementation
+// ===========================================================================
+
+const (
+	// maxContactTags số tag tối đa của một khách
+	maxContactTags = 50
+
+	// maxMergeParticipants số khách tối đa gộp trong một lần
+	maxMergeParticipants = 20
+)
+
+// contactService triển khai ContactService
+type contactService struct {
+	contactRepo  repositories.ContactRepository
+	fieldService CustomFieldService
+	logger       *zap.Logger
+}
+
+// NewContactService tạo instance mới của ContactService
+func NewContactService(contactRepo repositories.ContactRepository, fieldService CustomFieldService, logger *zap.Logger) ContactService {
+	return &contactService{
+		contactRepo:  contactRepo,
+		fieldService: fieldService,
+		logger:       logger,
+	}
+}
+
+// List lấy danh sách khách của workspace
+func (s *contactService) List(ctx context.Context, actor Actor, input ContactListInput) ([]models.Participant, int64, error) {
+	fields, err := s.fieldService.ParseConditions(ctx, actor.WorkspaceID, input.Fields)
+	if err != nil {
+		return nil, 0, err
+	}
+	if input.SortField != "" {
+		if err := s.fieldService.CheckSortField(ctx, actor.WorkspaceID, input.SortField); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	contacts, total, err := s.contactRepo.FindByWorkspace(ctx, repositories.ContactQuery{
+		WorkspaceID:      actor.WorkspaceID,
+		Text:             input.Query,
+		ChannelAccountID: input.ChannelAccountID,
+		ChannelType:      input.ChannelType,
+		Tag:              strings.TrimSpace(input.Tag),
+		SeenFrom:         input.SeenFrom,
+		SeenTo:           input.SeenTo,
+		Fields:           fields,
+		Sort:             input.Sort,
+		SortField:        input.SortField,
+		SortDesc:         input.SortDesc,
+		Offset:           input.Offset,
+		Limit:            input.Limit,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("find contacts: %w", err)
+	}
+	return contacts, total, nil
+}
+
+// Get lấy khách kèm các hội thoại
+func (s *contactService) Get(ctx context.Context, actor Actor, id uuid.UUID) (*ContactDetail, error) {
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := s.identities(ctx, contact)
+	if err != nil {
+		return nil, err
+	}
+	conversations, err := s.contactRepo.FindConversations(ctx, participantIDs(identities))
+	if err != nil {
+		return nil, fmt.Errorf("find contact conversations: %w", err)
+	}
+
+	detail := &ContactDetail{Contact: contact, Identities: identities, Conversations: conversations}
+	for _, conv := range conversations {
+		if conv.Status != models.StatusClosed {
+			detail.OpenConversations++
+		}
+	}
+	return detail, nil
+}
+
+// Update sửa thông tin liên hệ và trường tùy chỉnh
+func (s *contactService) Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateContactInput) (*models.Participant, error) {
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := models.AuditData{}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if len([]rune(name)) > 255 {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "Tên quá dài")
+		}
+		setContactField(changes, "name", &contact.Name, name)
+	}
+	if input.Email != nil {
+		email, err := normalizeEmail(*input.Email)
+		if err != nil {
+			return nil, err
+		}
+		setContactField(changes, "email", &contact.Email, email)
+	}
+	if input.Phone != nil {
+		phone, err := normalizePhone(*input.Phone)
+		if err != nil {
+			return nil, err
+		}
+		setContactField(changes, "phone", &contact.Phone, phone)
+	}
+	if len(input.CustomFields) > 0 {
+		fieldChanges, err := s.fieldService.ApplyValues(ctx, contact.WorkspaceID, &contact.Metadata, input.CustomFields)
+		if err != nil {
+			return nil, err
+		}
+		if len(fieldChanges) > 0 {
+			changes["custom_fields"] = fieldChanges
+		}
+	}
+
+	// Không có gì thay đổi thì không ghi audit
+	if len(changes) == 0 {
+		return contact, nil
+	}
+
+	if err := s.save(ctx, actor, contact, models.AuditContactUpdated, changes); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// AddTags gắn tag cho khách
+func (s *contactService) AddTags(ctx context.Context, actor Actor, id uuid.UUID, tags []string) (*models.Participant, error) {
+	names, err := normalizeContactTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	added := contact.Metadata.AddTags(names)
+	if len(added) == 0 {
+		return contact, nil
+	}
+	if len(contact.Metadata.Tags) > maxContactTags {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Mỗi khách có tối đa %d tag", maxContactTags))
+	}
+
+	if err := s.save(ctx, actor, contact, models.AuditContactTagged, models.AuditData{"tags": added}); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// RemoveTag gỡ tag của khách
+func (s *contactService) RemoveTag(ctx context.Context, actor Actor, id uuid.UUID, tag string) (*models.Participant, error) {
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tag = strings.TrimSpace(tag)
+	if !contact.Metadata.RemoveTag(tag) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Khách không có tag "+tag)
+	}
+
+	if err := s.save(ctx, actor, contact, models.AuditContactUntagged, models.AuditData{"tags": []string{tag}}); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// Timeline lịch sử hoạt động của khách
+func (s *contactService) Timeline(ctx context.Context, actor Actor, id uuid.UUID, before *time.Time, limit int) ([]repositories.ContactActivity, error) {
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := s.identities(ctx, contact)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now()
+	if before != nil {
+		until = *before
+	}
+	activities, err := s.contactRepo.Timeline(ctx, participantIDs(identities), until, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find contact timeline: %w", err)
+	}
+	return activities, nil
+}
+
+// Messages tin nhắn của mọi danh tính của khách
+func (s *contactService) Messages(ctx context.Context, actor Actor, id uuid.UUID, q repositories.CursorQuery) ([]models.Message, bool, error) {
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, false, err
+	}
+	identities, err := s.identities(ctx, contact)
+	if err != nil {
+		return nil, false, err
+	}
+
+	messages, hasMore, err := s.contactRepo.FindMessages(ctx, participantIDs(identities), q)
+	if err != nil {
+		return nil, false, fmt.Errorf("find contact messages: %w", err)
+	}
+	return messages, hasMore, nil
+}
+
+// Merge gộp các khách khác vào khách id
+func (s *contactService) Merge(ctx context.Context, actor Actor, id uuid.UUID, ids []uuid.UUID) (*ContactDetail, error) {
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	others := make([]uuid.UUID, 0, len(ids))
+	seen := map[uuid.UUID]bool{contact.ID: true}
+	for _, otherID := range ids {
+		if !seen[otherID] {
+			seen[otherID] = true
+			others = append(others, otherID)
+		}
+	}
+	if len(others) == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Cần ít nhất một khách khác để gộp")
+	}
+	if len(others) > maxMergeParticipants {
+		return nil, apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("Chỉ gộp tối đa %d khách mỗi lần", maxMergeParticipants))
+	}
+
+	found, err := s.contactRepo.FindByIDs(ctx, actor.WorkspaceID, others)
+	if err != nil {
+		return nil, fmt.Errorf("find merge participants: %w", err)
+	}
+	if len(found) != len(others) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy khách hàng cần gộp")
+	}
+
+	// Mọi danh tính đã cùng một khách thì không cần gộp lại
+	if contact.PersonID != nil {
+		merged := true
+		for _, p := range found {
+			if p.PersonID == nil || *p.PersonID != *contact.PersonID {
+				merged = false
+				break
+			}
+		}
+		if merged {
+			return s.Get(ctx, actor, contact.ID)
+		}
+	}
+
+	actorID := actor.UserID
+	audit := &models.AuditLog{
+		WorkspaceID: contact.WorkspaceID,
+		ActorID:     &actorID,
+		Action:      models.AuditContactMerged,
+		EntityType:  models.AuditEntityContact,
+		EntityID:    contact.ID,
+		Data:        models.AuditData{"participant_ids": others},
+	}
+	personID, err := s.contactRepo.Merge(ctx, actor.WorkspaceID, actor.UserID, append([]uuid.UUID{contact.ID}, others...), audit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy khách hàng cần gộp")
+		}
+		return nil, fmt.Errorf("merge contacts: %w", err)
+	}
+
+	s.logger.Info("contacts merged",
+		zap.String("participant_id", contact.ID.String()),
+		zap.String("person_id", personID.String()),
+		zap.Int("merged", len(others)),
+		zap.String("user_id", actor.UserID.String()),
+	)
+	return s.Get(ctx, actor, contact.ID)
+}
+
+// Unmerge tách khách id khỏi các danh tính đã gộp
+func (s *contactService) Unmerge(ctx context.Context, actor Actor, id uuid.UUID) (*models.Participant, error) {
+	contact, err := s.loadContact(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if contact.PersonID == nil {
+		return nil, apperrors.New(apperrors.ErrConflict, "Khách chưa được gộp với danh tính nào")
+	}
+
+	personID := *contact.PersonID
+	actorID := actor.UserID
+	audit := &models.AuditLog{
+		WorkspaceID: contact.WorkspaceID,
+		ActorID:     &actorID,
+		Action:      models.AuditContactUnmerged,
+		EntityType:  models.AuditEntityContact,
+		EntityID:    contact.ID,
+		Data:        models.AuditData{"person_id": personID},
+	}
+	detached, err := s.contactRepo.Unmerge(ctx, contact, audit)
+	if err != nil {
+		return nil, fmt.Errorf("unmerge contact: %w", err)
+	}
+	if !detached {
+		return nil, apperrors.New(apperrors.ErrConflict, "Khách đã được tách hoặc gộp lại, vui lòng tải lại")
+	}
+
+	s.logger.Info("contact unmerged",
+		zap.String("participant_id", contact.ID.String()),
+		zap.String("person_id", personID.String()),
+		zap.String("user_id", actor.UserID.String()),
+	)
+	return contact, nil
+}
+
+// MergeSuggestions gợi ý gộp các khách trùng số điện thoại hoặc email
+func (s *contactService) MergeSuggestions(ctx context.Context, actor Actor, id *uuid.UUID, limit int) ([]MergeSuggestion, error) {
+	if id != nil {
+		if _, err := s.loadContact(ctx, actor, *id); err != nil {
+			return nil, err
+		}
+	}
+
+	candidates, err := s.contactRepo.FindMergeSuggestions(ctx, actor.WorkspaceID, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find merge suggestions: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []MergeSuggestion{}, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(candidates)*2)
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ParticipantID, candidate.CandidateID)
+	}
+	participants, err := s.contactRepo.FindByIDs(ctx, actor.WorkspaceID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("find suggested participants: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.Participant, len(participants))
+	for i := range participants {
+		byID[participants[i].ID] = &participants[i]
+	}
+
+	suggestions := make([]MergeSuggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		contact, other := byID[candidate.ParticipantID], byID[candidate.CandidateID]
+		if contact == nil || other == nil {
+			continue
+		}
+		suggestion := MergeSuggestion{Contact: contact, Candidate: other}
+		if candidate.PhoneMatch {
+			suggestion.MatchedOn = append(suggestion.MatchedOn, "phone")
+		}
+		if candidate.EmailMatch {
+			suggestion.MatchedOn = append(suggestion.MatchedOn, "email")
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// loadContact tải khách và kiểm tra thuộc workspace của actor
+func (s *contactService) loadContact(ctx context.Context, actor Actor, id uuid.UUID) (*models.Participant, error) {
+	contact, err := s.contactRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy khách hàng")
+		}
+		return nil, fmt.Errorf("find contact: %w", err)
+	}
+	if contact.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy khách hàng")
+	}
+	return contact, nil
+}
+
+// identities mọi danh tính đã gộp của khách (chỉ khách nếu chưa gộp)
+func (s *contactService) identities(ctx context.Context, contact *models.Participant) ([]models.Participant, error) {
+	if contact.PersonID == nil {
+		return []models.Participant{*contact}, nil
+	}
+	identities, err := s.contactRepo.FindIdentities(ctx, *contact.PersonID)
+	if err != nil {
+		return nil, fmt.Errorf("find contact identities: %w", err)
+	}
+	if len(identities) == 0 {
+		return []models.Participant{*contact}, nil
+	}
+	return identities, nil
+}
+
+// participantIDs ID của các participant
+func participantIDs(participants []models.Participant) []uuid.UUID {
+	ids := make([]uuid.UUID, len(participants))
+	for i, p := range participants {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+// save lưu khách kèm audit log của thao tác
+func (s *contactService) save(ctx context.Context, actor Actor, contact *models.Participant, action string, data models.AuditData) error {
+	actorID := actor.UserID
+	audit := &models.AuditLog{
+		WorkspaceID: contact.WorkspaceID,
+		ActorID:     &actorID,
+		Action:      action,
+		EntityType:  models.AuditEntityContact,
+		EntityID:    contact.ID,
+		Data:        data,
+	}
+	if err := s.contactRepo.Update(ctx, contact, audit); err != nil {
+		return fmt.Errorf("update contact: %w", err)
+	}
+
+	s.logger.Info("contact updated",
+		zap.String("participant_id", contact.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+		zap.String("action", action),
+	)
+	return nil
+}
+
+// setContactField đặt giá trị mới (rỗng = xóa) và ghi lại thay đổi
+func setContactField(changes models.AuditData, field string, target **string, value string) {
+	old := derefString(*target)
+	if old == value {
+		return
+	}
+	if value == "" {
+		*target = nil
+	} else {
+		*target = &value
+	}
+	changes[field] = map[string]interface{}{"from": old, "to": value}
+}
+
+// normalizeEmail kiểm tra email (rỗng = xóa)
+func normalizeEmail(value string) (string, error) {
+	email := strings.TrimSpace(value)
+	if email == "" {
+		return "", nil
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 255 {
+		return "", apperrors.New(apperrors.ErrInvalidInput, "Email không hợp lệ")
+	}
+	return email, nil
+}
+
+// normalizePhone kiểm tra số điện thoại (rỗng = xóa)
+// Cho phép chữ số, dấu + ở đầu và các ký tự phân cách thường gặp
+func normalizePhone(value string) (string, error) {
+	phone := strings.TrimSpace(value)
+	if phone == "" {
+		return "", nil
+	}
+	digits := 0
+	for i, r := range phone {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", apperrors.New(apperrors.ErrInvalidInput, "Số điện thoại không hợp lệ")
+		}
+	}
+	if digits < 6 || digits > 15 || len(phone) > 50 {
+		return "", apperrors.New(apperrors.ErrInvalidInput, "Số điện thoại không hợp lệ")
+	}
+	return phone, nil
+}
+
+// normalizeContactTags bỏ khoảng trắng, kiểm tra độ dài và bỏ tag trùng
+func normalizeContactTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Cần ít nhất một tag")
+	}
+	names := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name := strings.TrimSpace(tag)
+		if name == "" || len([]rune(name)) > 100 {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "Tên tag không hợp lệ")
+		}
+		if key := strings.ToLower(name); !seen[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
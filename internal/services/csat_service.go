@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// CSAT Service Interface
+// Gửi khảo sát mức độ hài lòng khi đóng hội thoại, ghi nhận câu trả lời
+// và tổng hợp báo cáo theo agent, kênh, kỳ
+// ===========================================================================
+
+// CSATListInput filter danh sách khảo sát
+type CSATListInput struct {
+	AgentID          *uuid.UUID
+	ChannelAccountID *uuid.UUID
+	From             *time.Time
+	To               *time.Time
+	Score            *int
+	Offset           int
+	Limit            int
+}
+
+// CSATReportInput điều kiện báo cáo
+type CSATReportInput struct {
+	// From, To khoảng thời gian gửi khảo sát (mặc định 30 ngày gần nhất)
+	From *time.Time
+	To   *time.Time
+
+	AgentID          *uuid.UUID
+	ChannelAccountID *uuid.UUID
+
+	// GroupBy agent, channel_account, day, week, month (rỗng = chỉ số tổng)
+	GroupBy repositories.CSATGroupBy
+}
+
+// CSATReportEntry một dòng báo cáo kèm chỉ số đã tính
+type CSATReportEntry struct {
+	repositories.CSATReportRow
+
+	// Label tên hiển thị của nhóm (tên agent, tên kênh)
+	Label string `json:"label,omitempty"`
+
+	// ResponseRate tỉ lệ khách trả lời (%)
+	ResponseRate float64 `json:"response_rate"`
+
+	// CSATPercent tỉ lệ câu trả lời 4-5 điểm trên số câu trả lời (%)
+	CSATPercent float64 `json:"csat_percent"`
+}
+
+// CSATReport báo cáo CSAT
+type CSATReport struct {
+	From    time.Time                `json:"from"`
+	To      time.Time                `json:"to"`
+	GroupBy repositories.CSATGroupBy `json:"group_by,omitempty"`
+	Summary CSATReportEntry          `json:"summary"`
+	Rows    []CSATReportEntry        `json:"rows,omitempty"`
+}
+
+// SurveyOptions tùy chọn khi gửi khảo sát
+type SurveyOptions struct {
+	// Force gửi cả khi workspace chưa bật CSAT (VD: auto-close bật send_csat)
+	Force bool
+
+	// Prompt câu hỏi thay cho câu hỏi trong cài đặt CSAT (rỗng = dùng cài đặt)
+	Prompt string
+}
+
+// CSATService interface cho khảo sát CSAT
+type CSATService interface {
+	// GetSettings lấy cấu hình khảo sát của workspace
+	GetSettings(ctx context.Context, actor Actor) (models.CSATSettings, error)
+
+	// UpdateSettings cập nhật cấu hình khảo sát (chỉ admin)
+	UpdateSettings(ctx context.Context, actor Actor, settings models.CSATSettings) (models.CSATSettings, error)
+
+	// SendSurvey gửi câu hỏi khảo sát cho conversation vừa đóng
+	// Bỏ qua nếu workspace không bật CSAT hoặc conversation đã được khảo sát
+	SendSurvey(ctx context.Context, conv *models.Conversation) error
+
+	// SendSurveyWith gửi khảo sát với tùy chọn (gửi bắt buộc, câu hỏi riêng)
+	// Vẫn bỏ qua nếu conversation đã được khảo sát
+	SendSurveyWith(ctx context.Context, conv *models.Conversation, opts SurveyOptions) error
+
+	// CaptureAnswer kiểm tra tin nhắn của khách có phải câu trả lời khảo sát không
+	// Trả về khảo sát đã cập nhật, nil nếu tin nhắn không thuộc khảo sát nào
+	CaptureAnswer(ctx context.Context, participantID uuid.UUID, content string) (*models.CSATResponse, error)
+
+	// SendFollowUp gửi câu hỏi góp ý hoặc lời cảm ơn sau khi ghi nhận câu trả lời
+	SendFollowUp(ctx context.Context, conv *models.Conversation, response *models.CSATResponse) error
+
+	// ListResponses lấy danh sách khảo sát (agent chỉ xem của mình)
+	ListResponses(ctx context.Context, actor Actor, input CSATListInput) ([]models.CSATResponse, int64, error)
+
+	// Report tổng hợp CSAT (agent chỉ xem của mình)
+	Report(ctx context.Context, actor Actor, input CSATReportInput) (*CSATReport, error)
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// CSAT Service Implementation
+// ===========================================================================
+
+// csatService triển khai CSATService
+type csatService struct {
+	csatRepo           repositories.CSATRepository
+	workspaceRepo      repositories.WorkspaceRepository
+	channelAccountRepo repositories.ChannelAccountRepository
+	userRepo           repositories.UserRepository
+	outboundService    OutboundService
+	logger             *zap.Logger
+}
+
+// NewCSATService tạo instance mới của CSATService
+func NewCSATService(
+	csatRepo repositories.CSATRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	channelAccountRepo repositories.ChannelAccountRepository,
+	userRepo repositories.UserRepository,
+	outboundService OutboundService,
+	logger *zap.Logger,
+) CSATService {
+	return &csatService{
+		csatRepo:           csatRepo,
+		workspaceRepo:      workspaceRepo,
+		channelAccountRepo: channelAccountRepo,
+		userRepo:           userRepo,
+		outboundService:    outboundService,
+		logger:             logger,
+	}
+}
+
+// GetSettings lấy cấu hình khảo sát của workspace
+func (s *csatService) GetSettings(ctx context.Context, actor Actor) (models.CSATSettings, error) {
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.CSATSettings{}, fmt.Errorf("find workspace: %w", err)
+	}
+	return workspace.Settings.CSATConfig(), nil
+}
+
+// UpdateSettings cập nhật cấu hình khảo sát
+func (s *csatService) UpdateSettings(ctx context.Context, actor Actor, settings models.CSATSettings) (models.CSATSettings, error) {
+	if !actor.IsAdmin() {
+		return models.CSATSettings{}, apperrors.New(apperrors.ErrForbidden, "Chỉ admin được cấu hình khảo sát CSAT")
+	}
+	if settings.ExpireHours < 0 {
+		return models.CSATSettings{}, apperrors.New(apperrors.ErrInvalidInput, "Thời hạn khảo sát không hợp lệ")
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.CSATSettings{}, fmt.Errorf("find workspace: %w", err)
+	}
+
+	workspace.Settings.CSAT = &settings
+	if err := s.workspaceRepo.Update(ctx, workspace); err != nil {
+		return models.CSATSettings{}, fmt.Errorf("update workspace: %w", err)
+	}
+
+	s.logger.Info("csat settings updated",
+		zap.String("workspace_id", workspace.ID.String()),
+		zap.Bool("enabled", settings.Enabled),
+	)
+	return workspace.Settings.CSATConfig(), nil
+}
+
+// SendSurvey gửi câu hỏi khảo sát cho conversation vừa đóng
+func (s *csatService) SendSurvey(ctx context.Context, conv *models.Conversation) error {
+	return s.SendSurveyWith(ctx, conv, SurveyOptions{})
+}
+
+// SendSurveyWith gửi khảo sát với tùy chọn (gửi bắt buộc, câu hỏi riêng)
+func (s *csatService) SendSurveyWith(ctx context.Context, conv *models.Conversation, opts SurveyOptions) error {
+	workspace, err := s.workspaceRepo.FindByID(ctx, conv.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("find workspace: %w", err)
+	}
+	cfg := workspace.Settings.CSATConfig()
+	if !cfg.Enabled && !opts.Force {
+		return nil
+	}
+	if opts.Prompt != "" {
+		cfg.Prompt = opts.Prompt
+	}
+
+	// Mỗi conversation chỉ khảo sát một lần (kể cả khi đóng - mở lại nhiều lần)
+	if _, err := s.csatRepo.FindByConversation(ctx, conv.ID); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("find survey: %w", err)
+	}
+
+	// Ngoài cửa sổ nhắn tin channel sẽ từ chối câu hỏi, không tạo khảo sát treo
+	if err := s.outboundService.CheckAutomated(ctx, conv, ""); err != nil {
+		if apperrors.Is(err, apperrors.ErrMessagingWindowClosed) {
+			s.logger.Info("csat survey skipped: messaging window closed",
+				zap.String("conversation_id", conv.ID.String()),
+			)
+			return nil
+		}
+		return err
+	}
+
+	channelAccount, err := s.channelAccountRepo.FindByID(ctx, conv.ChannelAccountID)
+	if err != nil {
+		return fmt.Errorf("find channel account: %w", err)
+	}
+
+	now := time.Now()
+	response := &models.CSATResponse{
+		WorkspaceID:      conv.WorkspaceID,
+		ConversationID:   conv.ID,
+		ChannelAccountID: conv.ChannelAccountID,
+		ParticipantID:    conv.ParticipantID,
+		AgentID:          conv.AssignedTo,
+		Status:           models.CSATPending,
+		SentAt:           now,
+		ExpiresAt:        now.Add(time.Duration(cfg.ExpireHours) * time.Hour),
+	}
+	if err := s.csatRepo.Create(ctx, response); err != nil {
+		return fmt.Errorf("create survey: %w", err)
+	}
+
+	// Facebook hỗ trợ quick replies, các kênh khác khách trả lời bằng số
+	input := OutboundInput{
+		Conversation: conv,
+		SenderType:   models.SenderBot,
+		Content:      cfg.Prompt,
+	}
+	if channelAccount.IsFacebook() {
+		input.QuickReplies = csatQuickReplies()
+	} else {
+		input.Content = cfg.Prompt + "\nVui lòng trả lời một số từ 1 đến 5."
+	}
+
+	if _, err := s.outboundService.Send(ctx, input); err != nil {
+		return fmt.Errorf("send survey: %w", err)
+	}
+
+	s.logger.Info("csat survey sent",
+		zap.String("conversation_id", conv.ID.String()),
+		zap.String("channel", string(channelAccount.ChannelType)),
+	)
+	return nil
+}
+
+// csatQuickReplies lựa chọn chấm điểm 1-5 sao
+func csatQuickReplies() []models.QuickReply {
+	replies := make([]models.QuickReply, 0, models.CSATMaxScore)
+	for score := models.CSATMinScore; score <= models.CSATMaxScore; score++ {
+		replies = append(replies, models.QuickReply{
+			Title:   strings.Repeat("⭐", score),
+			Payload: models.CSATPayloadPrefix + strconv.Itoa(score),
+		})
+	}
+	return replies
+}
+
+// CaptureAnswer ghi nhận câu trả lời khảo sát từ tin nhắn của khách
+func (s *csatService) CaptureAnswer(ctx context.Context, participantID uuid.UUID, content string) (*models.CSATResponse, error) {
+	now := time.Now()
+	response, err := s.csatRepo.FindOpenByParticipant(ctx, participantID, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find open survey: %w", err)
+	}
+
+	score, comment, isScore := models.ParseCSATAnswer(content)
+
+	switch {
+	case isScore:
+		// Chấm điểm (hoặc chấm lại khi khách bấm quick reply khác)
+		workspace, err := s.workspaceRepo.FindByID(ctx, response.WorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("find workspace: %w", err)
+		}
+		response.Rate(score, comment, now, workspace.Settings.CSATConfig().AskComment)
+
+	case response.Status == models.CSATAwaitingComment && strings.TrimSpace(content) != "":
+		response.AddComment(strings.TrimSpace(content))
+
+	case response.Status == models.CSATAwaitingComment:
+		// Tin nhắn không có text (ảnh, sticker): kết thúc khảo sát, xử lý như tin nhắn thường
+		response.Status = models.CSATCompleted
+		if err := s.csatRepo.Update(ctx, response); err != nil {
+			return nil, fmt.Errorf("update survey: %w", err)
+		}
+		return nil, nil
+
+	default:
+		// Khách nhắn nội dung khác: bỏ khảo sát để không bắt nhầm các tin nhắn sau
+		response.Status = models.CSATIgnored
+		if err := s.csatRepo.Update(ctx, response); err != nil {
+			return nil, fmt.Errorf("update survey: %w", err)
+		}
+		return nil, nil
+	}
+
+	if err := s.csatRepo.Update(ctx, response); err != nil {
+		return nil, fmt.Errorf("update survey: %w", err)
+	}
+
+	s.logger.Info("csat answer captured",
+		zap.String("conversation_id", response.ConversationID.String()),
+		zap.String("status", string(response.Status)),
+	)
+	return response, nil
+}
+
+// SendFollowUp gửi câu hỏi góp ý hoặc lời cảm ơn
+func (s *csatService) SendFollowUp(ctx context.Context, conv *models.Conversation, response *models.CSATResponse) error {
+	workspace, err := s.workspaceRepo.FindByID(ctx, conv.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("find workspace: %w", err)
+	}
+	cfg := workspace.Settings.CSATConfig()
+
+	content := cfg.ThankYouMessage
+	if response.Status == models.CSATAwaitingComment {
+		content = cfg.CommentPrompt
+	}
+
+	_, err = s.outboundService.Send(ctx, OutboundInput{
+		Conversation: conv,
+		SenderType:   models.SenderBot,
+		Content:      content,
+	})
+	return err
+}
+
+// ListResponses lấy danh sách khảo sát
+func (s *csatService) ListResponses(ctx context.Context, actor Actor, input CSATListInput) ([]models.CSATResponse, int64, error) {
+	filters := map[string]interface{}{}
+	if !actor.IsAdmin() {
+		filters["agent_id"] = actor.UserID
+	} else if input.AgentID != nil {
+		filters["agent_id"] = *input.AgentID
+	}
+	if input.ChannelAccountID != nil {
+		filters["channel_account_id"] = *input.ChannelAccountID
+	}
+	if input.From != nil {
+		filters["from"] = *input.From
+	}
+	if input.To != nil {
+		filters["to"] = *input.To
+	}
+	if input.Score != nil {
+		filters["score"] = *input.Score
+	}
+
+	return s.csatRepo.FindByWorkspace(ctx, actor.WorkspaceID, repositories.FindOptions{
+		Offset:  input.Offset,
+		Limit:   input.Limit,
+		Filters: filters,
+	})
+}
+
+// Report tổng hợp CSAT
+func (s *csatService) Report(ctx context.Context, actor Actor, input CSATReportInput) (*CSATReport, error) {
+	switch input.GroupBy {
+	case repositories.CSATGroupNone, repositories.CSATGroupAgent, repositories.CSATGroupChannelAccount,
+		repositories.CSATGroupDay, repositories.CSATGroupWeek, repositories.CSATGroupMonth:
+	default:
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "group_by không hợp lệ: "+string(input.GroupBy))
+	}
+
+	to := time.Now()
+	if input.To != nil {
+		to = *input.To
+	}
+	from := to.AddDate(0, 0, -30)
+	if input.From != nil {
+		from = *input.From
+	}
+	if !from.Before(to) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Khoảng thời gian không hợp lệ")
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find workspace: %w", err)
+	}
+	timezone := workspace.Settings.Timezone
+	if _, err := time.LoadLocation(timezone); timezone == "" || err != nil {
+		timezone = "UTC"
+	}
+
+	query := repositories.CSATReportQuery{
+		WorkspaceID:      actor.WorkspaceID,
+		From:             from,
+		To:               to,
+		AgentID:          input.AgentID,
+		ChannelAccountID: input.ChannelAccountID,
+		Timezone:         timezone,
+	}
+	// Agent chỉ xem được số liệu của chính mình
+	if !actor.IsAdmin() {
+		query.AgentID = &actor.UserID
+	}
+
+	summary, err := s.csatRepo.Report(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("csat summary: %w", err)
+	}
+
+	report := &CSATReport{From: from, To: to, GroupBy: input.GroupBy}
+	if len(summary) > 0 {
+		report.Summary = newCSATReportEntry(summary[0], "")
+	}
+
+	if input.GroupBy == repositories.CSATGroupNone {
+		return report, nil
+	}
+
+	query.GroupBy = input.GroupBy
+	rows, err := s.csatRepo.Report(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("csat report: %w", err)
+	}
+
+	labels := s.groupLabels(ctx, actor.WorkspaceID, input.GroupBy)
+	report.Rows = make([]CSATReportEntry, 0, len(rows))
+	for _, row := range rows {
+		report.Rows = append(report.Rows, newCSATReportEntry(row, labels[row.Key]))
+	}
+	return report, nil
+}
+
+// groupLabels tên hiển thị cho các nhóm agent / kênh
+func (s *csatService) groupLabels(ctx context.Context, workspaceID uuid.UUID, groupBy repositories.CSATGroupBy) map[string]string {
+	labels := map[string]string{}
+
+	switch groupBy {
+	case repositories.CSATGroupAgent:
+		labels[""] = "Bot"
+		users, _, err := s.userRepo.FindByWorkspace(ctx, workspaceID, repositories.FindOptions{})
+		if err != nil {
+			s.logger.Warn("failed to load agents for csat report", zap.Error(err))
+			return labels
+		}
+		for _, user := range users {
+			labels[user.ID.String()] = user.Name
+		}
+
+	case repositories.CSATGroupChannelAccount:
+		accounts, err := s.channelAccountRepo.FindByWorkspace(ctx, workspaceID)
+		if err != nil {
+			s.logger.Warn("failed to load channels for csat report", zap.Error(err))
+			return labels
+		}
+		for _, account := range accounts {
+			labels[account.ID.String()] = account.Name
+		}
+	}
+
+	return labels
+}
+
+// newCSATReportEntry tính tỉ lệ cho một dòng báo cáo
+func newCSATReportEntry(row repositories.CSATReportRow, label string) CSATReportEntry {
+	entry := CSATReportEntry{CSATReportRow: row, Label: label}
+	if row.Sent > 0 {
+		entry.ResponseRate = roundPercent(row.Responded, row.Sent)
+	}
+	if row.Responded > 0 {
+		entry.CSATPercent = roundPercent(row.Satisfied, row.Responded)
+	}
+	return entry
+}
+
+// roundPercent tính phần trăm làm tròn 1 chữ số thập phân
+func roundPercent(part, total int64) float64 {
+	return float64(part*1000/total) / 10
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Custom Field Service Interface
+// Workspace định nghĩa trường tùy chỉnh của khách (key, kiểu, bắt buộc, lựa chọn)
+// Mọi chỗ ghi giá trị (API sửa khách, bot rule) và lọc/sắp xếp theo trường đều
+// kiểm tra qua định nghĩa này
+// ===========================================================================
+
+// CustomFieldInput dữ liệu tạo định nghĩa trường
+type CustomFieldInput struct {
+	Key      string
+	Label    string
+	Type     models.CustomFieldType
+	Required bool
+	Options  []string
+	Position int
+}
+
+// UpdateCustomFieldInput dữ liệu sửa định nghĩa trường (nil = không đổi)
+// Key và kiểu không đổi được vì giá trị đã lưu của khách phụ thuộc vào chúng
+type UpdateCustomFieldInput struct {
+	Label    *string
+	Required *bool
+	Options  *[]string
+	Position *int
+}
+
+// CustomFieldService interface cho định nghĩa trường tùy chỉnh
+type CustomFieldService interface {
+	// List lấy mọi định nghĩa trường của workspace theo thứ tự hiển thị
+	List(ctx context.Context, actor Actor) ([]models.CustomFieldDefinition, error)
+
+	// Create tạo định nghĩa trường (chỉ admin)
+	Create(ctx context.Context, actor Actor, input CustomFieldInput) (*models.CustomFieldDefinition, error)
+
+	// Update sửa định nghĩa trường (chỉ admin)
+	Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateCustomFieldInput) (*models.CustomFieldDefinition, error)
+
+	// Delete xóa định nghĩa trường (chỉ admin), giá trị đã lưu của khách được giữ nguyên
+	Delete(ctx context.Context, actor Actor, id uuid.UUID) error
+
+	// ParseValues kiểm tra và chuẩn hóa giá trị theo định nghĩa của workspace (null = xóa trường)
+	// Key chưa được định nghĩa bị từ chối
+	ParseValues(ctx context.Context, workspaceID uuid.UUID, values map[string]interface{}) (map[string]interface{}, error)
+
+	// ApplyValues kiểm tra rồi đặt/xóa giá trị trên metadata của khách, trả về các thay đổi
+	// Không được xóa giá trị của trường bắt buộc
+	ApplyValues(ctx context.Context, workspaceID uuid.UUID, metadata *models.ParticipantMetadata, values map[string]interface{}) (map[string]interface{}, error)
+
+	// ParseConditions kiểm tra toán tử và chuẩn hóa giá trị điều kiện theo kiểu trường
+	ParseConditions(ctx context.Context, workspaceID uuid.UUID, conditions []models.FieldCondition) ([]models.FieldCondition, error)
+
+	// CheckSortField kiểm tra key dùng để sắp xếp đã được định nghĩa
+	CheckSortField(ctx context.Context, workspaceID uuid.UUID, key string) error
+}
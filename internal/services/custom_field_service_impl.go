@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Custom Field Service Implementation
+// ===========================================================================
+
+const (
+	// maxCustomFields số định nghĩa trường tối đa của một workspace
+	maxCustomFields = 50
+
+	// maxCustomFieldOptions số lựa chọn tối đa của trường select
+	maxCustomFieldOptions = 100
+
+	// maxFieldConditions số điều kiện lọc theo trường tối đa trong một lần
+	maxFieldConditions = 10
+)
+
+// customFieldService triển khai CustomFieldService
+type customFieldService struct {
+	fieldRepo repositories.CustomFieldRepository
+	logger    *zap.Logger
+}
+
+// NewCustomFieldService tạo instance mới của CustomFieldService
+func NewCustomFieldService(fieldRepo repositories.CustomFieldRepository, logger *zap.Logger) CustomFieldService {
+	return &customFieldService{
+		fieldRepo: fieldRepo,
+		logger:    logger,
+	}
+}
+
+// List lấy mọi định nghĩa trường của workspace
+func (s *customFieldService) List(ctx context.Context, actor Actor) ([]models.CustomFieldDefinition, error) {
+	fields, err := s.fieldRepo.FindByWorkspace(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list custom fields: %w", err)
+	}
+	return fields, nil
+}
+
+// Create tạo định nghĩa trường
+func (s *customFieldService) Create(ctx context.Context, actor Actor, input CustomFieldInput) (*models.CustomFieldDefinition, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được tạo trường tùy chỉnh")
+	}
+
+	field := &models.CustomFieldDefinition{
+		WorkspaceID: actor.WorkspaceID,
+		Key:         strings.TrimSpace(input.Key),
+		Label:       strings.TrimSpace(input.Label),
+		Type:        input.Type,
+		Required:    input.Required,
+		Options:     models.StringList(input.Options),
+		Position:    input.Position,
+	}
+	if !models.CustomFieldKeyPattern.MatchString(field.Key) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput,
+			"Key chỉ gồm chữ thường, số, gạch dưới, bắt đầu bằng chữ và tối đa 50 ký tự")
+	}
+	if !field.Type.IsValidType() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Kiểu trường không được hỗ trợ")
+	}
+	if err := validateCustomField(field); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.fieldRepo.FindByWorkspace(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find custom fields: %w", err)
+	}
+	if len(existing) >= maxCustomFields {
+		return nil, apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("Mỗi workspace có tối đa %d trường tùy chỉnh", maxCustomFields))
+	}
+	for _, other := range existing {
+		if other.Key == field.Key {
+			return nil, apperrors.New(apperrors.ErrDuplicateEntry, "Key "+field.Key+" đã được dùng")
+		}
+	}
+
+	if err := s.fieldRepo.Create(ctx, field); err != nil {
+		return nil, fmt.Errorf("create custom field: %w", err)
+	}
+
+	s.logger.Info("custom field created",
+		zap.String("field_id", field.ID.String()),
+		zap.String("key", field.Key),
+		zap.String("type", string(field.Type)),
+	)
+	return field, nil
+}
+
+// Update sửa định nghĩa trường
+func (s *customFieldService) Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateCustomFieldInput) (*models.CustomFieldDefinition, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được sửa trường tùy chỉnh")
+	}
+	field, err := s.loadField(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Label != nil {
+		field.Label = strings.TrimSpace(*input.Label)
+	}
+	if input.Required != nil {
+		field.Required = *input.Required
+	}
+	if input.Options != nil {
+		field.Options = models.StringList(*input.Options)
+	}
+	if input.Position != nil {
+		field.Position = *input.Position
+	}
+	if err := validateCustomField(field); err != nil {
+		return nil, err
+	}
+
+	if err := s.fieldRepo.Update(ctx, field); err != nil {
+		return nil, fmt.Errorf("update custom field: %w", err)
+	}
+	return field, nil
+}
+
+// Delete xóa định nghĩa trường
+func (s *customFieldService) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	if !actor.IsAdmin() {
+		return apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được xóa trường tùy chỉnh")
+	}
+	field, err := s.loadField(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.fieldRepo.Delete(ctx, field.ID); err != nil {
+		return fmt.Errorf("delete custom field: %w", err)
+	}
+
+	s.logger.Info("custom field deleted",
+		zap.String("field_id", field.ID.String()),
+		zap.String("key", field.Key),
+		zap.String("user_id", actor.UserID.String()),
+	)
+	return nil
+}
+
+// ParseValues kiểm tra và chuẩn hóa giá trị theo định nghĩa
+func (s *customFieldService) ParseValues(ctx context.Context, workspaceID uuid.UUID, values map[string]interface{}) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return values, nil
+	}
+	definitions, err := s.definitions(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return parseFieldValues(definitions, values)
+}
+
+// ApplyValues kiểm tra rồi đặt/xóa giá trị trên metadata của khách
+func (s *customFieldService) ApplyValues(ctx context.Context, workspaceID uuid.UUID, metadata *models.ParticipantMetadata, values map[string]interface{}) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	definitions, err := s.definitions(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := parseFieldValues(definitions, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.CustomFields == nil {
+		metadata.CustomFields = map[string]interface{}{}
+	}
+	changes := map[string]interface{}{}
+	for key, value := range parsed {
+		old, exists := metadata.CustomFields[key]
+		if value == nil {
+			if !exists {
+				continue
+			}
+			if definitions[key].Required {
+				return nil, apperrors.New(apperrors.ErrInvalidInput, "Trường "+definitions[key].Label+" là bắt buộc")
+			}
+			delete(metadata.CustomFields, key)
+		} else {
+			if exists && old == value {
+				continue
+			}
+			metadata.CustomFields[key] = value
+		}
+		changes[key] = map[string]interface{}{"from": old, "to": value}
+	}
+	return changes, nil
+}
+
+// ParseConditions kiểm tra và chuẩn hóa điều kiện lọc theo trường
+func (s *customFieldService) ParseConditions(ctx context.Context, workspaceID uuid.UUID, conditions []models.FieldCondition) ([]models.FieldCondition, error) {
+	if len(conditions) == 0 {
+		return conditions, nil
+	}
+	if len(conditions) > maxFieldConditions {
+		return nil, apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("Tối đa %d điều kiện theo trường tùy chỉnh", maxFieldConditions))
+	}
+	definitions, err := s.definitions(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make([]models.FieldCondition, 0, len(conditions))
+	for _, cond := range conditions {
+		field, ok := definitions[cond.Field]
+		if !ok {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "Trường "+cond.Field+" chưa được định nghĩa")
+		}
+		cond, err := field.ParseCondition(cond)
+		if err != nil {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Điều kiện trường %s: %s", field.Label, err.Error()))
+		}
+		parsed = append(parsed, cond)
+	}
+	return parsed, nil
+}
+
+// CheckSortField kiểm tra key dùng để sắp xếp
+func (s *customFieldService) CheckSortField(ctx context.Context, workspaceID uuid.UUID, key string) error {
+	definitions, err := s.definitions(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if _, ok := definitions[key]; !ok {
+		return apperrors.New(apperrors.ErrInvalidInput, "Trường "+key+" chưa được định nghĩa")
+	}
+	return nil
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// loadField tải định nghĩa trường và kiểm tra thuộc workspace của actor
+func (s *customFieldService) loadField(ctx context.Context, actor Actor, id uuid.UUID) (*models.CustomFieldDefinition, error) {
+	field, err := s.fieldRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy trường tùy chỉnh")
+		}
+		return nil, fmt.Errorf("find custom field: %w", err)
+	}
+	if field.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy trường tùy chỉnh")
+	}
+	return field, nil
+}
+
+// definitions định nghĩa trường của workspace theo key
+func (s *customFieldService) definitions(ctx context.Context, workspaceID uuid.UUID) (map[string]*models.CustomFieldDefinition, error) {
+	fields, err := s.fieldRepo.FindByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find custom fields: %w", err)
+	}
+	byKey := make(map[string]*models.CustomFieldDefinition, len(fields))
+	for i := range fields {
+		byKey[fields[i].Key] = &fields[i]
+	}
+	return byKey, nil
+}
+
+// parseFieldValues kiểm tra và chuẩn hóa giá trị theo định nghĩa (null = xóa trường)
+func parseFieldValues(definitions map[string]*models.CustomFieldDefinition, values map[string]interface{}) (map[string]interface{}, error) {
+	parsed := make(map[string]interface{}, len(values))
+	for rawKey, value := range values {
+		key := strings.TrimSpace(rawKey)
+		field, ok := definitions[key]
+		if !ok {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "Trường "+key+" chưa được định nghĩa")
+		}
+		v, err := field.Parse(value)
+		if err != nil {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Trường %s: %s", field.Label, err.Error()))
+		}
+		parsed[key] = v
+	}
+	return parsed, nil
+}
+
+// validateCustomField kiểm tra label và lựa chọn, bỏ lựa chọn trùng
+func validateCustomField(field *models.CustomFieldDefinition) error {
+	if field.Label == "" || len([]rune(field.Label)) > 255 {
+		return apperrors.New(apperrors.ErrInvalidInput, "Tên hiển thị không hợp lệ")
+	}
+
+	if field.Type != models.FieldSelect {
+		if len(field.Options) > 0 {
+			return apperrors.New(apperrors.ErrInvalidInput, "Chỉ trường select mới có lựa chọn")
+		}
+		field.Options = models.StringList{}
+		return nil
+	}
+
+	options := make(models.StringList, 0, len(field.Options))
+	seen := make(map[string]bool, len(field.Options))
+	for _, option := range field.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || len([]rune(option)) > 100 {
+			return apperrors.New(apperrors.ErrInvalidInput, "Lựa chọn không hợp lệ")
+		}
+		if key := strings.ToLower(option); !seen[key] {
+			seen[key] = true
+			options = append(options, option)
+		}
+	}
+	if len(options) == 0 {
+		return apperrors.New(apperrors.ErrInvalidInput, "Trường select cần ít nhất một lựa chọn")
+	}
+	if len(options) > maxCustomFieldOptions {
+		return apperrors.New(apperrors.ErrInvalidInput,
+			fmt.Sprintf("Trường select có tối đa %d lựa chọn", maxCustomFieldOptions))
+	}
+	field.Options = options
+	return nil
+}
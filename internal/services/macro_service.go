@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Macro Service Interface
+// Macro gom nhiều thao tác trên hội thoại, admin định nghĩa, agent chạy bằng một click
+// Mỗi lần chạy được ghi trong một transaction kèm một bản ghi audit
+// ===========================================================================
+
+// MacroInput dữ liệu tạo macro
+type MacroInput struct {
+	Name        string
+	Description string
+	Actions     []models.MacroAction
+	IsActive    *bool
+}
+
+// UpdateMacroInput dữ liệu sửa macro (nil = không đổi)
+type UpdateMacroInput struct {
+	Name        *string
+	Description *string
+	Actions     *[]models.MacroAction
+	IsActive    *bool
+}
+
+// MacroResult kết quả chạy macro
+type MacroResult struct {
+	Conversation *models.Conversation `json:"conversation"`
+	Message      *models.Message      `json:"message,omitempty"`
+	Audit        *models.AuditLog     `json:"audit"`
+}
+
+// MacroService interface cho macro
+type MacroService interface {
+	// List lấy danh sách macro của workspace (activeOnly: chỉ macro đang bật)
+	List(ctx context.Context, actor Actor, activeOnly bool) ([]models.Macro, error)
+
+	// Get lấy chi tiết macro
+	Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.Macro, error)
+
+	// Create tạo macro (chỉ admin)
+	Create(ctx context.Context, actor Actor, input MacroInput) (*models.Macro, error)
+
+	// Update sửa macro (chỉ admin)
+	Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateMacroInput) (*models.Macro, error)
+
+	// Delete xóa macro (chỉ admin)
+	Delete(ctx context.Context, actor Actor, id uuid.UUID) error
+
+	// Execute chạy macro trên conversation
+	// Mọi thay đổi được ghi cùng lúc (lỗi thì không có gì thay đổi), sau đó mới gửi tin nhắn
+	// qua channel và publish một conversation_update duy nhất
+	Execute(ctx context.Context, actor Actor, conversationID, macroID uuid.UUID) (*MacroResult, error)
+}
@@ -0,0 +1,646 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/routing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Macro Service Implementation
+// ===========================================================================
+
+// maxMacroActions số thao tác tối đa của một macro
+const maxMacroActions = 20
+
+// macroService triển khai MacroService
+type macroService struct {
+	macroRepo        repositories.MacroRepository
+	conversationRepo repositories.ConversationRepository
+	tagRepo          repositories.TagRepository
+	userRepo         repositories.UserRepository
+	router           routing.Router
+	cannedService    CannedResponseService
+	outboundService  OutboundService
+	csatService      CSATService
+	publisher        realtime.Publisher
+	logger           *zap.Logger
+}
+
+// NewMacroService tạo instance mới của MacroService
+func NewMacroService(
+	macroRepo repositories.MacroRepository,
+	conversationRepo repositories.ConversationRepository,
+	tagRepo repositories.TagRepository,
+	userRepo repositories.UserRepository,
+	router routing.Router,
+	cannedService CannedResponseService,
+	outboundService OutboundService,
+	csatService CSATService,
+	publisher realtime.Publisher,
+	logger *zap.Logger,
+) MacroService {
+	return &macroService{
+		macroRepo:        macroRepo,
+		conversationRepo: conversationRepo,
+		tagRepo:          tagRepo,
+		userRepo:         userRepo,
+		router:           router,
+		cannedService:    cannedService,
+		outboundService:  outboundService,
+		csatService:      csatService,
+		publisher:        publisher,
+		logger:           logger,
+	}
+}
+
+// List lấy danh sách macro của workspace
+func (s *macroService) List(ctx context.Context, actor Actor, activeOnly bool) ([]models.Macro, error) {
+	macros, err := s.macroRepo.FindByWorkspace(ctx, actor.WorkspaceID, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("list macros: %w", err)
+	}
+	return macros, nil
+}
+
+// Get lấy chi tiết macro
+func (s *macroService) Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.Macro, error) {
+	return s.loadMacro(ctx, actor, id)
+}
+
+// Create tạo macro
+func (s *macroService) Create(ctx context.Context, actor Actor, input MacroInput) (*models.Macro, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được tạo macro")
+	}
+
+	macro := &models.Macro{
+		WorkspaceID: actor.WorkspaceID,
+		CreatedBy:   actor.UserID,
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		Actions:     models.MacroActions(input.Actions),
+		IsActive:    true,
+	}
+	if input.IsActive != nil {
+		macro.IsActive = *input.IsActive
+	}
+	if err := s.validate(ctx, actor, macro); err != nil {
+		return nil, err
+	}
+
+	if err := s.macroRepo.Create(ctx, macro); err != nil {
+		return nil, fmt.Errorf("create macro: %w", err)
+	}
+
+	s.logger.Info("macro created",
+		zap.String("macro_id", macro.ID.String()),
+		zap.Int("actions", len(macro.Actions)),
+	)
+
+	return macro, nil
+}
+
+// Update sửa macro
+func (s *macroService) Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateMacroInput) (*models.Macro, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được sửa macro")
+	}
+	macro, err := s.loadMacro(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		macro.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		macro.Description = *input.Description
+	}
+	if input.Actions != nil {
+		macro.Actions = models.MacroActions(*input.Actions)
+	}
+	if input.IsActive != nil {
+		macro.IsActive = *input.IsActive
+	}
+	if err := s.validate(ctx, actor, macro); err != nil {
+		return nil, err
+	}
+
+	if err := s.macroRepo.Update(ctx, macro); err != nil {
+		return nil, fmt.Errorf("update macro: %w", err)
+	}
+	return macro, nil
+}
+
+// Delete xóa macro
+func (s *macroService) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	if !actor.IsAdmin() {
+		return apperrors.New(apperrors.ErrForbidden, "Chỉ admin mới được xóa macro")
+	}
+	macro, err := s.loadMacro(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.macroRepo.Delete(ctx, macro.ID); err != nil {
+		return fmt.Errorf("delete macro: %w", err)
+	}
+
+	s.logger.Info("macro deleted",
+		zap.String("macro_id", macro.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+	)
+	return nil
+}
+
+// Execute chạy macro trên conversation
+func (s *macroService) Execute(ctx context.Context, actor Actor, conversationID, macroID uuid.UUID) (*MacroResult, error) {
+	conv, err := s.loadConversation(ctx, actor, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	macro, err := s.loadMacro(ctx, actor, macroID)
+	if err != nil {
+		return nil, err
+	}
+	if !macro.IsActive {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Macro đang tắt")
+	}
+
+	before := *conv
+	app := &repositories.MacroApplication{
+		MacroID:      macro.ID,
+		Conversation: conv,
+		TaggedBy:     &actor.UserID,
+	}
+	data := models.AuditData{
+		"macro_id":   macro.ID,
+		"macro_name": macro.Name,
+	}
+	var canned *CannedExpansion
+	var addedTags, removedTags []string
+	justClosed := false
+
+	// Áp dụng lần lượt lên conversation trong bộ nhớ, chưa ghi gì vào database
+	for _, action := range macro.Actions {
+		switch action.Type {
+		case models.MacroSendReply:
+			app.Message, canned, err = s.buildReply(ctx, actor, conv, action)
+			if err != nil {
+				return nil, err
+			}
+			data["message_id"] = app.Message.ID
+
+		case models.MacroAddTags:
+			// Tag chưa có được tạo cùng transaction với macro, lỗi thì không để lại tag thừa
+			for _, name := range action.Tags {
+				tag, err := s.resolveTag(ctx, conv.WorkspaceID, name, app.AddTags)
+				if err != nil {
+					return nil, err
+				}
+				app.AddTags = append(app.AddTags, tag)
+				addedTags = append(addedTags, tag.Name)
+			}
+
+		case models.MacroRemoveTags:
+			for _, name := range action.Tags {
+				tag, err := s.tagRepo.FindByName(ctx, conv.WorkspaceID, strings.TrimSpace(name))
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("find tag: %w", err)
+				}
+				app.RemoveTagIDs = append(app.RemoveTagIDs, tag.ID)
+				removedTags = append(removedTags, tag.Name)
+			}
+
+		case models.MacroSetPriority:
+			conv.Priority = action.Priority
+
+		case models.MacroAssign:
+			if err := s.applyAssign(ctx, actor, conv, action, app, data); err != nil {
+				return nil, err
+			}
+
+		case models.MacroPauseBot:
+			conv.PauseBot(action.Reason)
+
+		case models.MacroResumeBot:
+			conv.ResumeBot()
+
+		case models.MacroClose:
+			if !conv.IsClosed() {
+				conv.Close(action.Reason)
+				justClosed = true
+			}
+		}
+	}
+
+	data["actions"] = macroActionTypes(macro.Actions)
+	data["changes"] = conversationChanges(&before, conv)
+	if len(addedTags) > 0 {
+		data["tags_added"] = addedTags
+	}
+	if len(removedTags) > 0 {
+		data["tags_removed"] = removedTags
+	}
+	actorID := actor.UserID
+	app.Audit = &models.AuditLog{
+		WorkspaceID: conv.WorkspaceID,
+		ActorID:     &actorID,
+		Action:      models.AuditMacroExecuted,
+		EntityType:  models.AuditEntityConversation,
+		EntityID:    conv.ID,
+		Data:        data,
+	}
+
+	if err := s.macroRepo.Apply(ctx, app); err != nil {
+		return nil, fmt.Errorf("apply macro: %w", err)
+	}
+
+	s.afterApply(ctx, macro, conv, app.Message, canned, justClosed)
+
+	s.logger.Info("macro executed",
+		zap.String("macro_id", macro.ID.String()),
+		zap.String("conversation_id", conv.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+	)
+
+	return &MacroResult{
+		Conversation: conv,
+		Message:      app.Message,
+		Audit:        app.Audit,
+	}, nil
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// buildReply tạo tin nhắn trả lời (chưa lưu) và cập nhật tin nhắn cuối của conversation
+func (s *macroService) buildReply(ctx context.Context, actor Actor, conv *models.Conversation, action models.MacroAction) (*models.Message, *CannedExpansion, error) {
+	// Macro không chọn message tag: ngoài cửa sổ nhắn tin thì theo chính sách block/warn
+	outsideWindow, err := s.outboundService.CheckReply(ctx, conv, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var canned *CannedExpansion
+	content := ""
+	var attachments models.Attachments
+	var quickReplies []models.QuickReply
+
+	if action.CannedResponseID != nil {
+		expansion, err := s.cannedService.Expand(ctx, actor, conv, *action.CannedResponseID)
+		if err != nil {
+			return nil, nil, err
+		}
+		canned = expansion
+		content = expansion.Content
+		attachments = expansion.Attachments
+		quickReplies = expansion.QuickReplies
+	} else {
+		content = s.cannedService.RenderText(ctx, actor, conv, action.Content)
+	}
+
+	senderID := actor.UserID
+	message := &models.Message{
+		ConversationID: conv.ID,
+		Direction:      models.DirectionOut,
+		SenderType:     models.SenderAgent,
+		SenderID:       &senderID,
+		ContentType:    models.ContentText,
+		Attachments:    attachments,
+		Metadata: models.MessageMetadata{
+			QuickReplies:  quickReplies,
+			OutsideWindow: outsideWindow,
+		},
+	}
+	// ID tạo trước để ghi vào audit trong cùng transaction
+	message.ID = uuid.New()
+	if content != "" {
+		message.Content = &content
+	}
+	if len(attachments) > 0 {
+		message.ContentType = AttachmentContentType(attachments)
+	}
+	if len(quickReplies) > 0 {
+		message.ContentType = models.ContentQuickReply
+	}
+	if canned != nil {
+		cannedID := canned.CannedResponse.ID
+		message.Metadata.CannedResponseID = &cannedID
+	}
+
+	preview := content
+	if preview == "" {
+		preview = attachments.Preview()
+	}
+	now := time.Now()
+	conv.UpdateLastMessage(preview, now)
+	conv.SetFirstResponse(now)
+
+	return message, canned, nil
+}
+
+// applyAssign assign cho agent, cho team (chọn agent có kỹ năng team) hoặc bỏ assign
+func (s *macroService) applyAssign(ctx context.Context, actor Actor, conv *models.Conversation, action models.MacroAction, app *repositories.MacroApplication, data models.AuditData) error {
+	switch {
+	case action.UserID != nil:
+		users, err := s.userRepo.FindByIDs(ctx, conv.WorkspaceID, []uuid.UUID{*action.UserID})
+		if err != nil {
+			return fmt.Errorf("find assignee: %w", err)
+		}
+		if len(users) == 0 {
+			return apperrors.New(apperrors.ErrInvalidInput, "Agent được assign không tồn tại hoặc đã ngừng hoạt động")
+		}
+		conv.Assign(users[0].ID)
+
+	case action.Team != "":
+		log, err := s.router.PickByTeam(ctx, conv, action.Team, models.RoutingTriggerMacro)
+		if err != nil {
+			return fmt.Errorf("pick team agent: %w", err)
+		}
+		app.RoutingLog = log
+		data["routing"] = log.Reason
+		// Không có ai trong team đủ điều kiện thì giữ nguyên người đang xử lý
+		if log.IsAssigned() {
+			conv.Assign(*log.AssignedTo)
+		}
+
+	default:
+		conv.Unassign()
+	}
+	return nil
+}
+
+// afterApply gửi tin nhắn qua channel, khảo sát CSAT và publish realtime sau khi đã ghi xong
+func (s *macroService) afterApply(ctx context.Context, macro *models.Macro, conv *models.Conversation, message *models.Message, canned *CannedExpansion, justClosed bool) {
+	if canned != nil {
+		s.cannedService.RecordUsage(ctx, canned.CannedResponse.ID)
+	}
+
+	// Bản sao để goroutine không đụng conversation đang trả về
+	snapshot := *conv
+	if message != nil || justClosed {
+		go func() {
+			bg := context.Background()
+			if message != nil {
+				if err := s.outboundService.Deliver(bg, &snapshot, message); err != nil {
+					s.logger.Warn("macro: send reply failed",
+						zap.String("message_id", message.ID.String()),
+						zap.Error(err),
+					)
+				}
+			}
+			// Khảo sát gửi sau tin trả lời để khách nhận đúng thứ tự
+			if justClosed {
+				if err := s.csatService.SendSurvey(bg, &snapshot); err != nil {
+					s.logger.Warn("macro: send survey failed",
+						zap.String("conversation_id", snapshot.ID.String()),
+						zap.Error(err),
+					)
+				}
+			}
+		}()
+	}
+
+	if s.publisher == nil {
+		return
+	}
+
+	event := &realtime.ConversationEvent{
+		ConversationID: conv.ID,
+		Status:         string(conv.Status),
+		Priority:       string(conv.Priority),
+		MacroID:        macro.ID.String(),
+	}
+	if conv.AssignedTo != nil {
+		event.AssignedTo = conv.AssignedTo.String()
+	}
+	if detail, err := s.conversationRepo.FindDetailByID(ctx, conv.ID); err == nil {
+		event.Tags = make([]string, 0, len(detail.Tags))
+		for _, tag := range detail.Tags {
+			event.Tags = append(event.Tags, tag.Name)
+		}
+	}
+
+	var messageEvent *realtime.MessageEvent
+	if message != nil {
+		content := ""
+		if message.Content != nil {
+			content = *message.Content
+		}
+		messageEvent = &realtime.MessageEvent{
+			MessageID:      message.ID,
+			ConversationID: conv.ID,
+			Direction:      string(message.Direction),
+			SenderType:     string(message.SenderType),
+			Content:        content,
+			CreatedAt:      message.CreatedAt,
+		}
+	}
+
+	go func() {
+		if messageEvent != nil {
+			if err := s.publisher.PublishNewMessage(conv.WorkspaceID, messageEvent); err != nil {
+				s.logger.Warn("failed to publish macro message", zap.Error(err))
+			}
+		}
+		if err := s.publisher.PublishConversationUpdate(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish macro conversation update", zap.Error(err))
+		}
+	}()
+}
+
+// resolveTag tìm tag theo tên, chưa có thì trả về tag mới chưa lưu (Apply tạo trong transaction)
+// Tên trùng với tag đã chọn trước đó trong cùng macro dùng lại tag đó
+func (s *macroService) resolveTag(ctx context.Context, workspaceID uuid.UUID, name string, selected []*models.Tag) (*models.Tag, error) {
+	name = strings.TrimSpace(name)
+	for _, tag := range selected {
+		if strings.EqualFold(tag.Name, name) {
+			return tag, nil
+		}
+	}
+
+	tag, err := s.tagRepo.FindByName(ctx, workspaceID, name)
+	if err == nil {
+		return tag, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("find tag: %w", err)
+	}
+	return &models.Tag{WorkspaceID: workspaceID, Name: name}, nil
+}
+
+// findOrCreateTag tìm tag theo tên, tạo mới nếu workspace chưa có
+func findOrCreateTag(ctx context.Context, tagRepo repositories.TagRepository, workspaceID uuid.UUID, name string) (*models.Tag, error) {
+	name = strings.TrimSpace(name)
+	tag, err := tagRepo.FindByName(ctx, workspaceID, name)
+	if err == nil {
+		return tag, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("find tag: %w", err)
+	}
+
+	tag = &models.Tag{WorkspaceID: workspaceID, Name: name}
+	if err := tagRepo.Create(ctx, tag); err != nil {
+		return nil, fmt.Errorf("create tag: %w", err)
+	}
+	return tag, nil
+}
+
+// validate kiểm tra tên và các thao tác của macro
+func (s *macroService) validate(ctx context.Context, actor Actor, macro *models.Macro) error {
+	if macro.Name == "" {
+		return apperrors.New(apperrors.ErrInvalidInput, "Tên macro không được để trống")
+	}
+	if len(macro.Actions) == 0 || len(macro.Actions) > maxMacroActions {
+		return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Macro cần từ 1 đến %d thao tác", maxMacroActions))
+	}
+
+	replies := 0
+	for i, action := range macro.Actions {
+		invalid := func(msg string) error {
+			return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Thao tác %d (%s): %s", i+1, action.Type, msg))
+		}
+
+		switch action.Type {
+		case models.MacroSendReply:
+			replies++
+			if replies > 1 {
+				return invalid("mỗi macro chỉ gửi một tin nhắn")
+			}
+			if (strings.TrimSpace(action.Content) == "") == (action.CannedResponseID == nil) {
+				return invalid("cần content hoặc canned_response_id (chỉ một trong hai)")
+			}
+			if action.CannedResponseID != nil {
+				// Macro dùng chung cho cả workspace nên chỉ được dùng câu trả lời shared
+				response, err := s.cannedService.Get(ctx, actor, *action.CannedResponseID)
+				if err != nil {
+					return err
+				}
+				if response.IsPersonal() {
+					return invalid("chỉ dùng được câu trả lời soạn sẵn dùng chung")
+				}
+			} else if err := s.cannedService.ValidateText(action.Content); err != nil {
+				return err
+			}
+
+		case models.MacroAddTags, models.MacroRemoveTags:
+			if len(action.Tags) == 0 {
+				return invalid("cần ít nhất một tag")
+			}
+			for _, name := range action.Tags {
+				if name = strings.TrimSpace(name); name == "" || len(name) > 100 {
+					return invalid("tên tag không hợp lệ")
+				}
+			}
+
+		case models.MacroSetPriority:
+			switch action.Priority {
+			case models.PriorityLow, models.PriorityNormal, models.PriorityHigh, models.PriorityUrgent:
+			default:
+				return invalid("priority phải là low, normal, high hoặc urgent")
+			}
+
+		case models.MacroAssign:
+			if action.UserID != nil && action.Team != "" {
+				return invalid("chỉ chọn user_id hoặc team")
+			}
+			if action.UserID != nil {
+				users, err := s.userRepo.FindByIDs(ctx, actor.WorkspaceID, []uuid.UUID{*action.UserID})
+				if err != nil {
+					return fmt.Errorf("find assignee: %w", err)
+				}
+				if len(users) == 0 {
+					return invalid("agent không tồn tại hoặc đã ngừng hoạt động")
+				}
+			}
+
+		case models.MacroPauseBot, models.MacroResumeBot, models.MacroClose:
+
+		default:
+			return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Thao tác %d: loại %q không được hỗ trợ", i+1, action.Type))
+		}
+	}
+	return nil
+}
+
+// loadMacro lấy macro và kiểm tra thuộc workspace của actor
+func (s *macroService) loadMacro(ctx context.Context, actor Actor, id uuid.UUID) (*models.Macro, error) {
+	macro, err := s.macroRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy macro")
+		}
+		return nil, fmt.Errorf("find macro: %w", err)
+	}
+	if macro.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy macro")
+	}
+	return macro, nil
+}
+
+// loadConversation lấy conversation và kiểm tra thuộc workspace của actor
+func (s *macroService) loadConversation(ctx context.Context, actor Actor, conversationID uuid.UUID) (*models.Conversation, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+		}
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+	if conv.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+	}
+	return conv, nil
+}
+
+// macroActionTypes danh sách loại thao tác (ghi vào audit)
+func macroActionTypes(actions models.MacroActions) []string {
+	types := make([]string, len(actions))
+	for i, a := range actions {
+		types[i] = string(a.Type)
+	}
+	return types
+}
+
+// conversationChanges các trường conversation đã đổi: {"status": {"from": ..., "to": ...}}
+func conversationChanges(before, after *models.Conversation) map[string]interface{} {
+	changes := map[string]interface{}{}
+	change := func(field string, from, to interface{}) {
+		if from != to {
+			changes[field] = map[string]interface{}{"from": from, "to": to}
+		}
+	}
+
+	change("status", string(before.Status), string(after.Status))
+	change("priority", string(before.Priority), string(after.Priority))
+	change("assigned_to", uuidString(before.AssignedTo), uuidString(after.AssignedTo))
+	change("closed_reason", before.Metadata.ClosedReason, after.Metadata.ClosedReason)
+	return changes
+}
+
+// uuidString chuỗi của UUID (rỗng nếu nil)
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Media Service Interface
+// Tải file đính kèm của khách từ channel về blob storage (chạy nền),
+// tạo ảnh thu nhỏ và phục vụ file qua /api/v1/media/:id
+// ===========================================================================
+
+// MediaContent file mở để trả về cho client
+type MediaContent struct {
+	Media *models.Media
+
+	// Body nội dung file, caller phải Close (nil nếu RedirectURL khác rỗng)
+	Body io.ReadCloser
+
+	// ContentType MIME type của nội dung trả về
+	ContentType string
+
+	// Size dung lượng nội dung, -1 nếu không biết (ảnh thu nhỏ)
+	Size int64
+
+	// RedirectURL URL gốc trên channel khi file chưa tải xong
+	RedirectURL string
+}
+
+// UploadFile file agent upload để gửi kèm tin nhắn
+type UploadFile struct {
+	// Name tên file gốc
+	Name string
+
+	// Size dung lượng khai báo (bytes)
+	Size int64
+
+	// ContentType MIME type client khai báo, chỉ dùng khi không nhận diện được từ nội dung
+	ContentType string
+
+	// Body nội dung file
+	Body io.Reader
+}
+
+// MediaService interface cho file đính kèm
+type MediaService interface {
+	// IngestMessage tạo media cho các attachment có URL của tin nhắn và đưa vào hàng đợi tải
+	// Không chờ tải xong: attachment được cập nhật URL mới và publish media_update khi lưu xong
+	IngestMessage(ctx context.Context, workspaceID uuid.UUID, message *models.Message) error
+
+	// Open mở file (hoặc ảnh thu nhỏ) thuộc workspace của actor
+	Open(ctx context.Context, actor Actor, mediaID uuid.UUID, thumbnail bool) (*MediaContent, error)
+
+	// Upload kiểm tra số file, dung lượng, MIME type rồi lưu các file agent upload vào storage
+	// Trả về ErrInvalidInput nếu có file không hợp lệ (khi đó không file nào được lưu)
+	Upload(ctx context.Context, actor Actor, conv *models.Conversation, files []UploadFile) (models.Attachments, error)
+
+	// UploadLibrary giống Upload nhưng file không gắn với hội thoại nào
+	// Dùng cho file gửi lại nhiều lần (VD: đính kèm của câu trả lời soạn sẵn)
+	UploadLibrary(ctx context.Context, actor Actor, files []UploadFile) (models.Attachments, error)
+
+	// MaxUploadBytes dung lượng tối đa của một request upload (tất cả file)
+	MaxUploadBytes() int64
+
+	// OpenStored mở file đã lưu (không kiểm tra quyền), dùng khi upload file lên channel
+	OpenStored(ctx context.Context, mediaID uuid.UUID) (io.ReadCloser, error)
+
+	// RetryPending đưa các file tải lỗi (còn lượt thử) vào lại hàng đợi, chạy định kỳ
+	RetryPending(ctx context.Context) error
+
+	// Start khởi động các worker tải file
+	Start(ctx context.Context)
+
+	// Stop dừng worker và chờ file đang tải kết thúc
+	// File còn trong hàng đợi vẫn ở trạng thái pending và được RetryPending xử lý sau
+	Stop()
+}
@@ -0,0 +1,555 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"chatbox-gin/internal/config"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/media"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/storage"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Media Service Implementation
+// Hàng đợi trong bộ nhớ + worker pool; trạng thái bền vững nằm ở bảng media
+// nên file chưa tải xong khi server tắt sẽ được RetryPending tải lại
+// ===========================================================================
+
+// mediaQueueSize số file tối đa chờ trong hàng đợi, đầy thì để RetryPending xử lý
+const mediaQueueSize = 1000
+
+// mediaRetryBatch số file tối đa đưa lại vào hàng đợi mỗi lần quét
+const mediaRetryBatch = 200
+
+// errMediaPermanent lỗi không thể thành công khi thử lại (file quá lớn, URL đã hết hạn, ...)
+var errMediaPermanent = errors.New("permanent media error")
+
+// mediaService triển khai MediaService
+type mediaService struct {
+	mediaRepo   repositories.MediaRepository
+	messageRepo repositories.MessageRepository
+	store       storage.BlobStore
+	publisher   realtime.Publisher
+	cfg         config.MediaConfig
+	client      *http.Client
+	logger      *zap.Logger
+
+	queue chan uuid.UUID
+
+	// inflight media đang nằm trong hàng đợi hoặc đang tải, tránh tải trùng khi RetryPending quét
+	mu       sync.Mutex
+	inflight map[uuid.UUID]bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMediaService tạo instance mới của MediaService
+func NewMediaService(
+	mediaRepo repositories.MediaRepository,
+	messageRepo repositories.MessageRepository,
+	store storage.BlobStore,
+	publisher realtime.Publisher,
+	cfg config.MediaConfig,
+	logger *zap.Logger,
+) MediaService {
+	return &mediaService{
+		mediaRepo:   mediaRepo,
+		messageRepo: messageRepo,
+		store:       store,
+		publisher:   publisher,
+		cfg:         cfg,
+		client:      &http.Client{},
+		logger:      logger,
+		queue:       make(chan uuid.UUID, mediaQueueSize),
+		inflight:    make(map[uuid.UUID]bool),
+	}
+}
+
+// IngestMessage tạo media cho từng attachment và đưa vào hàng đợi
+func (s *mediaService) IngestMessage(ctx context.Context, workspaceID uuid.UUID, message *models.Message) error {
+	for i, att := range message.Attachments {
+		if att.URL == "" || att.MediaID != nil {
+			continue
+		}
+		item := &models.Media{
+			WorkspaceID:     workspaceID,
+			ConversationID:  &message.ConversationID,
+			MessageID:       &message.ID,
+			AttachmentIndex: i,
+			Source:          models.MediaSourceInbound,
+			Status:          models.MediaPending,
+			OriginalURL:     att.URL,
+			Name:            att.Name,
+			Size:            att.Size,
+			MimeType:        att.MimeType,
+		}
+		if err := s.mediaRepo.Create(ctx, item); err != nil {
+			return fmt.Errorf("create media: %w", err)
+		}
+		message.Attachments[i].MediaID = &item.ID
+		s.enqueue(item.ID)
+	}
+	return nil
+}
+
+// Open mở file trong storage
+func (s *mediaService) Open(ctx context.Context, actor Actor, mediaID uuid.UUID, thumbnail bool) (*MediaContent, error) {
+	item, err := s.mediaRepo.FindByID(ctx, mediaID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && item.WorkspaceID != actor.WorkspaceID) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find media: %w", err)
+	}
+
+	switch item.Status {
+	case models.MediaPending:
+		// Chưa tải xong: URL gốc trên channel thường vẫn còn hạn
+		return &MediaContent{Media: item, RedirectURL: item.OriginalURL}, nil
+	case models.MediaFailed:
+		return nil, apperrors.New(apperrors.ErrNotFound, "File không còn khả dụng")
+	}
+
+	key, contentType, size := item.StorageKey, item.MimeType, item.Size
+	if thumbnail && item.ThumbnailKey != "" && item.ThumbnailKey != item.StorageKey {
+		key, contentType, size = item.ThumbnailKey, "image/jpeg", -1
+	}
+
+	body, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "File không còn khả dụng")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open blob: %w", err)
+	}
+	return &MediaContent{Media: item, Body: body, ContentType: contentType, Size: size}, nil
+}
+
+// Upload lưu các file agent upload, lỗi ở một file thì xóa các file đã lưu trước đó
+func (s *mediaService) Upload(ctx context.Context, actor Actor, conv *models.Conversation, files []UploadFile) (models.Attachments, error) {
+	if conv.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy hội thoại")
+	}
+	conversationID := conv.ID
+	return s.uploadAll(ctx, actor, &conversationID, files)
+}
+
+// UploadLibrary lưu file dùng lại nhiều lần, không thuộc hội thoại nào
+func (s *mediaService) UploadLibrary(ctx context.Context, actor Actor, files []UploadFile) (models.Attachments, error) {
+	return s.uploadAll(ctx, actor, nil, files)
+}
+
+// uploadAll kiểm tra số file rồi lưu lần lượt, xóa các file đã lưu nếu có file lỗi
+func (s *mediaService) uploadAll(ctx context.Context, actor Actor, conversationID *uuid.UUID, files []UploadFile) (models.Attachments, error) {
+	if len(files) > s.cfg.MaxUploadFiles {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Chỉ gửi được tối đa %d file mỗi tin nhắn", s.cfg.MaxUploadFiles))
+	}
+
+	attachments := make(models.Attachments, 0, len(files))
+	saved := make([]*models.Media, 0, len(files))
+	for _, file := range files {
+		item, err := s.upload(ctx, actor, conversationID, file)
+		if err != nil {
+			for _, m := range saved {
+				s.discard(ctx, m)
+			}
+			return nil, err
+		}
+		saved = append(saved, item)
+		attachments = append(attachments, item.Attachment(media.KindOf(item.MimeType)))
+	}
+	return attachments, nil
+}
+
+// MaxUploadBytes dung lượng tối đa của một request upload
+func (s *mediaService) MaxUploadBytes() int64 {
+	return s.cfg.MaxUploadBytes * int64(s.cfg.MaxUploadFiles)
+}
+
+// upload kiểm tra và lưu một file
+func (s *mediaService) upload(ctx context.Context, actor Actor, conversationID *uuid.UUID, file UploadFile) (*models.Media, error) {
+	limit := s.cfg.MaxUploadBytes
+	if file.Size > limit {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("File %s vượt quá dung lượng cho phép (%s)", file.Name, formatBytes(limit)))
+	}
+	data, err := io.ReadAll(io.LimitReader(file.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("File %s vượt quá dung lượng cho phép (%s)", file.Name, formatBytes(limit)))
+	}
+	if len(data) == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("File %s rỗng", file.Name))
+	}
+
+	// MIME type theo nội dung file, không tin hoàn toàn vào khai báo của client
+	declared := file.ContentType
+	if declared == "" || declared == "application/octet-stream" {
+		declared = media.TypeByName(file.Name)
+	}
+	mimeType := media.DetectMimeType(data, declared)
+	if !media.Allowed(mimeType, s.cfg.AllowedUploadTypes) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Không hỗ trợ gửi loại file %s (%s)", file.Name, mimeType))
+	}
+
+	now := time.Now()
+	uploadedBy := actor.UserID
+	item := &models.Media{
+		WorkspaceID:    actor.WorkspaceID,
+		ConversationID: conversationID,
+		UploadedBy:     &uploadedBy,
+		Source:         models.MediaSourceUpload,
+		Status:         models.MediaStored,
+		Name:           media.CleanName(file.Name, mimeType),
+		Size:           int64(len(data)),
+		MimeType:       mimeType,
+		LastAttemptAt:  &now,
+		Attempts:       1,
+	}
+	item.ID = uuid.New()
+
+	if err := s.saveBlob(ctx, item, data); err != nil {
+		return nil, err
+	}
+	if err := s.mediaRepo.Create(ctx, item); err != nil {
+		s.deleteBlobs(ctx, item)
+		return nil, fmt.Errorf("create media: %w", err)
+	}
+	return item, nil
+}
+
+// discard xóa file đã upload khi cả tin nhắn không gửi được
+func (s *mediaService) discard(ctx context.Context, item *models.Media) {
+	s.deleteBlobs(ctx, item)
+	if err := s.mediaRepo.Delete(ctx, item.ID); err != nil {
+		s.logger.Warn("failed to delete media", zap.String("media_id", item.ID.String()), zap.Error(err))
+	}
+}
+
+// deleteBlobs xóa file và ảnh thu nhỏ trong storage
+func (s *mediaService) deleteBlobs(ctx context.Context, item *models.Media) {
+	keys := []string{item.StorageKey}
+	if item.ThumbnailKey != "" && item.ThumbnailKey != item.StorageKey {
+		keys = append(keys, item.ThumbnailKey)
+	}
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			s.logger.Warn("failed to delete blob", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// OpenStored mở file đã lưu
+func (s *mediaService) OpenStored(ctx context.Context, mediaID uuid.UUID) (io.ReadCloser, error) {
+	item, err := s.mediaRepo.FindByID(ctx, mediaID)
+	if err != nil {
+		return nil, fmt.Errorf("find media: %w", err)
+	}
+	if !item.IsStored() {
+		return nil, fmt.Errorf("media %s is %s", item.ID, item.Status)
+	}
+	return s.store.Get(ctx, item.StorageKey)
+}
+
+// RetryPending đưa lại vào hàng đợi các file chưa tải xong
+func (s *mediaService) RetryPending(ctx context.Context) error {
+	before := time.Now().Add(-s.cfg.RetryInterval)
+	items, err := s.mediaRepo.FindRetryable(ctx, s.cfg.MaxAttempts, before, mediaRetryBatch)
+	if err != nil {
+		return fmt.Errorf("find retryable media: %w", err)
+	}
+	for _, item := range items {
+		s.enqueue(item.ID)
+	}
+	if len(items) > 0 {
+		s.logger.Info("media retry queued", zap.Int("count", len(items)))
+	}
+	return nil
+}
+
+// Start khởi động worker pool
+func (s *mediaService) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker(ctx)
+	}
+	s.logger.Info("media workers started", zap.Int("workers", s.cfg.Workers))
+}
+
+// Stop dừng worker pool
+func (s *mediaService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	s.logger.Info("media workers stopped")
+}
+
+// enqueue đưa media vào hàng đợi nếu chưa có, bỏ qua khi hàng đợi đầy
+func (s *mediaService) enqueue(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inflight[id] {
+		return
+	}
+	select {
+	case s.queue <- id:
+		s.inflight[id] = true
+	default:
+		s.logger.Warn("media queue full, will retry later", zap.String("media_id", id.String()))
+	}
+}
+
+// done đánh dấu media đã xử lý xong một lượt
+func (s *mediaService) done(id uuid.UUID) {
+	s.mu.Lock()
+	delete(s.inflight, id)
+	s.mu.Unlock()
+}
+
+// worker lấy media từ hàng đợi và xử lý cho đến khi context bị hủy
+func (s *mediaService) worker(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			s.process(ctx, id)
+			s.done(id)
+		}
+	}
+}
+
+// process tải một file về storage và cập nhật attachment của tin nhắn
+func (s *mediaService) process(ctx context.Context, id uuid.UUID) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("media processing panicked", zap.String("media_id", id.String()), zap.Any("panic", r))
+		}
+	}()
+
+	item, err := s.mediaRepo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("failed to load media", zap.String("media_id", id.String()), zap.Error(err))
+		return
+	}
+	if item.Status != models.MediaPending {
+		return
+	}
+
+	now := time.Now()
+	item.Attempts++
+	item.LastAttemptAt = &now
+
+	err = s.fetchAndStore(ctx, item)
+	if err != nil && ctx.Err() != nil {
+		// Server đang tắt: không tính lượt thử, để lần khởi động sau tải lại
+		return
+	}
+
+	if err != nil {
+		item.LastError = err.Error()
+		if errors.Is(err, errMediaPermanent) || item.Attempts >= s.cfg.MaxAttempts {
+			item.Status = models.MediaFailed
+		}
+		s.logger.Warn("media download failed",
+			zap.String("media_id", item.ID.String()),
+			zap.Int("attempts", item.Attempts),
+			zap.Error(err),
+		)
+	} else {
+		item.Status = models.MediaStored
+		item.LastError = ""
+	}
+
+	if err := s.mediaRepo.Update(ctx, item); err != nil {
+		s.logger.Warn("failed to update media", zap.String("media_id", item.ID.String()), zap.Error(err))
+		return
+	}
+
+	if item.Status == models.MediaStored {
+		s.patchAttachment(ctx, item)
+	}
+	if item.Status != models.MediaPending {
+		s.publishMedia(item)
+	}
+}
+
+// fetchAndStore tải file từ URL gốc, lưu vào storage kèm ảnh thu nhỏ và điền thông tin file
+func (s *mediaService) fetchAndStore(ctx context.Context, item *models.Media) error {
+	data, header, err := s.download(ctx, item.OriginalURL)
+	if err != nil {
+		return err
+	}
+
+	item.MimeType = media.DetectMimeType(data, header.Get("Content-Type"))
+	item.Size = int64(len(data))
+	if item.Name == "" {
+		item.Name = media.FileName(header.Get("Content-Disposition"), item.OriginalURL, item.MimeType)
+	}
+
+	return s.saveBlob(ctx, item, data)
+}
+
+// saveBlob lưu nội dung file (và ảnh thu nhỏ nếu là ảnh) vào storage
+// item phải có ID (key trong storage theo workspace và ID)
+func (s *mediaService) saveBlob(ctx context.Context, item *models.Media, data []byte) error {
+	item.StorageKey = fmt.Sprintf("%s/%s", item.WorkspaceID, item.ID)
+	if err := s.store.Put(ctx, item.StorageKey, bytes.NewReader(data), int64(len(data)), item.MimeType); err != nil {
+		return fmt.Errorf("store media: %w", err)
+	}
+
+	if media.KindOf(item.MimeType) == "image" {
+		s.storeThumbnail(ctx, item, data)
+	}
+	return nil
+}
+
+// storeThumbnail tạo ảnh thu nhỏ, lỗi chỉ ghi log (file gốc vẫn dùng được)
+func (s *mediaService) storeThumbnail(ctx context.Context, item *models.Media, data []byte) {
+	thumb, err := media.MakeThumbnail(data, s.cfg.ThumbnailSize)
+	if err != nil {
+		s.logger.Debug("skip thumbnail", zap.String("media_id", item.ID.String()), zap.Error(err))
+		return
+	}
+	item.Width, item.Height = thumb.Width, thumb.Height
+
+	// Ảnh gốc đã đủ nhỏ thì dùng luôn làm thumbnail
+	if thumb.Data == nil {
+		item.ThumbnailKey = item.StorageKey
+		return
+	}
+
+	key := item.StorageKey + "_thumb"
+	if err := s.store.Put(ctx, key, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), "image/jpeg"); err != nil {
+		s.logger.Warn("failed to store thumbnail", zap.String("media_id", item.ID.String()), zap.Error(err))
+		return
+	}
+	item.ThumbnailKey = key
+}
+
+// download tải file với giới hạn thời gian và dung lượng
+func (s *mediaService) download(ctx context.Context, rawURL string) ([]byte, http.Header, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, nil, fmt.Errorf("%w: unsupported url", errMediaPermanent)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.DownloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errMediaPermanent, err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusGone:
+		// CDN trả 403/404/410 khi URL đã hết hạn, thử lại cũng không được
+		return nil, nil, fmt.Errorf("%w: status %d", errMediaPermanent, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, nil, fmt.Errorf("download: status %d", resp.StatusCode)
+	}
+
+	limit := s.cfg.MaxDownloadBytes
+	if resp.ContentLength > limit {
+		return nil, nil, fmt.Errorf("%w: file too large (%d bytes)", errMediaPermanent, resp.ContentLength)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("download: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, nil, fmt.Errorf("%w: file too large (> %d bytes)", errMediaPermanent, limit)
+	}
+	return data, resp.Header, nil
+}
+
+// patchAttachment trỏ attachment của tin nhắn về file đã lưu
+func (s *mediaService) patchAttachment(ctx context.Context, item *models.Media) {
+	if item.MessageID == nil {
+		return
+	}
+	patch := map[string]interface{}{
+		"url":       item.URL(),
+		"name":      item.Name,
+		"size":      item.Size,
+		"mime_type": item.MimeType,
+		"media_id":  item.ID,
+	}
+	if thumbnailURL := item.ThumbnailURL(); thumbnailURL != "" {
+		patch["thumbnail_url"] = thumbnailURL
+	}
+	if item.Width > 0 {
+		patch["width"] = item.Width
+		patch["height"] = item.Height
+	}
+	if err := s.messageRepo.PatchAttachment(ctx, *item.MessageID, item.AttachmentIndex, patch); err != nil {
+		s.logger.Warn("failed to update message attachment",
+			zap.String("media_id", item.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// formatBytes hiển thị dung lượng dạng dễ đọc (VD: "25 MB")
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10:
+		return fmt.Sprintf("%d KB", n>>10)
+	default:
+		return fmt.Sprintf("%d bytes", n)
+	}
+}
+
+// publishMedia báo FE cập nhật attachment
+func (s *mediaService) publishMedia(item *models.Media) {
+	if s.publisher == nil || item.MessageID == nil || item.ConversationID == nil {
+		return
+	}
+	event := &realtime.MediaEvent{
+		MediaID:         item.ID,
+		MessageID:       *item.MessageID,
+		ConversationID:  *item.ConversationID,
+		AttachmentIndex: item.AttachmentIndex,
+		Status:          string(item.Status),
+	}
+	if item.IsStored() {
+		event.URL = item.URL()
+		event.ThumbnailURL = item.ThumbnailURL()
+		event.Name = item.Name
+		event.Size = item.Size
+		event.MimeType = item.MimeType
+	}
+	go func() {
+		if err := s.publisher.PublishMedia(item.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish media event", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chatbox-gin/internal/channel"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Message Service - Webhook Events
+// Các sự kiện ngoài tin nhắn của khách: echo, báo nhận/đã xem, reaction, referral
+// ===========================================================================
+
+// ProcessEvent xử lý một sự kiện webhook theo loại
+func (s *messageService) ProcessEvent(ctx context.Context, workspaceID, channelAccountID uuid.UUID, event *channel.InboundEvent) (*ProcessResult, error) {
+	switch event.Type {
+	case channel.EventMessage:
+		return s.ProcessInbound(ctx, workspaceID, channelAccountID, event.Message)
+	case channel.EventEcho:
+		return s.processEcho(ctx, workspaceID, channelAccountID, event)
+	case channel.EventDelivery, channel.EventRead:
+		return s.processReceipt(ctx, channelAccountID, event)
+	case channel.EventReaction:
+		return s.processReaction(ctx, channelAccountID, event)
+	case channel.EventReferral:
+		return s.processReferral(ctx, channelAccountID, event)
+	}
+	return nil, fmt.Errorf("unsupported event type: %s", event.Type)
+}
+
+// processEcho lưu tin nhắn page gửi trực tiếp (Meta Business Suite, Page inbox)
+// thành tin nhắn của agent để lịch sử hội thoại đầy đủ
+// Echo của tin nhắn do hệ thống gửi đã được lưu khi gửi nên bỏ qua
+func (s *messageService) processEcho(ctx context.Context, workspaceID, channelAccountID uuid.UUID, event *channel.InboundEvent) (*ProcessResult, error) {
+	result := &ProcessResult{}
+	inbound := event.Message
+	if event.OwnEcho {
+		return result, nil
+	}
+	if inbound.ChannelMessageID != "" {
+		existing, err := s.messageRepo.FindByChannelMessageID(ctx, inbound.ChannelMessageID)
+		if err == nil {
+			result.MessageID = existing.ID
+			result.ConversationID = existing.ConversationID
+			return result, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("find echo message: %w", err)
+		}
+	}
+
+	participant, participantCreated, err := s.findOrCreateParticipant(ctx, workspaceID, channelAccountID, inbound)
+	if err != nil {
+		return nil, err
+	}
+	result.ParticipantID = participant.ID
+	result.ParticipantCreated = participantCreated
+
+	conversation, conversationCreated, err := s.findOrCreateConversation(ctx, workspaceID, channelAccountID, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.ConversationID = conversation.ID
+	result.ConversationCreated = conversationCreated
+
+	message := messageFromInbound(conversation.ID, inbound)
+	message.Direction = models.DirectionOut
+	message.SenderType = models.SenderAgent
+	sentAt := inbound.Timestamp
+	message.Metadata.SentAt = &sentAt
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, err
+	}
+	result.MessageID = message.ID
+
+	// Trả lời từ Page inbox vẫn tính là agent đã phản hồi (SLA)
+	conversation.UpdateLastMessage(inbound.Content, inbound.Timestamp)
+	conversation.SetFirstResponse(inbound.Timestamp)
+	if err := s.conversationRepo.Update(ctx, conversation); err != nil {
+		s.logger.Warn("failed to update conversation last message", zap.Error(err))
+	}
+
+	if s.publisher != nil {
+		go func() {
+			event := &realtime.MessageEvent{
+				MessageID:      message.ID,
+				ConversationID: conversation.ID,
+				Direction:      string(message.Direction),
+				SenderType:     string(message.SenderType),
+				Content:        inbound.Content,
+				CreatedAt:      message.CreatedAt,
+				ChannelType:    inbound.ChannelType,
+			}
+			if err := s.publisher.PublishNewMessage(workspaceID, event); err != nil {
+				s.logger.Warn("failed to publish echo message event", zap.Error(err))
+			}
+		}()
+	}
+
+	s.logger.Info("echo message stored",
+		zap.String("message_id", message.ID.String()),
+		zap.String("conversation_id", conversation.ID.String()),
+	)
+
+	return result, nil
+}
+
+// processReceipt ghi nhận khách đã nhận/đã xem tin nhắn gửi đi
+// Đã xem thì cũng đã nhận, nên read cập nhật cả delivered_at còn thiếu
+func (s *messageService) processReceipt(ctx context.Context, channelAccountID uuid.UUID, event *channel.InboundEvent) (*ProcessResult, error) {
+	result := &ProcessResult{}
+	participant, err := s.participantRepo.FindByChannelUserID(ctx, channelAccountID, event.SenderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find participant: %w", err)
+	}
+	result.ParticipantID = participant.ID
+
+	receipt := repositories.ReceiptUpdate{
+		ParticipantID:     participant.ID,
+		Field:             repositories.ReceiptDelivered,
+		ChannelMessageIDs: event.Receipt.MessageIDs,
+		At:                event.Timestamp,
+	}
+	if !event.Receipt.Watermark.IsZero() {
+		watermark := event.Receipt.Watermark
+		receipt.Watermark = &watermark
+	}
+
+	fields := []repositories.ReceiptField{repositories.ReceiptDelivered}
+	if event.Type == channel.EventRead {
+		fields = append(fields, repositories.ReceiptSeen)
+	}
+	for _, field := range fields {
+		receipt.Field = field
+		updated, err := s.messageRepo.MarkReceipt(ctx, receipt)
+		if err != nil {
+			return nil, fmt.Errorf("mark %s: %w", field, err)
+		}
+		s.logger.Debug("message receipts updated",
+			zap.String("participant_id", participant.ID.String()),
+			zap.String("field", string(field)),
+			zap.Int64("updated", updated),
+		)
+	}
+
+	return result, nil
+}
+
+// processReaction lưu reaction của khách lên tin nhắn được reaction
+func (s *messageService) processReaction(ctx context.Context, channelAccountID uuid.UUID, event *channel.InboundEvent) (*ProcessResult, error) {
+	result := &ProcessResult{}
+	message, err := s.messageRepo.FindByChannelMessageID(ctx, event.Reaction.MessageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Debug("reaction on unknown message", zap.String("channel_message_id", event.Reaction.MessageID))
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find reacted message: %w", err)
+	}
+	result.MessageID = message.ID
+	result.ConversationID = message.ConversationID
+
+	if event.Reaction.Removed {
+		message.RemoveReaction(event.SenderID)
+	} else {
+		message.SetReaction(models.MessageReaction{
+			By:        event.SenderID,
+			Emoji:     event.Reaction.Emoji,
+			Reaction:  event.Reaction.Reaction,
+			ReactedAt: event.Timestamp,
+		})
+	}
+
+	if err := s.messageRepo.Update(ctx, message); err != nil {
+		return nil, fmt.Errorf("update message reactions: %w", err)
+	}
+	return result, nil
+}
+
+// processReferral ghi nhận nguồn giới thiệu khi khách đã có thread mở lại qua link/quảng cáo
+func (s *messageService) processReferral(ctx context.Context, channelAccountID uuid.UUID, event *channel.InboundEvent) (*ProcessResult, error) {
+	result := &ProcessResult{}
+	participant, err := s.participantRepo.FindByChannelUserID(ctx, channelAccountID, event.SenderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find participant: %w", err)
+	}
+	result.ParticipantID = participant.ID
+
+	conversation, err := s.conversationRepo.FindOpenByParticipant(ctx, participant.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("find open conversation: %w", err)
+	}
+
+	s.applyReferral(ctx, participant, conversation, event.Referral, event.Timestamp)
+	if conversation != nil {
+		result.ConversationID = conversation.ID
+		if err := s.conversationRepo.Update(ctx, conversation); err != nil {
+			return nil, fmt.Errorf("update conversation referral: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// applyReferral gắn nguồn giới thiệu vào conversation (caller lưu) và participant
+// Nguồn của participant chỉ ghi lần đầu
+func (s *messageService) applyReferral(ctx context.Context, participant *models.Participant, conversation *models.Conversation, referral *channel.Referral, at time.Time) {
+	source := referralSource(referral)
+
+	if conversation != nil {
+		conversation.Metadata.Source = source
+		conversation.Metadata.Referral = &models.ConversationReferral{
+			Ref:        referral.Ref,
+			Source:     referral.Source,
+			Type:       referral.Type,
+			AdID:       referral.AdID,
+			ReceivedAt: at,
+		}
+	}
+
+	if participant.Metadata.Source == "" {
+		participant.Metadata.Source = source
+		if err := s.participantRepo.Update(ctx, participant); err != nil {
+			s.logger.Warn("failed to save participant source", zap.Error(err))
+		}
+	}
+}
+
+// referralSource tên nguồn lưu trong metadata (VD: "facebook_ad", "facebook_shortlink")
+func referralSource(referral *channel.Referral) string {
+	source := strings.ToLower(referral.Source)
+	if source == "ads" {
+		return "facebook_ad"
+	}
+	return "facebook_" + source
+}
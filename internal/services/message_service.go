@@ -37,6 +37,7 @@ type ProcessResult struct {
 	BotHandoff bool
 
 	// ResponseSent tin nhắn response đã gửi (nếu có)
+	// Channel có typing delay thì response được gửi sau khi ProcessInbound trả về
 	ResponseSent *channel.OutboundMessage
 }
 
@@ -45,4 +46,11 @@ type MessageService interface {
 	// ProcessInbound xử lý inbound message từ channel
 	// Flow: normalize -> find/create participant -> find/create conversation -> save message -> match rule -> send response
 	ProcessInbound(ctx context.Context, workspaceID, channelAccountID uuid.UUID, inbound *channel.InboundMessage) (*ProcessResult, error)
+
+	// ProcessEvent xử lý một sự kiện webhook có kiểu (message, echo, delivery, read, reaction, referral)
+	// Tin nhắn của khách đi qua ProcessInbound, các sự kiện khác cập nhật dữ liệu liên quan
+	ProcessEvent(ctx context.Context, workspaceID, channelAccountID uuid.UUID, event *channel.InboundEvent) (*ProcessResult, error)
+
+	// Stop gửi ngay các câu trả lời bot đang chờ typing delay và chờ gửi xong (khi tắt server)
+	Stop()
 }
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"chatbox-gin/internal/bot"
@@ -9,6 +11,7 @@ import (
 	"chatbox-gin/internal/models"
 	"chatbox-gin/internal/realtime"
 	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/routing"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -27,8 +30,18 @@ type messageService struct {
 	channelAccountRepo repositories.ChannelAccountRepository
 	channelRegistry    *channel.Registry
 	botResponder       bot.Responder
+	router             routing.Router
+	csatService        CSATService
+	mediaService       MediaService
+	fieldService       CustomFieldService
 	publisher          realtime.Publisher
 	logger             *zap.Logger
+
+	// Câu trả lời của bot đang chờ typing delay (gửi ở goroutine riêng)
+	// stopping đóng khi Stop để gửi ngay các câu trả lời còn chờ
+	replies  sync.WaitGroup
+	stopping chan struct{}
+	stopOnce sync.Once
 }
 
 // NewMessageService tạo instance mới của MessageService
@@ -39,6 +52,10 @@ func NewMessageService(
 	channelAccountRepo repositories.ChannelAccountRepository,
 	channelRegistry *channel.Registry,
 	botResponder bot.Responder,
+	router routing.Router,
+	csatService CSATService,
+	mediaService MediaService,
+	fieldService CustomFieldService,
 	publisher realtime.Publisher,
 	logger *zap.Logger,
 ) MessageService {
@@ -49,11 +66,22 @@ func NewMessageService(
 		channelAccountRepo: channelAccountRepo,
 		channelRegistry:    channelRegistry,
 		botResponder:       botResponder,
+		router:             router,
+		csatService:        csatService,
+		mediaService:       mediaService,
+		fieldService:       fieldService,
 		publisher:          publisher,
 		logger:             logger,
+		stopping:           make(chan struct{}),
 	}
 }
 
+// Stop gửi ngay các câu trả lời đang chờ typing delay và chờ gửi xong
+func (s *messageService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopping) })
+	s.replies.Wait()
+}
+
 // ProcessInbound xử lý inbound message
 func (s *messageService) ProcessInbound(ctx context.Context, workspaceID, channelAccountID uuid.UUID, inbound *channel.InboundMessage) (*ProcessResult, error) {
 	result := &ProcessResult{}
@@ -66,7 +94,22 @@ func (s *messageService) ProcessInbound(ctx context.Context, workspaceID, channe
 	result.ParticipantID = participant.ID
 	result.ParticipantCreated = participantCreated
 
-	// 2. Tìm hoặc tạo Conversation
+	// Khách vừa nhắn tin: mở lại cửa sổ nhắn tin của channel
+	if err := s.participantRepo.RecordInbound(ctx, participant.ID, inbound.Timestamp); err != nil {
+		s.logger.Warn("failed to record participant inbound", zap.Error(err))
+	}
+
+	// 2. Câu trả lời khảo sát CSAT: lưu vào conversation đã đóng, bỏ qua rule matching
+	if s.csatService != nil {
+		survey, err := s.csatService.CaptureAnswer(ctx, participant.ID, inbound.Content)
+		if err != nil {
+			s.logger.Warn("failed to capture csat answer", zap.Error(err))
+		} else if survey != nil {
+			return s.processCSATAnswer(ctx, result, survey, inbound)
+		}
+	}
+
+	// 3. Tìm hoặc tạo Conversation
 	conversation, conversationCreated, err := s.findOrCreateConversation(ctx, workspaceID, channelAccountID, participant.ID)
 	if err != nil {
 		return nil, err
@@ -74,39 +117,41 @@ func (s *messageService) ProcessInbound(ctx context.Context, workspaceID, channe
 	result.ConversationID = conversation.ID
 	result.ConversationCreated = conversationCreated
 
-	// 3. Lưu Message
+	// Khách đến từ quảng cáo/link m.me: ghi nhận nguồn (lưu cùng bước 5)
+	if inbound.Referral != nil {
+		s.applyReferral(ctx, participant, conversation, inbound.Referral, inbound.Timestamp)
+	}
+
+	// 4. Lưu Message
 	message, err := s.saveMessage(ctx, conversation.ID, inbound)
 	if err != nil {
 		return nil, err
 	}
 	result.MessageID = message.ID
 
-	// 4. Cập nhật conversation last message
+	// Tải file đính kèm về storage (chạy nền, URL của channel sẽ hết hạn)
+	if s.mediaService != nil && len(message.Attachments) > 0 {
+		if err := s.mediaService.IngestMessage(ctx, workspaceID, message); err != nil {
+			s.logger.Warn("failed to queue attachments", zap.String("message_id", message.ID.String()), zap.Error(err))
+		}
+	}
+
+	// 5. Cập nhật conversation last message
 	if err := s.updateConversationLastMessage(ctx, conversation, inbound); err != nil {
 		s.logger.Warn("failed to update conversation last message", zap.Error(err))
 	}
 
-	// 5. Publish realtime event cho FE
-	if s.publisher != nil {
-		go func() {
-			event := &realtime.MessageEvent{
-				MessageID:       message.ID,
-				ConversationID:  conversation.ID,
-				Direction:       string(message.Direction),
-				SenderType:      string(message.SenderType),
-				Content:         inbound.Content,
-				CreatedAt:       message.CreatedAt,
-				ParticipantName: inbound.SenderName,
-			}
-			if err := s.publisher.PublishNewMessage(workspaceID, event); err != nil {
-				s.logger.Warn("failed to publish new message event", zap.Error(err))
-			}
-		}()
+	// 6. Publish realtime event cho FE
+	s.publishInboundMessage(conversation, message, inbound)
+
+	// 7. Tự động phân công agent cho conversation mới
+	if conversationCreated {
+		s.routeConversation(ctx, conversation, models.RoutingTriggerNewConversation)
 	}
 
-	// 5. Xử lý bot response (nếu conversation không bị pause)
-	if !conversation.IsBotPaused() {
-		botResponse, err := s.processBotResponse(ctx, workspaceID, channelAccountID, conversation, inbound, message)
+	// 8. Xử lý bot response (nếu conversation không bị pause hoặc đang tạm ẩn chờ agent)
+	if !conversation.IsBotPaused() && !conversation.IsSnoozed() {
+		botResponse, err := s.processBotResponse(ctx, workspaceID, channelAccountID, participant, conversation, inbound, message)
 		if err != nil {
 			s.logger.Warn("bot response failed", zap.Error(err))
 		} else if botResponse != nil {
@@ -126,6 +171,84 @@ func (s *messageService) ProcessInbound(ctx context.Context, workspaceID, channe
 	return result, nil
 }
 
+// processCSATAnswer lưu câu trả lời khảo sát vào conversation được khảo sát
+// Conversation giữ nguyên trạng thái đóng, không tạo conversation mới
+func (s *messageService) processCSATAnswer(ctx context.Context, result *ProcessResult, survey *models.CSATResponse, inbound *channel.InboundMessage) (*ProcessResult, error) {
+	conversation, err := s.conversationRepo.FindByID(ctx, survey.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	result.ConversationID = conversation.ID
+
+	message, err := s.saveMessage(ctx, conversation.ID, inbound)
+	if err != nil {
+		return nil, err
+	}
+	result.MessageID = message.ID
+
+	if err := s.updateConversationLastMessage(ctx, conversation, inbound); err != nil {
+		s.logger.Warn("failed to update conversation last message", zap.Error(err))
+	}
+
+	s.publishInboundMessage(conversation, message, inbound)
+
+	if err := s.csatService.SendFollowUp(ctx, conversation, survey); err != nil {
+		s.logger.Warn("failed to send csat follow-up", zap.Error(err))
+	} else {
+		result.BotReplied = true
+	}
+
+	s.logger.Info("csat answer processed",
+		zap.String("message_id", result.MessageID.String()),
+		zap.String("conversation_id", conversation.ID.String()),
+	)
+
+	return result, nil
+}
+
+// publishInboundMessage gửi realtime event tin nhắn mới của khách
+// kèm số tin nhắn chưa đọc mới của conversation
+func (s *messageService) publishInboundMessage(conversation *models.Conversation, message *models.Message, inbound *channel.InboundMessage) {
+	if s.publisher == nil {
+		return
+	}
+	workspaceID := conversation.WorkspaceID
+	conversationID := conversation.ID
+	assignedTo := ""
+	if conversation.AssignedTo != nil {
+		assignedTo = conversation.AssignedTo.String()
+	}
+	go func() {
+		event := &realtime.MessageEvent{
+			MessageID:       message.ID,
+			ConversationID:  conversationID,
+			Direction:       string(message.Direction),
+			SenderType:      string(message.SenderType),
+			Content:         inbound.Content,
+			CreatedAt:       message.CreatedAt,
+			ParticipantName: inbound.SenderName,
+		}
+		if err := s.publisher.PublishNewMessage(workspaceID, event); err != nil {
+			s.logger.Warn("failed to publish new message event", zap.Error(err))
+		}
+
+		counts, err := s.messageRepo.CountUnreadByConversations(context.Background(), []uuid.UUID{conversationID})
+		if err != nil {
+			s.logger.Warn("failed to count unread messages", zap.Error(err))
+			return
+		}
+		unread := &realtime.UnreadEvent{
+			ConversationID: conversationID,
+			UnreadCount:    counts[conversationID],
+			AssignedTo:     assignedTo,
+			UpdatedAt:      time.Now(),
+		}
+		if err := s.publisher.PublishUnread(workspaceID, unread); err != nil {
+			s.logger.Warn("failed to publish unread event", zap.Error(err))
+		}
+	}()
+}
+
 // findOrCreateParticipant tìm hoặc tạo participant
 func (s *messageService) findOrCreateParticipant(ctx context.Context, workspaceID, channelAccountID uuid.UUID, inbound *channel.InboundMessage) (*models.Participant, bool, error) {
 	participant := &models.Participant{
@@ -223,6 +346,16 @@ func (s *messageService) findOrCreateConversation(ctx context.Context, workspace
 
 // saveMessage lưu message vào database
 func (s *messageService) saveMessage(ctx context.Context, conversationID uuid.UUID, inbound *channel.InboundMessage) (*models.Message, error) {
+	message := messageFromInbound(conversationID, inbound)
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// messageFromInbound tạo message của khách từ tin nhắn đã chuẩn hóa
+func messageFromInbound(conversationID uuid.UUID, inbound *channel.InboundMessage) *models.Message {
 	message := &models.Message{
 		ConversationID: conversationID,
 		Direction:      models.DirectionIn,
@@ -255,29 +388,31 @@ func (s *messageService) saveMessage(ctx context.Context, conversationID uuid.UU
 		message.Attachments = attachments
 	}
 
-	if err := s.messageRepo.Create(ctx, message); err != nil {
-		return nil, err
-	}
-
-	return message, nil
+	return message
 }
 
 // updateConversationLastMessage cập nhật last message của conversation
 func (s *messageService) updateConversationLastMessage(ctx context.Context, conv *models.Conversation, inbound *channel.InboundMessage) error {
 	conv.UpdateLastMessage(inbound.Content, inbound.Timestamp)
+	conv.RecordInbound(inbound.Timestamp)
 	return s.conversationRepo.Update(ctx, conv)
 }
 
 // processBotResponse xử lý bot response
-func (s *messageService) processBotResponse(ctx context.Context, workspaceID, channelAccountID uuid.UUID, conv *models.Conversation, inbound *channel.InboundMessage, inboundMsg *models.Message) (*botProcessResult, error) {
+func (s *messageService) processBotResponse(ctx context.Context, workspaceID, channelAccountID uuid.UUID, participant *models.Participant, conv *models.Conversation, inbound *channel.InboundMessage, inboundMsg *models.Message) (*botProcessResult, error) {
 	// Gọi bot responder
-	botResponse, err := s.botResponder.Process(ctx, workspaceID, inbound.SenderID, inbound.Content)
+	botResponse, err := s.botResponder.Process(ctx, workspaceID, participant, inbound.SenderID, inbound.Content)
 	if err != nil {
 		return nil, err
 	}
 
 	result := &botProcessResult{}
 
+	// Rule đặt trường tùy chỉnh cho khách (kiểm tra lại theo định nghĩa hiện tại của workspace)
+	if len(botResponse.SetFields) > 0 {
+		s.applyRuleFields(ctx, participant, botResponse)
+	}
+
 	// Xử lý handoff
 	if botResponse.ShouldHandoff {
 		conv.PauseBot(botResponse.HandoffReason)
@@ -285,6 +420,9 @@ func (s *messageService) processBotResponse(ctx context.Context, workspaceID, ch
 			s.logger.Warn("failed to pause bot on conversation", zap.Error(err))
 		}
 		result.ShouldHandoff = true
+
+		// Tìm agent nhận hội thoại sau khi bot chuyển
+		s.routeConversation(ctx, conv, models.RoutingTriggerHandoff)
 	}
 
 	// Gửi response nếu có
@@ -347,32 +485,124 @@ func (s *messageService) processBotResponse(ctx context.Context, workspaceID, ch
 			"app_secret":        channelAcct.Credentials.AppSecret,
 		}
 
-		sendResult, err := ch.Send(ctx, botResponse.Response, credentials)
-		if err != nil {
-			s.logger.Warn("failed to send bot response", zap.Error(err))
-		} else if sendResult.Success {
+		// Hiển thị "đang soạn tin" một lúc trước khi trả lời (nếu channel bật)
+		// Chờ và gửi ở goroutine riêng để webhook trả về ngay
+		if delay := channelAcct.Settings.TypingDelay(botResponse.Response.Content); delay > 0 {
+			s.replyAfterTyping(ctx, ch, credentials, outboundMsg, botResponse.Response, delay)
+			result.ShouldReply = true
+			result.ResponseSent = botResponse.Response
+			return result, nil
+		}
+
+		if s.sendBotReply(ctx, ch, credentials, outboundMsg, botResponse.Response) {
 			result.ShouldReply = true
 			result.ResponseSent = botResponse.Response
+		}
+	}
 
-			// Cập nhật channel message ID
-			if sendResult.ChannelMessageID != "" {
-				outboundMsg.ChannelMessageID = &sendResult.ChannelMessageID
-				s.messageRepo.Update(ctx, outboundMsg)
-			}
+	return result, nil
+}
 
-			s.logger.Info("bot response sent to channel",
-				zap.String("channel_type", inbound.ChannelType),
-				zap.String("channel_message_id", sendResult.ChannelMessageID),
-			)
-		} else if sendResult.Error != nil {
+// replyAfterTyping hiển thị "đang soạn tin" trong delay rồi gửi câu trả lời của bot ở goroutine riêng
+// Context tách khỏi request webhook để không bị hủy khi webhook đã trả về
+func (s *messageService) replyAfterTyping(ctx context.Context, ch channel.Channel, credentials map[string]string, outboundMsg *models.Message, response *channel.OutboundMessage, delay time.Duration) {
+	ctx = context.WithoutCancel(ctx)
+	s.replies.Add(1)
+	go func() {
+		defer s.replies.Done()
+		s.showTyping(ctx, ch, response.RecipientID, credentials, delay)
+		s.sendBotReply(ctx, ch, credentials, outboundMsg, response)
+	}()
+}
+
+// sendBotReply gửi câu trả lời của bot qua channel và lưu channel message ID, trả về true nếu gửi thành công
+func (s *messageService) sendBotReply(ctx context.Context, ch channel.Channel, credentials map[string]string, outboundMsg *models.Message, response *channel.OutboundMessage) bool {
+	sendResult, degradations, err := channel.SendAdapted(ctx, ch, response, credentials)
+	if err != nil {
+		s.logger.Warn("failed to send bot response", zap.Error(err))
+		return false
+	}
+	if !sendResult.Success {
+		if sendResult.Error != nil {
 			s.logger.Warn("channel send failed",
-				zap.String("channel_type", inbound.ChannelType),
+				zap.String("channel_type", ch.Type()),
 				zap.Error(sendResult.Error),
 			)
 		}
+		return false
 	}
 
-	return result, nil
+	// Cập nhật channel message ID và các điều chỉnh khi gửi
+	if sendResult.ChannelMessageID != "" || len(degradations) > 0 {
+		if sendResult.ChannelMessageID != "" {
+			outboundMsg.ChannelMessageID = &sendResult.ChannelMessageID
+		}
+		outboundMsg.Metadata.Degradations = degradationStrings(degradations)
+		s.messageRepo.Update(ctx, outboundMsg)
+	}
+
+	s.logger.Info("bot response sent to channel",
+		zap.String("channel_type", ch.Type()),
+		zap.String("channel_message_id", sendResult.ChannelMessageID),
+	)
+	return true
+}
+
+// applyRuleFields đặt trường tùy chỉnh của khách theo rule, lỗi chỉ ghi log
+func (s *messageService) applyRuleFields(ctx context.Context, participant *models.Participant, botResponse *bot.BotResponse) {
+	if s.fieldService == nil {
+		return
+	}
+	changes, err := s.fieldService.ApplyValues(ctx, participant.WorkspaceID, &participant.Metadata, botResponse.SetFields)
+	if err != nil {
+		s.logger.Warn("failed to apply rule custom fields",
+			zap.String("rule_id", botResponse.MatchedRule.ID.String()),
+			zap.String("participant_id", participant.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+	if err := s.participantRepo.UpdateMetadata(ctx, participant); err != nil {
+		s.logger.Warn("failed to save rule custom fields", zap.Error(err))
+	}
+}
+
+// showTyping gửi typing_on rồi chờ delay (hoặc đến khi Stop), channel không hỗ trợ sender actions thì gửi ngay
+// Channel tự tắt typing khi tin nhắn được gửi nên không cần typing_off
+func (s *messageService) showTyping(ctx context.Context, ch channel.Channel, recipientID string, credentials map[string]string, delay time.Duration) {
+	actions, ok := ch.(channel.SenderActions)
+	if !ok {
+		return
+	}
+	if err := actions.SendAction(ctx, recipientID, channel.ActionTypingOn, credentials); err != nil {
+		s.logger.Warn("failed to send typing indicator", zap.Error(err))
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	case <-s.stopping:
+	}
+}
+
+// routeConversation chạy routing engine, lỗi routing không làm hỏng luồng nhận tin
+func (s *messageService) routeConversation(ctx context.Context, conv *models.Conversation, trigger models.RoutingTrigger) {
+	if s.router == nil {
+		return
+	}
+	if _, err := s.router.Route(ctx, conv, trigger); err != nil && !errors.Is(err, routing.ErrRoutingSkipped) {
+		s.logger.Warn("routing failed",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.String("trigger", string(trigger)),
+			zap.Error(err),
+		)
+	}
 }
 
 // botProcessResult kết quả xử lý bot
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Note Service Interface
+// Ghi chú nội bộ trên conversation: tạo/sửa/xóa/ghim, @mention user khác
+// ===========================================================================
+
+// CreateNoteInput dữ liệu tạo note
+type CreateNoteInput struct {
+	ConversationID uuid.UUID
+	Content        string
+	// Mentions danh sách user được @mention (dashboard gửi kèm khi autocomplete)
+	Mentions []uuid.UUID
+	IsPinned bool
+}
+
+// UpdateNoteInput dữ liệu sửa note (nil = không đổi)
+type UpdateNoteInput struct {
+	Content  *string
+	Mentions *[]uuid.UUID
+}
+
+// NoteService interface cho internal notes
+type NoteService interface {
+	// List lấy danh sách notes của conversation
+	List(ctx context.Context, actor Actor, conversationID uuid.UUID) ([]models.Note, error)
+
+	// Create tạo note mới, gửi thông báo cho user được mention
+	Create(ctx context.Context, actor Actor, input CreateNoteInput) (*models.Note, error)
+
+	// Update sửa note (chỉ tác giả hoặc admin), thông báo cho mention mới
+	Update(ctx context.Context, actor Actor, conversationID, noteID uuid.UUID, input UpdateNoteInput) (*models.Note, error)
+
+	// Delete xóa note (chỉ tác giả hoặc admin)
+	Delete(ctx context.Context, actor Actor, conversationID, noteID uuid.UUID) error
+
+	// SetPinned ghim hoặc bỏ ghim note
+	SetPinned(ctx context.Context, actor Actor, conversationID, noteID uuid.UUID, pinned bool) (*models.Note, error)
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Note Service Implementation
+// ===========================================================================
+
+// noteService triển khai NoteService
+type noteService struct {
+	noteRepo            repositories.NoteRepository
+	conversationRepo    repositories.ConversationRepository
+	userRepo            repositories.UserRepository
+	notificationService NotificationService
+	publisher           realtime.Publisher
+	logger              *zap.Logger
+}
+
+// NewNoteService tạo instance mới của NoteService
+func NewNoteService(
+	noteRepo repositories.NoteRepository,
+	conversationRepo repositories.ConversationRepository,
+	userRepo repositories.UserRepository,
+	notificationService NotificationService,
+	publisher realtime.Publisher,
+	logger *zap.Logger,
+) NoteService {
+	return &noteService{
+		noteRepo:            noteRepo,
+		conversationRepo:    conversationRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		publisher:           publisher,
+		logger:              logger,
+	}
+}
+
+// List lấy danh sách notes của conversation
+func (s *noteService) List(ctx context.Context, actor Actor, conversationID uuid.UUID) ([]models.Note, error) {
+	if _, err := s.loadConversation(ctx, actor, conversationID); err != nil {
+		return nil, err
+	}
+	return s.noteRepo.FindByConversation(ctx, conversationID)
+}
+
+// Create tạo note mới
+func (s *noteService) Create(ctx context.Context, actor Actor, input CreateNoteInput) (*models.Note, error) {
+	conv, err := s.loadConversation(ctx, actor, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := s.resolveMentions(ctx, actor, input.Mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &models.Note{
+		ConversationID: conv.ID,
+		UserID:         actor.UserID,
+		Content:        input.Content,
+		IsPinned:       input.IsPinned,
+		Mentions:       mentions,
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, fmt.Errorf("create note: %w", err)
+	}
+
+	// Load lại để có thông tin tác giả
+	if created, err := s.noteRepo.FindByID(ctx, note.ID); err == nil {
+		note = created
+	}
+
+	s.notifyMentions(ctx, conv, note, mentions)
+	s.publish(conv.WorkspaceID, realtime.NoteCreated, note)
+
+	s.logger.Info("note created",
+		zap.String("note_id", note.ID.String()),
+		zap.String("conversation_id", conv.ID.String()),
+		zap.Int("mentions", len(mentions)),
+	)
+
+	return note, nil
+}
+
+// Update sửa note
+func (s *noteService) Update(ctx context.Context, actor Actor, conversationID, noteID uuid.UUID, input UpdateNoteInput) (*models.Note, error) {
+	conv, note, err := s.loadNote(ctx, actor, conversationID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !note.IsAuthor(actor.UserID) && !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ người tạo hoặc admin mới được sửa ghi chú")
+	}
+
+	var newMentions models.UUIDList
+	if input.Mentions != nil {
+		mentions, err := s.resolveMentions(ctx, actor, *input.Mentions)
+		if err != nil {
+			return nil, err
+		}
+		// Chỉ thông báo cho user được mention lần đầu
+		for _, id := range mentions {
+			if !note.Mentions.Contains(id) {
+				newMentions = append(newMentions, id)
+			}
+		}
+		note.Mentions = mentions
+	}
+
+	if input.Content != nil {
+		note.Edit(*input.Content)
+	}
+
+	if err := s.noteRepo.Update(ctx, note); err != nil {
+		return nil, fmt.Errorf("update note: %w", err)
+	}
+
+	s.notifyMentions(ctx, conv, note, newMentions)
+	s.publish(conv.WorkspaceID, realtime.NoteUpdated, note)
+
+	return note, nil
+}
+
+// Delete xóa note
+func (s *noteService) Delete(ctx context.Context, actor Actor, conversationID, noteID uuid.UUID) error {
+	conv, note, err := s.loadNote(ctx, actor, conversationID, noteID)
+	if err != nil {
+		return err
+	}
+
+	if !note.IsAuthor(actor.UserID) && !actor.IsAdmin() {
+		return apperrors.New(apperrors.ErrForbidden, "Chỉ người tạo hoặc admin mới được xóa ghi chú")
+	}
+
+	if err := s.noteRepo.Delete(ctx, note.ID); err != nil {
+		return fmt.Errorf("delete note: %w", err)
+	}
+
+	s.publish(conv.WorkspaceID, realtime.NoteDeleted, note)
+
+	s.logger.Info("note deleted",
+		zap.String("note_id", note.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+	)
+
+	return nil
+}
+
+// SetPinned ghim hoặc bỏ ghim note
+func (s *noteService) SetPinned(ctx context.Context, actor Actor, conversationID, noteID uuid.UUID, pinned bool) (*models.Note, error) {
+	conv, note, err := s.loadNote(ctx, actor, conversationID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pinned {
+		note.Pin()
+	} else {
+		note.Unpin()
+	}
+
+	if err := s.noteRepo.Update(ctx, note); err != nil {
+		return nil, fmt.Errorf("pin note: %w", err)
+	}
+
+	s.publish(conv.WorkspaceID, realtime.NoteUpdated, note)
+
+	return note, nil
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// loadConversation lấy conversation và kiểm tra thuộc workspace của actor
+func (s *noteService) loadConversation(ctx context.Context, actor Actor, conversationID uuid.UUID) (*models.Conversation, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+		}
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+	if conv.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+	}
+	return conv, nil
+}
+
+// loadNote lấy note và kiểm tra thuộc conversation
+func (s *noteService) loadNote(ctx context.Context, actor Actor, conversationID, noteID uuid.UUID) (*models.Conversation, *models.Note, error) {
+	conv, err := s.loadConversation(ctx, actor, conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	note, err := s.noteRepo.FindByID(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy ghi chú")
+		}
+		return nil, nil, fmt.Errorf("find note: %w", err)
+	}
+	if note.ConversationID != conv.ID {
+		return nil, nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy ghi chú")
+	}
+	return conv, note, nil
+}
+
+// resolveMentions lọc danh sách mention: bỏ trùng, bỏ chính tác giả,
+// chỉ giữ user active trong cùng workspace
+func (s *noteService) resolveMentions(ctx context.Context, actor Actor, ids []uuid.UUID) (models.UUIDList, error) {
+	mentions := models.UUIDList{}
+	if len(ids) == 0 {
+		return mentions, nil
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, actor.WorkspaceID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("find mentioned users: %w", err)
+	}
+	if len(users) == 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "User được mention không hợp lệ")
+	}
+
+	for _, u := range users {
+		if u.ID == actor.UserID || mentions.Contains(u.ID) {
+			continue
+		}
+		mentions = append(mentions, u.ID)
+	}
+	return mentions, nil
+}
+
+// notifyMentions tạo thông báo cho các user được mention
+func (s *noteService) notifyMentions(ctx context.Context, conv *models.Conversation, note *models.Note, userIDs models.UUIDList) {
+	if len(userIDs) == 0 || s.notificationService == nil {
+		return
+	}
+
+	title := "Bạn được nhắc đến trong một ghi chú"
+	if note.User.Name != "" {
+		title = note.User.Name + " đã nhắc đến bạn trong một ghi chú"
+	}
+
+	convID := conv.ID
+	noteID := note.ID
+	actorID := note.UserID
+	err := s.notificationService.Notify(ctx, NotifyInput{
+		WorkspaceID: conv.WorkspaceID,
+		UserIDs:     userIDs,
+		Type:        models.NotificationMention,
+		Title:       title,
+		Body:        truncateForPreview(note.Content, 200),
+		Data: models.NotificationData{
+			ConversationID: &convID,
+			NoteID:         &noteID,
+			ActorID:        &actorID,
+		},
+	})
+	if err != nil {
+		s.logger.Warn("failed to notify mentions",
+			zap.String("note_id", note.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// publish đẩy note event realtime cho dashboard
+func (s *noteService) publish(workspaceID uuid.UUID, eventType string, note *models.Note) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.NoteEvent{
+		Type:           eventType,
+		NoteID:         note.ID,
+		ConversationID: note.ConversationID,
+		UserID:         note.UserID,
+		UserName:       note.User.Name,
+		IsPinned:       note.IsPinned,
+		Mentions:       note.Mentions,
+		CreatedAt:      note.CreatedAt,
+	}
+	if eventType != realtime.NoteDeleted {
+		event.Content = note.Content
+	}
+	go func() {
+		if err := s.publisher.PublishNoteEvent(workspaceID, event); err != nil {
+			s.logger.Warn("failed to publish note event", zap.Error(err))
+		}
+	}()
+}
+
+// truncateForPreview cắt ngắn nội dung cho preview (theo rune để không vỡ tiếng Việt)
+func truncateForPreview(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
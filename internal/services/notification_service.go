@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Notification Service Interface
+// Tạo thông báo cho user và đẩy realtime đến kênh cá nhân của user
+// ===========================================================================
+
+// NotifyInput nội dung một thông báo gửi đến nhiều user
+type NotifyInput struct {
+	// WorkspaceID workspace của các user nhận
+	WorkspaceID uuid.UUID
+
+	// UserIDs danh sách user nhận thông báo
+	UserIDs []uuid.UUID
+
+	// Type loại thông báo
+	Type models.NotificationType
+
+	// Title tiêu đề ngắn
+	Title string
+
+	// Body nội dung chi tiết
+	Body string
+
+	// Data dữ liệu điều hướng (conversation, note, actor)
+	Data models.NotificationData
+}
+
+// NotificationService interface cho thông báo
+type NotificationService interface {
+	// Notify tạo thông báo cho từng user và publish realtime
+	Notify(ctx context.Context, input NotifyInput) error
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Notification Service Implementation
+// ===========================================================================
+
+// notificationService triển khai NotificationService
+type notificationService struct {
+	notificationRepo repositories.NotificationRepository
+	publisher        realtime.Publisher
+	logger           *zap.Logger
+}
+
+// NewNotificationService tạo instance mới của NotificationService
+func NewNotificationService(
+	notificationRepo repositories.NotificationRepository,
+	publisher realtime.Publisher,
+	logger *zap.Logger,
+) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		publisher:        publisher,
+		logger:           logger,
+	}
+}
+
+// Notify tạo thông báo cho từng user và publish realtime
+func (s *notificationService) Notify(ctx context.Context, input NotifyInput) error {
+	if len(input.UserIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]models.Notification, 0, len(input.UserIDs))
+	for _, userID := range input.UserIDs {
+		notifications = append(notifications, models.Notification{
+			WorkspaceID: input.WorkspaceID,
+			UserID:      userID,
+			Type:        input.Type,
+			Title:       input.Title,
+			Body:        input.Body,
+			Data:        input.Data,
+		})
+	}
+
+	if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+		return fmt.Errorf("create notifications: %w", err)
+	}
+
+	// Publish realtime cho từng user (không block request)
+	if s.publisher != nil {
+		go func() {
+			for _, n := range notifications {
+				event := &realtime.NotificationEvent{
+					NotificationID: n.ID,
+					Kind:           string(n.Type),
+					Title:          n.Title,
+					Body:           n.Body,
+					ConversationID: n.Data.ConversationID,
+					CreatedAt:      n.CreatedAt,
+				}
+				if err := s.publisher.PublishNotification(n.UserID, event); err != nil {
+					s.logger.Warn("failed to publish notification",
+						zap.String("user_id", n.UserID.String()),
+						zap.Error(err),
+					)
+				}
+			}
+		}()
+	}
+
+	s.logger.Info("notifications created",
+		zap.String("type", string(input.Type)),
+		zap.Int("count", len(notifications)),
+	)
+
+	return nil
+}
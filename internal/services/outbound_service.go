@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/channel"
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Outbound Service Interface
+// Gửi tin nhắn từ hệ thống (agent, bot, tác vụ nền) cho khách hàng
+// Dùng chung cho API gửi tin của agent và các job tự động
+// ===========================================================================
+
+// OutboundInput dữ liệu một tin nhắn gửi đi
+type OutboundInput struct {
+	// Conversation hội thoại nhận tin
+	Conversation *models.Conversation
+
+	// SenderType người gửi: agent, bot
+	SenderType models.SenderType
+
+	// SenderID ID agent (nếu SenderType = agent)
+	SenderID *uuid.UUID
+
+	// Content nội dung text
+	Content string
+
+	// QuickReplies các nút trả lời nhanh (channel không hỗ trợ sẽ bỏ qua)
+	QuickReplies []models.QuickReply
+
+	// Attachments file đính kèm đã lưu trong storage (xem MediaService.Upload)
+	Attachments models.Attachments
+
+	// ScheduledMessageID tin nhắn hẹn giờ đang được gửi (nếu có)
+	ScheduledMessageID *uuid.UUID
+
+	// CampaignID chiến dịch đang gửi tin nhắn này (nếu có)
+	CampaignID *uuid.UUID
+
+	// MessageTag message tag cho channel có cửa sổ nhắn tin (VD: POST_PURCHASE_UPDATE)
+	MessageTag string
+}
+
+// OutboundService interface cho gửi tin nhắn đi
+type OutboundService interface {
+	// Send lưu tin nhắn, cập nhật tin nhắn cuối của conversation,
+	// publish realtime rồi gửi qua channel (đồng bộ)
+	Send(ctx context.Context, input OutboundInput) (*models.Message, error)
+
+	// Deliver gửi một message đã lưu qua channel của conversation
+	// Ghi nhận ChannelMessageID/SentAt khi thành công, FailedAt/FailReason khi lỗi
+	Deliver(ctx context.Context, conv *models.Conversation, msg *models.Message) error
+
+	// SendAction gửi sender action (mark_seen, ...) cho khách
+	// Không làm gì nếu channel không hỗ trợ sender actions
+	SendAction(ctx context.Context, conv *models.Conversation, action channel.SenderAction) error
+
+	// CheckReply kiểm tra agent được gửi tin cho khách theo cửa sổ nhắn tin của channel
+	// messageTag phải được channel hỗ trợ. Ngoài cửa sổ và không có tag: trả về
+	// ErrMessagingWindowClosed (chính sách block) hoặc outsideWindow = true (chính sách warn)
+	// Tag có hạn dùng (VD: HUMAN_AGENT 7 ngày) quá hạn thì xử lý như không có tag
+	CheckReply(ctx context.Context, conv *models.Conversation, messageTag string) (outsideWindow bool, err error)
+
+	// CheckReplyAt giống CheckReply nhưng tính cửa sổ tại thời điểm at (VD: giờ gửi của tin hẹn giờ)
+	CheckReplyAt(ctx context.Context, conv *models.Conversation, messageTag string, at time.Time) (outsideWindow bool, err error)
+
+	// CheckAutomated kiểm tra tin tự động của bot (tin nhắn đóng, khảo sát CSAT) được gửi cho khách
+	// Không theo chính sách warn: ngoài cửa sổ và không có tag còn hạn luôn trả về ErrMessagingWindowClosed
+	CheckAutomated(ctx context.Context, conv *models.Conversation, messageTag string) error
+
+	// Window trạng thái cửa sổ nhắn tin của conversation
+	Window(ctx context.Context, conv *models.Conversation) (*models.MessagingWindow, error)
+
+	// FillWindows gắn trạng thái cửa sổ nhắn tin cho danh sách trả về API
+	// (conversation cần preload Participant và ChannelAccount)
+	FillWindows(conversations []models.Conversation)
+}
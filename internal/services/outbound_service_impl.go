@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"chatbox-gin/internal/channel"
+	"chatbox-gin/internal/config"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Outbound Service Implementation
+// ===========================================================================
+
+// outboundService triển khai OutboundService
+type outboundService struct {
+	conversationRepo   repositories.ConversationRepository
+	messageRepo        repositories.MessageRepository
+	participantRepo    repositories.ParticipantRepository
+	channelAccountRepo repositories.ChannelAccountRepository
+	channelRegistry    *channel.Registry
+	mediaService       MediaService
+	publisher          realtime.Publisher
+	windowCfg          config.WindowConfig
+	logger             *zap.Logger
+}
+
+// NewOutboundService tạo instance mới của OutboundService
+func NewOutboundService(
+	conversationRepo repositories.ConversationRepository,
+	messageRepo repositories.MessageRepository,
+	participantRepo repositories.ParticipantRepository,
+	channelAccountRepo repositories.ChannelAccountRepository,
+	channelRegistry *channel.Registry,
+	mediaService MediaService,
+	publisher realtime.Publisher,
+	windowCfg config.WindowConfig,
+	logger *zap.Logger,
+) OutboundService {
+	return &outboundService{
+		conversationRepo:   conversationRepo,
+		messageRepo:        messageRepo,
+		participantRepo:    participantRepo,
+		channelAccountRepo: channelAccountRepo,
+		channelRegistry:    channelRegistry,
+		mediaService:       mediaService,
+		publisher:          publisher,
+		windowCfg:          windowCfg,
+		logger:             logger,
+	}
+}
+
+// Send lưu tin nhắn, publish realtime rồi gửi qua channel
+func (s *outboundService) Send(ctx context.Context, input OutboundInput) (*models.Message, error) {
+	conv := input.Conversation
+	content := input.Content
+
+	// Kiểm tra cửa sổ nhắn tin trước khi lưu tin nhắn: agent theo chính sách block/warn,
+	// tin tự động của bot (tin nhắn đóng, khảo sát CSAT) ngoài cửa sổ thì không gửi
+	outsideWindow := false
+	switch input.SenderType {
+	case models.SenderAgent:
+		var err error
+		if outsideWindow, err = s.CheckReply(ctx, conv, input.MessageTag); err != nil {
+			return nil, err
+		}
+	case models.SenderBot:
+		if err := s.CheckAutomated(ctx, conv, input.MessageTag); err != nil {
+			return nil, err
+		}
+	}
+
+	message := &models.Message{
+		ConversationID: conv.ID,
+		Direction:      models.DirectionOut,
+		SenderType:     input.SenderType,
+		SenderID:       input.SenderID,
+		Content:        &content,
+		ContentType:    models.ContentText,
+		Attachments:    input.Attachments,
+		Metadata: models.MessageMetadata{
+			QuickReplies:       input.QuickReplies,
+			ScheduledMessageID: input.ScheduledMessageID,
+			CampaignID:         input.CampaignID,
+			MessageTag:         input.MessageTag,
+			OutsideWindow:      outsideWindow,
+		},
+	}
+	if len(input.Attachments) > 0 {
+		message.ContentType = AttachmentContentType(input.Attachments)
+	}
+	if len(input.QuickReplies) > 0 {
+		message.ContentType = models.ContentQuickReply
+	}
+
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, fmt.Errorf("create message: %w", err)
+	}
+
+	preview := content
+	if preview == "" {
+		preview = message.Attachments.Preview()
+	}
+	// Chỉ ghi tin nhắn cuối: conversation của caller có thể đã cũ (job nền tải trước khi gửi)
+	conv.UpdateLastMessage(preview, message.CreatedAt)
+	if err := s.conversationRepo.UpdateLastMessage(ctx, conv.ID, *conv.LastMessagePreview, message.CreatedAt); err != nil {
+		s.logger.Warn("failed to update conversation last message", zap.Error(err))
+	}
+
+	s.publishMessage(conv, message)
+
+	if err := s.Deliver(ctx, conv, message); err != nil {
+		return message, err
+	}
+	return message, nil
+}
+
+// Deliver gửi một message đã lưu qua channel của conversation
+func (s *outboundService) Deliver(ctx context.Context, conv *models.Conversation, msg *models.Message) error {
+	err := s.deliver(ctx, conv, msg)
+
+	now := time.Now()
+	if err != nil {
+		msg.Metadata.FailedAt = &now
+		msg.Metadata.FailReason = err.Error()
+	} else {
+		msg.Metadata.SentAt = &now
+		msg.Metadata.FailedAt = nil
+		msg.Metadata.FailReason = ""
+	}
+	if updateErr := s.messageRepo.Update(ctx, msg); updateErr != nil {
+		s.logger.Warn("failed to update message delivery state", zap.Error(updateErr))
+	}
+
+	return err
+}
+
+// SendAction gửi sender action cho khách qua channel của conversation
+func (s *outboundService) SendAction(ctx context.Context, conv *models.Conversation, action channel.SenderAction) error {
+	target, err := s.resolveTarget(ctx, conv)
+	if err != nil {
+		return err
+	}
+
+	actions, ok := target.channel.(channel.SenderActions)
+	if !ok {
+		return nil
+	}
+	if err := actions.SendAction(ctx, target.recipientID, action, target.credentials); err != nil {
+		return fmt.Errorf("send %s via %s: %w", action, target.account.ChannelType, err)
+	}
+	return nil
+}
+
+// CheckReply kiểm tra agent được gửi tin theo cửa sổ nhắn tin của channel
+func (s *outboundService) CheckReply(ctx context.Context, conv *models.Conversation, messageTag string) (bool, error) {
+	return s.CheckReplyAt(ctx, conv, messageTag, time.Now())
+}
+
+// CheckReplyAt kiểm tra agent được gửi tin vào thời điểm at theo cửa sổ nhắn tin của channel
+func (s *outboundService) CheckReplyAt(ctx context.Context, conv *models.Conversation, messageTag string, at time.Time) (bool, error) {
+	caps, open, err := s.windowOpen(ctx, conv, messageTag, at)
+	if err != nil || open {
+		return false, err
+	}
+	if s.windowCfg.Policy == config.WindowPolicyWarn {
+		return true, nil
+	}
+	return false, windowClosedError(caps, messageTag)
+}
+
+// CheckAutomated kiểm tra tin tự động của bot được gửi theo cửa sổ nhắn tin của channel
+func (s *outboundService) CheckAutomated(ctx context.Context, conv *models.Conversation, messageTag string) error {
+	caps, open, err := s.windowOpen(ctx, conv, messageTag, time.Now())
+	if err != nil || open {
+		return err
+	}
+	return windowClosedError(caps, messageTag)
+}
+
+// windowOpen kiểm tra cửa sổ nhắn tin (hoặc message tag còn hạn) cho phép gửi tin vào thời điểm at
+func (s *outboundService) windowOpen(ctx context.Context, conv *models.Conversation, messageTag string, at time.Time) (channel.Capabilities, bool, error) {
+	caps, err := s.capabilities(ctx, conv)
+	if err != nil {
+		return caps, false, err
+	}
+	if messageTag != "" && !caps.SupportsMessageTag(messageTag) {
+		return caps, false, apperrors.New(apperrors.ErrInvalidInput, "Channel không hỗ trợ message tag "+messageTag)
+	}
+
+	lastInbound, err := s.lastInboundAt(ctx, conv)
+	if err != nil {
+		return caps, false, err
+	}
+	open := caps.InMessagingWindow(lastInbound, at) || (messageTag != "" && caps.MessageTagAllowed(messageTag, lastInbound, at))
+	return caps, open, nil
+}
+
+// windowClosedError lỗi khi cửa sổ nhắn tin đã đóng (hoặc message tag đã quá hạn)
+func windowClosedError(caps channel.Capabilities, messageTag string) error {
+	if messageTag != "" {
+		return apperrors.New(apperrors.ErrMessagingWindowClosed, fmt.Sprintf(
+			"Message tag %s chỉ dùng được trong %d giờ kể từ tin nhắn cuối của khách",
+			messageTag, caps.MessageTagMaxAgeHours[messageTag]))
+	}
+	return apperrors.New(apperrors.ErrMessagingWindowClosed, fmt.Sprintf(
+		"Đã quá %d giờ kể từ tin nhắn cuối của khách, cần chọn message tag để gửi", caps.MessagingWindowHours))
+}
+
+// Window trạng thái cửa sổ nhắn tin của conversation
+func (s *outboundService) Window(ctx context.Context, conv *models.Conversation) (*models.MessagingWindow, error) {
+	caps, err := s.capabilities(ctx, conv)
+	if err != nil {
+		return nil, err
+	}
+	lastInbound, err := s.lastInboundAt(ctx, conv)
+	if err != nil {
+		return nil, err
+	}
+	return messagingWindow(caps, lastInbound, time.Now()), nil
+}
+
+// FillWindows gắn trạng thái cửa sổ nhắn tin cho danh sách trả về API
+// Channel chưa đăng ký thì bỏ qua (messaging_window không có trong response)
+func (s *outboundService) FillWindows(conversations []models.Conversation) {
+	now := time.Now()
+	for i := range conversations {
+		conv := &conversations[i]
+		ch, err := s.channelRegistry.Get(string(conv.ChannelAccount.ChannelType))
+		if err != nil {
+			continue
+		}
+		conv.MessagingWindow = messagingWindow(ch.Capabilities(), conv.CustomerLastInboundAt(), now)
+	}
+}
+
+// capabilities lấy capabilities của channel của conversation
+func (s *outboundService) capabilities(ctx context.Context, conv *models.Conversation) (channel.Capabilities, error) {
+	account := &conv.ChannelAccount
+	if account.ID != conv.ChannelAccountID {
+		var err error
+		if account, err = s.channelAccountRepo.FindByID(ctx, conv.ChannelAccountID); err != nil {
+			return channel.Capabilities{}, fmt.Errorf("find channel account: %w", err)
+		}
+	}
+
+	ch, err := s.channelRegistry.Get(string(account.ChannelType))
+	if err != nil {
+		return channel.Capabilities{}, fmt.Errorf("channel %s not registered", account.ChannelType)
+	}
+	return ch.Capabilities(), nil
+}
+
+// lastInboundAt lần cuối khách nhắn tin, tải participant nếu conversation chưa preload
+func (s *outboundService) lastInboundAt(ctx context.Context, conv *models.Conversation) (*time.Time, error) {
+	if conv.Participant.ID == conv.ParticipantID {
+		return conv.CustomerLastInboundAt(), nil
+	}
+
+	participant, err := s.participantRepo.FindByID(ctx, conv.ParticipantID)
+	if err != nil {
+		return nil, fmt.Errorf("find participant: %w", err)
+	}
+	withParticipant := *conv
+	withParticipant.Participant = *participant
+	return withParticipant.CustomerLastInboundAt(), nil
+}
+
+// messagingWindow tính trạng thái cửa sổ nhắn tin theo capabilities của channel
+func messagingWindow(caps channel.Capabilities, lastInbound *time.Time, now time.Time) *models.MessagingWindow {
+	window := &models.MessagingWindow{
+		Hours:         caps.MessagingWindowHours,
+		Open:          caps.InMessagingWindow(lastInbound, now),
+		LastInboundAt: lastInbound,
+	}
+	if caps.MessagingWindowHours > 0 {
+		window.MessageTags = caps.AllowedMessageTags(lastInbound, now)
+		if lastInbound != nil {
+			expiresAt := lastInbound.Add(time.Duration(caps.MessagingWindowHours) * time.Hour)
+			window.ExpiresAt = &expiresAt
+		}
+	}
+	return window
+}
+
+// outboundTarget người nhận và channel để gửi cho một conversation
+type outboundTarget struct {
+	recipientID string
+	account     *models.ChannelAccount
+	channel     channel.Channel
+	credentials map[string]string
+}
+
+// resolveTarget lấy participant, channel account và adapter của conversation
+func (s *outboundService) resolveTarget(ctx context.Context, conv *models.Conversation) (*outboundTarget, error) {
+	// Lấy participant để có recipient ID
+	participant, err := s.participantRepo.FindByID(ctx, conv.ParticipantID)
+	if err != nil {
+		return nil, fmt.Errorf("find participant: %w", err)
+	}
+
+	// Lấy channel account để có credentials và channel type
+	channelAccount, err := s.channelAccountRepo.FindByID(ctx, conv.ChannelAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("find channel account: %w", err)
+	}
+
+	ch, err := s.channelRegistry.Get(string(channelAccount.ChannelType))
+	if err != nil {
+		return nil, fmt.Errorf("channel %s not registered", channelAccount.ChannelType)
+	}
+
+	return &outboundTarget{
+		recipientID: participant.ChannelUserID,
+		account:     channelAccount,
+		channel:     ch,
+		credentials: map[string]string{
+			"page_access_token": channelAccount.Credentials.PageAccessToken,
+			"app_secret":        channelAccount.Credentials.AppSecret,
+		},
+	}, nil
+}
+
+// deliver gọi channel để gửi message
+func (s *outboundService) deliver(ctx context.Context, conv *models.Conversation, msg *models.Message) error {
+	target, err := s.resolveTarget(ctx, conv)
+	if err != nil {
+		return err
+	}
+	channelAccount := target.account
+
+	content := ""
+	if msg.Content != nil {
+		content = *msg.Content
+	}
+	outbound := &channel.OutboundMessage{
+		RecipientID: target.recipientID,
+		Content:     content,
+		ContentType: string(msg.ContentType),
+	}
+	if msg.Metadata.MessageTag != "" {
+		outbound.Metadata = map[string]interface{}{
+			channel.MetadataMessageTag: msg.Metadata.MessageTag,
+		}
+	}
+	for _, qr := range msg.Metadata.QuickReplies {
+		outbound.QuickReplies = append(outbound.QuickReplies, channel.QuickReplyData{
+			Title:   qr.Title,
+			Payload: qr.Payload,
+		})
+	}
+	for _, b := range msg.Metadata.Buttons {
+		payload := b.Payload
+		if b.URL != "" {
+			payload = b.URL
+		}
+		outbound.Buttons = append(outbound.Buttons, channel.ButtonData{
+			Type:    b.Type,
+			Title:   b.Title,
+			Payload: payload,
+		})
+	}
+	for _, att := range msg.Attachments {
+		data := channel.AttachmentData{
+			Type:     att.Type,
+			URL:      att.URL,
+			Name:     att.Name,
+			Size:     att.Size,
+			MimeType: att.MimeType,
+		}
+		// File trong storage không truy cập được từ bên ngoài: channel upload nội dung file
+		if att.MediaID != nil && s.mediaService != nil {
+			data.Open = s.mediaOpener(*att.MediaID)
+		}
+		outbound.Attachments = append(outbound.Attachments, data)
+	}
+
+	// Điều chỉnh theo giới hạn của channel (tách text, chuyển nút thành text, ...)
+	result, degradations, err := channel.SendAdapted(ctx, target.channel, outbound, target.credentials)
+	msg.Metadata.Degradations = degradationStrings(degradations)
+	if err != nil {
+		return fmt.Errorf("send via %s: %w", channelAccount.ChannelType, err)
+	}
+	if !result.Success {
+		if result.Error != nil {
+			return result.Error
+		}
+		return errors.New("channel returned unsuccessful result")
+	}
+
+	if result.ChannelMessageID != "" {
+		msg.ChannelMessageID = &result.ChannelMessageID
+	}
+
+	s.logger.Info("message sent to channel",
+		zap.String("channel", string(channelAccount.ChannelType)),
+		zap.String("message_id", msg.ID.String()),
+		zap.String("channel_message_id", result.ChannelMessageID),
+		zap.Strings("degradations", msg.Metadata.Degradations),
+	)
+	return nil
+}
+
+// mediaOpener hàm mở nội dung file đã lưu cho channel
+func (s *outboundService) mediaOpener(mediaID uuid.UUID) func(ctx context.Context) (io.ReadCloser, error) {
+	return func(ctx context.Context) (io.ReadCloser, error) {
+		return s.mediaService.OpenStored(ctx, mediaID)
+	}
+}
+
+// AttachmentContentType loại nội dung của tin nhắn có file đính kèm
+// Toàn ảnh thì là image, có file khác thì là file
+func AttachmentContentType(attachments models.Attachments) models.ContentType {
+	for _, att := range attachments {
+		if att.Type != "image" {
+			return models.ContentFile
+		}
+	}
+	return models.ContentImage
+}
+
+// publishMessage gửi realtime event tin nhắn mới
+func (s *outboundService) publishMessage(conv *models.Conversation, msg *models.Message) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.MessageEvent{
+		MessageID:      msg.ID,
+		ConversationID: conv.ID,
+		Direction:      string(msg.Direction),
+		SenderType:     string(msg.SenderType),
+		Content:        *msg.Content,
+		CreatedAt:      msg.CreatedAt,
+	}
+	go func() {
+		if err := s.publisher.PublishNewMessage(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish outbound message event", zap.Error(err))
+		}
+	}()
+}
+
+// degradationStrings chuyển danh sách điều chỉnh sang dạng lưu trong metadata
+func degradationStrings(degradations []channel.Degradation) []string {
+	if len(degradations) == 0 {
+		return nil
+	}
+	result := make([]string, len(degradations))
+	for i, d := range degradations {
+		result[i] = d.String()
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Presence Service Interface
+// Trạng thái làm việc của agent (online/away/offline), heartbeat,
+// tự động away khi idle và capacity nhận hội thoại
+// ===========================================================================
+
+// AgentAvailability tình trạng sẵn sàng của một agent
+type AgentAvailability struct {
+	UserID          uuid.UUID          `json:"user_id"`
+	Name            string             `json:"name"`
+	AvatarURL       *string            `json:"avatar_url,omitempty"`
+	Role            models.UserRole    `json:"role"`
+	Status          models.AgentStatus `json:"status"`
+	StatusAuto      bool               `json:"status_auto"`
+	LastSeenAt      *time.Time         `json:"last_seen_at,omitempty"`
+	StatusChangedAt *time.Time         `json:"status_changed_at,omitempty"`
+	OpenCount       int64              `json:"open_count"`
+	// Capacity số conversation tối đa (0 = không giới hạn)
+	Capacity int `json:"capacity"`
+	// AcceptingChats agent đang online và còn slot
+	AcceptingChats bool `json:"accepting_chats"`
+}
+
+// TeamAvailability tổng hợp tình trạng của cả team
+type TeamAvailability struct {
+	Agents    []AgentAvailability `json:"agents"`
+	Online    int                 `json:"online"`
+	Away      int                 `json:"away"`
+	Offline   int                 `json:"offline"`
+	Accepting int                 `json:"accepting"`
+}
+
+// PresenceService interface cho presence
+type PresenceService interface {
+	// Heartbeat ghi nhận agent còn hoạt động
+	// Agent bị hệ thống tự chuyển away/offline sẽ được đưa về online
+	Heartbeat(ctx context.Context, actor Actor) (*models.User, error)
+
+	// SetStatus agent tự đổi trạng thái
+	SetStatus(ctx context.Context, actor Actor, status models.AgentStatus) (*models.User, error)
+
+	// SetCapacity đặt số conversation tối đa cho agent (chỉ admin, 0 = theo workspace)
+	SetCapacity(ctx context.Context, actor Actor, userID uuid.UUID, maxConcurrent int) (*models.User, error)
+
+	// TeamAvailability danh sách trạng thái + tải của các agent trong workspace
+	TeamAvailability(ctx context.Context, actor Actor) (*TeamAvailability, error)
+
+	// SweepIdle chuyển agent không hoạt động sang away/offline (chạy định kỳ)
+	SweepIdle(ctx context.Context) error
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"chatbox-gin/internal/config"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Presence Service Implementation
+// ===========================================================================
+
+// presenceService triển khai PresenceService
+type presenceService struct {
+	userRepo         repositories.UserRepository
+	conversationRepo repositories.ConversationRepository
+	workspaceRepo    repositories.WorkspaceRepository
+	publisher        realtime.Publisher
+	cfg              config.PresenceConfig
+	logger           *zap.Logger
+}
+
+// NewPresenceService tạo instance mới của PresenceService
+func NewPresenceService(
+	userRepo repositories.UserRepository,
+	conversationRepo repositories.ConversationRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	publisher realtime.Publisher,
+	cfg config.PresenceConfig,
+	logger *zap.Logger,
+) PresenceService {
+	return &presenceService{
+		userRepo:         userRepo,
+		conversationRepo: conversationRepo,
+		workspaceRepo:    workspaceRepo,
+		publisher:        publisher,
+		cfg:              cfg,
+		logger:           logger,
+	}
+}
+
+// Heartbeat ghi nhận agent còn hoạt động
+func (s *presenceService) Heartbeat(ctx context.Context, actor Actor) (*models.User, error) {
+	user, err := s.loadUser(ctx, actor.WorkspaceID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.UpdateLastSeen()
+	changed := false
+	if user.StatusAuto && user.Status != models.AgentOnline {
+		user.SetStatus(models.AgentOnline, false)
+		changed = true
+	}
+
+	if err := s.userRepo.UpdatePresence(ctx, user); err != nil {
+		return nil, fmt.Errorf("update presence: %w", err)
+	}
+	if changed {
+		s.publishPresence(user)
+	}
+	return user, nil
+}
+
+// SetStatus agent tự đổi trạng thái
+func (s *presenceService) SetStatus(ctx context.Context, actor Actor, status models.AgentStatus) (*models.User, error) {
+	switch status {
+	case models.AgentOnline, models.AgentAway, models.AgentOffline:
+	default:
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Trạng thái không hợp lệ")
+	}
+
+	user, err := s.loadUser(ctx, actor.WorkspaceID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.UpdateLastSeen()
+	user.SetStatus(status, false)
+	if err := s.userRepo.UpdatePresence(ctx, user); err != nil {
+		return nil, fmt.Errorf("update presence: %w", err)
+	}
+
+	s.publishPresence(user)
+	s.logger.Info("agent status changed",
+		zap.String("user_id", user.ID.String()),
+		zap.String("status", string(status)),
+	)
+	return user, nil
+}
+
+// SetCapacity đặt số conversation tối đa cho agent
+func (s *presenceService) SetCapacity(ctx context.Context, actor Actor, userID uuid.UUID, maxConcurrent int) (*models.User, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin được đặt capacity cho agent")
+	}
+	if maxConcurrent < 0 {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Capacity không hợp lệ")
+	}
+
+	user, err := s.loadUser(ctx, actor.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MaxConcurrent = maxConcurrent
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("update capacity: %w", err)
+	}
+	return user, nil
+}
+
+// TeamAvailability danh sách trạng thái + tải của các agent
+func (s *presenceService) TeamAvailability(ctx context.Context, actor Actor) (*TeamAvailability, error) {
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find workspace: %w", err)
+	}
+	defaultCapacity := workspace.Settings.RoutingConfig().MaxConcurrent
+
+	users, _, err := s.userRepo.FindByWorkspace(ctx, actor.WorkspaceID, repositories.FindOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("find users: %w", err)
+	}
+
+	userIDs := make([]uuid.UUID, len(users))
+	for i := range users {
+		userIDs[i] = users[i].ID
+	}
+	openCounts, err := s.conversationRepo.CountOpenByAssignees(ctx, actor.WorkspaceID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("count open conversations: %w", err)
+	}
+
+	team := &TeamAvailability{Agents: make([]AgentAvailability, 0, len(users))}
+	for i := range users {
+		user := &users[i]
+		capacity := user.Capacity(defaultCapacity)
+		agent := AgentAvailability{
+			UserID:          user.ID,
+			Name:            user.Name,
+			AvatarURL:       user.AvatarURL,
+			Role:            user.Role,
+			Status:          user.Status,
+			StatusAuto:      user.StatusAuto,
+			LastSeenAt:      user.LastSeenAt,
+			StatusChangedAt: user.StatusChangedAt,
+			OpenCount:       openCounts[user.ID],
+			Capacity:        capacity,
+		}
+		agent.AcceptingChats = user.IsAvailable() && (capacity == 0 || agent.OpenCount < int64(capacity))
+
+		switch user.Status {
+		case models.AgentOnline:
+			team.Online++
+		case models.AgentAway:
+			team.Away++
+		default:
+			team.Offline++
+		}
+		if agent.AcceptingChats {
+			team.Accepting++
+		}
+		team.Agents = append(team.Agents, agent)
+	}
+
+	return team, nil
+}
+
+// SweepIdle chuyển agent không hoạt động sang away/offline
+// online quá IdleTimeout → away, online/away quá OfflineTimeout → offline
+func (s *presenceService) SweepIdle(ctx context.Context) error {
+	now := time.Now()
+
+	offline, err := s.userRepo.FindByStatusSeenBefore(ctx,
+		[]models.AgentStatus{models.AgentOnline, models.AgentAway},
+		now.Add(-s.cfg.OfflineTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("find stale agents: %w", err)
+	}
+	for i := range offline {
+		s.autoSetStatus(ctx, &offline[i], models.AgentOffline)
+	}
+
+	away, err := s.userRepo.FindByStatusSeenBefore(ctx,
+		[]models.AgentStatus{models.AgentOnline},
+		now.Add(-s.cfg.IdleTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("find idle agents: %w", err)
+	}
+	for i := range away {
+		s.autoSetStatus(ctx, &away[i], models.AgentAway)
+	}
+
+	if len(offline)+len(away) > 0 {
+		s.logger.Info("idle agents swept",
+			zap.Int("away", len(away)),
+			zap.Int("offline", len(offline)),
+		)
+	}
+	return nil
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// autoSetStatus hệ thống tự đổi trạng thái agent
+// Agent tự chọn away sẽ giữ nguyên cờ manual khi chuyển tiếp sang offline
+func (s *presenceService) autoSetStatus(ctx context.Context, user *models.User, status models.AgentStatus) {
+	auto := user.StatusAuto || user.Status == models.AgentOnline
+	user.SetStatus(status, auto)
+	if err := s.userRepo.UpdatePresence(ctx, user); err != nil {
+		s.logger.Warn("failed to update agent presence",
+			zap.String("user_id", user.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	s.publishPresence(user)
+}
+
+// loadUser lấy user và kiểm tra thuộc workspace
+func (s *presenceService) loadUser(ctx context.Context, workspaceID, userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy agent")
+		}
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+	if user.WorkspaceID != workspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy agent")
+	}
+	return user, nil
+}
+
+// publishPresence gửi realtime event khi trạng thái agent thay đổi
+func (s *presenceService) publishPresence(user *models.User) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.PresenceEvent{
+		UserID:     user.ID,
+		Status:     string(user.Status),
+		Auto:       user.StatusAuto,
+		LastSeenAt: user.LastSeenAt,
+		ChangedAt:  time.Now(),
+	}
+	if user.StatusChangedAt != nil {
+		event.ChangedAt = *user.StatusChangedAt
+	}
+	go func() {
+		if err := s.publisher.PublishPresence(user.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish presence event", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Read Service Interface
+// Đánh dấu đã đọc tin nhắn của khách và đếm tin nhắn chưa đọc
+// Khi agent đọc, gửi mark_seen để khách thấy tin nhắn đã được xem
+// ===========================================================================
+
+// ReadResult kết quả đánh dấu đã đọc
+type ReadResult struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+
+	// Marked số tin nhắn vừa được đánh dấu đã đọc
+	Marked int64 `json:"marked"`
+
+	// UnreadCount số tin nhắn còn chưa đọc (mới hơn tin nhắn được đánh dấu)
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// UnreadTotals tổng tin nhắn chưa đọc của workspace và của agent
+type UnreadTotals struct {
+	// Workspace các conversation chưa đóng trong workspace
+	Workspace repositories.UnreadCount `json:"workspace"`
+
+	// Mine các conversation chưa đóng được assign cho user hiện tại
+	Mine repositories.UnreadCount `json:"mine"`
+}
+
+// ReadService interface cho read receipts
+type ReadService interface {
+	// MarkRead đánh dấu đã đọc tin nhắn của khách đến hết upToMessageID
+	// upToMessageID nil = đánh dấu toàn bộ conversation
+	MarkRead(ctx context.Context, actor Actor, conversationID uuid.UUID, upToMessageID *uuid.UUID) (*ReadResult, error)
+
+	// UnreadTotals đếm tin nhắn chưa đọc của workspace và của actor
+	UnreadTotals(ctx context.Context, actor Actor) (*UnreadTotals, error)
+
+	// FillUnreadCounts gắn UnreadCount cho danh sách conversation trong một query
+	FillUnreadCounts(ctx context.Context, conversations []models.Conversation) error
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"chatbox-gin/internal/channel"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Read Service Implementation
+// ===========================================================================
+
+// readService triển khai ReadService
+type readService struct {
+	conversationRepo repositories.ConversationRepository
+	messageRepo      repositories.MessageRepository
+	outboundService  OutboundService
+	publisher        realtime.Publisher
+	logger           *zap.Logger
+}
+
+// NewReadService tạo instance mới của ReadService
+func NewReadService(
+	conversationRepo repositories.ConversationRepository,
+	messageRepo repositories.MessageRepository,
+	outboundService OutboundService,
+	publisher realtime.Publisher,
+	logger *zap.Logger,
+) ReadService {
+	return &readService{
+		conversationRepo: conversationRepo,
+		messageRepo:      messageRepo,
+		outboundService:  outboundService,
+		publisher:        publisher,
+		logger:           logger,
+	}
+}
+
+// MarkRead đánh dấu đã đọc, publish số chưa đọc mới và gửi mark_seen cho khách
+func (s *readService) MarkRead(ctx context.Context, actor Actor, conversationID uuid.UUID, upToMessageID *uuid.UUID) (*ReadResult, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && conv.WorkspaceID != actor.WorkspaceID) {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy hội thoại")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+
+	var upTo *repositories.Cursor
+	if upToMessageID != nil {
+		msg, err := s.messageRepo.FindByID(ctx, *upToMessageID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && msg.ConversationID != conv.ID) {
+			return nil, apperrors.New(apperrors.ErrInvalidInput, "Tin nhắn không thuộc hội thoại")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find message: %w", err)
+		}
+		cursor := repositories.MessageCursor(msg)
+		upTo = &cursor
+	}
+
+	marked, err := s.messageRepo.MarkReadUpTo(ctx, conv.ID, upTo)
+	if err != nil {
+		return nil, fmt.Errorf("mark read: %w", err)
+	}
+
+	counts, err := s.messageRepo.CountUnreadByConversations(ctx, []uuid.UUID{conv.ID})
+	if err != nil {
+		return nil, fmt.Errorf("count unread: %w", err)
+	}
+	result := &ReadResult{
+		ConversationID: conv.ID,
+		Marked:         marked,
+		UnreadCount:    counts[conv.ID],
+	}
+
+	// Không có gì mới được đọc thì không cần báo cho ai
+	if marked > 0 {
+		s.publishUnread(conv, actor.UserID, result.UnreadCount)
+		go s.sendSeen(context.Background(), conv)
+	}
+
+	return result, nil
+}
+
+// UnreadTotals đếm tin nhắn chưa đọc của workspace và của actor
+func (s *readService) UnreadTotals(ctx context.Context, actor Actor) (*UnreadTotals, error) {
+	workspace, err := s.messageRepo.CountUnread(ctx, actor.WorkspaceID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("count workspace unread: %w", err)
+	}
+	mine, err := s.messageRepo.CountUnread(ctx, actor.WorkspaceID, &actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("count agent unread: %w", err)
+	}
+	return &UnreadTotals{Workspace: workspace, Mine: mine}, nil
+}
+
+// FillUnreadCounts gắn UnreadCount cho danh sách conversation
+func (s *readService) FillUnreadCounts(ctx context.Context, conversations []models.Conversation) error {
+	if len(conversations) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(conversations))
+	for i := range conversations {
+		ids[i] = conversations[i].ID
+	}
+	counts, err := s.messageRepo.CountUnreadByConversations(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("count unread: %w", err)
+	}
+	for i := range conversations {
+		conversations[i].UnreadCount = counts[conversations[i].ID]
+	}
+	return nil
+}
+
+// publishUnread gửi realtime event số tin nhắn chưa đọc cho các agent khác
+func (s *readService) publishUnread(conv *models.Conversation, readBy uuid.UUID, unread int64) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.UnreadEvent{
+		ConversationID: conv.ID,
+		UnreadCount:    unread,
+		ReadBy:         &readBy,
+		UpdatedAt:      time.Now(),
+	}
+	if conv.AssignedTo != nil {
+		event.AssignedTo = conv.AssignedTo.String()
+	}
+	go func() {
+		if err := s.publisher.PublishUnread(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish unread event", zap.Error(err))
+		}
+	}()
+}
+
+// sendSeen báo cho khách biết tin nhắn đã được xem (FB mark_seen)
+// Channel không hỗ trợ sender action thì bỏ qua
+func (s *readService) sendSeen(ctx context.Context, conv *models.Conversation) {
+	if err := s.outboundService.SendAction(ctx, conv, channel.ActionMarkSeen); err != nil {
+		s.logger.Warn("failed to send mark_seen",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Routing Service Interface
+// Phân công thủ công, xem routing log, cấu hình routing và kỹ năng agent
+// (việc chọn agent nằm trong package routing)
+// ===========================================================================
+
+// RoutingService interface cho routing qua API
+type RoutingService interface {
+	// Route chạy routing thủ công cho conversation
+	Route(ctx context.Context, actor Actor, conversationID uuid.UUID) (*models.RoutingLog, error)
+
+	// ListLogs lấy lịch sử routing của conversation
+	ListLogs(ctx context.Context, actor Actor, conversationID uuid.UUID) ([]models.RoutingLog, error)
+
+	// GetSettings lấy cấu hình routing của workspace
+	GetSettings(ctx context.Context, actor Actor) (models.RoutingSettings, error)
+
+	// UpdateSettings cập nhật cấu hình routing (chỉ admin)
+	UpdateSettings(ctx context.Context, actor Actor, settings models.RoutingSettings) (models.RoutingSettings, error)
+
+	// UpdateAgentSkills cập nhật kỹ năng của agent (chỉ admin)
+	UpdateAgentSkills(ctx context.Context, actor Actor, userID uuid.UUID, skills []string) (*models.User, error)
+}
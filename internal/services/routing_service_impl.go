@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/routing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Routing Service Implementation
+// ===========================================================================
+
+// routingService triển khai RoutingService
+type routingService struct {
+	router           routing.Router
+	routingLogRepo   repositories.RoutingLogRepository
+	conversationRepo repositories.ConversationRepository
+	workspaceRepo    repositories.WorkspaceRepository
+	userRepo         repositories.UserRepository
+	logger           *zap.Logger
+}
+
+// NewRoutingService tạo instance mới của RoutingService
+func NewRoutingService(
+	router routing.Router,
+	routingLogRepo repositories.RoutingLogRepository,
+	conversationRepo repositories.ConversationRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	userRepo repositories.UserRepository,
+	logger *zap.Logger,
+) RoutingService {
+	return &routingService{
+		router:           router,
+		routingLogRepo:   routingLogRepo,
+		conversationRepo: conversationRepo,
+		workspaceRepo:    workspaceRepo,
+		userRepo:         userRepo,
+		logger:           logger,
+	}
+}
+
+// Route chạy routing thủ công cho conversation
+func (s *routingService) Route(ctx context.Context, actor Actor, conversationID uuid.UUID) (*models.RoutingLog, error) {
+	conv, err := s.loadConversation(ctx, actor, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.IsClosed() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Hội thoại đã đóng")
+	}
+
+	log, err := s.router.Route(ctx, conv, models.RoutingTriggerManual)
+	if err != nil {
+		return nil, fmt.Errorf("route conversation: %w", err)
+	}
+	return log, nil
+}
+
+// ListLogs lấy lịch sử routing của conversation
+func (s *routingService) ListLogs(ctx context.Context, actor Actor, conversationID uuid.UUID) ([]models.RoutingLog, error) {
+	if _, err := s.loadConversation(ctx, actor, conversationID); err != nil {
+		return nil, err
+	}
+	return s.routingLogRepo.FindByConversation(ctx, conversationID)
+}
+
+// GetSettings lấy cấu hình routing của workspace
+func (s *routingService) GetSettings(ctx context.Context, actor Actor) (models.RoutingSettings, error) {
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.RoutingSettings{}, fmt.Errorf("find workspace: %w", err)
+	}
+	return workspace.Settings.RoutingConfig(), nil
+}
+
+// UpdateSettings cập nhật cấu hình routing
+func (s *routingService) UpdateSettings(ctx context.Context, actor Actor, settings models.RoutingSettings) (models.RoutingSettings, error) {
+	if !actor.IsAdmin() {
+		return models.RoutingSettings{}, apperrors.New(apperrors.ErrForbidden, "Chỉ admin được cấu hình routing")
+	}
+	if settings.MaxConcurrent < 0 {
+		return models.RoutingSettings{}, apperrors.New(apperrors.ErrInvalidInput, "Giá trị cấu hình không hợp lệ")
+	}
+	// Chiến lược lạ khiến router không chọn được ai, rỗng dùng mặc định (least_open)
+	switch settings.Strategy {
+	case "", models.RoutingRoundRobin, models.RoutingLeastOpen, models.RoutingSkill:
+	default:
+		return models.RoutingSettings{}, apperrors.New(apperrors.ErrInvalidInput, "Chiến lược routing không hợp lệ: "+string(settings.Strategy))
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.RoutingSettings{}, fmt.Errorf("find workspace: %w", err)
+	}
+
+	workspace.Settings.Routing = &settings
+	if err := s.workspaceRepo.Update(ctx, workspace); err != nil {
+		return models.RoutingSettings{}, fmt.Errorf("update workspace: %w", err)
+	}
+
+	s.logger.Info("routing settings updated",
+		zap.String("workspace_id", workspace.ID.String()),
+		zap.String("strategy", string(settings.Strategy)),
+		zap.Bool("enabled", settings.Enabled),
+	)
+
+	return workspace.Settings.RoutingConfig(), nil
+}
+
+// UpdateAgentSkills cập nhật kỹ năng của agent
+func (s *routingService) UpdateAgentSkills(ctx context.Context, actor Actor, userID uuid.UUID, skills []string) (*models.User, error) {
+	if !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ admin được cập nhật kỹ năng agent")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy agent")
+		}
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+	if user.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy agent")
+	}
+
+	user.Skills = normalizeSkills(skills)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("update user skills: %w", err)
+	}
+	return user, nil
+}
+
+// ===========================================================================
+// Helpers
+// ===========================================================================
+
+// loadConversation lấy conversation và kiểm tra thuộc workspace của actor
+func (s *routingService) loadConversation(ctx context.Context, actor Actor, conversationID uuid.UUID) (*models.Conversation, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+		}
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+	if conv.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+	}
+	return conv, nil
+}
+
+// normalizeSkills bỏ khoảng trắng, bỏ rỗng và bỏ trùng (không phân biệt hoa thường)
+func normalizeSkills(skills []string) models.StringList {
+	result := models.StringList{}
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, skill)
+	}
+	return result
+}
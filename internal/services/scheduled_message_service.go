@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Scheduled Message Service Interface
+// Agent hẹn giờ gửi tin nhắn trong hội thoại (VD: "gửi link theo dõi 9h sáng mai")
+// Giờ gửi không kèm múi giờ được hiểu theo WorkspaceSettings.Timezone
+// ===========================================================================
+
+// ScheduleMessageInput dữ liệu hẹn giờ một tin nhắn
+type ScheduleMessageInput struct {
+	// Content nội dung text
+	Content string
+
+	// SendAt thời điểm gửi: RFC3339 có múi giờ (2026-10-19T09:00:00+07:00)
+	// hoặc giờ địa phương của workspace (2026-10-19T09:00, 2026-10-19 09:00)
+	SendAt string
+
+	// MessageTag message tag dùng nếu đến giờ gửi khách đã ngoài cửa sổ nhắn tin
+	MessageTag string
+}
+
+// ScheduledMessageService interface cho tin nhắn hẹn giờ
+type ScheduledMessageService interface {
+	// Create hẹn giờ gửi tin nhắn (hội thoại chưa đóng, giờ gửi ở tương lai)
+	// Cửa sổ nhắn tin được kiểm tra tại giờ gửi theo tin nhắn cuối hiện tại của khách
+	Create(ctx context.Context, actor Actor, conversationID uuid.UUID, input ScheduleMessageInput) (*models.ScheduledMessage, error)
+
+	// List lấy tin nhắn hẹn giờ của hội thoại theo giờ gửi (status rỗng = tất cả)
+	List(ctx context.Context, actor Actor, conversationID uuid.UUID, status models.ScheduledMessageStatus) ([]models.ScheduledMessage, error)
+
+	// Cancel hủy tin nhắn đang chờ gửi (người hẹn hoặc admin)
+	Cancel(ctx context.Context, actor Actor, conversationID, id uuid.UUID) (*models.ScheduledMessage, error)
+
+	// PendingMessages các tin nhắn đang chờ gửi dưới dạng message để hiển thị
+	// trong danh sách tin nhắn, giờ gửi muộn nhất trước
+	PendingMessages(ctx context.Context, conversationID uuid.UUID) ([]models.Message, error)
+
+	// SendDue gửi các tin nhắn đến giờ và hủy tin nhắn của hội thoại đã đóng (chạy định kỳ)
+	// Tin nhắn không gửi được (VD: ngoài cửa sổ nhắn tin) bị đánh dấu failed và báo cho người hẹn
+	SendDue(ctx context.Context) error
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Scheduled Message Service Implementation
+// ===========================================================================
+
+const (
+	// maxScheduleAhead thời gian hẹn trước tối đa
+	maxScheduleAhead = 90 * 24 * time.Hour
+
+	// maxScheduledContentLength độ dài nội dung tối đa (giống gửi tin nhắn thường)
+	maxScheduledContentLength = 5000
+
+	// scheduledSendingStaleAfter tin nhắn ở trạng thái sending quá lâu coi như worker đã dừng
+	scheduledSendingStaleAfter = 5 * time.Minute
+)
+
+// localSendAtLayouts các định dạng giờ gửi không kèm múi giờ (hiểu theo múi giờ workspace)
+var localSendAtLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// scheduledMessageService triển khai ScheduledMessageService
+type scheduledMessageService struct {
+	scheduledRepo       repositories.ScheduledMessageRepository
+	conversationRepo    repositories.ConversationRepository
+	workspaceRepo       repositories.WorkspaceRepository
+	outboundService     OutboundService
+	notificationService NotificationService
+	batchSize           int
+	logger              *zap.Logger
+}
+
+// NewScheduledMessageService tạo instance mới của ScheduledMessageService
+func NewScheduledMessageService(
+	scheduledRepo repositories.ScheduledMessageRepository,
+	conversationRepo repositories.ConversationRepository,
+	workspaceRepo repositories.WorkspaceRepository,
+	outboundService OutboundService,
+	notificationService NotificationService,
+	batchSize int,
+	logger *zap.Logger,
+) ScheduledMessageService {
+	return &scheduledMessageService{
+		scheduledRepo:       scheduledRepo,
+		conversationRepo:    conversationRepo,
+		workspaceRepo:       workspaceRepo,
+		outboundService:     outboundService,
+		notificationService: notificationService,
+		batchSize:           batchSize,
+		logger:              logger,
+	}
+}
+
+// Create hẹn giờ gửi tin nhắn
+func (s *scheduledMessageService) Create(ctx context.Context, actor Actor, conversationID uuid.UUID, input ScheduleMessageInput) (*models.ScheduledMessage, error) {
+	content := strings.TrimSpace(input.Content)
+	if content == "" {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Nội dung tin nhắn không được để trống")
+	}
+	if len([]rune(content)) > maxScheduledContentLength {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Nội dung tin nhắn quá dài")
+	}
+
+	conv, err := s.loadConversation(ctx, actor, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.IsClosed() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Không thể hẹn giờ gửi trong hội thoại đã đóng")
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("find workspace: %w", err)
+	}
+	timezone, loc := workspaceLocation(workspace.Settings)
+
+	sendAt, err := parseSendAt(input.SendAt, loc)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !sendAt.After(now) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Thời điểm gửi phải ở tương lai")
+	}
+	if sendAt.Sub(now) > maxScheduleAhead {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Chỉ được hẹn giờ gửi trước tối đa 90 ngày")
+	}
+
+	// Worker không chọn được message tag: kiểm tra cửa sổ nhắn tin tại giờ gửi ngay khi hẹn
+	messageTag := strings.TrimSpace(input.MessageTag)
+	if _, err := s.outboundService.CheckReplyAt(ctx, conv, messageTag, sendAt); err != nil {
+		return nil, err
+	}
+
+	scheduled := &models.ScheduledMessage{
+		WorkspaceID:    actor.WorkspaceID,
+		ConversationID: conv.ID,
+		CreatedBy:      actor.UserID,
+		Content:        content,
+		SendAt:         sendAt.UTC(),
+		Timezone:       timezone,
+		MessageTag:     messageTag,
+		Status:         models.ScheduledPending,
+	}
+	if err := s.scheduledRepo.Create(ctx, scheduled); err != nil {
+		return nil, fmt.Errorf("create scheduled message: %w", err)
+	}
+
+	s.logger.Info("message scheduled",
+		zap.String("scheduled_message_id", scheduled.ID.String()),
+		zap.String("conversation_id", conv.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+		zap.Time("send_at", scheduled.SendAt),
+	)
+
+	scheduled.SendAt = scheduled.LocalSendAt()
+	return scheduled, nil
+}
+
+// List lấy tin nhắn hẹn giờ của hội thoại
+func (s *scheduledMessageService) List(ctx context.Context, actor Actor, conversationID uuid.UUID, status models.ScheduledMessageStatus) ([]models.ScheduledMessage, error) {
+	if _, err := s.loadConversation(ctx, actor, conversationID); err != nil {
+		return nil, err
+	}
+
+	var statuses []models.ScheduledMessageStatus
+	if status != "" {
+		statuses = append(statuses, status)
+	}
+	scheduled, err := s.scheduledRepo.FindByConversation(ctx, conversationID, statuses)
+	if err != nil {
+		return nil, fmt.Errorf("find scheduled messages: %w", err)
+	}
+	for i := range scheduled {
+		scheduled[i].SendAt = scheduled[i].LocalSendAt()
+	}
+	return scheduled, nil
+}
+
+// Cancel hủy tin nhắn đang chờ gửi
+func (s *scheduledMessageService) Cancel(ctx context.Context, actor Actor, conversationID, id uuid.UUID) (*models.ScheduledMessage, error) {
+	scheduled, err := s.scheduledRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy tin nhắn hẹn giờ")
+		}
+		return nil, fmt.Errorf("find scheduled message: %w", err)
+	}
+	if scheduled.WorkspaceID != actor.WorkspaceID || scheduled.ConversationID != conversationID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy tin nhắn hẹn giờ")
+	}
+	if scheduled.CreatedBy != actor.UserID && !actor.IsAdmin() {
+		return nil, apperrors.New(apperrors.ErrForbidden, "Chỉ người hẹn giờ hoặc admin được hủy tin nhắn này")
+	}
+	if !scheduled.IsPending() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Tin nhắn đã được gửi hoặc đã hủy")
+	}
+
+	// Chỉ hủy nếu vẫn đang chờ: worker có thể đã nhận gửi sau khi đọc ở trên
+	cancelledBy := actor.UserID
+	scheduled.CancelReason = models.CancelReasonAgent
+	scheduled.CancelledBy = &cancelledBy
+	cancelled, err := s.scheduledRepo.Cancel(ctx, scheduled)
+	if err != nil {
+		return nil, fmt.Errorf("cancel scheduled message: %w", err)
+	}
+	if !cancelled {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Tin nhắn đã được gửi hoặc đã hủy")
+	}
+
+	scheduled.SendAt = scheduled.LocalSendAt()
+	return scheduled, nil
+}
+
+// PendingMessages các tin nhắn đang chờ gửi dưới dạng message
+func (s *scheduledMessageService) PendingMessages(ctx context.Context, conversationID uuid.UUID) ([]models.Message, error) {
+	scheduled, err := s.scheduledRepo.FindByConversation(ctx, conversationID, []models.ScheduledMessageStatus{
+		models.ScheduledPending,
+		models.ScheduledSending,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find scheduled messages: %w", err)
+	}
+
+	messages := make([]models.Message, 0, len(scheduled))
+	for i := len(scheduled) - 1; i >= 0; i-- {
+		messages = append(messages, scheduled[i].PendingMessage())
+	}
+	return messages, nil
+}
+
+// SendDue gửi các tin nhắn đến giờ
+func (s *scheduledMessageService) SendDue(ctx context.Context) error {
+	now := time.Now()
+
+	cancelled, err := s.scheduledRepo.CancelForClosedConversations(ctx)
+	if err != nil {
+		return fmt.Errorf("cancel scheduled messages of closed conversations: %w", err)
+	}
+	if cancelled > 0 {
+		s.logger.Info("scheduled messages cancelled for closed conversations", zap.Int64("count", cancelled))
+	}
+
+	stale, err := s.scheduledRepo.FailStale(ctx, now.Add(-scheduledSendingStaleAfter))
+	if err != nil {
+		return fmt.Errorf("fail stale scheduled messages: %w", err)
+	}
+	if stale > 0 {
+		s.logger.Warn("scheduled messages interrupted while sending", zap.Int64("count", stale))
+	}
+
+	due, err := s.scheduledRepo.FindDue(ctx, now, s.batchSize)
+	if err != nil {
+		return fmt.Errorf("find due scheduled messages: %w", err)
+	}
+
+	sent := 0
+	for i := range due {
+		ok, err := s.send(ctx, &due[i])
+		if err != nil {
+			s.logger.Warn("failed to send scheduled message",
+				zap.String("scheduled_message_id", due[i].ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		s.logger.Info("scheduled messages sent", zap.Int("count", sent))
+	}
+	return nil
+}
+
+// send nhận và gửi một tin nhắn hẹn giờ, trả về true nếu đã tạo tin nhắn
+func (s *scheduledMessageService) send(ctx context.Context, scheduled *models.ScheduledMessage) (bool, error) {
+	claimed, err := s.scheduledRepo.Claim(ctx, scheduled)
+	if err != nil {
+		return false, fmt.Errorf("claim scheduled message: %w", err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	conv, err := s.conversationRepo.FindByID(ctx, scheduled.ConversationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			scheduled.Status = models.ScheduledFailed
+			scheduled.Error = "Không tìm thấy conversation"
+			return false, s.scheduledRepo.Update(ctx, scheduled)
+		}
+		return false, s.release(ctx, scheduled, fmt.Errorf("find conversation: %w", err))
+	}
+
+	// Hội thoại vừa đóng sau lần quét hủy ở đầu SendDue
+	if conv.IsClosed() {
+		scheduled.Status = models.ScheduledCancelled
+		scheduled.CancelReason = models.CancelReasonConversationClosed
+		return false, s.scheduledRepo.Update(ctx, scheduled)
+	}
+
+	now := time.Now()
+	conv.SetFirstResponse(now)
+	senderID := scheduled.CreatedBy
+	scheduledID := scheduled.ID
+	message, sendErr := s.outboundService.Send(ctx, OutboundInput{
+		Conversation:       conv,
+		SenderType:         models.SenderAgent,
+		SenderID:           &senderID,
+		Content:            scheduled.Content,
+		ScheduledMessageID: &scheduledID,
+		MessageTag:         scheduled.MessageTag,
+	})
+	if message == nil {
+		// Không tạo được tin nhắn (VD: khách không nhắn lại, cửa sổ nhắn tin đã đóng)
+		scheduled.Status = models.ScheduledFailed
+		scheduled.Error = sendErr.Error()
+		if err := s.scheduledRepo.Update(ctx, scheduled); err != nil {
+			return false, err
+		}
+		s.notifyFailed(ctx, conv, scheduled)
+		return false, nil
+	}
+
+	// Tin nhắn đã lưu, lỗi channel (nếu có) được ghi ở message (FailedAt/FailReason) như tin agent gửi
+	messageID := message.ID
+	scheduled.Status = models.ScheduledSent
+	scheduled.MessageID = &messageID
+	scheduled.SentAt = &now
+	if sendErr != nil {
+		scheduled.Error = sendErr.Error()
+	}
+	if err := s.scheduledRepo.Update(ctx, scheduled); err != nil {
+		return true, fmt.Errorf("update scheduled message: %w", err)
+	}
+	return true, nil
+}
+
+// notifyFailed báo agent hẹn giờ rằng tin nhắn không gửi được
+func (s *scheduledMessageService) notifyFailed(ctx context.Context, conv *models.Conversation, scheduled *models.ScheduledMessage) {
+	if s.notificationService == nil {
+		return
+	}
+
+	conversationID := conv.ID
+	err := s.notificationService.Notify(ctx, NotifyInput{
+		WorkspaceID: conv.WorkspaceID,
+		UserIDs:     []uuid.UUID{scheduled.CreatedBy},
+		Type:        models.NotificationScheduledFailed,
+		Title:       "Tin nhắn hẹn giờ không gửi được",
+		Body:        "Tin nhắn cho " + conv.Participant.GetDisplayName() + " không gửi được: " + scheduled.Error,
+		Data:        models.NotificationData{ConversationID: &conversationID},
+	})
+	if err != nil {
+		s.logger.Warn("failed to send scheduled message notification",
+			zap.String("scheduled_message_id", scheduled.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// release trả tin nhắn về pending để lần quét sau thử lại (lỗi tạm thời trước khi gửi)
+func (s *scheduledMessageService) release(ctx context.Context, scheduled *models.ScheduledMessage, cause error) error {
+	scheduled.Status = models.ScheduledPending
+	if err := s.scheduledRepo.Update(ctx, scheduled); err != nil {
+		return fmt.Errorf("%v; release scheduled message: %w", cause, err)
+	}
+	return cause
+}
+
+// loadConversation tải conversation và kiểm tra thuộc workspace của actor
+func (s *scheduledMessageService) loadConversation(ctx context.Context, actor Actor, id uuid.UUID) (*models.Conversation, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+		}
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+	if conv.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+	}
+	return conv, nil
+}
+
+// workspaceLocation múi giờ của workspace, UTC nếu chưa cấu hình hoặc không hợp lệ
+func workspaceLocation(settings models.WorkspaceSettings) (string, *time.Location) {
+	if settings.Timezone != "" {
+		if loc, err := time.LoadLocation(settings.Timezone); err == nil {
+			return settings.Timezone, loc
+		}
+	}
+	return "UTC", time.UTC
+}
+
+// parseSendAt parse giờ gửi, giờ không kèm múi giờ được hiểu theo loc
+func parseSendAt(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, apperrors.New(apperrors.ErrInvalidInput, "Thiếu thời điểm gửi")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range localSendAtLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, apperrors.New(apperrors.ErrInvalidInput, "Thời điểm gửi không hợp lệ (dùng RFC3339 hoặc YYYY-MM-DDTHH:MM theo múi giờ workspace)")
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Search Service Interface
+// Tìm kiếm lịch sử hội thoại trong workspace của user
+// ===========================================================================
+
+// SearchInput điều kiện tìm kiếm từ API
+type SearchInput struct {
+	Query            string
+	Types            []repositories.SearchHitType
+	ChannelAccountID *uuid.UUID
+	ChannelType      string
+	From             *time.Time
+	To               *time.Time
+	SenderType       string
+	Tags             []string
+	Offset           int
+	Limit            int
+}
+
+// SearchResultHit kết quả kèm ngữ cảnh conversation
+type SearchResultHit struct {
+	repositories.SearchHit
+
+	// Conversation conversation chứa kết quả (participant, channel, tags)
+	Conversation *models.Conversation `json:"conversation,omitempty"`
+}
+
+// SearchService interface cho tìm kiếm
+type SearchService interface {
+	// Search tìm kiếm tin nhắn, ghi chú và khách hàng trong workspace của actor
+	Search(ctx context.Context, actor Actor, input SearchInput) ([]SearchResultHit, int64, error)
+}
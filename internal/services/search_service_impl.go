@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// Search Service Implementation
+// ===========================================================================
+
+// searchService triển khai SearchService
+type searchService struct {
+	searchRepo       repositories.SearchRepository
+	conversationRepo repositories.ConversationRepository
+	logger           *zap.Logger
+}
+
+// NewSearchService tạo instance mới của SearchService
+func NewSearchService(
+	searchRepo repositories.SearchRepository,
+	conversationRepo repositories.ConversationRepository,
+	logger *zap.Logger,
+) SearchService {
+	return &searchService{
+		searchRepo:       searchRepo,
+		conversationRepo: conversationRepo,
+		logger:           logger,
+	}
+}
+
+// Search tìm kiếm và gắn ngữ cảnh conversation cho từng kết quả
+func (s *searchService) Search(ctx context.Context, actor Actor, input SearchInput) ([]SearchResultHit, int64, error) {
+	text := strings.TrimSpace(input.Query)
+	if utf8.RuneCountInString(text) < 2 {
+		return nil, 0, apperrors.New(apperrors.ErrInvalidInput, "Từ khóa tìm kiếm phải có ít nhất 2 ký tự")
+	}
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		return nil, 0, apperrors.New(apperrors.ErrInvalidInput, "Khoảng thời gian không hợp lệ")
+	}
+
+	hits, total, err := s.searchRepo.Search(ctx, repositories.SearchQuery{
+		WorkspaceID:      actor.WorkspaceID,
+		Text:             text,
+		Types:            input.Types,
+		ChannelAccountID: input.ChannelAccountID,
+		ChannelType:      input.ChannelType,
+		From:             input.From,
+		To:               input.To,
+		SenderType:       input.SenderType,
+		Tags:             input.Tags,
+		Offset:           input.Offset,
+		Limit:            input.Limit,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("search: %w", err)
+	}
+
+	// Lấy conversation của các kết quả trong một query
+	ids := make([]uuid.UUID, 0, len(hits))
+	for _, hit := range hits {
+		if hit.ConversationID != nil {
+			ids = append(ids, *hit.ConversationID)
+		}
+	}
+	conversations, err := s.conversationRepo.FindByIDs(ctx, actor.WorkspaceID, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find conversations: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.Conversation, len(conversations))
+	for i := range conversations {
+		byID[conversations[i].ID] = &conversations[i]
+	}
+
+	results := make([]SearchResultHit, len(hits))
+	for i, hit := range hits {
+		results[i] = SearchResultHit{SearchHit: hit}
+		if hit.ConversationID != nil {
+			results[i].Conversation = byID[*hit.ConversationID]
+		}
+	}
+
+	s.logger.Debug("search executed",
+		zap.String("workspace_id", actor.WorkspaceID.String()),
+		zap.Int64("total", total),
+	)
+	return results, total, nil
+}
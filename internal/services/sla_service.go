@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"chatbox-gin/internal/models"
+)
+
+// ===========================================================================
+// SLA Service Interface
+// Chính sách SLA của workspace và evaluator chạy nền
+// (cách tính theo giờ làm việc nằm trong package sla)
+// ===========================================================================
+
+// SLAService interface cho SLA
+type SLAService interface {
+	// GetPolicy lấy chính sách SLA của workspace
+	GetPolicy(ctx context.Context, actor Actor) (models.SLAPolicy, error)
+
+	// UpdatePolicy cập nhật chính sách SLA (chỉ admin)
+	UpdatePolicy(ctx context.Context, actor Actor, policy models.SLAPolicy) (models.SLAPolicy, error)
+
+	// EvaluateAll đánh giá SLA của mọi conversation chưa đóng (chạy định kỳ)
+	// Gắn cờ warning/breached, escalate khi vi phạm
+	EvaluateAll(ctx context.Context) error
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+	"chatbox-gin/internal/sla"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ===========================================================================
+// SLA Service Implementation
+// ===========================================================================
+
+// slaService triển khai SLAService
+type slaService struct {
+	workspaceRepo       repositories.WorkspaceRepository
+	conversationRepo    repositories.ConversationRepository
+	userRepo            repositories.UserRepository
+	notificationService NotificationService
+	publisher           realtime.Publisher
+	logger              *zap.Logger
+}
+
+// NewSLAService tạo instance mới của SLAService
+func NewSLAService(
+	workspaceRepo repositories.WorkspaceRepository,
+	conversationRepo repositories.ConversationRepository,
+	userRepo repositories.UserRepository,
+	notificationService NotificationService,
+	publisher realtime.Publisher,
+	logger *zap.Logger,
+) SLAService {
+	return &slaService{
+		workspaceRepo:       workspaceRepo,
+		conversationRepo:    conversationRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		publisher:           publisher,
+		logger:              logger,
+	}
+}
+
+// GetPolicy lấy chính sách SLA của workspace
+func (s *slaService) GetPolicy(ctx context.Context, actor Actor) (models.SLAPolicy, error) {
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.SLAPolicy{}, fmt.Errorf("find workspace: %w", err)
+	}
+	return workspace.Settings.SLAConfig(), nil
+}
+
+// UpdatePolicy cập nhật chính sách SLA
+func (s *slaService) UpdatePolicy(ctx context.Context, actor Actor, policy models.SLAPolicy) (models.SLAPolicy, error) {
+	if !actor.IsAdmin() {
+		return models.SLAPolicy{}, apperrors.New(apperrors.ErrForbidden, "Chỉ admin được cấu hình SLA")
+	}
+	for priority, target := range policy.Targets {
+		switch priority {
+		case models.PriorityLow, models.PriorityNormal, models.PriorityHigh, models.PriorityUrgent:
+		default:
+			return models.SLAPolicy{}, apperrors.New(apperrors.ErrInvalidInput, "Priority không hợp lệ: "+string(priority))
+		}
+		if target.FirstResponseMinutes < 0 || target.ResolutionMinutes < 0 {
+			return models.SLAPolicy{}, apperrors.New(apperrors.ErrInvalidInput, "Thời gian SLA không hợp lệ")
+		}
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, actor.WorkspaceID)
+	if err != nil {
+		return models.SLAPolicy{}, fmt.Errorf("find workspace: %w", err)
+	}
+
+	workspace.Settings.SLA = &policy
+	if err := s.workspaceRepo.Update(ctx, workspace); err != nil {
+		return models.SLAPolicy{}, fmt.Errorf("update workspace: %w", err)
+	}
+
+	s.logger.Info("sla policy updated",
+		zap.String("workspace_id", workspace.ID.String()),
+		zap.Bool("enabled", policy.Enabled),
+	)
+	return workspace.Settings.SLAConfig(), nil
+}
+
+// EvaluateAll đánh giá SLA của mọi conversation chưa đóng
+func (s *slaService) EvaluateAll(ctx context.Context) error {
+	workspaces, err := s.workspaceRepo.FindActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find workspaces: %w", err)
+	}
+
+	for i := range workspaces {
+		if err := s.evaluateWorkspace(ctx, &workspaces[i]); err != nil {
+			s.logger.Warn("sla evaluation failed",
+				zap.String("workspace_id", workspaces[i].ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// evaluateWorkspace đánh giá SLA các conversation của một workspace
+func (s *slaService) evaluateWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	policy := workspace.Settings.SLAConfig()
+	if !policy.Enabled {
+		return nil
+	}
+
+	conversations, err := s.conversationRepo.FindUnresolved(ctx, workspace.ID)
+	if err != nil {
+		return fmt.Errorf("find conversations: %w", err)
+	}
+
+	cal := sla.NewCalendar(workspace.Settings)
+	now := time.Now()
+
+	for i := range conversations {
+		conv := &conversations[i]
+		previous := conv.SLAStatus
+		result := sla.Evaluate(conv, policy, cal, now)
+
+		changed := previous != result.Status ||
+			!sameTime(conv.SLAFirstResponseDueAt, result.FirstResponseDueAt) ||
+			!sameTime(conv.SLAResolutionDueAt, result.ResolutionDueAt)
+		if !changed {
+			continue
+		}
+
+		conv.SLAStatus = result.Status
+		conv.SLAFirstResponseDueAt = result.FirstResponseDueAt
+		conv.SLAResolutionDueAt = result.ResolutionDueAt
+
+		// Chỉ xử lý khi trạng thái xấu đi, tránh gửi thông báo lặp lại mỗi lần quét
+		worsened := result.Status.Severity() > previous.Severity()
+		if worsened && result.Status == models.SLABreached {
+			s.escalate(ctx, conv, policy, result)
+		}
+
+		if err := s.conversationRepo.UpdateSLA(ctx, conv); err != nil {
+			s.logger.Warn("failed to update conversation sla",
+				zap.String("conversation_id", conv.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		if worsened {
+			if result.Status == models.SLAWarning {
+				s.notifyWarning(ctx, conv, result)
+			}
+			s.publishUpdate(conv)
+		}
+	}
+	return nil
+}
+
+// escalate xử lý khi conversation vi phạm SLA: gắn cờ, tăng priority, báo admin
+func (s *slaService) escalate(ctx context.Context, conv *models.Conversation, policy models.SLAPolicy, result sla.Result) {
+	now := time.Now()
+	conv.Metadata.SLABreached = true
+	conv.Metadata.SLABreachedTargets = result.Breached
+	conv.Metadata.SLAEscalatedAt = &now
+
+	if policy.RaisePriority {
+		conv.Priority = conv.Priority.Raise()
+	}
+
+	recipients := make([]uuid.UUID, 0)
+	if conv.AssignedTo != nil {
+		recipients = append(recipients, *conv.AssignedTo)
+	}
+	if policy.NotifyAdmins {
+		admins, err := s.findAdmins(ctx, conv.WorkspaceID)
+		if err != nil {
+			s.logger.Warn("failed to find admins for sla escalation", zap.Error(err))
+		}
+		for _, id := range admins {
+			if conv.AssignedTo == nil || *conv.AssignedTo != id {
+				recipients = append(recipients, id)
+			}
+		}
+	}
+
+	s.notify(ctx, conv, recipients, models.NotificationSLABreach,
+		"Hội thoại đã vi phạm SLA",
+		"Quá hạn: "+describeTargets(result.Breached),
+	)
+
+	s.logger.Info("sla breached",
+		zap.String("conversation_id", conv.ID.String()),
+		zap.Strings("targets", result.Breached),
+		zap.String("priority", string(conv.Priority)),
+	)
+}
+
+// notifyWarning báo agent được assign khi hội thoại sắp vi phạm SLA
+func (s *slaService) notifyWarning(ctx context.Context, conv *models.Conversation, result sla.Result) {
+	if conv.AssignedTo == nil {
+		return
+	}
+	s.notify(ctx, conv, []uuid.UUID{*conv.AssignedTo}, models.NotificationSLAWarning,
+		"Hội thoại sắp vi phạm SLA",
+		"Sắp hết hạn: "+describeTargets(result.Warning),
+	)
+}
+
+// notify gửi thông báo SLA, lỗi chỉ ghi log
+func (s *slaService) notify(ctx context.Context, conv *models.Conversation, userIDs []uuid.UUID, kind models.NotificationType, title, body string) {
+	if s.notificationService == nil || len(userIDs) == 0 {
+		return
+	}
+	conversationID := conv.ID
+	err := s.notificationService.Notify(ctx, NotifyInput{
+		WorkspaceID: conv.WorkspaceID,
+		UserIDs:     userIDs,
+		Type:        kind,
+		Title:       title,
+		Body:        body,
+		Data:        models.NotificationData{ConversationID: &conversationID},
+	})
+	if err != nil {
+		s.logger.Warn("failed to send sla notification",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// findAdmins lấy ID owner/admin của workspace
+func (s *slaService) findAdmins(ctx context.Context, workspaceID uuid.UUID) ([]uuid.UUID, error) {
+	users, _, err := s.userRepo.FindByWorkspace(ctx, workspaceID, repositories.FindOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0)
+	for i := range users {
+		if users[i].IsAdmin() {
+			ids = append(ids, users[i].ID)
+		}
+	}
+	return ids, nil
+}
+
+// publishUpdate gửi realtime event khi trạng thái SLA thay đổi
+func (s *slaService) publishUpdate(conv *models.Conversation) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.ConversationEvent{
+		ConversationID: conv.ID,
+		Status:         string(conv.Status),
+		SLAStatus:      string(conv.SLAStatus),
+		Priority:       string(conv.Priority),
+	}
+	if conv.AssignedTo != nil {
+		event.AssignedTo = conv.AssignedTo.String()
+	}
+	go func() {
+		if err := s.publisher.PublishConversationUpdate(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish sla update", zap.Error(err))
+		}
+	}()
+}
+
+// describeTargets mô tả các mục tiêu SLA bằng tiếng Việt
+func describeTargets(targets []string) string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		switch t {
+		case sla.TargetFirstResponse:
+			names = append(names, "trả lời lần đầu")
+		case sla.TargetResolution:
+			names = append(names, "giải quyết")
+		default:
+			names = append(names, t)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// sameTime so sánh 2 con trỏ thời gian (nil bằng nil)
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"chatbox-gin/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Snooze Service Interface
+// Tạm ẩn hội thoại khỏi inbox đến một thời điểm hoặc đến khi khách nhắn lại
+// Job định kỳ mở lại hội thoại, đưa lên đầu inbox và báo cho agent được assign
+// ===========================================================================
+
+// SnoozeService interface cho tạm ẩn hội thoại
+type SnoozeService interface {
+	// Snooze tạm ẩn hội thoại đến until
+	Snooze(ctx context.Context, actor Actor, conversationID uuid.UUID, until time.Time) (*models.Conversation, error)
+
+	// Unsnooze mở lại hội thoại đang tạm ẩn trước hạn
+	Unsnooze(ctx context.Context, actor Actor, conversationID uuid.UUID) (*models.Conversation, error)
+
+	// WakeDue mở lại các hội thoại hết hạn tạm ẩn hoặc khách đã nhắn lại (chạy định kỳ)
+	WakeDue(ctx context.Context) error
+}
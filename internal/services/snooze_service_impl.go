@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Snooze Service Implementation
+// ===========================================================================
+
+// maxSnoozeDuration thời gian tạm ẩn tối đa
+const maxSnoozeDuration = 90 * 24 * time.Hour
+
+// snoozeService triển khai SnoozeService
+type snoozeService struct {
+	conversationRepo    repositories.ConversationRepository
+	notificationService NotificationService
+	publisher           realtime.Publisher
+	batchSize           int
+	logger              *zap.Logger
+}
+
+// NewSnoozeService tạo instance mới của SnoozeService
+func NewSnoozeService(
+	conversationRepo repositories.ConversationRepository,
+	notificationService NotificationService,
+	publisher realtime.Publisher,
+	batchSize int,
+	logger *zap.Logger,
+) SnoozeService {
+	return &snoozeService{
+		conversationRepo:    conversationRepo,
+		notificationService: notificationService,
+		publisher:           publisher,
+		batchSize:           batchSize,
+		logger:              logger,
+	}
+}
+
+// Snooze tạm ẩn hội thoại đến until
+func (s *snoozeService) Snooze(ctx context.Context, actor Actor, conversationID uuid.UUID, until time.Time) (*models.Conversation, error) {
+	now := time.Now()
+	if !until.After(now) {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Thời điểm tạm ẩn phải ở tương lai")
+	}
+	if until.Sub(now) > maxSnoozeDuration {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Chỉ được tạm ẩn tối đa 90 ngày")
+	}
+
+	conv, err := s.loadConversation(ctx, actor, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.IsClosed() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Không thể tạm ẩn hội thoại đã đóng")
+	}
+
+	conv.Snooze(until)
+	if err := s.conversationRepo.UpdateState(ctx, conv); err != nil {
+		return nil, fmt.Errorf("snooze conversation: %w", err)
+	}
+
+	s.logger.Info("conversation snoozed",
+		zap.String("conversation_id", conv.ID.String()),
+		zap.String("user_id", actor.UserID.String()),
+		zap.Time("until", until),
+	)
+	s.publishUpdate(conv)
+	return conv, nil
+}
+
+// Unsnooze mở lại hội thoại đang tạm ẩn trước hạn
+func (s *snoozeService) Unsnooze(ctx context.Context, actor Actor, conversationID uuid.UUID) (*models.Conversation, error) {
+	conv, err := s.loadConversation(ctx, actor, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if !conv.IsSnoozed() {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, "Hội thoại không ở trạng thái tạm ẩn")
+	}
+
+	conv.Unsnooze()
+	if err := s.conversationRepo.UpdateState(ctx, conv); err != nil {
+		return nil, fmt.Errorf("unsnooze conversation: %w", err)
+	}
+
+	s.publishUpdate(conv)
+	return conv, nil
+}
+
+// WakeDue mở lại các hội thoại hết hạn tạm ẩn hoặc khách đã nhắn lại
+func (s *snoozeService) WakeDue(ctx context.Context) error {
+	now := time.Now()
+	conversations, err := s.conversationRepo.FindSnoozedDue(ctx, now, s.batchSize)
+	if err != nil {
+		return fmt.Errorf("find snoozed conversations: %w", err)
+	}
+
+	woken := 0
+	for i := range conversations {
+		ok, err := s.wake(ctx, &conversations[i], now)
+		if err != nil {
+			s.logger.Warn("failed to wake snoozed conversation",
+				zap.String("conversation_id", conversations[i].ID.String()),
+				zap.Error(err),
+			)
+		}
+		if ok {
+			woken++
+		}
+	}
+
+	if woken > 0 {
+		s.logger.Info("snoozed conversations reopened", zap.Int("count", woken))
+	}
+	return nil
+}
+
+// wake mở lại một hội thoại về trạng thái trước khi tạm ẩn, đưa lên đầu inbox và báo cho agent được assign
+// Trả về false nếu hội thoại không còn tạm ẩn hoặc đã được tạm ẩn lại
+func (s *snoozeService) wake(ctx context.Context, conv *models.Conversation, now time.Time) (bool, error) {
+	replied := conv.RepliedWhileSnoozed()
+
+	// Bản quét có thể đã cũ: agent đóng, mở lại hoặc tạm ẩn lại thì bỏ qua
+	woken, err := s.conversationRepo.Wake(ctx, conv, now)
+	if err != nil {
+		return false, fmt.Errorf("unsnooze conversation: %w", err)
+	}
+	if !woken {
+		return false, nil
+	}
+	conv.SnoozedUntil = nil
+	conv.SnoozedAt = nil
+	conv.SnoozedFromStatus = ""
+	if err := s.conversationRepo.Bump(ctx, conv.ID, now); err != nil {
+		return true, fmt.Errorf("bump conversation: %w", err)
+	}
+
+	s.publishUpdate(conv)
+	s.notifyAssignee(ctx, conv, replied)
+	return true, nil
+}
+
+// notifyAssignee báo agent được assign rằng hội thoại đã quay lại inbox
+func (s *snoozeService) notifyAssignee(ctx context.Context, conv *models.Conversation, replied bool) {
+	if s.notificationService == nil || conv.AssignedTo == nil {
+		return
+	}
+
+	name := conv.Participant.GetDisplayName()
+	title := "Hội thoại tạm ẩn đã đến hạn"
+	body := "Hội thoại với " + name + " đã quay lại inbox"
+	if replied {
+		title = "Khách đã nhắn lại hội thoại đang tạm ẩn"
+		body = name + " vừa nhắn tin, hội thoại đã quay lại inbox"
+	}
+
+	conversationID := conv.ID
+	err := s.notificationService.Notify(ctx, NotifyInput{
+		WorkspaceID: conv.WorkspaceID,
+		UserIDs:     []uuid.UUID{*conv.AssignedTo},
+		Type:        models.NotificationSnoozeEnded,
+		Title:       title,
+		Body:        body,
+		Data:        models.NotificationData{ConversationID: &conversationID},
+	})
+	if err != nil {
+		s.logger.Warn("failed to send snooze notification",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// loadConversation tải conversation và kiểm tra thuộc workspace của actor
+func (s *snoozeService) loadConversation(ctx context.Context, actor Actor, id uuid.UUID) (*models.Conversation, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+		}
+		return nil, fmt.Errorf("find conversation: %w", err)
+	}
+	if conv.WorkspaceID != actor.WorkspaceID {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Không tìm thấy conversation")
+	}
+	return conv, nil
+}
+
+// publishUpdate gửi realtime event khi trạng thái tạm ẩn thay đổi
+func (s *snoozeService) publishUpdate(conv *models.Conversation) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.ConversationEvent{
+		ConversationID: conv.ID,
+		Status:         string(conv.Status),
+		Priority:       string(conv.Priority),
+		SnoozedUntil:   conv.SnoozedUntil,
+	}
+	if conv.AssignedTo != nil {
+		event.AssignedTo = conv.AssignedTo.String()
+	}
+	go func() {
+		if err := s.publisher.PublishConversationUpdate(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish snooze update", zap.Error(err))
+		}
+	}()
+}
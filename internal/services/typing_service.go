@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ===========================================================================
+// Typing Service Interface
+// Chuyển trạng thái "đang soạn tin" của agent tới khách (sender action)
+// và tới các agent khác đang xem cùng hội thoại (realtime)
+// ===========================================================================
+
+// TypingService interface cho typing indicator của agent
+type TypingService interface {
+	// SetTyping cập nhật trạng thái đang soạn tin của actor trong conversation
+	SetTyping(ctx context.Context, actor Actor, conversationID uuid.UUID, typing bool) error
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"chatbox-gin/internal/channel"
+	apperrors "chatbox-gin/internal/errors"
+	"chatbox-gin/internal/models"
+	"chatbox-gin/internal/realtime"
+	"chatbox-gin/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ===========================================================================
+// Typing Service Implementation
+// ===========================================================================
+
+// typingForwardInterval khoảng cách tối thiểu giữa hai lần gửi typing_on cho khách
+// Dashboard báo typing liên tục khi agent gõ, channel giữ trạng thái vài giây nên không cần gửi lại
+const typingForwardInterval = 5 * time.Second
+
+// typingService triển khai TypingService
+type typingService struct {
+	conversationRepo repositories.ConversationRepository
+	userRepo         repositories.UserRepository
+	outboundService  OutboundService
+	publisher        realtime.Publisher
+	logger           *zap.Logger
+
+	// mu bảo vệ forwarded: thời điểm gửi typing_on gần nhất theo conversation
+	mu        sync.Mutex
+	forwarded map[uuid.UUID]time.Time
+}
+
+// NewTypingService tạo instance mới của TypingService
+func NewTypingService(
+	conversationRepo repositories.ConversationRepository,
+	userRepo repositories.UserRepository,
+	outboundService OutboundService,
+	publisher realtime.Publisher,
+	logger *zap.Logger,
+) TypingService {
+	return &typingService{
+		conversationRepo: conversationRepo,
+		userRepo:         userRepo,
+		outboundService:  outboundService,
+		publisher:        publisher,
+		logger:           logger,
+		forwarded:        make(map[uuid.UUID]time.Time),
+	}
+}
+
+// SetTyping publish trạng thái cho agent khác và chuyển tiếp cho khách
+func (s *typingService) SetTyping(ctx context.Context, actor Actor, conversationID uuid.UUID, typing bool) error {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && conv.WorkspaceID != actor.WorkspaceID) {
+		return apperrors.New(apperrors.ErrNotFound, "Không tìm thấy hội thoại")
+	}
+	if err != nil {
+		return fmt.Errorf("find conversation: %w", err)
+	}
+
+	s.publishTyping(ctx, conv, actor.UserID, typing)
+
+	if action, ok := s.nextAction(conv, typing); ok {
+		go s.forward(context.Background(), conv, action)
+	}
+	return nil
+}
+
+// nextAction quyết định sender action cần gửi cho khách
+// typing_on được gửi tối đa một lần mỗi typingForwardInterval,
+// typing_off chỉ gửi khi trước đó đã gửi typing_on
+func (s *typingService) nextAction(conv *models.Conversation, typing bool) (channel.SenderAction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, sent := s.forwarded[conv.ID]
+	if !typing {
+		delete(s.forwarded, conv.ID)
+		return channel.ActionTypingOff, sent
+	}
+
+	// Hội thoại đã đóng thì không báo cho khách
+	if conv.IsClosed() {
+		return "", false
+	}
+	now := time.Now()
+	if sent && now.Sub(last) < typingForwardInterval {
+		return "", false
+	}
+	s.forwarded[conv.ID] = now
+	return channel.ActionTypingOn, true
+}
+
+// forward gửi sender action cho khách qua channel của conversation
+func (s *typingService) forward(ctx context.Context, conv *models.Conversation, action channel.SenderAction) {
+	if err := s.outboundService.SendAction(ctx, conv, action); err != nil {
+		s.logger.Warn("failed to forward typing indicator",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.String("action", string(action)),
+			zap.Error(err),
+		)
+	}
+}
+
+// publishTyping gửi realtime event cho các agent khác trong workspace
+func (s *typingService) publishTyping(ctx context.Context, conv *models.Conversation, userID uuid.UUID, typing bool) {
+	if s.publisher == nil {
+		return
+	}
+	event := &realtime.TypingEvent{
+		ConversationID: conv.ID,
+		UserID:         userID,
+		Typing:         typing,
+		At:             time.Now(),
+	}
+	if user, err := s.userRepo.FindByID(ctx, userID); err == nil {
+		event.UserName = user.Name
+	}
+	go func() {
+		if err := s.publisher.PublishTyping(conv.WorkspaceID, event); err != nil {
+			s.logger.Warn("failed to publish typing event", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,151 @@
+package sla
+
+import (
+	"time"
+
+	"chatbox-gin/internal/models"
+)
+
+// ===========================================================================
+// Calendar
+// Tính thời gian theo giờ làm việc của workspace
+// Không cấu hình giờ làm việc = tính 24/7
+// ===========================================================================
+
+// maxCalendarDays giới hạn số ngày duyệt để tránh lặp vô hạn khi cấu hình lỗi
+const maxCalendarDays = 366
+
+// Calendar lịch làm việc của workspace
+type Calendar struct {
+	loc   *time.Location
+	start time.Duration // offset từ 00:00
+	end   time.Duration // offset từ 00:00
+	days  map[time.Weekday]bool
+	// always = true khi không có giờ làm việc hợp lệ
+	always bool
+}
+
+// NewCalendar tạo Calendar từ cấu hình workspace
+func NewCalendar(settings models.WorkspaceSettings) Calendar {
+	loc := time.Local
+	if settings.Timezone != "" {
+		if l, err := time.LoadLocation(settings.Timezone); err == nil {
+			loc = l
+		}
+	}
+
+	cal := Calendar{loc: loc, always: true}
+
+	wh := settings.WorkingHours
+	if wh == nil || len(wh.Days) == 0 {
+		return cal
+	}
+	start, okStart := parseClock(wh.Start)
+	end, okEnd := parseClock(wh.End)
+	if !okStart || !okEnd || end <= start {
+		return cal
+	}
+
+	cal.always = false
+	cal.start = start
+	cal.end = end
+	cal.days = make(map[time.Weekday]bool, len(wh.Days))
+	for _, d := range wh.Days {
+		if d >= 0 && d <= 6 {
+			cal.days[time.Weekday(d)] = true
+		}
+	}
+	return cal
+}
+
+// Location múi giờ của lịch
+func (c Calendar) Location() *time.Location { return c.loc }
+
+// Add cộng d thời gian làm việc vào from, trả về thời điểm hết hạn
+func (c Calendar) Add(from time.Time, d time.Duration) time.Time {
+	if c.always || d <= 0 {
+		return from.Add(d)
+	}
+
+	remaining := d
+	cursor := from.In(c.loc)
+	for i := 0; i < maxCalendarDays; i++ {
+		winStart, winEnd, ok := c.window(cursor)
+		if ok {
+			if cursor.Before(winStart) {
+				cursor = winStart
+			}
+			if cursor.Before(winEnd) {
+				available := winEnd.Sub(cursor)
+				if remaining <= available {
+					return cursor.Add(remaining)
+				}
+				remaining -= available
+			}
+		}
+		cursor = startOfNextDay(cursor)
+	}
+	return from.Add(d)
+}
+
+// Elapsed thời gian làm việc đã trôi qua trong khoảng [from, to)
+func (c Calendar) Elapsed(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	if c.always {
+		return to.Sub(from)
+	}
+
+	var total time.Duration
+	cursor := from.In(c.loc)
+	to = to.In(c.loc)
+	for i := 0; i < maxCalendarDays && cursor.Before(to); i++ {
+		winStart, winEnd, ok := c.window(cursor)
+		if ok {
+			start := latest(cursor, winStart)
+			end := earliest(to, winEnd)
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		cursor = startOfNextDay(cursor)
+	}
+	return total
+}
+
+// window khung giờ làm việc của ngày chứa t
+func (c Calendar) window(t time.Time) (time.Time, time.Time, bool) {
+	if !c.days[t.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.loc)
+	return day.Add(c.start), day.Add(c.end), true
+}
+
+// parseClock parse "HH:mm" thành offset trong ngày
+func parseClock(s string) (time.Duration, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+func startOfNextDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
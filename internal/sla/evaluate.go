@@ -0,0 +1,85 @@
+package sla
+
+import (
+	"time"
+
+	"chatbox-gin/internal/models"
+)
+
+// ===========================================================================
+// Evaluate
+// Tính trạng thái SLA của một conversation tại một thời điểm
+// Hàm thuần, không truy cập DB (evaluator nền gọi cho từng conversation)
+// ===========================================================================
+
+// Target tên các mục tiêu SLA
+const (
+	TargetFirstResponse = "first_response"
+	TargetResolution    = "resolution"
+)
+
+// Result kết quả đánh giá SLA
+type Result struct {
+	Status             models.SLAStatus
+	FirstResponseDueAt *time.Time
+	ResolutionDueAt    *time.Time
+	// Breached các mục tiêu đã vi phạm
+	Breached []string
+	// Warning các mục tiêu sắp vi phạm
+	Warning []string
+}
+
+// Evaluate đánh giá SLA của conversation theo policy
+// Trả về Status = SLANone nếu priority không có target
+func Evaluate(conv *models.Conversation, policy models.SLAPolicy, cal Calendar, now time.Time) Result {
+	var result Result
+
+	target, ok := policy.TargetFor(conv.Priority)
+	if !policy.Enabled || !ok {
+		return result
+	}
+	result.Status = models.SLAOnTrack
+
+	if target.FirstResponseMinutes > 0 {
+		limit := time.Duration(target.FirstResponseMinutes) * time.Minute
+		due := cal.Add(conv.CreatedAt, limit)
+		result.FirstResponseDueAt = &due
+		result.check(TargetFirstResponse, conv.CreatedAt, conv.FirstResponseAt, limit, due, policy.WarningPercent, cal, now)
+	}
+
+	if target.ResolutionMinutes > 0 {
+		limit := time.Duration(target.ResolutionMinutes) * time.Minute
+		due := cal.Add(conv.CreatedAt, limit)
+		result.ResolutionDueAt = &due
+		result.check(TargetResolution, conv.CreatedAt, conv.ResolvedAt, limit, due, policy.WarningPercent, cal, now)
+	}
+
+	switch {
+	case len(result.Breached) > 0:
+		result.Status = models.SLABreached
+	case len(result.Warning) > 0:
+		result.Status = models.SLAWarning
+	}
+	return result
+}
+
+// check đánh giá một mục tiêu: đã hoàn thành (doneAt != nil) thì so với hạn,
+// chưa hoàn thành thì so thời gian làm việc đã trôi qua
+func (r *Result) check(name string, start time.Time, doneAt *time.Time, limit time.Duration, due time.Time, warnPercent int, cal Calendar, now time.Time) {
+	if doneAt != nil {
+		if doneAt.After(due) {
+			r.Breached = append(r.Breached, name)
+		}
+		return
+	}
+
+	if !now.Before(due) {
+		r.Breached = append(r.Breached, name)
+		return
+	}
+
+	elapsed := cal.Elapsed(start, now)
+	if elapsed*100 >= limit*time.Duration(warnPercent) {
+		r.Warning = append(r.Warning, name)
+	}
+}
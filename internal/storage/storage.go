@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"chatbox-gin/internal/config"
+)
+
+// ===========================================================================
+// Blob Storage
+// Lưu file nhị phân (ảnh, video, tài liệu) theo key
+// Có 2 driver: local (thư mục trên máy chủ) và s3 (AWS S3, MinIO, R2, ...)
+// ===========================================================================
+
+// ErrNotFound không tìm thấy object theo key
+var ErrNotFound = errors.New("blob not found")
+
+// ErrInvalidKey key rỗng hoặc chứa thành phần không hợp lệ ("..", bắt đầu bằng "/")
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// BlobStore interface lưu trữ file
+// Key dạng đường dẫn tương đối, phân cách bằng "/" (VD: "media/<workspace>/<id>")
+type BlobStore interface {
+	// Put ghi object, size = -1 nếu chưa biết trước dung lượng
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+
+	// Get mở object để đọc, trả về ErrNotFound nếu không tồn tại
+	// Caller phải Close reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete xóa object, không lỗi nếu object không tồn tại
+	Delete(ctx context.Context, key string) error
+}
+
+// New tạo BlobStore theo cấu hình
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.LocalDir)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
+
+// validateKey kiểm tra key không thoát ra ngoài thư mục/bucket gốc
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
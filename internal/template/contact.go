@@ -0,0 +1,43 @@
+package template
+
+import (
+	"strings"
+
+	"chatbox-gin/internal/models"
+)
+
+// ===========================================================================
+// Contact Variables
+// Biến template theo thông tin khách: {{contact.name}}, {{contact.fields.<key>}}
+// ===========================================================================
+
+// FieldPrefix tiền tố biến trường tùy chỉnh của khách (VD: {{contact.fields.plan}})
+const FieldPrefix = "contact.fields."
+
+// ContactVars giá trị các biến của khách, gồm mọi trường tùy chỉnh đã có giá trị
+func ContactVars(participant *models.Participant) Vars {
+	vars := Vars{
+		"contact.name":  deref(participant.Name),
+		"contact.email": deref(participant.Email),
+		"contact.phone": deref(participant.Phone),
+	}
+	for key, value := range participant.Metadata.CustomFields {
+		vars[FieldPrefix+key] = models.FormatCustomFieldValue(value)
+	}
+	return vars
+}
+
+// IsFieldVariable kiểm tra tên biến có dạng contact.fields.<key> với key hợp lệ
+// Trường chưa được định nghĩa hoặc khách chưa có giá trị thì biến rỗng (dùng fallback)
+func IsFieldVariable(name string) bool {
+	key, ok := strings.CutPrefix(name, FieldPrefix)
+	return ok && models.CustomFieldKeyPattern.MatchString(key)
+}
+
+// deref giá trị của con trỏ string (rỗng nếu nil)
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,46 @@
+package template
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ===========================================================================
+// Template Variables
+// Thay biến dạng {{contact.first_name}} trong nội dung tin nhắn soạn sẵn
+// Hỗ trợ giá trị mặc định khi biến rỗng: {{contact.first_name|bạn}}
+// ===========================================================================
+
+// placeholder khớp {{ name }} hoặc {{ name | fallback }}
+var placeholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_.]+)\s*(?:\|([^}]*))?\}\}`)
+
+// Vars giá trị các biến theo tên (VD: "contact.name" -> "Nguyễn Văn A")
+type Vars map[string]string
+
+// Render thay các biến trong text bằng giá trị tương ứng
+// Biến không có giá trị được thay bằng fallback (nếu có) hoặc chuỗi rỗng
+func Render(text string, vars Vars) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	return placeholder.ReplaceAllStringFunc(text, func(match string) string {
+		parts := placeholder.FindStringSubmatch(match)
+		if value := vars[parts[1]]; value != "" {
+			return value
+		}
+		return strings.TrimSpace(parts[2])
+	})
+}
+
+// Names danh sách tên biến được dùng trong text (không trùng, theo thứ tự xuất hiện)
+func Names(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, parts := range placeholder.FindAllStringSubmatch(text, -1) {
+		if !seen[parts[1]] {
+			seen[parts[1]] = true
+			names = append(names, parts[1])
+		}
+	}
+	return names
+}